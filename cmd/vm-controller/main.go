@@ -4,26 +4,50 @@ import (
 	"flag"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/golang/glog"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
 	"github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned"
 	"github.com/llparse/kube-crd-skel/pkg/client/informers/externalversions"
+	"github.com/llparse/kube-crd-skel/pkg/clientconfig"
+	"github.com/llparse/kube-crd-skel/pkg/console"
+	"github.com/llparse/kube-crd-skel/pkg/controller/attachment"
+	"github.com/llparse/kube-crd-skel/pkg/controller/backup"
+	"github.com/llparse/kube-crd-skel/pkg/controller/export"
+	"github.com/llparse/kube-crd-skel/pkg/controller/guestagent"
+	"github.com/llparse/kube-crd-skel/pkg/controller/migration"
+	"github.com/llparse/kube-crd-skel/pkg/controller/quota"
+	"github.com/llparse/kube-crd-skel/pkg/controller/snapshot"
+	"github.com/llparse/kube-crd-skel/pkg/controller/upload"
 	"github.com/llparse/kube-crd-skel/pkg/controller/vm"
 )
 
 func main() {
-	kubeconfig := flag.String("kubeconfig", "", "Path to a kube config; only required if out-of-cluster.")
+	kubeconfig := flag.String("kubeconfig", "", "Path to a kube config; defaults to $KUBECONFIG or ~/.kube/config, then the in-cluster config.")
 	workers := flag.Int("workers", 5, "Concurrent VM syncs")
+	launcherImage := flag.String("launcher-image", "docker.io/llparse/ranchervm-launcher:dev", "Image run in VM pods")
+	consoleAddr := flag.String("console-addr", ":9500", "Address to serve VM consoles on; empty to disable.")
+	consoleCert := flag.String("console-tls-cert", "", "TLS certificate for the console server")
+	consoleKey := flag.String("console-tls-key", "", "TLS private key for the console server")
+	consoleOrigins := flag.String("console-allowed-origins", "", "Comma-separated origins of web UIs allowed to use the console server besides its own")
+	kvmResource := flag.String("kvm-resource", "devices.kubevirt.io/kvm", "Extended resource of the device plugin providing /dev/kvm")
+	kvmNodeLabel := flag.String("kvm-node-label", ranchervm.GroupName+"/kvm", "Label set to \"true\" on nodes with /dev/kvm, used with --allow-privileged-kvm")
+	allowPrivileged := flag.Bool("allow-privileged-kvm", false, "Run VMs in privileged pods on nodes with --kvm-node-label if no node advertises --kvm-resource")
+	guestAgentInterval := flag.Duration("guest-agent-poll-interval", 30*time.Second, "How often guest agents are queried")
+	minCpuMillis := flag.Int("min-cpu-milli", int(ranchervm.DefaultSizeLimits.MinCpuMillis), "Smallest cpu_milli of a VM")
+	maxCpuMillis := flag.Int("max-cpu-milli", int(ranchervm.DefaultSizeLimits.MaxCpuMillis), "Largest cpu_milli of a VM")
+	minMemoryMB := flag.Int("min-memory-mb", int(ranchervm.DefaultSizeLimits.MinMemoryMB), "Smallest memory_mb of a VM")
+	maxMemoryMB := flag.Int("max-memory-mb", int(ranchervm.DefaultSizeLimits.MaxMemoryMB), "Largest memory_mb of a VM")
+	memoryOvercommit := flag.Int("memory-overcommit", 100, "Guest memory given per 100MB requested for VM pods, for VMs without memory_request_mb; 150 overcommits by half")
+	promoterClass := flag.String("snapshot-promoter-class", "snapshot-promoter", "StorageClass restoring claims from VolumeSnapshots")
 	flag.Set("logtostderr", "true")
 	flag.Parse()
 
@@ -32,8 +56,26 @@ func main() {
 		panic(err)
 	}
 
+	sizeLimits := ranchervm.SizeLimits{
+		MinCpuMillis: int32(*minCpuMillis),
+		MaxCpuMillis: int32(*maxCpuMillis),
+		MinMemoryMB:  int32(*minMemoryMB),
+		MaxMemoryMB:  int32(*maxMemoryMB),
+	}
+	if err := sizeLimits.Check(); err != nil {
+		glog.Fatalf("invalid size limits: %v", err)
+	}
+
+	if *allowPrivileged {
+		glog.Warningf("--allow-privileged-kvm is set: VMs may run in privileged pods with full access to their node if no node advertises %s", *kvmResource)
+	}
+
+	if *memoryOvercommit < 100 {
+		glog.Fatalf("--memory-overcommit must be at least 100")
+	}
+
 	apiextensionsclientset := apiextensionsclient.NewForConfigOrDie(config)
-	if err := ranchervm.CreateCustomResourceDefinition(apiextensionsclientset); err != nil && !apierrors.IsAlreadyExists(err) {
+	if err := ranchervm.CreateCustomResourceDefinition(apiextensionsclientset, sizeLimits); err != nil {
 		panic(err)
 	}
 
@@ -45,11 +87,131 @@ func main() {
 
 	stopCh := makeStopChan()
 
+	if *consoleAddr != "" {
+		var allowedOrigins []string
+		if *consoleOrigins != "" {
+			allowedOrigins = strings.Split(*consoleOrigins, ",")
+		}
+		go console.NewServer(
+			config,
+			kubeClientset,
+			allowedOrigins,
+			vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachines(),
+			vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineExports(),
+			vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineImageUploads(),
+			kubeInformerFactory.Core().V1().Pods(),
+		).Run(*consoleAddr, *consoleCert, *consoleKey, stopCh)
+	}
+
 	go vm.NewVirtualMachineController(
+		config,
+		vmClientset,
+		kubeClientset,
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachines(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineMigrations(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineInstanceTypes(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachinePreferences(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineQuotas(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineExports(),
+		kubeInformerFactory.Core().V1().Pods(),
+		kubeInformerFactory.Core().V1().Services(),
+		kubeInformerFactory.Core().V1().Nodes(),
+		kubeInformerFactory.Policy().V1beta1().PodDisruptionBudgets(),
+		kubeInformerFactory.Core().V1().PersistentVolumeClaims(),
+		kubeInformerFactory.Storage().V1().StorageClasses(),
+		*launcherImage,
+		*kvmResource,
+		*kvmNodeLabel,
+		*allowPrivileged,
+		sizeLimits,
+		*memoryOvercommit,
+	).Run(*workers, stopCh)
+
+	go snapshot.NewSnapshotController(
+		config,
+		vmClientset,
+		kubeClientset,
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachines(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineSnapshots(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineRestores(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineClones(),
+		kubeInformerFactory.Core().V1().Pods(),
+		kubeInformerFactory.Core().V1().PersistentVolumeClaims(),
+		kubeInformerFactory.Batch().V1().Jobs(),
+		*launcherImage,
+		*promoterClass,
+	).Run(*workers, stopCh)
+
+	go migration.NewMigrationController(
+		config,
+		vmClientset,
+		kubeClientset,
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachines(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineMigrations(),
+		kubeInformerFactory.Core().V1().Pods(),
+		kubeInformerFactory.Core().V1().PersistentVolumeClaims(),
+	).Run(*workers, stopCh)
+
+	go attachment.NewAttachmentController(
+		config,
 		vmClientset,
 		kubeClientset,
 		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachines(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineVolumeAttachments(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineMigrations(),
 		kubeInformerFactory.Core().V1().Pods(),
+		*launcherImage,
+	).Run(*workers, stopCh)
+
+	go backup.NewBackupController(
+		vmClientset,
+		kubeClientset,
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachines(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineSnapshots(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineBackupTargets(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineBackups(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineBackupRestores(),
+		kubeInformerFactory.Core().V1().PersistentVolumeClaims(),
+		kubeInformerFactory.Batch().V1().Jobs(),
+		*launcherImage,
+	).Run(*workers, stopCh)
+
+	go export.NewExportController(
+		vmClientset,
+		kubeClientset,
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachines(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineExports(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineImports(),
+		kubeInformerFactory.Core().V1().Pods(),
+		kubeInformerFactory.Core().V1().PersistentVolumeClaims(),
+		kubeInformerFactory.Batch().V1().Jobs(),
+		*launcherImage,
+	).Run(*workers, stopCh)
+
+	go upload.NewUploadController(
+		vmClientset,
+		kubeClientset,
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineImageUploads(),
+		kubeInformerFactory.Core().V1().Pods(),
+		kubeInformerFactory.Core().V1().PersistentVolumeClaims(),
+		*launcherImage,
+	).Run(*workers, stopCh)
+
+	go quota.NewQuotaController(
+		vmClientset,
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachines(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineQuotas(),
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachineInstanceTypes(),
+		kubeInformerFactory.Core().V1().PersistentVolumeClaims(),
+	).Run(*workers, stopCh)
+
+	go guestagent.NewGuestAgentController(
+		config,
+		vmClientset,
+		kubeClientset,
+		vmInformerFactory.Virtualmachine().V1alpha1().VirtualMachines(),
+		kubeInformerFactory.Core().V1().Pods(),
+		*guestAgentInterval,
 	).Run(*workers, stopCh)
 
 	vmInformerFactory.Start(stopCh)
@@ -59,10 +221,7 @@ func main() {
 }
 
 func NewKubeClientConfig(configPath string) (*rest.Config, error) {
-	if configPath != "" {
-		return clientcmd.BuildConfigFromFlags("", configPath)
-	}
-	return rest.InClusterConfig()
+	return clientconfig.NewKubeClientConfig(configPath).ClientConfig()
 }
 
 func makeStopChan() <-chan struct{} {
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"github.com/golang/glog"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/backup"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "console":
+			console(os.Args[2:])
+			return
+		case "freeze":
+			run(launcher.Freeze)
+			return
+		case "thaw":
+			run(launcher.Thaw)
+			return
+		case "migrate":
+			if len(os.Args) != 3 {
+				glog.Fatalf("usage: %s migrate <target-ip>", os.Args[0])
+			}
+			run(func() error { return launcher.Migrate(os.Args[2]) })
+			return
+		case "migrate-status":
+			run(migrationStatus)
+			return
+		case "migrate-cancel":
+			run(launcher.CancelMigration)
+			return
+		case "shutdown":
+			run(launcher.ShutdownAndWait)
+			return
+		case "guest-info":
+			run(guestInfo)
+			return
+		case "hotplug":
+			if len(os.Args) != 4 {
+				glog.Fatalf("usage: %s hotplug <vcpus> <memory-mb>", os.Args[0])
+			}
+			vcpus, err := strconv.Atoi(os.Args[2])
+			if err != nil {
+				glog.Fatalf("invalid vcpus: %v", err)
+			}
+			memoryMB, err := strconv.Atoi(os.Args[3])
+			if err != nil {
+				glog.Fatalf("invalid memory: %v", err)
+			}
+			run(func() error { return hotplug(vcpus, memoryMB) })
+			return
+		case "memory-stats":
+			run(memoryStats)
+			return
+		case "balloon":
+			if len(os.Args) != 3 {
+				glog.Fatalf("usage: %s balloon <target-mb>", os.Args[0])
+			}
+			targetMB, err := strconv.Atoi(os.Args[2])
+			if err != nil {
+				glog.Fatalf("invalid target: %v", err)
+			}
+			run(func() error { return launcher.SetBalloon(targetMB) })
+			return
+		case "serve-disk":
+			if len(os.Args) != 3 {
+				glog.Fatalf("usage: %s serve-disk <disk>", os.Args[0])
+			}
+			run(func() error { return launcher.ServeDisk(os.Args[2]) })
+			return
+		case "attach-disk":
+			if len(os.Args) != 4 {
+				glog.Fatalf("usage: %s attach-disk <disk> <server-ip>", os.Args[0])
+			}
+			run(func() error { return launcher.AttachDisk(os.Args[2], os.Args[3]) })
+			return
+		case "resize-disk":
+			if len(os.Args) != 3 {
+				glog.Fatalf("usage: %s resize-disk <disk>", os.Args[0])
+			}
+			run(func() error { return resizeDisk(os.Args[2]) })
+			return
+		case "backup-disk":
+			if len(os.Args) != 5 && len(os.Args) != 6 {
+				glog.Fatalf("usage: %s backup-disk <image> <location> <disk> [parent]", os.Args[0])
+			}
+			parent := ""
+			if len(os.Args) == 6 {
+				parent = os.Args[5]
+			}
+			run(func() error { return backupDisk(os.Args[2], os.Args[3], os.Args[4], parent) })
+			return
+		case "restore-disk":
+			if len(os.Args) != 5 {
+				glog.Fatalf("usage: %s restore-disk <location> <disk> <image>", os.Args[0])
+			}
+			run(func() error { return restoreDisk(os.Args[2], os.Args[3], os.Args[4]) })
+			return
+		case "export":
+			if len(os.Args) != 3 {
+				glog.Fatalf("usage: %s export <format>", os.Args[0])
+			}
+			run(func() error { return launcher.Export(vmapi.ExportFormat(os.Args[2])) })
+			return
+		case "read-descriptor":
+			if len(os.Args) != 3 {
+				glog.Fatalf("usage: %s read-descriptor <url>", os.Args[0])
+			}
+			run(func() error { return launcher.ReadDescriptor(os.Args[2]) })
+			return
+		case "import-disk":
+			if len(os.Args) != 5 {
+				glog.Fatalf("usage: %s import-disk <url> <file> <image>", os.Args[0])
+			}
+			run(func() error { return launcher.ImportDisk(os.Args[2], os.Args[3], os.Args[4]) })
+			return
+		case "upload":
+			if len(os.Args) != 3 {
+				glog.Fatalf("usage: %s upload <image>", os.Args[0])
+			}
+			run(func() error { return upload(os.Args[2]) })
+			return
+		case "detach-disk":
+			if len(os.Args) != 3 {
+				glog.Fatalf("usage: %s detach-disk <disk>", os.Args[0])
+			}
+			run(func() error { return launcher.DetachDisk(os.Args[2]) })
+			return
+		}
+	}
+
+	flag.Set("logtostderr", "true")
+	flag.Parse()
+
+	config, err := readConfig()
+	if err != nil {
+		glog.Fatalf("error reading vm config: %v", err)
+	}
+
+	dhcpServers, err := launcher.SetupNetwork(config)
+	if err != nil {
+		glog.Fatalf("error setting up network: %v", err)
+	}
+	// Bridged guests configure themselves as soon as they boot
+	stopCh := make(chan struct{})
+	for _, server := range dhcpServers {
+		go func(server *launcher.DHCPServer) {
+			if err := server.Run(stopCh); err != nil {
+				glog.Errorf("error serving dhcp: %v", err)
+			}
+		}(server)
+	}
+	if err := launcher.PrepareDisks(config); err != nil {
+		glog.Fatalf("error preparing disks: %v", err)
+	}
+	if err := launcher.CreateCloudInitImage(config); err != nil {
+		glog.Fatalf("error creating cloud-init image: %v", err)
+	}
+
+	if config.DedicatedCPUPlacement {
+		if config.HostCPUs, err = launcher.DedicatedCPUs(config); err != nil {
+			glog.Fatalf("error picking dedicated cpus: %v", err)
+		}
+	}
+
+	qemu, err := launcher.StartQemu(config)
+	if err != nil {
+		glog.Fatalf("error starting qemu: %v", err)
+	}
+	if len(config.HostCPUs) > 0 {
+		if err := launcher.PinVCPUs(config); err != nil {
+			qemu.Process.Kill()
+			glog.Fatalf("error pinning vcpus: %v", err)
+		}
+	}
+
+	go func() {
+		if err := launcher.NewSerialConsole().Run(stopCh); err != nil {
+			glog.Errorf("error serving serial console: %v", err)
+		}
+	}()
+	go func() {
+		if err := launcher.NewMetricsServer(config).Run(stopCh); err != nil {
+			glog.Errorf("error serving metrics: %v", err)
+		}
+	}()
+	if config.Incoming {
+		go func() {
+			if err := launcher.ServeIncomingMigration(config.MigrationSource, stopCh); err != nil {
+				glog.Errorf("error serving incoming migration: %v", err)
+			}
+		}()
+	}
+	c := make(chan os.Signal, 2)
+	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-c
+		glog.Infof("Received %v, shutting down guest", sig)
+		if err := launcher.Shutdown(); err != nil {
+			glog.Errorf("error shutting down guest, stopping qemu: %v", err)
+			qemu.Process.Signal(syscall.SIGTERM)
+			return
+		}
+		sig = <-c
+		glog.Infof("Received %v, stopping qemu", sig)
+		qemu.Process.Signal(syscall.SIGTERM)
+	}()
+
+	err = qemu.Wait()
+	close(stopCh)
+	if err != nil {
+		terminationMessage(fmt.Sprintf("qemu exited: %v", err))
+		glog.Fatalf("qemu exited: %v", err)
+	}
+	terminationMessage("Guest powered off")
+	glog.Info("qemu exited")
+}
+
+// readConfig reads the VM config from the pod annotations
+func readConfig() (*launcher.Config, error) {
+	annotations, err := launcher.ReadAnnotations(filepath.Join(launcher.PodInfoDir, "annotations"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading pod annotations: %v", err)
+	}
+	return launcher.ConfigFromAnnotations(annotations)
+}
+
+// terminationMessage tells the controller how the VM stopped
+func terminationMessage(message string) {
+	if err := ioutil.WriteFile(launcher.TerminationLog, []byte(message), 0644); err != nil {
+		glog.Errorf("error writing termination message: %v", err)
+	}
+}
+
+// console attaches stdin/stdout to the serial console of the VM running in
+// this pod. It's meant to be exec'd by consoles outside the pod.
+func console(args []string) {
+	flags := flag.NewFlagSet("console", flag.ExitOnError)
+	log := flags.Bool("log", false, "Print buffered console output and exit")
+	flags.Parse(args)
+
+	command := launcher.ConsoleAttach
+	if *log {
+		command = launcher.ConsoleLog
+	}
+	conn, err := launcher.ConnectConsole(command)
+	if err != nil {
+		glog.Fatalf("error connecting to serial console: %v", err)
+	}
+	defer conn.Close()
+
+	if !*log {
+		go func() {
+			// Detach once the client closes stdin
+			io.Copy(conn, os.Stdin)
+			conn.Close()
+		}()
+	}
+	io.Copy(os.Stdout, conn)
+}
+
+// guestInfo prints what the guest agent reports about the guest as JSON
+func guestInfo() error {
+	info, err := launcher.GetGuestInfo()
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(info)
+}
+
+// hotplug grows the guest and prints what was hotplugged into it as JSON
+func hotplug(vcpus, memoryMB int) error {
+	config, err := readConfig()
+	if err != nil {
+		return err
+	}
+	state, err := launcher.Hotplug(config, vcpus, memoryMB)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(state)
+}
+
+// resizeDisk grows a disk and prints its size as JSON
+func resizeDisk(name string) error {
+	size, err := launcher.ResizeDisk(name)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(size)
+}
+
+// backupDisk uploads a disk image to the backup target configured by the
+// environment
+func backupDisk(image, location, disk, parent string) error {
+	store, err := backup.StoreFromEnv()
+	if err != nil {
+		return err
+	}
+	_, err = store.BackupDisk(image, location, disk, parent)
+	return err
+}
+
+// restoreDisk downloads a disk image from the backup target configured by
+// the environment
+func restoreDisk(location, disk, image string) error {
+	store, err := backup.StoreFromEnv()
+	if err != nil {
+		return err
+	}
+	return store.RestoreDisk(location, disk, image)
+}
+
+// upload receives an image and reports what was uploaded to the controller
+// as JSON
+func upload(image string) error {
+	info, err := launcher.Upload(image)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	terminationMessage(string(data))
+	return nil
+}
+
+// memoryStats prints the guest's memory as JSON
+func memoryStats() error {
+	status, err := launcher.MemoryStats()
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(status)
+}
+
+// migrationStatus prints the state of the outgoing migration as JSON
+func migrationStatus() error {
+	info, err := launcher.MigrationStatus()
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(info)
+}
+
+// run runs a command against the VM running in this pod. It's meant to be
+// exec'd by the controller.
+func run(command func() error) {
+	flag.Set("logtostderr", "true")
+	if err := command(); err != nil {
+		glog.Fatalf("%s: %v", os.Args[1], err)
+	}
+}
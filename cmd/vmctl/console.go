@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/ssh/terminal"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/llparse/kube-crd-skel/pkg/console"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+// escapeChar (^]) detaches from the console, as in telnet
+const escapeChar = 0x1d
+
+// escapeReader returns EOF once escapeChar is read
+type escapeReader struct {
+	r io.Reader
+}
+
+func (e escapeReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if i := bytes.IndexByte(p[:n], escapeChar); i >= 0 {
+		return i, io.EOF
+	}
+	return n, err
+}
+
+func consoleCommand(args []string) error {
+	flags, o := newFlagSet("console")
+	log := flags.Bool("log", false, "Print buffered console output and exit")
+	flags.Parse(args)
+	requireArgs(flags, 1)
+
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+	pod, err := c.launcherPod(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *log {
+		return console.Exec(c.config, c.kube, pod, launcher.SerialCommand(true), remotecommand.StreamOptions{
+			Stdout: os.Stdout,
+			Stderr: os.Stderr,
+		})
+	}
+
+	// The guest does its own echo and line editing
+	fd := int(os.Stdin.Fd())
+	if terminal.IsTerminal(fd) {
+		state, err := terminal.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer terminal.Restore(fd, state)
+	}
+	fmt.Fprintf(os.Stderr, "Connected to %s/%s, press ^] to detach\r\n", c.namespace, flags.Arg(0))
+
+	return console.Exec(c.config, c.kube, pod, launcher.SerialCommand(false), remotecommand.StreamOptions{
+		Stdin:  escapeReader{os.Stdin},
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// portsFlag collects repeated NAME:PORT[/PROTOCOL] flags
+type portsFlag []vmapi.VirtualMachinePort
+
+func (f *portsFlag) String() string {
+	ports := []string{}
+	for _, port := range *f {
+		ports = append(ports, fmt.Sprintf("%s:%d/%s", port.Name, port.Port, port.Protocol))
+	}
+	return strings.Join(ports, ",")
+}
+
+func (f *portsFlag) Set(value string) error {
+	port := vmapi.VirtualMachinePort{}
+	if i := strings.Index(value, "/"); i >= 0 {
+		port.Protocol = corev1.Protocol(strings.ToUpper(value[i+1:]))
+		value = value[:i]
+	}
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("expected NAME:PORT[/PROTOCOL], got %q", value)
+	}
+	n, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return fmt.Errorf("invalid port %q", parts[1])
+	}
+	port.Name = parts[0]
+	port.Port = int32(n)
+	*f = append(*f, port)
+	return nil
+}
+
+func createCommand(args []string) error {
+	flags, o := newFlagSet("create")
+	cpuMillis := flags.Int("cpu-milli", 1000, "CPU in thousandths of a core")
+	memoryMB := flags.Int("memory-mb", 1024, "Memory in MiB")
+	serviceType := flags.String("service-type", "", "Type of the Service exposing --port (ClusterIP, NodePort or LoadBalancer)")
+	stopped := flags.Bool("stopped", false, "Create the VM without starting it")
+	ports := portsFlag{}
+	flags.Var(&ports, "port", "Guest port to expose as NAME:PORT[/PROTOCOL]; may be repeated")
+	flags.Parse(args)
+	requireArgs(flags, 1)
+
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+
+	vm, err := c.vm.VirtualmachineV1alpha1().VirtualMachines(c.namespace).Create(&vmapi.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      flags.Arg(0),
+			Namespace: c.namespace,
+		},
+		Spec: vmapi.VirtualMachineSpec{
+			CpuMillis:   int32(*cpuMillis),
+			MemoryMB:    int32(*memoryMB),
+			Ports:       ports,
+			ServiceType: corev1.ServiceType(*serviceType),
+			Stopped:     *stopped,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("virtualmachine %s/%s created\n", vm.Namespace, vm.Name)
+	return nil
+}
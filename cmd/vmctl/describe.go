@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+func describeCommand(args []string) error {
+	flags, o := newFlagSet("describe")
+	flags.Parse(args)
+	requireArgs(flags, 1)
+
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+	vm, err := c.vm.VirtualmachineV1alpha1().VirtualMachines(c.namespace).Get(flags.Arg(0), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", vm.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", vm.Namespace)
+	fmt.Fprintf(w, "Created:\t%s\n", vm.CreationTimestamp.Time)
+	fmt.Fprintf(w, "Phase:\t%s\n", orNone(string(vm.Status.Phase)))
+	fmt.Fprintf(w, "Node:\t%s\n", orNone(vm.Status.NodeName))
+	fmt.Fprintf(w, "CPU:\t%dm\n", vm.Spec.CpuMillis)
+	fmt.Fprintf(w, "Memory:\t%dMi\n", vm.Spec.MemoryMB)
+	fmt.Fprintf(w, "Stopped:\t%t\n", vm.Spec.Stopped)
+	fmt.Fprintf(w, "Interfaces:\t\n")
+	for _, iface := range vm.Status.Interfaces {
+		fmt.Fprintf(w, "  %s:\tMAC %s, IP %s\n", iface.Name, iface.MACAddress, orNone(iface.IP))
+	}
+	if len(vm.Spec.Ports) > 0 {
+		fmt.Fprintf(w, "Ports:\t\n")
+		for _, port := range vm.Spec.Ports {
+			fmt.Fprintf(w, "  %s:\t%d/%s\n", port.Name, port.Port, port.Protocol)
+		}
+	}
+	w.Flush()
+
+	// Events of the VM and of the pod and service named after it
+	events, err := c.kube.CoreV1().Events(c.namespace).List(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.name", vm.Name).String(),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	if len(events.Items) == 0 {
+		fmt.Println("Events: <none>")
+		return nil
+	}
+	sort.Slice(events.Items, func(i, j int) bool {
+		return events.Items[i].LastTimestamp.Before(&events.Items[j].LastTimestamp)
+	})
+
+	fmt.Println("Events:")
+	w = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "  TYPE\tREASON\tAGE\tOBJECT\tMESSAGE")
+	for _, event := range events.Items {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n",
+			event.Type,
+			event.Reason,
+			age(event.LastTimestamp),
+			involvedObject(&event),
+			event.Message)
+	}
+	return w.Flush()
+}
+
+func involvedObject(event *corev1.Event) string {
+	return event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func listCommand(args []string) error {
+	flags, o := newFlagSet("list")
+	allNamespaces := flags.Bool("all-namespaces", false, "List VMs in all namespaces")
+	flags.Parse(args)
+	requireArgs(flags, 0)
+
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+	ns := c.namespace
+	if *allNamespaces {
+		ns = metav1.NamespaceAll
+	}
+
+	list, err := c.vm.VirtualmachineV1alpha1().VirtualMachines(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 3, ' ', 0)
+	defer w.Flush()
+	if *allNamespaces {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tPHASE\tIP\tCPU\tMEMORY\tNODE\tAGE")
+	for _, vm := range list.Items {
+		if *allNamespaces {
+			fmt.Fprintf(w, "%s\t", vm.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%dm\t%dMi\t%s\t%s\n",
+			vm.Name,
+			orNone(string(vm.Status.Phase)),
+			orNone(vmIP(&vm)),
+			vm.Spec.CpuMillis,
+			vm.Spec.MemoryMB,
+			orNone(vm.Status.NodeName),
+			age(vm.CreationTimestamp))
+	}
+	return nil
+}
+
+// vmIP returns the address of the VM's first interface
+func vmIP(vm *vmapi.VirtualMachine) string {
+	if len(vm.Status.Interfaces) == 0 {
+		return ""
+	}
+	return vm.Status.Interfaces[0].IP
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+// age formats the time since t like kubectl does
+func age(t metav1.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	d := time.Since(t.Time)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
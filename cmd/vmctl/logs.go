@@ -0,0 +1,37 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// logsCommand prints the launcher's logs. Guest output is available through
+// console --log.
+func logsCommand(args []string) error {
+	flags, o := newFlagSet("logs")
+	follow := flags.Bool("f", false, "Follow the log")
+	flags.Parse(args)
+	requireArgs(flags, 1)
+
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+	pod, err := c.launcherPod(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	stream, err := c.kube.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Follow: *follow,
+	}).Stream()
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	_, err = io.Copy(os.Stdout, stream)
+	return err
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned"
+	"github.com/llparse/kube-crd-skel/pkg/clientconfig"
+	"github.com/llparse/kube-crd-skel/pkg/console"
+)
+
+type command struct {
+	usage       string
+	description string
+	run         func(args []string) error
+}
+
+var commands map[string]command
+
+// Commands refer back to the table for their usage, so it can't be
+// initialized statically
+func init() {
+	commands = map[string]command{
+		"console":      command{"NAME", "Attach to the serial console of a VM", consoleCommand},
+		"create":       command{"NAME", "Create a VM", createCommand},
+		"describe":     command{"NAME", "Show details and events of a VM", describeCommand},
+		"image-upload": command{"NAME FILE", "Upload a local disk image into a new claim", imageUploadCommand},
+		"list":         command{"", "List VMs", listCommand},
+		"logs":         command{"NAME", "Print the logs of a VM's launcher", logsCommand},
+		"restart":      command{"NAME", "Restart a running VM", restartCommand},
+		"ssh":          command{"NAME [-- ARGS...]", "SSH into a VM", sshCommand},
+		"start":        command{"NAME", "Start a stopped VM", startCommand},
+		"stop":         command{"NAME", "Stop a running VM", stopCommand},
+		"wait":         command{"NAME", "Wait for a VM to reach a phase", waitCommand},
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := []string{}
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(os.Stderr, "Usage: vmctl COMMAND [flags]\n\nCommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].description)
+	}
+	os.Exit(2)
+}
+
+// options are the flags common to all commands
+type options struct {
+	kubeconfig string
+	namespace  string
+}
+
+// clients are the API clients and namespace commands operate against
+type clients struct {
+	config    *rest.Config
+	namespace string
+	kube      kubernetes.Interface
+	vm        versioned.Interface
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *options) {
+	o := &options{}
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.StringVar(&o.kubeconfig, "kubeconfig", "", "Path to a kube config; defaults to $KUBECONFIG or ~/.kube/config, then the in-cluster config.")
+	flags.StringVar(&o.namespace, "n", "", "Namespace; defaults to the kube config context's namespace.")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: vmctl %s %s [flags]\n\n%s\n\nFlags:\n", name, commands[name].usage, commands[name].description)
+		flags.PrintDefaults()
+	}
+	return flags, o
+}
+
+func (o *options) clients() (*clients, error) {
+	clientConfig := clientconfig.NewKubeClientConfig(o.kubeconfig)
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := o.namespace
+	if namespace == "" {
+		if namespace, _, err = clientConfig.Namespace(); err != nil {
+			return nil, err
+		}
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	vmClient, err := versioned.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clients{
+		config:    config,
+		namespace: namespace,
+		kube:      kubeClient,
+		vm:        vmClient,
+	}, nil
+}
+
+// launcherPod returns the running pod hosting the named VM
+func (c *clients) launcherPod(name string) (*corev1.Pod, error) {
+	vm, err := c.vm.VirtualmachineV1alpha1().VirtualMachines(c.namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return console.GetLauncherPod(c.kube, vm)
+}
+
+// requireArgs prints usage and exits unless flags has exactly n positional args
+func requireArgs(flags *flag.FlagSet, n int) {
+	if flags.NArg() != n {
+		flags.Usage()
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+func startCommand(args []string) error {
+	return setStopped("start", args, false)
+}
+
+func stopCommand(args []string) error {
+	return setStopped("stop", args, true)
+}
+
+func setStopped(name string, args []string, stopped bool) error {
+	flags, o := newFlagSet(name)
+	flags.Parse(args)
+	requireArgs(flags, 1)
+
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+
+	vms := c.vm.VirtualmachineV1alpha1().VirtualMachines(c.namespace)
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		vm, err := vms.Get(flags.Arg(0), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if vm.Spec.Stopped == stopped {
+			return nil
+		}
+		vm.Spec.Stopped = stopped
+		_, err = vms.Update(vm)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	state := "started"
+	if stopped {
+		state = "stopped"
+	}
+	fmt.Printf("virtualmachine %s/%s %s\n", c.namespace, flags.Arg(0), state)
+	return nil
+}
+
+// restartCommand deletes the VM's pod; the controller replaces it
+func restartCommand(args []string) error {
+	flags, o := newFlagSet("restart")
+	flags.Parse(args)
+	requireArgs(flags, 1)
+
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+	pod, err := c.launcherPod(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := c.kube.CoreV1().Pods(pod.Namespace).Delete(pod.Name, &metav1.DeleteOptions{}); err != nil {
+		return err
+	}
+	fmt.Printf("virtualmachine %s/%s restarted\n", c.namespace, flags.Arg(0))
+	return nil
+}
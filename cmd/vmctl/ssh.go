@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/llparse/kube-crd-skel/pkg/console"
+)
+
+// sshCommand runs the local ssh client against the VM through a port
+// forward to its pod. Masqueraded guests must expose the port for the
+// launcher to forward it.
+func sshCommand(args []string) error {
+	flags, o := newFlagSet("ssh")
+	user := flags.String("l", "", "User to log in as")
+	port := flags.Int("p", 22, "Guest SSH port")
+	flags.Parse(args)
+	if flags.NArg() < 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+	pod, err := c.launcherPod(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				stream, err := console.DialPod(c.config, c.kube, pod, *port)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "error forwarding to %s/%s: %v\n", pod.Namespace, pod.Name, err)
+					return
+				}
+				console.Proxy(conn, stream)
+			}()
+		}
+	}()
+
+	host := "localhost"
+	if *user != "" {
+		host = *user + "@" + host
+	}
+	sshArgs := []string{
+		"-p", strconv.Itoa(listener.Addr().(*net.TCPAddr).Port),
+		// Every VM is localhost from ssh's point of view
+		"-o", "HostKeyAlias=" + c.namespace + "." + flags.Arg(0),
+		host,
+	}
+	command := flags.Args()[1:]
+	if len(command) > 0 && command[0] == "--" {
+		command = command[1:]
+	}
+	sshArgs = append(sshArgs, command...)
+
+	cmd := exec.Command("ssh", sshArgs...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+// Failed chunks are retried from the offset the server reports
+const chunkRetries = 5
+
+func imageUploadCommand(args []string) error {
+	flags, o := newFlagSet("image-upload")
+	server := flags.String("server", os.Getenv("VMCTL_CONSOLE_SERVER"), "URL of the controller's console server; defaults to $VMCTL_CONSOLE_SERVER.")
+	token := flags.String("token", "", "Bearer token; defaults to the kube config's.")
+	insecure := flags.Bool("insecure-skip-tls-verify", false, "Don't verify the console server's certificate.")
+	sizeMB := flags.Int64("size-mb", 0, "Size of the claim; defaults to fit the image.")
+	storageClass := flags.String("storage-class", "", "Storage class of the claim.")
+	chunkMB := flags.Int64("chunk-mb", 16, "Size of the chunks the image is sent in.")
+	timeout := flags.Duration("timeout", 30*time.Minute, "How long to wait for the upload to start, and for the image to be converted.")
+	flags.Parse(args)
+	requireArgs(flags, 2)
+	name, filename := flags.Arg(0), flags.Arg(1)
+	if *server == "" {
+		return fmt.Errorf("--server is required")
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	size, err := imageSize(f)
+	if err != nil {
+		return err
+	}
+
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+	client, err := consoleClient(c.config, *token, *insecure)
+	if err != nil {
+		return err
+	}
+
+	// Uploads are resumed by running the command again
+	uploads := c.vm.VirtualmachineV1alpha1().VirtualMachineImageUploads(c.namespace)
+	upload, err := uploads.Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		upload = &vmapi.VirtualMachineImageUpload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: vmapi.VirtualMachineImageUploadSpec{
+				SizeMB: *sizeMB,
+			},
+		}
+		if upload.Spec.SizeMB == 0 {
+			// Leave room for the claim's filesystem
+			upload.Spec.SizeMB = ((size+1<<20-1)>>20)*100/90 + 1
+		}
+		if *storageClass != "" {
+			upload.Spec.StorageClassName = storageClass
+		}
+		if upload, err = uploads.Create(upload); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Created virtualmachineimageupload %s/%s\n", c.namespace, name)
+	} else if err != nil {
+		return err
+	}
+
+	err = wait.PollImmediate(time.Second, *timeout, func() (bool, error) {
+		if upload, err = uploads.Get(name, metav1.GetOptions{}); err != nil {
+			return false, err
+		}
+		switch upload.Status.Phase {
+		case vmapi.UploadFailed:
+			return false, fmt.Errorf("upload failed: %s", upload.Status.Message)
+		case vmapi.UploadComplete:
+			return false, fmt.Errorf("upload is already complete")
+		}
+		return upload.Status.Phase == vmapi.UploadReady, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for the upload to be ready")
+	}
+	if err != nil {
+		return err
+	}
+
+	u := &uploader{
+		url:    strings.TrimSuffix(*server, "/") + upload.Status.Path,
+		client: client,
+	}
+	if err := u.upload(f, *chunkMB<<20); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Converting image\n")
+	err = wait.PollImmediate(2*time.Second, *timeout, func() (bool, error) {
+		if upload, err = uploads.Get(name, metav1.GetOptions{}); err != nil {
+			return false, err
+		}
+		if upload.Status.Phase == vmapi.UploadFailed {
+			return false, fmt.Errorf("upload failed: %s", upload.Status.Message)
+		}
+		return upload.Status.Phase == vmapi.UploadComplete, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for the image to be converted")
+	}
+	if err != nil {
+		return err
+	}
+	claimName := upload.Spec.ClaimName
+	if claimName == "" {
+		claimName = upload.Name
+	}
+	fmt.Printf("Uploaded %s image to claim %s/%s\n", upload.Status.Format, c.namespace, claimName)
+	return nil
+}
+
+// imageSize returns the size of the disk in the image, which is larger than
+// the file for sparse images
+func imageSize(f *os.File) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if info.Size() == 0 {
+		return 0, fmt.Errorf("%s is empty", f.Name())
+	}
+	size, err := launcher.VirtualSize(f)
+	if err != nil {
+		return 0, err
+	}
+	if size < info.Size() {
+		size = info.Size()
+	}
+	return size, nil
+}
+
+// consoleClient returns a client of the console server, authenticated with
+// token, or else as the kube config authenticates with the API server. The
+// console server reviews bearer tokens, so only those are sent, whether set
+// in the kube config or refreshed by its auth provider.
+func consoleClient(config *rest.Config, token string, insecure bool) (*http.Client, error) {
+	var rt http.RoundTripper = &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+	}
+	if token != "" {
+		return &http.Client{Transport: transport.NewBearerAuthRoundTripper(token, rt)}, nil
+	}
+	if config.BearerToken == "" && config.AuthProvider == nil {
+		return nil, fmt.Errorf("the kube config has no bearer token, pass --token")
+	}
+	// Client certificates are meant for the API server
+	authConfig := &rest.Config{
+		Host:                config.Host,
+		BearerToken:         config.BearerToken,
+		AuthProvider:        config.AuthProvider,
+		AuthConfigPersister: config.AuthConfigPersister,
+	}
+	rt, err := rest.HTTPWrappersForConfig(authConfig, rt)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: rt}, nil
+}
+
+// uploader sends an image in chunks through the console server
+type uploader struct {
+	url    string
+	client *http.Client
+}
+
+// do sends a request with the body and returns the upload's status. Only
+// conflicts, which report the offset to resume from, aren't errors.
+func (u *uploader) do(method string, body io.Reader, contentLength int64, contentRange string) (*launcher.UploadStatus, error) {
+	req, err := http.NewRequest(method, u.url, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentRange != "" {
+		req.ContentLength = contentLength
+		req.Header.Set("Content-Range", contentRange)
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusConflict {
+		message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s %s: %s: %s", method, u.url, resp.Status, strings.TrimSpace(string(message)))
+	}
+	status := &launcher.UploadStatus{}
+	if err := json.NewDecoder(resp.Body).Decode(status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// upload sends the file from the offset the server has received up to
+func (u *uploader) upload(f *os.File, chunkSize int64) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	total := info.Size()
+	status, err := u.do("GET", nil, 0, "")
+	if err != nil {
+		return err
+	}
+	if status.Size != 0 && status.Size != total {
+		return fmt.Errorf("the upload was started with a %d byte file", status.Size)
+	}
+	if status.Offset > 0 {
+		fmt.Fprintf(os.Stderr, "Resuming upload at %d MiB\n", status.Offset>>20)
+	}
+
+	offset, retries := status.Offset, 0
+	for offset < total {
+		n := chunkSize
+		if offset+n > total {
+			n = total - offset
+		}
+		contentRange := fmt.Sprintf("bytes %d-%d/%d", offset, offset+n-1, total)
+		status, err := u.do("PUT", io.NewSectionReader(f, offset, n), n, contentRange)
+		if err != nil {
+			if retries++; retries > chunkRetries {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "\nRetrying: %v\n", err)
+			time.Sleep(time.Duration(retries) * time.Second)
+			if status, err = u.do("GET", nil, 0, ""); err != nil {
+				continue
+			}
+		} else {
+			retries = 0
+		}
+		offset = status.Offset
+		fmt.Fprintf(os.Stderr, "\rUploaded %d of %d MiB", offset>>20, total>>20)
+	}
+	fmt.Fprintf(os.Stderr, "\n")
+	return nil
+}
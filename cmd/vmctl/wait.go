@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func waitCommand(args []string) error {
+	flags, o := newFlagSet("wait")
+	phase := flags.String("phase", string(vmapi.VirtualMachineRunning), "Phase to wait for")
+	timeout := flags.Duration("timeout", 5*time.Minute, "How long to wait")
+	flags.Parse(args)
+	requireArgs(flags, 1)
+
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+
+	vms := c.vm.VirtualmachineV1alpha1().VirtualMachines(c.namespace)
+	var last vmapi.VirtualMachinePhase
+	err = wait.PollImmediate(time.Second, *timeout, func() (bool, error) {
+		vm, err := vms.Get(flags.Arg(0), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		last = vm.Status.Phase
+		return string(last) == *phase, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for phase %s, last observed %s", *phase, orNone(string(last)))
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("virtualmachine %s/%s is %s\n", c.namespace, flags.Arg(0), *phase)
+	return nil
+}
@@ -2,20 +2,30 @@
 
 REPO=${REPO:-llparse}
 
-if [ "$IMAGE" == "" ]; then
-  go build -o bin/vm-controller cmd/vm-controller/main.go
-else
-  GOOS=linux GOARCH=amd64 go build -o bin/image/vm-controller cmd/vm-controller/main.go
-  cp -f hack/Dockerfile bin/image/
+build_image() {
+  local image_tag=$1
+  local context=$2
 
-  image_tag="$REPO/ranchervm-controller:dev"
-
-  docker build -t ${image_tag} bin/image
+  docker build -t ${image_tag} ${context}
   echo
   read -p "Push ${image_tag} (y/n)? " choice
   case "$choice" in 
     y|Y ) docker push ${image_tag} ;;
     * ) ;;
   esac
+}
+
+if [ "$IMAGE" == "" ]; then
+  go build -o bin/vm-controller cmd/vm-controller/main.go
+  go build -o bin/vm-launcher ./cmd/vm-launcher
+  go build -o bin/vmctl ./cmd/vmctl
+else
+  GOOS=linux GOARCH=amd64 go build -o bin/image/controller/vm-controller cmd/vm-controller/main.go
+  GOOS=linux GOARCH=amd64 go build -o bin/image/launcher/vm-launcher ./cmd/vm-launcher
+  cp -f hack/Dockerfile bin/image/controller/
+  cp -f hack/launcher/Dockerfile bin/image/launcher/
+
+  build_image "$REPO/ranchervm-controller:dev" bin/image/controller
+  build_image "$REPO/ranchervm-launcher:dev" bin/image/launcher
   rm -r bin/image
 fi
@@ -2,10 +2,13 @@ package ranchervm
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -13,52 +16,186 @@ import (
 
 const (
 	GroupName = "vm.rancher.com"
+
+	// LabelVMName is set on resources created on behalf of a VM to the VM's
+	// name, so they can be selected without relying on their own names.
+	LabelVMName = GroupName + "/name"
+
+	// LabelMigrationTarget is set on the target pod of a migration to the
+	// migration's name. Target pods only get LabelVMName once they take
+	// over, so that they receive no traffic before.
+	LabelMigrationTarget = GroupName + "/migration-target"
+
+	// FinalizerVolumeAttachment holds off the deletion of a volume
+	// attachment until its disk is detached from the VM
+	FinalizerVolumeAttachment = GroupName + "/volume-attachment"
 )
 
-func CreateCustomResourceDefinition(clientset apiextensionsclient.Interface) error {
-	var minCpuMilli, maxCpuMilli, minMemoryMB, maxMemoryMB float64
-	minCpuMilli = 500.0
-	maxCpuMilli = 8000.0
-	minMemoryMB = 512.0
-	maxMemoryMB = 65536.0
+// SizeLimits bound the CPU and memory of VMs and instance types
+type SizeLimits struct {
+	MinCpuMillis int32
+	MaxCpuMillis int32
+	MinMemoryMB  int32
+	MaxMemoryMB  int32
+}
+
+var DefaultSizeLimits = SizeLimits{
+	MinCpuMillis: 500,
+	MaxCpuMillis: 8000,
+	MinMemoryMB:  512,
+	MaxMemoryMB:  65536,
+}
+
+// Check returns an error if no size fits the limits
+func (l SizeLimits) Check() error {
+	if l.MinCpuMillis <= 0 || l.MinMemoryMB <= 0 {
+		return fmt.Errorf("minimum cpu_milli and memory_mb must be positive")
+	}
+	if l.MinCpuMillis > l.MaxCpuMillis {
+		return fmt.Errorf("minimum cpu_milli %d is above the maximum %d", l.MinCpuMillis, l.MaxCpuMillis)
+	}
+	if l.MinMemoryMB > l.MaxMemoryMB {
+		return fmt.Errorf("minimum memory_mb %d is above the maximum %d", l.MinMemoryMB, l.MaxMemoryMB)
+	}
+	return nil
+}
+
+// Validate checks the given size against the limits
+func (l SizeLimits) Validate(cpuMillis, memoryMB int32) error {
+	if cpuMillis < l.MinCpuMillis || cpuMillis > l.MaxCpuMillis {
+		return fmt.Errorf("cpu_milli %d is outside [%d, %d]", cpuMillis, l.MinCpuMillis, l.MaxCpuMillis)
+	}
+	if memoryMB < l.MinMemoryMB || memoryMB > l.MaxMemoryMB {
+		return fmt.Errorf("memory_mb %d is outside [%d, %d]", memoryMB, l.MinMemoryMB, l.MaxMemoryMB)
+	}
+	return nil
+}
+
+// CreateCustomResourceDefinition creates the CRDs of every resource in the
+// group. The validation schema of those that already exist is updated, so
+// that changed size limits take effect.
+func CreateCustomResourceDefinition(clientset apiextensionsclient.Interface, limits SizeLimits) error {
+	for _, crd := range []*apiextensionsv1beta1.CustomResourceDefinition{
+		withValidation(newCustomResourceDefinition("virtualmachines", "VirtualMachine", "vm"), limits),
+		newCustomResourceDefinition("virtualmachinesnapshots", "VirtualMachineSnapshot", "vmsnapshot"),
+		newCustomResourceDefinition("virtualmachinerestores", "VirtualMachineRestore", "vmrestore"),
+		newCustomResourceDefinition("virtualmachineclones", "VirtualMachineClone", "vmclone"),
+		newCustomResourceDefinition("virtualmachinemigrations", "VirtualMachineMigration", "vmmigration"),
+		withInstanceTypeValidation(clusterScoped(newCustomResourceDefinition("virtualmachineinstancetypes", "VirtualMachineInstanceType", "vminstancetype")), limits),
+		newCustomResourceDefinition("virtualmachinepreferences", "VirtualMachinePreference", "vmpreference"),
+		newCustomResourceDefinition("virtualmachinequotas", "VirtualMachineQuota", "vmquota"),
+		newCustomResourceDefinition("virtualmachinevolumeattachments", "VirtualMachineVolumeAttachment", "vmvolumeattachment"),
+		newCustomResourceDefinition("virtualmachinebackuptargets", "VirtualMachineBackupTarget", "vmbackuptarget"),
+		newCustomResourceDefinition("virtualmachinebackups", "VirtualMachineBackup", "vmbackup"),
+		newCustomResourceDefinition("virtualmachinebackuprestores", "VirtualMachineBackupRestore", "vmbackuprestore"),
+		newCustomResourceDefinition("virtualmachineexports", "VirtualMachineExport", "vmexport"),
+		newCustomResourceDefinition("virtualmachineimports", "VirtualMachineImport", "vmimport"),
+		newCustomResourceDefinition("virtualmachineimageuploads", "VirtualMachineImageUpload", "vmupload"),
+	} {
+		err := createCustomResourceDefinition(clientset, crd)
+		if apierrors.IsAlreadyExists(err) {
+			err = updateValidation(clientset, crd)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	crd := &apiextensionsv1beta1.CustomResourceDefinition{
+func newCustomResourceDefinition(plural, kind string, shortNames ...string) *apiextensionsv1beta1.CustomResourceDefinition {
+	return &apiextensionsv1beta1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "virtualmachines." + GroupName,
+			Name: plural + "." + GroupName,
 		},
 		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
 			Group:   GroupName,
 			Version: "v1alpha1",
 			Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
-				Plural:     "virtualmachines",
-				Singular:   "virtualmachine",
-				Kind:       "VirtualMachine",
-				ShortNames: []string{"vm"},
+				Plural:     plural,
+				Singular:   strings.ToLower(kind),
+				Kind:       kind,
+				ShortNames: shortNames,
 			},
 			Scope: apiextensionsv1beta1.NamespaceScoped,
-			Validation: &apiextensionsv1beta1.CustomResourceValidation{
-				OpenAPIV3Schema: &apiextensionsv1beta1.JSONSchemaProps{
+		},
+	}
+}
+
+func clusterScoped(crd *apiextensionsv1beta1.CustomResourceDefinition) *apiextensionsv1beta1.CustomResourceDefinition {
+	crd.Spec.Scope = apiextensionsv1beta1.ClusterScoped
+	return crd
+}
+
+// withValidation bounds the cpu_milli and memory_mb of the resource's spec
+func withValidation(crd *apiextensionsv1beta1.CustomResourceDefinition, limits SizeLimits) *apiextensionsv1beta1.CustomResourceDefinition {
+	minCpuMilli := float64(limits.MinCpuMillis)
+	maxCpuMilli := float64(limits.MaxCpuMillis)
+	minMemoryMB := float64(limits.MinMemoryMB)
+	maxMemoryMB := float64(limits.MaxMemoryMB)
+
+	crd.Spec.Validation = &apiextensionsv1beta1.CustomResourceValidation{
+		OpenAPIV3Schema: &apiextensionsv1beta1.JSONSchemaProps{
+			Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+				"spec": apiextensionsv1beta1.JSONSchemaProps{
 					Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
-						"spec": apiextensionsv1beta1.JSONSchemaProps{
-							Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
-								"cpu_milli": apiextensionsv1beta1.JSONSchemaProps{
-									Type:    "integer",
-									Minimum: &minCpuMilli,
-									Maximum: &maxCpuMilli,
-								},
-								"memory_mb": apiextensionsv1beta1.JSONSchemaProps{
-									Type:    "integer",
-									Minimum: &minMemoryMB,
-									Maximum: &maxMemoryMB,
-								},
-							},
+						"cpu_milli": apiextensionsv1beta1.JSONSchemaProps{
+							Type:    "integer",
+							Minimum: &minCpuMilli,
+							Maximum: &maxCpuMilli,
+						},
+						"memory_mb": apiextensionsv1beta1.JSONSchemaProps{
+							Type:    "integer",
+							Minimum: &minMemoryMB,
+							Maximum: &maxMemoryMB,
 						},
 					},
 				},
 			},
 		},
 	}
+	return crd
+}
+
+// withInstanceTypeValidation also requires instance types to size their
+// VMs and restricts them to supported hugepage sizes
+func withInstanceTypeValidation(crd *apiextensionsv1beta1.CustomResourceDefinition, limits SizeLimits) *apiextensionsv1beta1.CustomResourceDefinition {
+	crd = withValidation(crd, limits)
+	schema := crd.Spec.Validation.OpenAPIV3Schema
+	schema.Required = []string{"spec"}
+
+	spec := schema.Properties["spec"]
+	spec.Required = []string{"cpu_milli", "memory_mb"}
+	// The sizes the launcher can back guest memory with
+	spec.Properties["hugepages"] = apiextensionsv1beta1.JSONSchemaProps{
+		Type: "string",
+		Enum: []apiextensionsv1beta1.JSON{
+			{Raw: []byte(`"2Mi"`)},
+			{Raw: []byte(`"1Gi"`)},
+		},
+	}
+	spec.Properties["dedicated_cpu_placement"] = apiextensionsv1beta1.JSONSchemaProps{
+		Type: "boolean",
+	}
+	schema.Properties["spec"] = spec
+	return crd
+}
+
+// updateValidation replaces the validation schema of an existing CRD
+func updateValidation(clientset apiextensionsclient.Interface, crd *apiextensionsv1beta1.CustomResourceDefinition) error {
+	existing, err := clientset.ApiextensionsV1beta1().CustomResourceDefinitions().Get(crd.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if apiequality.Semantic.DeepEqual(existing.Spec.Validation, crd.Spec.Validation) {
+		return nil
+	}
+	existing.Spec.Validation = crd.Spec.Validation
+	_, err = clientset.ApiextensionsV1beta1().CustomResourceDefinitions().Update(existing)
+	return err
+}
 
+func createCustomResourceDefinition(clientset apiextensionsclient.Interface, crd *apiextensionsv1beta1.CustomResourceDefinition) error {
 	if _, err := clientset.ApiextensionsV1beta1().CustomResourceDefinitions().Create(crd); err != nil {
 		return err
 	}
@@ -66,7 +203,7 @@ func CreateCustomResourceDefinition(clientset apiextensionsclient.Interface) err
 	// Wait for CRD to be established
 	if err := wait.Poll(500*time.Millisecond, 60*time.Second, func() (bool, error) {
 		crd, err := clientset.ApiextensionsV1beta1().CustomResourceDefinitions().
-			Get("virtualmachines."+GroupName, metav1.GetOptions{})
+			Get(crd.Name, metav1.GetOptions{})
 		if err != nil {
 			return false, err
 		}
@@ -87,7 +224,7 @@ func CreateCustomResourceDefinition(clientset apiextensionsclient.Interface) err
 		return false, err
 	}); err != nil {
 		if deleteErr := clientset.ApiextensionsV1beta1().CustomResourceDefinitions().
-			Delete("virtualmachines."+GroupName, nil); deleteErr != nil {
+			Delete(crd.Name, nil); deleteErr != nil {
 			return errors.NewAggregate([]error{err, deleteErr})
 		}
 		return err
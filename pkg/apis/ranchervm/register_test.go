@@ -0,0 +1,45 @@
+package ranchervm
+
+import "testing"
+
+func TestSizeLimitsCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  SizeLimits
+		wantErr bool
+	}{
+		{"defaults", DefaultSizeLimits, false},
+		{"single size", SizeLimits{1000, 1000, 1024, 1024}, false},
+		{"zero cpu", SizeLimits{0, 8000, 512, 65536}, true},
+		{"negative memory", SizeLimits{500, 8000, -1, 65536}, true},
+		{"cpu min above max", SizeLimits{4000, 2000, 512, 65536}, true},
+		{"memory min above max", SizeLimits{500, 8000, 4096, 2048}, true},
+	}
+	for _, test := range tests {
+		if err := test.limits.Check(); (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error %v", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestSizeLimitsValidate(t *testing.T) {
+	limits := SizeLimits{MinCpuMillis: 500, MaxCpuMillis: 4000, MinMemoryMB: 512, MaxMemoryMB: 8192}
+	tests := []struct {
+		cpuMillis, memoryMB int32
+		wantErr             bool
+	}{
+		{500, 512, false},
+		{4000, 8192, false},
+		{2000, 1024, false},
+		{499, 1024, true},
+		{4001, 1024, true},
+		{2000, 511, true},
+		{2000, 8193, true},
+		{0, 0, true},
+	}
+	for _, test := range tests {
+		if err := limits.Validate(test.cpuMillis, test.memoryMB); (err != nil) != test.wantErr {
+			t.Errorf("%d/%d: got error %v, want error %v", test.cpuMillis, test.memoryMB, err, test.wantErr)
+		}
+	}
+}
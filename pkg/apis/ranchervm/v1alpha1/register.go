@@ -31,6 +31,34 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&VirtualMachine{},
 		&VirtualMachineList{},
+		&VirtualMachineSnapshot{},
+		&VirtualMachineSnapshotList{},
+		&VirtualMachineRestore{},
+		&VirtualMachineRestoreList{},
+		&VirtualMachineClone{},
+		&VirtualMachineCloneList{},
+		&VirtualMachineMigration{},
+		&VirtualMachineMigrationList{},
+		&VirtualMachineInstanceType{},
+		&VirtualMachineInstanceTypeList{},
+		&VirtualMachinePreference{},
+		&VirtualMachinePreferenceList{},
+		&VirtualMachineQuota{},
+		&VirtualMachineQuotaList{},
+		&VirtualMachineVolumeAttachment{},
+		&VirtualMachineVolumeAttachmentList{},
+		&VirtualMachineBackupTarget{},
+		&VirtualMachineBackupTargetList{},
+		&VirtualMachineBackup{},
+		&VirtualMachineBackupList{},
+		&VirtualMachineBackupRestore{},
+		&VirtualMachineBackupRestoreList{},
+		&VirtualMachineExport{},
+		&VirtualMachineExportList{},
+		&VirtualMachineImport{},
+		&VirtualMachineImportList{},
+		&VirtualMachineImageUpload{},
+		&VirtualMachineImageUploadList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -1,7 +1,9 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // +genclient
@@ -19,13 +21,288 @@ type VirtualMachine struct {
 
 // VirtualMachineSpec is the spec for a VirtualMachine resource
 type VirtualMachineSpec struct {
-	CpuMillis int32 `json:"cpu_milli"`
-	MemoryMB  int32 `json:"memory_mb"`
+	// CpuMillis and MemoryMB size the VM unless InstanceType is set
+	CpuMillis int32 `json:"cpu_milli,omitempty"`
+	MemoryMB  int32 `json:"memory_mb,omitempty"`
+	// MaxCpuMillis and MaxMemoryMB bound how far CPU and memory can be
+	// raised while the VM runs. CPUs are hotplugged whole, and memory in
+	// multiples of 128MB. Both default to no hotplug.
+	MaxCpuMillis int32 `json:"max_cpu_milli,omitempty"`
+	MaxMemoryMB  int32 `json:"max_memory_mb,omitempty"`
+	// MemoryRequestMB is the memory requested for the VM's pod, on top of
+	// QEMU's own. Defaults to MemoryMB divided by the cluster's overcommit
+	// ratio. The guests of overcommitted VMs are ballooned down to what they
+	// use, and no lower than their request.
+	MemoryRequestMB int32 `json:"memory_request_mb,omitempty"`
+	// InstanceType names a VirtualMachineInstanceType sizing the VM
+	InstanceType string `json:"instance_type,omitempty"`
+	// Preference names a VirtualMachinePreference in the VM's namespace
+	// supplying defaults for fields the VM leaves unset
+	Preference string             `json:"preference,omitempty"`
+	Interfaces []NetworkInterface `json:"interfaces,omitempty"`
+	Disks      []Disk             `json:"disks,omitempty"`
+	// Hostname is given to the guest through DHCP and cloud-init. Defaults
+	// to the VM's name.
+	Hostname  string     `json:"hostname,omitempty"`
+	CloudInit *CloudInit `json:"cloud_init,omitempty"`
+	// Stopped VMs have no pod
+	Stopped bool `json:"stopped,omitempty"`
+	// Ports are exposed through a Service owned by the VM
+	Ports       []VirtualMachinePort `json:"ports,omitempty"`
+	ServiceType corev1.ServiceType   `json:"service_type,omitempty"`
+	// EvictionStrategy decides what happens to the VM when its node is
+	// cordoned or drained. Defaults to shutdown.
+	EvictionStrategy EvictionStrategy `json:"eviction_strategy,omitempty"`
+	// TerminationGracePeriodSeconds is how long the guest is given to shut
+	// down once powered off through ACPI before it is killed. Defaults to
+	// 120 seconds.
+	TerminationGracePeriodSeconds *int64 `json:"termination_grace_period_seconds,omitempty"`
+
+	// Scheduling constraints are carried into the VM's pod. The VM's labels
+	// are set on its pod too, so pod affinity terms select VMs by label.
+	NodeSelector      map[string]string   `json:"node_selector,omitempty"`
+	Affinity          *corev1.Affinity    `json:"affinity,omitempty"`
+	Tolerations       []corev1.Toleration `json:"tolerations,omitempty"`
+	PriorityClassName string              `json:"priority_class_name,omitempty"`
+
+	// AllowEmulation lets the VM run on nodes without /dev/kvm, falling
+	// back to much slower software emulation
+	AllowEmulation bool `json:"allow_emulation,omitempty"`
+
+	// DedicatedCPUPlacement gives every vCPU a host CPU of its own. The VM's
+	// pod gets the Guaranteed QoS class, so that a kubelet running the static
+	// CPU manager policy hands it exclusive CPUs, which the vCPUs are pinned
+	// to. cpu_milli must be a whole number of CPUs.
+	DedicatedCPUPlacement bool `json:"dedicated_cpu_placement,omitempty"`
+	// Hugepages is the size of the hugepages backing guest memory, 2Mi or
+	// 1Gi. Guest memory is not backed by hugepages if unset.
+	Hugepages string `json:"hugepages,omitempty"`
+	// NUMAPassthrough gives the guest the NUMA topology of its dedicated
+	// CPUs, binding the memory of every guest node to its host node.
+	// Requires dedicated_cpu_placement.
+	NUMAPassthrough bool `json:"numa_passthrough,omitempty"`
 }
 
+type EvictionStrategy string
+
+const (
+	// EvictionStrategyLiveMigrate migrates the VM off the node. Drains are
+	// held off by a PodDisruptionBudget until the migration is done.
+	EvictionStrategyLiveMigrate EvictionStrategy = "live_migrate"
+	// EvictionStrategyShutdown shuts the guest down and starts it on
+	// another node
+	EvictionStrategyShutdown EvictionStrategy = "shutdown"
+	// EvictionStrategyBlock keeps the VM running, blocking drains with a
+	// PodDisruptionBudget until it is stopped or moved by hand
+	EvictionStrategyBlock EvictionStrategy = "block"
+)
+
+type InterfaceBinding string
+
+const (
+	// InterfaceBindingMasquerade NATs guest traffic behind the pod IP
+	InterfaceBindingMasquerade InterfaceBinding = "masquerade"
+	// InterfaceBindingBridge hands the pod interface's address to the guest
+	InterfaceBindingBridge InterfaceBinding = "bridge"
+)
+
+// NetworkInterface is a guest network interface
+type NetworkInterface struct {
+	Name    string           `json:"name"`
+	Binding InterfaceBinding `json:"binding"`
+	// Network names a Multus NetworkAttachmentDefinition, optionally
+	// prefixed with its namespace. Empty means the pod network.
+	Network string `json:"network,omitempty"`
+	// MACAddress pins the guest MAC. If empty, the controller assigns one
+	// and persists it in status.
+	MACAddress string `json:"mac_address,omitempty"`
+}
+
+// Disk is a guest disk backed by a PersistentVolumeClaim. The claim's
+// filesystem holds the image as disk.img; an empty claim is given a sparse
+// raw image filling it.
+type Disk struct {
+	Name      string `json:"name"`
+	ClaimName string `json:"claim_name"`
+	// SizeMB is the least storage the claim requests. Raising it expands
+	// the claim and grows the disk of the running guest.
+	SizeMB int64 `json:"size_mb,omitempty"`
+}
+
+// CloudInit is passed to the guest on a NoCloud seed disk
+type CloudInit struct {
+	// InstanceID identifies the machine to cloud-init, which reruns its
+	// per-instance modules when it changes. Defaults to the VM's UID.
+	InstanceID  string `json:"instance_id,omitempty"`
+	UserData    string `json:"user_data,omitempty"`
+	NetworkData string `json:"network_data,omitempty"`
+}
+
+// VirtualMachinePort is a guest port exposed by the VM's Service
+type VirtualMachinePort struct {
+	Name     string          `json:"name"`
+	Port     int32           `json:"port"`
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+	// NodePort is only honoured for NodePort and LoadBalancer services
+	NodePort int32 `json:"node_port,omitempty"`
+}
+
+type VirtualMachinePhase string
+
+const (
+	VirtualMachinePending VirtualMachinePhase = "Pending"
+	VirtualMachineRunning VirtualMachinePhase = "Running"
+	VirtualMachineStopped VirtualMachinePhase = "Stopped"
+	VirtualMachineFailed  VirtualMachinePhase = "Failed"
+)
+
 // VirtualMachineStatus is the status for a VirtualMachine resource
 type VirtualMachineStatus struct {
-	Running bool `json:"running"`
+	Phase    VirtualMachinePhase `json:"phase,omitempty"`
+	Running  bool                `json:"running"`
+	NodeName string              `json:"node_name,omitempty"`
+	// PodName is the pod running the VM, which changes when it migrates
+	PodName    string                    `json:"pod_name,omitempty"`
+	Interfaces []NetworkInterfaceStatus  `json:"interfaces,omitempty"`
+	Conditions []VirtualMachineCondition `json:"conditions,omitempty"`
+	// GuestInfo is reported by the guest agent, if the guest runs one
+	GuestInfo *GuestInfo `json:"guest_info,omitempty"`
+	// Memory is reported by the guest's balloon driver
+	Memory *MemoryStatus `json:"memory,omitempty"`
+	// HotpluggedDisks are attached to the running VM by
+	// VirtualMachineVolumeAttachments
+	HotpluggedDisks []HotpluggedDiskStatus `json:"hotplugged_disks,omitempty"`
+	// Disks are the disks with a size_mb of the running VM
+	Disks []DiskStatus `json:"disks,omitempty"`
+	// InstanceType and Preference are copies of those the VM was last
+	// started with, so that editing them doesn't resize running VMs
+	InstanceType *ResolvedInstanceType `json:"instance_type,omitempty"`
+	Preference   *ResolvedPreference   `json:"preference,omitempty"`
+}
+
+// ResolvedInstanceType is a copy of a VirtualMachineInstanceType's spec
+type ResolvedInstanceType struct {
+	Name string                         `json:"name"`
+	Spec VirtualMachineInstanceTypeSpec `json:"spec"`
+}
+
+// ResolvedPreference is a copy of a VirtualMachinePreference's spec
+type ResolvedPreference struct {
+	Name string                       `json:"name"`
+	Spec VirtualMachinePreferenceSpec `json:"spec"`
+}
+
+// DiskStatus is the size of a disk of a running VM
+type DiskStatus struct {
+	Name string `json:"name"`
+	// CapacityMB is the capacity of the disk's claim the guest's disk was
+	// last grown to fill
+	CapacityMB int64 `json:"capacity_mb"`
+}
+
+// HotpluggedDiskStatus is a disk attached to a running VM
+type HotpluggedDiskStatus struct {
+	Name       string `json:"name"`
+	ClaimName  string `json:"claim_name"`
+	Attachment string `json:"attachment"`
+	// PodName is the pod the disk is attached to. Disks are attached again
+	// once the VM restarts in a new pod.
+	PodName string `json:"pod_name"`
+	// Server is the address of the pod serving the disk
+	Server string `json:"server"`
+	// ServerUID identifies the pod serving the disk, whose address may be
+	// reused by the pod replacing it
+	ServerUID types.UID `json:"server_uid,omitempty"`
+}
+
+// MemoryStatus is the memory of a guest
+type MemoryStatus struct {
+	// ActualMB is the memory left to the guest by the balloon
+	ActualMB int64 `json:"actual_mb"`
+	// UsedMB is the memory the guest can't give up, 0 if the guest doesn't
+	// report it
+	UsedMB int64 `json:"used_mb,omitempty"`
+	// TargetMB is the size the controller last ballooned the guest to
+	TargetMB int64 `json:"target_mb,omitempty"`
+}
+
+// GuestInfo is the state of the guest as reported by the QEMU guest agent
+type GuestInfo struct {
+	OSName        string            `json:"os_name,omitempty"`
+	OSVersion     string            `json:"os_version,omitempty"`
+	KernelRelease string            `json:"kernel_release,omitempty"`
+	Hostname      string            `json:"hostname,omitempty"`
+	Interfaces    []GuestInterface  `json:"interfaces,omitempty"`
+	Users         []GuestUser       `json:"users,omitempty"`
+	Filesystems   []GuestFilesystem `json:"filesystems,omitempty"`
+}
+
+type GuestInterface struct {
+	Name       string   `json:"name"`
+	MACAddress string   `json:"mac_address,omitempty"`
+	IPs        []string `json:"ips,omitempty"`
+}
+
+type GuestUser struct {
+	Name      string      `json:"name"`
+	Domain    string      `json:"domain,omitempty"`
+	LoginTime metav1.Time `json:"login_time,omitempty"`
+}
+
+type GuestFilesystem struct {
+	Name       string `json:"name"`
+	MountPoint string `json:"mount_point"`
+	Type       string `json:"type,omitempty"`
+	TotalBytes int64  `json:"total_bytes,omitempty"`
+	UsedBytes  int64  `json:"used_bytes,omitempty"`
+}
+
+type VirtualMachineConditionType string
+
+const (
+	// VirtualMachineCleanShutdown is true if the guest powered off by
+	// itself the last time its pod terminated, and false if it was killed
+	VirtualMachineCleanShutdown VirtualMachineConditionType = "CleanShutdown"
+	// VirtualMachineScheduled mirrors the scheduling state of the VM's pod,
+	// including why it can't be scheduled
+	VirtualMachineScheduled VirtualMachineConditionType = "Scheduled"
+	// VirtualMachineHardwareVirtualization is false if no node can run the
+	// VM with KVM. VMs allowing emulation don't have it.
+	VirtualMachineHardwareVirtualization VirtualMachineConditionType = "HardwareVirtualization"
+	// VirtualMachineRestartRequired is true if the running VM differs from
+	// its spec in ways only a restart resolves
+	VirtualMachineRestartRequired VirtualMachineConditionType = "RestartRequired"
+	// VirtualMachineDiskResizing is true while disks grow to their
+	// size_mb, and false if growing them failed
+	VirtualMachineDiskResizing VirtualMachineConditionType = "DiskResizing"
+	// VirtualMachineInvalidSpec is true if the controller can't act on the
+	// spec until it's corrected
+	VirtualMachineInvalidSpec VirtualMachineConditionType = "InvalidSpec"
+	// VirtualMachineEvictionBlocked is true while the VM keeps its cordoned
+	// node from being drained
+	VirtualMachineEvictionBlocked VirtualMachineConditionType = "EvictionBlocked"
+	// VirtualMachineExceededQuota is true while the quotas of its namespace
+	// hold the VM back from starting
+	VirtualMachineExceededQuota VirtualMachineConditionType = "ExceededQuota"
+	// VirtualMachineExporting is true while an export holding the VM's
+	// disks keeps it from starting
+	VirtualMachineExporting VirtualMachineConditionType = "Exporting"
+)
+
+// VirtualMachineCondition describes an aspect of the state of a VM
+type VirtualMachineCondition struct {
+	Type               VirtualMachineConditionType `json:"type"`
+	Status             corev1.ConditionStatus      `json:"status"`
+	LastTransitionTime metav1.Time                 `json:"last_transition_time,omitempty"`
+	Reason             string                      `json:"reason,omitempty"`
+	Message            string                      `json:"message,omitempty"`
+}
+
+// NetworkInterfaceStatus is the observed state of a guest network interface
+type NetworkInterfaceStatus struct {
+	Name       string `json:"name"`
+	MACAddress string `json:"mac_address"`
+	IP         string `json:"ip,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -37,3 +314,797 @@ type VirtualMachineList struct {
 
 	Items []VirtualMachine `json:"items"`
 }
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineSnapshot is a point-in-time copy of a VirtualMachine's spec
+// and disks
+type VirtualMachineSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualMachineSnapshotSpec   `json:"spec"`
+	Status VirtualMachineSnapshotStatus `json:"status"`
+}
+
+type SnapshotMethod string
+
+const (
+	// SnapshotMethodVolumeSnapshot snapshots disks through the storage
+	// provider with VolumeSnapshots
+	SnapshotMethodVolumeSnapshot SnapshotMethod = "volume_snapshot"
+	// SnapshotMethodCopy copies disks into new claims with a Job
+	SnapshotMethodCopy SnapshotMethod = "copy"
+)
+
+// VirtualMachineSnapshotSpec is the spec for a VirtualMachineSnapshot resource
+type VirtualMachineSnapshotSpec struct {
+	// VirtualMachineName names the VM to snapshot in the snapshot's namespace
+	VirtualMachineName string `json:"vm_name"`
+	// Method defaults to volume_snapshot if the cluster serves VolumeSnapshots
+	// and copy otherwise
+	Method SnapshotMethod `json:"method,omitempty"`
+}
+
+type SnapshotPhase string
+
+const (
+	SnapshotPending    SnapshotPhase = "Pending"
+	SnapshotInProgress SnapshotPhase = "InProgress"
+	SnapshotReady      SnapshotPhase = "Ready"
+	SnapshotFailed     SnapshotPhase = "Failed"
+)
+
+// VirtualMachineSnapshotStatus is the status for a VirtualMachineSnapshot
+// resource
+type VirtualMachineSnapshotStatus struct {
+	Phase  SnapshotPhase  `json:"phase,omitempty"`
+	Ready  bool           `json:"ready"`
+	Method SnapshotMethod `json:"method,omitempty"`
+	// Frozen is set while the guest is paused for the snapshot
+	Frozen bool `json:"frozen,omitempty"`
+	// StoppedVM is set while the VM is stopped for a copy snapshot, so that
+	// it is started again once its disks are copied
+	StoppedVM bool `json:"stopped_vm,omitempty"`
+	// VirtualMachineSpec is the VM's spec when the snapshot was taken
+	VirtualMachineSpec *VirtualMachineSpec  `json:"vm_spec,omitempty"`
+	Disks              []DiskSnapshotStatus `json:"disks,omitempty"`
+	CreationTime       *metav1.Time         `json:"creation_time,omitempty"`
+	Message            string               `json:"message,omitempty"`
+}
+
+// DiskSnapshotStatus is the state of the snapshot of a single disk
+type DiskSnapshotStatus struct {
+	Name string `json:"name"`
+	// SourceClaimName is the claim the disk was snapshotted from
+	SourceClaimName string `json:"source_claim_name"`
+	// ClaimSpec is the spec of the source claim, used to size restored disks
+	ClaimSpec corev1.PersistentVolumeClaimSpec `json:"claim_spec"`
+	// VolumeSnapshotName is set for volume_snapshot snapshots
+	VolumeSnapshotName string `json:"volume_snapshot_name,omitempty"`
+	// ClaimName is set for copy snapshots
+	ClaimName string `json:"claim_name,omitempty"`
+	Ready     bool   `json:"ready"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineSnapshotList is a list of VirtualMachineSnapshot resources
+type VirtualMachineSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineSnapshot `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineRestore rolls a VirtualMachine back to a snapshot. The VM is
+// stopped and its disks are replaced by new claims restored from the
+// snapshot; the original claims are left in place.
+type VirtualMachineRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualMachineRestoreSpec   `json:"spec"`
+	Status VirtualMachineRestoreStatus `json:"status"`
+}
+
+// VirtualMachineRestoreSpec is the spec for a VirtualMachineRestore resource
+type VirtualMachineRestoreSpec struct {
+	VirtualMachineName string `json:"vm_name"`
+	SnapshotName       string `json:"snapshot_name"`
+}
+
+type RestorePhase string
+
+const (
+	RestorePending    RestorePhase = "Pending"
+	RestoreInProgress RestorePhase = "InProgress"
+	RestoreComplete   RestorePhase = "Complete"
+	RestoreFailed     RestorePhase = "Failed"
+)
+
+// VirtualMachineRestoreStatus is the status for a VirtualMachineRestore
+// resource
+type VirtualMachineRestoreStatus struct {
+	Phase          RestorePhase        `json:"phase,omitempty"`
+	Complete       bool                `json:"complete"`
+	Disks          []DiskRestoreStatus `json:"disks,omitempty"`
+	CompletionTime *metav1.Time        `json:"completion_time,omitempty"`
+	Message        string              `json:"message,omitempty"`
+}
+
+// DiskRestoreStatus is the state of a single restored disk
+type DiskRestoreStatus struct {
+	Name      string `json:"name"`
+	ClaimName string `json:"claim_name"`
+	Ready     bool   `json:"ready"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineRestoreList is a list of VirtualMachineRestore resources
+type VirtualMachineRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineRestore `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineClone creates a VirtualMachine from an existing VM or
+// snapshot. The clone's disks are copied into new claims and its MAC
+// addresses, hostname and cloud-init instance ID are regenerated so that it
+// boots as a distinct machine.
+type VirtualMachineClone struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualMachineCloneSpec   `json:"spec"`
+	Status VirtualMachineCloneStatus `json:"status"`
+}
+
+// VirtualMachineCloneSpec is the spec for a VirtualMachineClone resource
+type VirtualMachineCloneSpec struct {
+	Source CloneSource `json:"source"`
+	// Target names the VM to create in the clone's namespace
+	Target string `json:"target"`
+}
+
+// CloneSource names either a VM or a snapshot to clone. VMs are snapshotted
+// first, so running VMs are briefly frozen.
+type CloneSource struct {
+	VirtualMachineName string `json:"vm_name,omitempty"`
+	SnapshotName       string `json:"snapshot_name,omitempty"`
+}
+
+type ClonePhase string
+
+const (
+	ClonePending    ClonePhase = "Pending"
+	CloneInProgress ClonePhase = "InProgress"
+	CloneComplete   ClonePhase = "Complete"
+	CloneFailed     ClonePhase = "Failed"
+)
+
+// VirtualMachineCloneStatus is the status for a VirtualMachineClone resource
+type VirtualMachineCloneStatus struct {
+	Phase    ClonePhase `json:"phase,omitempty"`
+	Complete bool       `json:"complete"`
+	// SnapshotName is the snapshot the clone is made from
+	SnapshotName   string              `json:"snapshot_name,omitempty"`
+	Disks          []DiskRestoreStatus `json:"disks,omitempty"`
+	CompletionTime *metav1.Time        `json:"completion_time,omitempty"`
+	Message        string              `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineCloneList is a list of VirtualMachineClone resources
+type VirtualMachineCloneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineClone `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineMigration live migrates a running VirtualMachine to another
+// node. A second launcher pod is started on the target node and QEMU streams
+// the guest's memory to it; the VM's disks must be ReadWriteMany so that
+// both pods can mount them during the handoff.
+type VirtualMachineMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualMachineMigrationSpec   `json:"spec"`
+	Status VirtualMachineMigrationStatus `json:"status"`
+}
+
+// VirtualMachineMigrationSpec is the spec for a VirtualMachineMigration
+// resource
+type VirtualMachineMigrationSpec struct {
+	VirtualMachineName string `json:"vm_name"`
+	// NodeName optionally picks the target node, otherwise the scheduler
+	// picks any node other than the current one
+	NodeName string `json:"node_name,omitempty"`
+}
+
+type MigrationPhase string
+
+const (
+	MigrationPending    MigrationPhase = "Pending"
+	MigrationScheduling MigrationPhase = "Scheduling"
+	MigrationMigrating  MigrationPhase = "Migrating"
+	MigrationSucceeded  MigrationPhase = "Succeeded"
+	MigrationFailed     MigrationPhase = "Failed"
+)
+
+// VirtualMachineMigrationStatus is the status for a VirtualMachineMigration
+// resource
+type VirtualMachineMigrationStatus struct {
+	Phase      MigrationPhase `json:"phase,omitempty"`
+	SourcePod  string         `json:"source_pod,omitempty"`
+	SourceNode string         `json:"source_node,omitempty"`
+	TargetPod  string         `json:"target_pod,omitempty"`
+	TargetNode string         `json:"target_node,omitempty"`
+	// Progress is the percentage of guest memory transferred
+	Progress         int32        `json:"progress"`
+	TransferredBytes int64        `json:"transferred_bytes,omitempty"`
+	RemainingBytes   int64        `json:"remaining_bytes,omitempty"`
+	TotalBytes       int64        `json:"total_bytes,omitempty"`
+	BandwidthMbps    int64        `json:"bandwidth_mbps,omitempty"`
+	StartTime        *metav1.Time `json:"start_time,omitempty"`
+	CompletionTime   *metav1.Time `json:"completion_time,omitempty"`
+	Message          string       `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineMigrationList is a list of VirtualMachineMigration resources
+type VirtualMachineMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineMigration `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineInstanceType is a reusable VM size. It is cluster scoped,
+// so that administrators can offer the same sizes in every namespace.
+type VirtualMachineInstanceType struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VirtualMachineInstanceTypeSpec `json:"spec"`
+}
+
+// VirtualMachineInstanceTypeSpec is the spec for a
+// VirtualMachineInstanceType resource
+type VirtualMachineInstanceTypeSpec struct {
+	CpuMillis int32 `json:"cpu_milli"`
+	MemoryMB  int32 `json:"memory_mb"`
+	// Hugepages and DedicatedCPUPlacement apply as they do on VMs
+	Hugepages             string `json:"hugepages,omitempty"`
+	DedicatedCPUPlacement bool   `json:"dedicated_cpu_placement,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineInstanceTypeList is a list of VirtualMachineInstanceType
+// resources
+type VirtualMachineInstanceTypeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineInstanceType `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachinePreference holds defaults for the VMs of a namespace that
+// refer to it. Fields set on a VM take precedence.
+type VirtualMachinePreference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VirtualMachinePreferenceSpec `json:"spec"`
+}
+
+// VirtualMachinePreferenceSpec is the spec for a VirtualMachinePreference
+// resource
+type VirtualMachinePreferenceSpec struct {
+	TerminationGracePeriodSeconds *int64              `json:"termination_grace_period_seconds,omitempty"`
+	NodeSelector                  map[string]string   `json:"node_selector,omitempty"`
+	Affinity                      *corev1.Affinity    `json:"affinity,omitempty"`
+	Tolerations                   []corev1.Toleration `json:"tolerations,omitempty"`
+	PriorityClassName             string              `json:"priority_class_name,omitempty"`
+	AllowEmulation                bool                `json:"allow_emulation,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachinePreferenceList is a list of VirtualMachinePreference
+// resources
+type VirtualMachinePreferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachinePreference `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineQuota limits the VMs of its namespace. Every quota of a
+// namespace must be satisfied for a VM to start.
+type VirtualMachineQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualMachineQuotaSpec   `json:"spec"`
+	Status VirtualMachineQuotaStatus `json:"status"`
+}
+
+// VirtualMachineQuotaSpec is the spec for a VirtualMachineQuota resource.
+// Limits left unset are unlimited.
+type VirtualMachineQuotaSpec struct {
+	// VirtualMachines limits the number of VMs, stopped or not
+	VirtualMachines *int32 `json:"virtual_machines,omitempty"`
+	// CpuMillis and MemoryMB limit the total size of VMs that aren't
+	// stopped
+	CpuMillis *int64 `json:"cpu_milli,omitempty"`
+	MemoryMB  *int64 `json:"memory_mb,omitempty"`
+	// DiskMB limits the total storage requested by the claims of VM disks
+	DiskMB *int64 `json:"disk_mb,omitempty"`
+}
+
+// VirtualMachineQuotaStatus is the status for a VirtualMachineQuota
+// resource
+type VirtualMachineQuotaStatus struct {
+	Used VirtualMachineQuotaUsage `json:"used"`
+	// OverQuota lists the VMs held back from starting
+	OverQuota []string `json:"over_quota,omitempty"`
+}
+
+// VirtualMachineQuotaUsage is what the VMs of a namespace count against
+// quotas
+type VirtualMachineQuotaUsage struct {
+	VirtualMachines int32 `json:"virtual_machines"`
+	CpuMillis       int64 `json:"cpu_milli"`
+	MemoryMB        int64 `json:"memory_mb"`
+	DiskMB          int64 `json:"disk_mb"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineQuotaList is a list of VirtualMachineQuota resources
+type VirtualMachineQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineQuota `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineVolumeAttachment attaches a disk to a running VM. Deleting
+// it detaches the disk.
+type VirtualMachineVolumeAttachment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualMachineVolumeAttachmentSpec   `json:"spec"`
+	Status VirtualMachineVolumeAttachmentStatus `json:"status"`
+}
+
+// VirtualMachineVolumeAttachmentSpec is the spec for a
+// VirtualMachineVolumeAttachment resource
+type VirtualMachineVolumeAttachmentSpec struct {
+	// VirtualMachineName names the VM in the attachment's namespace
+	VirtualMachineName string `json:"vm_name"`
+	// Disk is attached like the VM's own disks. Its name must not be used
+	// by any of them.
+	Disk Disk `json:"disk"`
+}
+
+type AttachmentPhase string
+
+const (
+	AttachmentPending  AttachmentPhase = "Pending"
+	AttachmentAttached AttachmentPhase = "Attached"
+	AttachmentFailed   AttachmentPhase = "Failed"
+)
+
+// VirtualMachineVolumeAttachmentStatus is the status for a
+// VirtualMachineVolumeAttachment resource
+type VirtualMachineVolumeAttachmentStatus struct {
+	Phase AttachmentPhase `json:"phase,omitempty"`
+	// ServerPod serves the disk to the VM from the VM's node
+	ServerPod string `json:"server_pod,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineVolumeAttachmentList is a list of
+// VirtualMachineVolumeAttachment resources
+type VirtualMachineVolumeAttachmentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineVolumeAttachment `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineBackupTarget is an S3-compatible bucket VMs are backed up to
+type VirtualMachineBackupTarget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VirtualMachineBackupTargetSpec `json:"spec"`
+}
+
+// VirtualMachineBackupTargetSpec is the spec for a VirtualMachineBackupTarget
+// resource
+type VirtualMachineBackupTargetSpec struct {
+	// Endpoint is the URL of the object store, such as https://s3.amazonaws.com
+	Endpoint string `json:"endpoint"`
+	Bucket   string `json:"bucket"`
+	// Region defaults to us-east-1
+	Region string `json:"region,omitempty"`
+	// Prefix is prepended to the keys of all objects
+	Prefix string `json:"prefix,omitempty"`
+	// CredentialsSecret names a secret in the target's namespace with an
+	// access_key_id and a secret_access_key
+	CredentialsSecret string `json:"credentials_secret"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineBackupTargetList is a list of VirtualMachineBackupTarget
+// resources
+type VirtualMachineBackupTargetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineBackupTarget `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineBackup uploads a VirtualMachine's spec and disks to a backup
+// target. The VM is snapshotted first, so running VMs are briefly frozen.
+type VirtualMachineBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualMachineBackupSpec   `json:"spec"`
+	Status VirtualMachineBackupStatus `json:"status"`
+}
+
+// VirtualMachineBackupSpec is the spec for a VirtualMachineBackup resource
+type VirtualMachineBackupSpec struct {
+	// VirtualMachineName names the VM to back up in the backup's namespace
+	VirtualMachineName string `json:"vm_name"`
+	// Target names the VirtualMachineBackupTarget in the backup's namespace
+	Target string `json:"target"`
+}
+
+type BackupPhase string
+
+const (
+	BackupPending    BackupPhase = "Pending"
+	BackupInProgress BackupPhase = "InProgress"
+	BackupComplete   BackupPhase = "Complete"
+	BackupFailed     BackupPhase = "Failed"
+)
+
+// VirtualMachineBackupStatus is the status for a VirtualMachineBackup
+// resource
+type VirtualMachineBackupStatus struct {
+	Phase BackupPhase `json:"phase,omitempty"`
+	// Location identifies the backup within its target for restores, as
+	// <namespace>/<name>
+	Location string `json:"location,omitempty"`
+	// SnapshotName is the snapshot the disks are uploaded from. It is
+	// deleted once the backup completes.
+	SnapshotName string `json:"snapshot_name,omitempty"`
+	// Parent is the location of the previous backup of the VM to the same
+	// target. Disks are read in full, but only chunks the target lacks are
+	// uploaded.
+	Parent         string             `json:"parent,omitempty"`
+	Disks          []DiskBackupStatus `json:"disks,omitempty"`
+	CompletionTime *metav1.Time       `json:"completion_time,omitempty"`
+	Message        string             `json:"message,omitempty"`
+}
+
+// DiskBackupStatus is the state of the backup of a single disk
+type DiskBackupStatus struct {
+	Name   string `json:"name"`
+	SizeMB int64  `json:"size_mb,omitempty"`
+	// Chunks is how many chunks the disk has, without those full of zeros
+	Chunks int `json:"chunks,omitempty"`
+	// UploadedChunks is how many of them were not in the store yet
+	UploadedChunks int  `json:"uploaded_chunks,omitempty"`
+	Ready          bool `json:"ready"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineBackupList is a list of VirtualMachineBackup resources
+type VirtualMachineBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineBackup `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineBackupRestore creates a VirtualMachine from a backup. It
+// reads the backup from the target alone, so that VMs can be restored into
+// any namespace or cluster with a target for the same bucket.
+type VirtualMachineBackupRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualMachineBackupRestoreSpec   `json:"spec"`
+	Status VirtualMachineBackupRestoreStatus `json:"status"`
+}
+
+// VirtualMachineBackupRestoreSpec is the spec for a
+// VirtualMachineBackupRestore resource
+type VirtualMachineBackupRestoreSpec struct {
+	// Target names the VirtualMachineBackupTarget in the restore's namespace
+	Target string `json:"target"`
+	// Backup is the location of the backup within the target
+	Backup string `json:"backup"`
+	// VirtualMachineName names the VM to create in the restore's namespace.
+	// Defaults to the name of the backed up VM.
+	VirtualMachineName string `json:"vm_name,omitempty"`
+	// StorageClassName overrides the storage class of the restored claims
+	StorageClassName *string `json:"storage_class_name,omitempty"`
+}
+
+// VirtualMachineBackupRestoreStatus is the status for a
+// VirtualMachineBackupRestore resource
+type VirtualMachineBackupRestoreStatus struct {
+	Phase              RestorePhase        `json:"phase,omitempty"`
+	VirtualMachineName string              `json:"vm_name,omitempty"`
+	Disks              []DiskRestoreStatus `json:"disks,omitempty"`
+	CompletionTime     *metav1.Time        `json:"completion_time,omitempty"`
+	Message            string              `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineBackupRestoreList is a list of VirtualMachineBackupRestore
+// resources
+type VirtualMachineBackupRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineBackupRestore `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineExport makes a stopped VirtualMachine downloadable as a
+// bundle of its spec and disks. The VM must stay stopped while the export
+// exists, as the export's pod holds its disks.
+type VirtualMachineExport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualMachineExportSpec   `json:"spec"`
+	Status VirtualMachineExportStatus `json:"status"`
+}
+
+type ExportFormat string
+
+const (
+	// ExportFormatQcow2 bundles the VM's spec as manifest.json with qcow2
+	// disks, for import into another cluster
+	ExportFormatQcow2 ExportFormat = "qcow2"
+	// ExportFormatOVA bundles an OVF descriptor with streamOptimized VMDK
+	// disks, for import into other hypervisors
+	ExportFormatOVA ExportFormat = "ova"
+)
+
+// VirtualMachineExportSpec is the spec for a VirtualMachineExport resource
+type VirtualMachineExportSpec struct {
+	// VirtualMachineName names the VM to export in the export's namespace
+	VirtualMachineName string `json:"vm_name"`
+	// Format defaults to qcow2
+	Format ExportFormat `json:"format,omitempty"`
+}
+
+type ExportPhase string
+
+const (
+	ExportPending    ExportPhase = "Pending"
+	ExportConverting ExportPhase = "Converting"
+	ExportReady      ExportPhase = "Ready"
+	ExportFailed     ExportPhase = "Failed"
+)
+
+// VirtualMachineExportStatus is the status for a VirtualMachineExport
+// resource
+type VirtualMachineExportStatus struct {
+	Phase ExportPhase `json:"phase,omitempty"`
+	// PodName is the pod converting and serving the bundle. The VM can't
+	// start once it is set, until the export fails or is deleted.
+	PodName string `json:"pod_name,omitempty"`
+	// Path is where the console server serves the bundle once it is ready
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineExportList is a list of VirtualMachineExport resources
+type VirtualMachineExportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineExport `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineImport creates a VirtualMachine from a bundle served over
+// HTTP, either exported by a VirtualMachineExport or an OVA
+type VirtualMachineImport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualMachineImportSpec   `json:"spec"`
+	Status VirtualMachineImportStatus `json:"status"`
+}
+
+// VirtualMachineImportSpec is the spec for a VirtualMachineImport resource
+type VirtualMachineImportSpec struct {
+	// URL is an http or https URL of the bundle
+	URL string `json:"url"`
+	// TokenSecret names a secret in the import's namespace whose token is
+	// sent as a bearer token, such as for the exports of another cluster
+	TokenSecret string `json:"token_secret,omitempty"`
+	// VirtualMachineName names the VM to create in the import's namespace.
+	// Defaults to the name in the bundle.
+	VirtualMachineName string `json:"vm_name,omitempty"`
+	// StorageClassName sets the storage class of the imported claims
+	StorageClassName *string `json:"storage_class_name,omitempty"`
+}
+
+type ImportPhase string
+
+const (
+	ImportPending    ImportPhase = "Pending"
+	ImportInProgress ImportPhase = "InProgress"
+	ImportComplete   ImportPhase = "Complete"
+	ImportFailed     ImportPhase = "Failed"
+)
+
+// VirtualMachineImportStatus is the status for a VirtualMachineImport
+// resource
+type VirtualMachineImportStatus struct {
+	Phase              ImportPhase `json:"phase,omitempty"`
+	VirtualMachineName string      `json:"vm_name,omitempty"`
+	// VirtualMachineSpec is read from the bundle's descriptor
+	VirtualMachineSpec *VirtualMachineSpec `json:"vm_spec,omitempty"`
+	Disks              []DiskImportStatus  `json:"disks,omitempty"`
+	CompletionTime     *metav1.Time        `json:"completion_time,omitempty"`
+	Message            string              `json:"message,omitempty"`
+}
+
+// DiskImportStatus is the state of the import of a single disk
+type DiskImportStatus struct {
+	Name string `json:"name"`
+	// File is the disk's file within the bundle
+	File          string `json:"file"`
+	CapacityBytes int64  `json:"capacity_bytes"`
+	ClaimName     string `json:"claim_name"`
+	Ready         bool   `json:"ready"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineImportList is a list of VirtualMachineImport resources
+type VirtualMachineImportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineImport `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineImageUpload receives a disk image uploaded in chunks through
+// the console server, and writes it to a new claim as a raw image
+type VirtualMachineImageUpload struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualMachineImageUploadSpec   `json:"spec"`
+	Status VirtualMachineImageUploadStatus `json:"status"`
+}
+
+// VirtualMachineImageUploadSpec is the spec for a VirtualMachineImageUpload
+// resource
+type VirtualMachineImageUploadSpec struct {
+	// ClaimName names the claim to create. Defaults to the upload's name.
+	ClaimName string `json:"claim_name,omitempty"`
+	// SizeMB is the storage the claim requests, which must hold the raw
+	// image. The uploaded image is staged on a claim of the same size.
+	SizeMB           int64   `json:"size_mb"`
+	StorageClassName *string `json:"storage_class_name,omitempty"`
+}
+
+type UploadPhase string
+
+const (
+	UploadPending  UploadPhase = "Pending"
+	UploadReady    UploadPhase = "Ready"
+	UploadComplete UploadPhase = "Complete"
+	UploadFailed   UploadPhase = "Failed"
+)
+
+// VirtualMachineImageUploadStatus is the status for a
+// VirtualMachineImageUpload resource
+type VirtualMachineImageUploadStatus struct {
+	Phase UploadPhase `json:"phase,omitempty"`
+	// PodName is the pod receiving and converting the image
+	PodName string `json:"pod_name,omitempty"`
+	// Path is where the console server accepts the image once ready
+	Path string `json:"path,omitempty"`
+	// Format is the detected format of the uploaded image
+	Format           string       `json:"format,omitempty"`
+	VirtualSizeBytes int64        `json:"virtual_size_bytes,omitempty"`
+	CompletionTime   *metav1.Time `json:"completion_time,omitempty"`
+	Message          string       `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualMachineImageUploadList is a list of VirtualMachineImageUpload
+// resources
+type VirtualMachineImageUploadList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualMachineImageUpload `json:"items"`
+}
@@ -21,6 +21,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	reflect "reflect"
@@ -31,14 +33,294 @@ import (
 // Deprecated: deepcopy registration will go away when static deepcopy is fully implemented.
 func GetGeneratedDeepCopyFuncs() []conversion.GeneratedDeepCopyFunc {
 	return []conversion.GeneratedDeepCopyFunc{
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*CloneSource).DeepCopyInto(out.(*CloneSource))
+			return nil
+		}, InType: reflect.TypeOf(&CloneSource{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*CloudInit).DeepCopyInto(out.(*CloudInit))
+			return nil
+		}, InType: reflect.TypeOf(&CloudInit{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*Disk).DeepCopyInto(out.(*Disk))
+			return nil
+		}, InType: reflect.TypeOf(&Disk{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*DiskBackupStatus).DeepCopyInto(out.(*DiskBackupStatus))
+			return nil
+		}, InType: reflect.TypeOf(&DiskBackupStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*DiskImportStatus).DeepCopyInto(out.(*DiskImportStatus))
+			return nil
+		}, InType: reflect.TypeOf(&DiskImportStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*DiskRestoreStatus).DeepCopyInto(out.(*DiskRestoreStatus))
+			return nil
+		}, InType: reflect.TypeOf(&DiskRestoreStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*DiskSnapshotStatus).DeepCopyInto(out.(*DiskSnapshotStatus))
+			return nil
+		}, InType: reflect.TypeOf(&DiskSnapshotStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*DiskStatus).DeepCopyInto(out.(*DiskStatus))
+			return nil
+		}, InType: reflect.TypeOf(&DiskStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*GuestFilesystem).DeepCopyInto(out.(*GuestFilesystem))
+			return nil
+		}, InType: reflect.TypeOf(&GuestFilesystem{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*GuestInfo).DeepCopyInto(out.(*GuestInfo))
+			return nil
+		}, InType: reflect.TypeOf(&GuestInfo{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*GuestInterface).DeepCopyInto(out.(*GuestInterface))
+			return nil
+		}, InType: reflect.TypeOf(&GuestInterface{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*GuestUser).DeepCopyInto(out.(*GuestUser))
+			return nil
+		}, InType: reflect.TypeOf(&GuestUser{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*HotpluggedDiskStatus).DeepCopyInto(out.(*HotpluggedDiskStatus))
+			return nil
+		}, InType: reflect.TypeOf(&HotpluggedDiskStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*MemoryStatus).DeepCopyInto(out.(*MemoryStatus))
+			return nil
+		}, InType: reflect.TypeOf(&MemoryStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*NetworkInterface).DeepCopyInto(out.(*NetworkInterface))
+			return nil
+		}, InType: reflect.TypeOf(&NetworkInterface{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*NetworkInterfaceStatus).DeepCopyInto(out.(*NetworkInterfaceStatus))
+			return nil
+		}, InType: reflect.TypeOf(&NetworkInterfaceStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*ResolvedInstanceType).DeepCopyInto(out.(*ResolvedInstanceType))
+			return nil
+		}, InType: reflect.TypeOf(&ResolvedInstanceType{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*ResolvedPreference).DeepCopyInto(out.(*ResolvedPreference))
+			return nil
+		}, InType: reflect.TypeOf(&ResolvedPreference{})},
 		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
 			in.(*VirtualMachine).DeepCopyInto(out.(*VirtualMachine))
 			return nil
 		}, InType: reflect.TypeOf(&VirtualMachine{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineBackup).DeepCopyInto(out.(*VirtualMachineBackup))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineBackup{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineBackupList).DeepCopyInto(out.(*VirtualMachineBackupList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineBackupList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineBackupRestore).DeepCopyInto(out.(*VirtualMachineBackupRestore))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineBackupRestore{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineBackupRestoreList).DeepCopyInto(out.(*VirtualMachineBackupRestoreList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineBackupRestoreList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineBackupRestoreSpec).DeepCopyInto(out.(*VirtualMachineBackupRestoreSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineBackupRestoreSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineBackupRestoreStatus).DeepCopyInto(out.(*VirtualMachineBackupRestoreStatus))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineBackupRestoreStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineBackupSpec).DeepCopyInto(out.(*VirtualMachineBackupSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineBackupSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineBackupStatus).DeepCopyInto(out.(*VirtualMachineBackupStatus))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineBackupStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineBackupTarget).DeepCopyInto(out.(*VirtualMachineBackupTarget))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineBackupTarget{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineBackupTargetList).DeepCopyInto(out.(*VirtualMachineBackupTargetList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineBackupTargetList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineBackupTargetSpec).DeepCopyInto(out.(*VirtualMachineBackupTargetSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineBackupTargetSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineClone).DeepCopyInto(out.(*VirtualMachineClone))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineClone{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineCloneList).DeepCopyInto(out.(*VirtualMachineCloneList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineCloneList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineCloneSpec).DeepCopyInto(out.(*VirtualMachineCloneSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineCloneSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineCloneStatus).DeepCopyInto(out.(*VirtualMachineCloneStatus))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineCloneStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineCondition).DeepCopyInto(out.(*VirtualMachineCondition))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineCondition{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineExport).DeepCopyInto(out.(*VirtualMachineExport))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineExport{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineExportList).DeepCopyInto(out.(*VirtualMachineExportList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineExportList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineExportSpec).DeepCopyInto(out.(*VirtualMachineExportSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineExportSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineExportStatus).DeepCopyInto(out.(*VirtualMachineExportStatus))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineExportStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineImageUpload).DeepCopyInto(out.(*VirtualMachineImageUpload))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineImageUpload{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineImageUploadList).DeepCopyInto(out.(*VirtualMachineImageUploadList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineImageUploadList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineImageUploadSpec).DeepCopyInto(out.(*VirtualMachineImageUploadSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineImageUploadSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineImageUploadStatus).DeepCopyInto(out.(*VirtualMachineImageUploadStatus))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineImageUploadStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineImport).DeepCopyInto(out.(*VirtualMachineImport))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineImport{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineImportList).DeepCopyInto(out.(*VirtualMachineImportList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineImportList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineImportSpec).DeepCopyInto(out.(*VirtualMachineImportSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineImportSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineImportStatus).DeepCopyInto(out.(*VirtualMachineImportStatus))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineImportStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineInstanceType).DeepCopyInto(out.(*VirtualMachineInstanceType))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineInstanceType{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineInstanceTypeList).DeepCopyInto(out.(*VirtualMachineInstanceTypeList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineInstanceTypeList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineInstanceTypeSpec).DeepCopyInto(out.(*VirtualMachineInstanceTypeSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineInstanceTypeSpec{})},
 		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
 			in.(*VirtualMachineList).DeepCopyInto(out.(*VirtualMachineList))
 			return nil
 		}, InType: reflect.TypeOf(&VirtualMachineList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineMigration).DeepCopyInto(out.(*VirtualMachineMigration))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineMigration{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineMigrationList).DeepCopyInto(out.(*VirtualMachineMigrationList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineMigrationList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineMigrationSpec).DeepCopyInto(out.(*VirtualMachineMigrationSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineMigrationSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineMigrationStatus).DeepCopyInto(out.(*VirtualMachineMigrationStatus))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineMigrationStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachinePort).DeepCopyInto(out.(*VirtualMachinePort))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachinePort{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachinePreference).DeepCopyInto(out.(*VirtualMachinePreference))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachinePreference{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachinePreferenceList).DeepCopyInto(out.(*VirtualMachinePreferenceList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachinePreferenceList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachinePreferenceSpec).DeepCopyInto(out.(*VirtualMachinePreferenceSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachinePreferenceSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineQuota).DeepCopyInto(out.(*VirtualMachineQuota))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineQuota{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineQuotaList).DeepCopyInto(out.(*VirtualMachineQuotaList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineQuotaList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineQuotaSpec).DeepCopyInto(out.(*VirtualMachineQuotaSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineQuotaSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineQuotaStatus).DeepCopyInto(out.(*VirtualMachineQuotaStatus))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineQuotaStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineQuotaUsage).DeepCopyInto(out.(*VirtualMachineQuotaUsage))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineQuotaUsage{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineRestore).DeepCopyInto(out.(*VirtualMachineRestore))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineRestore{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineRestoreList).DeepCopyInto(out.(*VirtualMachineRestoreList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineRestoreList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineRestoreSpec).DeepCopyInto(out.(*VirtualMachineRestoreSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineRestoreSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineRestoreStatus).DeepCopyInto(out.(*VirtualMachineRestoreStatus))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineRestoreStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineSnapshot).DeepCopyInto(out.(*VirtualMachineSnapshot))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineSnapshot{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineSnapshotList).DeepCopyInto(out.(*VirtualMachineSnapshotList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineSnapshotList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineSnapshotSpec).DeepCopyInto(out.(*VirtualMachineSnapshotSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineSnapshotSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineSnapshotStatus).DeepCopyInto(out.(*VirtualMachineSnapshotStatus))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineSnapshotStatus{})},
 		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
 			in.(*VirtualMachineSpec).DeepCopyInto(out.(*VirtualMachineSpec))
 			return nil
@@ -47,31 +329,1663 @@ func GetGeneratedDeepCopyFuncs() []conversion.GeneratedDeepCopyFunc {
 			in.(*VirtualMachineStatus).DeepCopyInto(out.(*VirtualMachineStatus))
 			return nil
 		}, InType: reflect.TypeOf(&VirtualMachineStatus{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineVolumeAttachment).DeepCopyInto(out.(*VirtualMachineVolumeAttachment))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineVolumeAttachment{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineVolumeAttachmentList).DeepCopyInto(out.(*VirtualMachineVolumeAttachmentList))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineVolumeAttachmentList{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineVolumeAttachmentSpec).DeepCopyInto(out.(*VirtualMachineVolumeAttachmentSpec))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineVolumeAttachmentSpec{})},
+		{Fn: func(in interface{}, out interface{}, c *conversion.Cloner) error {
+			in.(*VirtualMachineVolumeAttachmentStatus).DeepCopyInto(out.(*VirtualMachineVolumeAttachmentStatus))
+			return nil
+		}, InType: reflect.TypeOf(&VirtualMachineVolumeAttachmentStatus{})},
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSource) DeepCopyInto(out *CloneSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSource.
+func (in *CloneSource) DeepCopy() *CloneSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudInit) DeepCopyInto(out *CloudInit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInit.
+func (in *CloudInit) DeepCopy() *CloudInit {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudInit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Disk) DeepCopyInto(out *Disk) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Disk.
+func (in *Disk) DeepCopy() *Disk {
+	if in == nil {
+		return nil
+	}
+	out := new(Disk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskBackupStatus) DeepCopyInto(out *DiskBackupStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskBackupStatus.
+func (in *DiskBackupStatus) DeepCopy() *DiskBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskImportStatus) DeepCopyInto(out *DiskImportStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskImportStatus.
+func (in *DiskImportStatus) DeepCopy() *DiskImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskRestoreStatus) DeepCopyInto(out *DiskRestoreStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskRestoreStatus.
+func (in *DiskRestoreStatus) DeepCopy() *DiskRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskSnapshotStatus) DeepCopyInto(out *DiskSnapshotStatus) {
+	*out = *in
+	in.ClaimSpec.DeepCopyInto(&out.ClaimSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskSnapshotStatus.
+func (in *DiskSnapshotStatus) DeepCopy() *DiskSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskStatus) DeepCopyInto(out *DiskStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskStatus.
+func (in *DiskStatus) DeepCopy() *DiskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestFilesystem) DeepCopyInto(out *GuestFilesystem) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestFilesystem.
+func (in *GuestFilesystem) DeepCopy() *GuestFilesystem {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestFilesystem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestInfo) DeepCopyInto(out *GuestInfo) {
+	*out = *in
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]GuestInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]GuestUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Filesystems != nil {
+		in, out := &in.Filesystems, &out.Filesystems
+		*out = make([]GuestFilesystem, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestInfo.
+func (in *GuestInfo) DeepCopy() *GuestInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestInterface) DeepCopyInto(out *GuestInterface) {
+	*out = *in
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestInterface.
+func (in *GuestInterface) DeepCopy() *GuestInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestUser) DeepCopyInto(out *GuestUser) {
+	*out = *in
+	in.LoginTime.DeepCopyInto(&out.LoginTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestUser.
+func (in *GuestUser) DeepCopy() *GuestUser {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HotpluggedDiskStatus) DeepCopyInto(out *HotpluggedDiskStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HotpluggedDiskStatus.
+func (in *HotpluggedDiskStatus) DeepCopy() *HotpluggedDiskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HotpluggedDiskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryStatus) DeepCopyInto(out *MemoryStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryStatus.
+func (in *MemoryStatus) DeepCopy() *MemoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
+func (in *NetworkInterface) DeepCopy() *NetworkInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterfaceStatus) DeepCopyInto(out *NetworkInterfaceStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterfaceStatus.
+func (in *NetworkInterfaceStatus) DeepCopy() *NetworkInterfaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterfaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedInstanceType) DeepCopyInto(out *ResolvedInstanceType) {
+	*out = *in
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedInstanceType.
+func (in *ResolvedInstanceType) DeepCopy() *ResolvedInstanceType {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedInstanceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedPreference) DeepCopyInto(out *ResolvedPreference) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedPreference.
+func (in *ResolvedPreference) DeepCopy() *ResolvedPreference {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedPreference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachine) DeepCopyInto(out *VirtualMachine) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachine.
+func (in *VirtualMachine) DeepCopy() *VirtualMachine {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachine) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineBackup) DeepCopyInto(out *VirtualMachineBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackup.
+func (in *VirtualMachineBackup) DeepCopy() *VirtualMachineBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineBackupList) DeepCopyInto(out *VirtualMachineBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupList.
+func (in *VirtualMachineBackupList) DeepCopy() *VirtualMachineBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineBackupRestore) DeepCopyInto(out *VirtualMachineBackupRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupRestore.
+func (in *VirtualMachineBackupRestore) DeepCopy() *VirtualMachineBackupRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineBackupRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineBackupRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineBackupRestoreList) DeepCopyInto(out *VirtualMachineBackupRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineBackupRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupRestoreList.
+func (in *VirtualMachineBackupRestoreList) DeepCopy() *VirtualMachineBackupRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineBackupRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineBackupRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineBackupRestoreSpec) DeepCopyInto(out *VirtualMachineBackupRestoreSpec) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupRestoreSpec.
+func (in *VirtualMachineBackupRestoreSpec) DeepCopy() *VirtualMachineBackupRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineBackupRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineBackupRestoreStatus) DeepCopyInto(out *VirtualMachineBackupRestoreStatus) {
+	*out = *in
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DiskRestoreStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(meta_v1.Time)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupRestoreStatus.
+func (in *VirtualMachineBackupRestoreStatus) DeepCopy() *VirtualMachineBackupRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineBackupRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineBackupSpec) DeepCopyInto(out *VirtualMachineBackupSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupSpec.
+func (in *VirtualMachineBackupSpec) DeepCopy() *VirtualMachineBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineBackupStatus) DeepCopyInto(out *VirtualMachineBackupStatus) {
+	*out = *in
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DiskBackupStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(meta_v1.Time)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupStatus.
+func (in *VirtualMachineBackupStatus) DeepCopy() *VirtualMachineBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineBackupTarget) DeepCopyInto(out *VirtualMachineBackupTarget) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupTarget.
+func (in *VirtualMachineBackupTarget) DeepCopy() *VirtualMachineBackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineBackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineBackupTarget) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineBackupTargetList) DeepCopyInto(out *VirtualMachineBackupTargetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineBackupTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupTargetList.
+func (in *VirtualMachineBackupTargetList) DeepCopy() *VirtualMachineBackupTargetList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineBackupTargetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineBackupTargetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineBackupTargetSpec) DeepCopyInto(out *VirtualMachineBackupTargetSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupTargetSpec.
+func (in *VirtualMachineBackupTargetSpec) DeepCopy() *VirtualMachineBackupTargetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineBackupTargetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineClone) DeepCopyInto(out *VirtualMachineClone) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineClone.
+func (in *VirtualMachineClone) DeepCopy() *VirtualMachineClone {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineClone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineClone) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineCloneList) DeepCopyInto(out *VirtualMachineCloneList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineClone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineCloneList.
+func (in *VirtualMachineCloneList) DeepCopy() *VirtualMachineCloneList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineCloneList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineCloneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineCloneSpec) DeepCopyInto(out *VirtualMachineCloneSpec) {
+	*out = *in
+	out.Source = in.Source
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineCloneSpec.
+func (in *VirtualMachineCloneSpec) DeepCopy() *VirtualMachineCloneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineCloneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineCloneStatus) DeepCopyInto(out *VirtualMachineCloneStatus) {
+	*out = *in
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DiskRestoreStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(meta_v1.Time)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineCloneStatus.
+func (in *VirtualMachineCloneStatus) DeepCopy() *VirtualMachineCloneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineCloneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineCondition) DeepCopyInto(out *VirtualMachineCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineCondition.
+func (in *VirtualMachineCondition) DeepCopy() *VirtualMachineCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExport) DeepCopyInto(out *VirtualMachineExport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExport.
+func (in *VirtualMachineExport) DeepCopy() *VirtualMachineExport {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineExport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportList) DeepCopyInto(out *VirtualMachineExportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineExport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportList.
+func (in *VirtualMachineExportList) DeepCopy() *VirtualMachineExportList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineExportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportSpec) DeepCopyInto(out *VirtualMachineExportSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportSpec.
+func (in *VirtualMachineExportSpec) DeepCopy() *VirtualMachineExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineExportStatus) DeepCopyInto(out *VirtualMachineExportStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportStatus.
+func (in *VirtualMachineExportStatus) DeepCopy() *VirtualMachineExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImageUpload) DeepCopyInto(out *VirtualMachineImageUpload) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImageUpload.
+func (in *VirtualMachineImageUpload) DeepCopy() *VirtualMachineImageUpload {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImageUpload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineImageUpload) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImageUploadList) DeepCopyInto(out *VirtualMachineImageUploadList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineImageUpload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImageUploadList.
+func (in *VirtualMachineImageUploadList) DeepCopy() *VirtualMachineImageUploadList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImageUploadList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineImageUploadList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImageUploadSpec) DeepCopyInto(out *VirtualMachineImageUploadSpec) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImageUploadSpec.
+func (in *VirtualMachineImageUploadSpec) DeepCopy() *VirtualMachineImageUploadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImageUploadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImageUploadStatus) DeepCopyInto(out *VirtualMachineImageUploadStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(meta_v1.Time)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImageUploadStatus.
+func (in *VirtualMachineImageUploadStatus) DeepCopy() *VirtualMachineImageUploadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImageUploadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImport) DeepCopyInto(out *VirtualMachineImport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImport.
+func (in *VirtualMachineImport) DeepCopy() *VirtualMachineImport {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineImport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImportList) DeepCopyInto(out *VirtualMachineImportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineImport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImportList.
+func (in *VirtualMachineImportList) DeepCopy() *VirtualMachineImportList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineImportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImportSpec) DeepCopyInto(out *VirtualMachineImportSpec) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImportSpec.
+func (in *VirtualMachineImportSpec) DeepCopy() *VirtualMachineImportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImportStatus) DeepCopyInto(out *VirtualMachineImportStatus) {
+	*out = *in
+	if in.VirtualMachineSpec != nil {
+		in, out := &in.VirtualMachineSpec, &out.VirtualMachineSpec
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(VirtualMachineSpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DiskImportStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(meta_v1.Time)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImportStatus.
+func (in *VirtualMachineImportStatus) DeepCopy() *VirtualMachineImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineInstanceType) DeepCopyInto(out *VirtualMachineInstanceType) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineInstanceType.
+func (in *VirtualMachineInstanceType) DeepCopy() *VirtualMachineInstanceType {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineInstanceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineInstanceType) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineInstanceTypeList) DeepCopyInto(out *VirtualMachineInstanceTypeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineInstanceType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineInstanceTypeList.
+func (in *VirtualMachineInstanceTypeList) DeepCopy() *VirtualMachineInstanceTypeList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineInstanceTypeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineInstanceTypeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineInstanceTypeSpec) DeepCopyInto(out *VirtualMachineInstanceTypeSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineInstanceTypeSpec.
+func (in *VirtualMachineInstanceTypeSpec) DeepCopy() *VirtualMachineInstanceTypeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineInstanceTypeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineList) DeepCopyInto(out *VirtualMachineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachine, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineList.
+func (in *VirtualMachineList) DeepCopy() *VirtualMachineList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineMigration) DeepCopyInto(out *VirtualMachineMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineMigration.
+func (in *VirtualMachineMigration) DeepCopy() *VirtualMachineMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineMigrationList) DeepCopyInto(out *VirtualMachineMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineMigrationList.
+func (in *VirtualMachineMigrationList) DeepCopy() *VirtualMachineMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineMigrationSpec) DeepCopyInto(out *VirtualMachineMigrationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineMigrationSpec.
+func (in *VirtualMachineMigrationSpec) DeepCopy() *VirtualMachineMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineMigrationStatus) DeepCopyInto(out *VirtualMachineMigrationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(meta_v1.Time)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(meta_v1.Time)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineMigrationStatus.
+func (in *VirtualMachineMigrationStatus) DeepCopy() *VirtualMachineMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachinePort) DeepCopyInto(out *VirtualMachinePort) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachinePort.
+func (in *VirtualMachinePort) DeepCopy() *VirtualMachinePort {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachinePort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachinePreference) DeepCopyInto(out *VirtualMachinePreference) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachinePreference.
+func (in *VirtualMachinePreference) DeepCopy() *VirtualMachinePreference {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachinePreference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachinePreference) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachinePreferenceList) DeepCopyInto(out *VirtualMachinePreferenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachinePreference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachinePreferenceList.
+func (in *VirtualMachinePreferenceList) DeepCopy() *VirtualMachinePreferenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachinePreferenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachinePreferenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachinePreferenceSpec) DeepCopyInto(out *VirtualMachinePreferenceSpec) {
+	*out = *in
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(int64)
+			**out = **in
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(core_v1.Affinity)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]core_v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachinePreferenceSpec.
+func (in *VirtualMachinePreferenceSpec) DeepCopy() *VirtualMachinePreferenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachinePreferenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineQuota) DeepCopyInto(out *VirtualMachineQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineQuota.
+func (in *VirtualMachineQuota) DeepCopy() *VirtualMachineQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineQuotaList) DeepCopyInto(out *VirtualMachineQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineQuotaList.
+func (in *VirtualMachineQuotaList) DeepCopy() *VirtualMachineQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineQuotaSpec) DeepCopyInto(out *VirtualMachineQuotaSpec) {
+	*out = *in
+	if in.VirtualMachines != nil {
+		in, out := &in.VirtualMachines, &out.VirtualMachines
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(int32)
+			**out = **in
+		}
+	}
+	if in.CpuMillis != nil {
+		in, out := &in.CpuMillis, &out.CpuMillis
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(int64)
+			**out = **in
+		}
+	}
+	if in.MemoryMB != nil {
+		in, out := &in.MemoryMB, &out.MemoryMB
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(int64)
+			**out = **in
+		}
+	}
+	if in.DiskMB != nil {
+		in, out := &in.DiskMB, &out.DiskMB
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(int64)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineQuotaSpec.
+func (in *VirtualMachineQuotaSpec) DeepCopy() *VirtualMachineQuotaSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(VirtualMachineQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VirtualMachine) DeepCopyInto(out *VirtualMachine) {
+func (in *VirtualMachineQuotaStatus) DeepCopyInto(out *VirtualMachineQuotaStatus) {
+	*out = *in
+	out.Used = in.Used
+	if in.OverQuota != nil {
+		in, out := &in.OverQuota, &out.OverQuota
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineQuotaStatus.
+func (in *VirtualMachineQuotaStatus) DeepCopy() *VirtualMachineQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineQuotaUsage) DeepCopyInto(out *VirtualMachineQuotaUsage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineQuotaUsage.
+func (in *VirtualMachineQuotaUsage) DeepCopy() *VirtualMachineQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineRestore) DeepCopyInto(out *VirtualMachineRestore) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachine.
-func (in *VirtualMachine) DeepCopy() *VirtualMachine {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineRestore.
+func (in *VirtualMachineRestore) DeepCopy() *VirtualMachineRestore {
 	if in == nil {
 		return nil
 	}
-	out := new(VirtualMachine)
+	out := new(VirtualMachineRestore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VirtualMachine) DeepCopyObject() runtime.Object {
+func (in *VirtualMachineRestore) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	} else {
@@ -80,13 +1994,13 @@ func (in *VirtualMachine) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VirtualMachineList) DeepCopyInto(out *VirtualMachineList) {
+func (in *VirtualMachineRestoreList) DeepCopyInto(out *VirtualMachineRestoreList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]VirtualMachine, len(*in))
+		*out = make([]VirtualMachineRestore, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -94,18 +2008,127 @@ func (in *VirtualMachineList) DeepCopyInto(out *VirtualMachineList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineList.
-func (in *VirtualMachineList) DeepCopy() *VirtualMachineList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineRestoreList.
+func (in *VirtualMachineRestoreList) DeepCopy() *VirtualMachineRestoreList {
 	if in == nil {
 		return nil
 	}
-	out := new(VirtualMachineList)
+	out := new(VirtualMachineRestoreList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VirtualMachineList) DeepCopyObject() runtime.Object {
+func (in *VirtualMachineRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineRestoreSpec) DeepCopyInto(out *VirtualMachineRestoreSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineRestoreSpec.
+func (in *VirtualMachineRestoreSpec) DeepCopy() *VirtualMachineRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineRestoreStatus) DeepCopyInto(out *VirtualMachineRestoreStatus) {
+	*out = *in
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DiskRestoreStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(meta_v1.Time)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineRestoreStatus.
+func (in *VirtualMachineRestoreStatus) DeepCopy() *VirtualMachineRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineSnapshot) DeepCopyInto(out *VirtualMachineSnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineSnapshot.
+func (in *VirtualMachineSnapshot) DeepCopy() *VirtualMachineSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineSnapshotList) DeepCopyInto(out *VirtualMachineSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineSnapshotList.
+func (in *VirtualMachineSnapshotList) DeepCopy() *VirtualMachineSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineSnapshotList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	} else {
@@ -113,9 +2136,122 @@ func (in *VirtualMachineList) DeepCopyObject() runtime.Object {
 	}
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineSnapshotSpec) DeepCopyInto(out *VirtualMachineSnapshotSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineSnapshotSpec.
+func (in *VirtualMachineSnapshotSpec) DeepCopy() *VirtualMachineSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineSnapshotStatus) DeepCopyInto(out *VirtualMachineSnapshotStatus) {
+	*out = *in
+	if in.VirtualMachineSpec != nil {
+		in, out := &in.VirtualMachineSpec, &out.VirtualMachineSpec
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(VirtualMachineSpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DiskSnapshotStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(meta_v1.Time)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineSnapshotStatus.
+func (in *VirtualMachineSnapshotStatus) DeepCopy() *VirtualMachineSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineSpec) DeepCopyInto(out *VirtualMachineSpec) {
 	*out = *in
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]NetworkInterface, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]Disk, len(*in))
+		copy(*out, *in)
+	}
+	if in.CloudInit != nil {
+		in, out := &in.CloudInit, &out.CloudInit
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(CloudInit)
+			**out = **in
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]VirtualMachinePort, len(*in))
+		copy(*out, *in)
+	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(int64)
+			**out = **in
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(core_v1.Affinity)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]core_v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -132,6 +2268,64 @@ func (in *VirtualMachineSpec) DeepCopy() *VirtualMachineSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineStatus) DeepCopyInto(out *VirtualMachineStatus) {
 	*out = *in
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]NetworkInterfaceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]VirtualMachineCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GuestInfo != nil {
+		in, out := &in.GuestInfo, &out.GuestInfo
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(GuestInfo)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(MemoryStatus)
+			**out = **in
+		}
+	}
+	if in.HotpluggedDisks != nil {
+		in, out := &in.HotpluggedDisks, &out.HotpluggedDisks
+		*out = make([]HotpluggedDiskStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DiskStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceType != nil {
+		in, out := &in.InstanceType, &out.InstanceType
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ResolvedInstanceType)
+			**out = **in
+		}
+	}
+	if in.Preference != nil {
+		in, out := &in.Preference, &out.Preference
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ResolvedPreference)
+			(*in).DeepCopyInto(*out)
+		}
+	}
 	return
 }
 
@@ -144,3 +2338,99 @@ func (in *VirtualMachineStatus) DeepCopy() *VirtualMachineStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineVolumeAttachment) DeepCopyInto(out *VirtualMachineVolumeAttachment) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineVolumeAttachment.
+func (in *VirtualMachineVolumeAttachment) DeepCopy() *VirtualMachineVolumeAttachment {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineVolumeAttachment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineVolumeAttachment) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineVolumeAttachmentList) DeepCopyInto(out *VirtualMachineVolumeAttachmentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineVolumeAttachment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineVolumeAttachmentList.
+func (in *VirtualMachineVolumeAttachmentList) DeepCopy() *VirtualMachineVolumeAttachmentList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineVolumeAttachmentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineVolumeAttachmentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	} else {
+		return nil
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineVolumeAttachmentSpec) DeepCopyInto(out *VirtualMachineVolumeAttachmentSpec) {
+	*out = *in
+	out.Disk = in.Disk
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineVolumeAttachmentSpec.
+func (in *VirtualMachineVolumeAttachmentSpec) DeepCopy() *VirtualMachineVolumeAttachmentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineVolumeAttachmentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineVolumeAttachmentStatus) DeepCopyInto(out *VirtualMachineVolumeAttachmentStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineVolumeAttachmentStatus.
+func (in *VirtualMachineVolumeAttachmentStatus) DeepCopy() *VirtualMachineVolumeAttachmentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineVolumeAttachmentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,154 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golang/glog"
+)
+
+// ChunkSize is the size of the chunks disks are split into
+const ChunkSize = 4 << 20
+
+// progressInterval is how many chunks are processed between progress logs
+const progressInterval = 256
+
+// BackupDisk uploads the image at path as the named disk of the backup at
+// location. Chunks listed by the same disk of the parent backup, if any,
+// are known to be in the store; others are only uploaded if they're not.
+func (s *Store) BackupDisk(image, location, disk, parent string) (*DiskManifest, error) {
+	known := map[string]bool{}
+	if parent != "" {
+		previous, err := s.DiskManifest(parent, disk)
+		if err != nil && !IsNotFound(err) {
+			return nil, fmt.Errorf("error reading parent backup: %v", err)
+		}
+		if previous != nil {
+			for _, sum := range previous.Chunks {
+				known[sum] = true
+			}
+		}
+	}
+
+	f, err := os.Open(image)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &DiskManifest{
+		SizeBytes: info.Size(),
+		ChunkSize: ChunkSize,
+	}
+	buf := make([]byte, ChunkSize)
+	for {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 {
+			sum, err := s.backupChunk(buf[:n], known)
+			if err != nil {
+				return nil, err
+			}
+			if sum != "" && !known[sum] {
+				known[sum] = true
+				manifest.UploadedChunks++
+			}
+			manifest.Chunks = append(manifest.Chunks, sum)
+			if len(manifest.Chunks)%progressInterval == 0 {
+				glog.Infof("Backed up %d of %d bytes of disk %s", int64(len(manifest.Chunks))*ChunkSize, manifest.SizeBytes, disk)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.putJSON(s.DiskManifestKey(location, disk), manifest); err != nil {
+		return nil, err
+	}
+	glog.Infof("Backed up disk %s: %d chunks, %d uploaded", disk, len(manifest.Chunks), manifest.UploadedChunks)
+	return manifest, nil
+}
+
+// backupChunk stores a chunk unless it's known or already stored, and
+// returns its SHA256. Chunks full of zeros aren't stored.
+func (s *Store) backupChunk(chunk []byte, known map[string]bool) (string, error) {
+	if isZero(chunk) {
+		return "", nil
+	}
+	hash := sha256.Sum256(chunk)
+	sum := hex.EncodeToString(hash[:])
+	if known[sum] {
+		return sum, nil
+	}
+	exists, err := s.Exists(s.chunkKey(sum))
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		// Count it as known rather than uploaded
+		known[sum] = true
+		return sum, nil
+	}
+	if err := s.put(s.chunkKey(sum), chunk, sum); err != nil {
+		return "", fmt.Errorf("error uploading chunk %s: %v", sum, err)
+	}
+	return sum, nil
+}
+
+// RestoreDisk writes the named disk of the backup at location to a sparse
+// image at path
+func (s *Store) RestoreDisk(location, disk, image string) error {
+	manifest, err := s.DiskManifest(location, disk)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(image, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0660)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := f.Truncate(manifest.SizeBytes); err != nil {
+		return err
+	}
+
+	for i, sum := range manifest.Chunks {
+		if sum == "" {
+			continue
+		}
+		chunk, err := s.Get(s.chunkKey(sum))
+		if err != nil {
+			return fmt.Errorf("error downloading chunk %s: %v", sum, err)
+		}
+		if hash := sha256.Sum256(chunk); hex.EncodeToString(hash[:]) != sum {
+			return fmt.Errorf("chunk %s is corrupt", sum)
+		}
+		if _, err := f.WriteAt(chunk, int64(i)*manifest.ChunkSize); err != nil {
+			return err
+		}
+		if (i+1)%progressInterval == 0 {
+			glog.Infof("Restored %d of %d bytes of disk %s", int64(i+1)*manifest.ChunkSize, manifest.SizeBytes, disk)
+		}
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	glog.Infof("Restored disk %s", disk)
+	return nil
+}
+
+var zeros = make([]byte, ChunkSize)
+
+func isZero(chunk []byte) bool {
+	return bytes.Equal(chunk, zeros[:len(chunk)])
+}
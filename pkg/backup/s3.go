@@ -0,0 +1,217 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRegion = "us-east-1"
+
+	// emptyPayloadHash is the SHA256 of an empty request body
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// S3 is a minimal client of the S3 object API, enough for backups. Buckets
+// are addressed by path, which MinIO and most S3-compatible stores support,
+// and requests are signed with AWS Signature Version 4.
+type S3 struct {
+	Endpoint        *url.URL
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+
+	client *http.Client
+}
+
+func NewS3(endpoint, bucket, region, accessKeyID, secretAccessKey string) (*S3, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %v", endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid endpoint %q: scheme must be http or https", endpoint)
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+	if region == "" {
+		region = defaultRegion
+	}
+	return &S3{
+		Endpoint:        u,
+		Bucket:          bucket,
+		Region:          region,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		client:          &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// Environment variables configuring S3FromEnv, set on backup jobs
+const (
+	EnvEndpoint        = "BACKUP_ENDPOINT"
+	EnvBucket          = "BACKUP_BUCKET"
+	EnvRegion          = "BACKUP_REGION"
+	EnvAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	EnvSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+)
+
+// S3FromEnv returns a client configured by the environment
+func S3FromEnv() (*S3, error) {
+	return NewS3(os.Getenv(EnvEndpoint), os.Getenv(EnvBucket), os.Getenv(EnvRegion),
+		os.Getenv(EnvAccessKeyID), os.Getenv(EnvSecretAccessKey))
+}
+
+// Put uploads an object
+func (s *S3) Put(key string, data []byte) error {
+	sum := sha256.Sum256(data)
+	return s.put(key, data, hex.EncodeToString(sum[:]))
+}
+
+// put uploads an object whose SHA256 is known
+func (s *S3) put(key string, data []byte, sum string) error {
+	resp, err := s.do("PUT", key, data, sum)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads an object. The error satisfies IsNotFound if there is none.
+func (s *S3) Get(key string) ([]byte, error) {
+	resp, err := s.do("GET", key, nil, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+// Exists returns true if there is an object at key
+func (s *S3) Exists(key string) (bool, error) {
+	resp, err := s.do("HEAD", key, nil, emptyPayloadHash)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+// Error is an error response of the object store
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("object store returned %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if err says that an object doesn't exist
+func IsNotFound(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// do sends a signed request for the object at key, returning the response
+// if it succeeded
+func (s *S3) do(method, key string, body []byte, payloadHash string) (*http.Response, error) {
+	u := *s.Endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.Bucket + "/" + key
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	s.sign(req, payloadHash, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+	return resp, nil
+}
+
+// sign adds an AWS Signature Version 4 to the request
+func (s *S3) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath URI-encodes every segment of path the way Signature Version 4
+// expects
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		var b bytes.Buffer
+		for _, c := range []byte(segment) {
+			if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+				b.WriteByte(c)
+			} else {
+				fmt.Fprintf(&b, "%%%02X", c)
+			}
+		}
+		segments[i] = b.String()
+	}
+	return strings.Join(segments, "/")
+}
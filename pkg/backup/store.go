@@ -0,0 +1,126 @@
+package backup
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// A backup target holds, under its prefix:
+//
+//	chunks/<sha256>                     disk contents, shared by all backups
+//	backups/<location>/backup.json      the Manifest of a complete backup
+//	backups/<location>/disks/<disk>.json the DiskManifest of each disk
+//
+// Chunks are addressed by their content, so backups only upload the chunks
+// that changed since any earlier backup to the same target. Finding them
+// still reads every disk in full, as no dirty bitmaps are kept.
+
+// Manifest describes a complete backup
+type Manifest struct {
+	Location           string                   `json:"location"`
+	VirtualMachineName string                   `json:"vm_name"`
+	VirtualMachineSpec vmapi.VirtualMachineSpec `json:"vm_spec"`
+	Disks              []ManifestDisk           `json:"disks"`
+	Parent             string                   `json:"parent,omitempty"`
+	CompletionTime     time.Time                `json:"completion_time"`
+}
+
+// ManifestDisk is a disk of a backup
+type ManifestDisk struct {
+	Name string `json:"name"`
+	// ClaimSpec is the spec of the disk's claim, used to size restored disks
+	ClaimSpec corev1.PersistentVolumeClaimSpec `json:"claim_spec"`
+}
+
+// DiskManifest lists the chunks of a disk image
+type DiskManifest struct {
+	SizeBytes int64 `json:"size_bytes"`
+	ChunkSize int64 `json:"chunk_size"`
+	// Chunks are the SHA256 of every chunk of the image, empty for chunks
+	// full of zeros
+	Chunks []string `json:"chunks"`
+	// UploadedChunks is how many chunks were not in the store yet
+	UploadedChunks int `json:"uploaded_chunks"`
+}
+
+// Store reads and writes backups in a target
+type Store struct {
+	*S3
+	Prefix string
+}
+
+// EnvPrefix sets the Prefix of the store returned by StoreFromEnv
+const EnvPrefix = "BACKUP_PREFIX"
+
+// StoreFromEnv returns a store configured by the environment
+func StoreFromEnv() (*Store, error) {
+	s3, err := S3FromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{S3: s3, Prefix: os.Getenv(EnvPrefix)}, nil
+}
+
+func (s *Store) key(elem ...string) string {
+	return path.Join(append([]string{s.Prefix}, elem...)...)
+}
+
+// ManifestKey is where the manifest of the backup at location is stored
+func (s *Store) ManifestKey(location string) string {
+	return s.key("backups", location, "backup.json")
+}
+
+// DiskManifestKey is where the manifest of a disk of the backup at location
+// is stored
+func (s *Store) DiskManifestKey(location, disk string) string {
+	return s.key("backups", location, "disks", disk+".json")
+}
+
+func (s *Store) chunkKey(sum string) string {
+	return s.key("chunks", sum)
+}
+
+// Manifest returns the manifest of the backup at location
+func (s *Store) Manifest(location string) (*Manifest, error) {
+	manifest := &Manifest{}
+	if err := s.getJSON(s.ManifestKey(location), manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// PutManifest completes a backup by storing its manifest
+func (s *Store) PutManifest(manifest *Manifest) error {
+	return s.putJSON(s.ManifestKey(manifest.Location), manifest)
+}
+
+// DiskManifest returns the manifest of a disk of the backup at location
+func (s *Store) DiskManifest(location, disk string) (*DiskManifest, error) {
+	manifest := &DiskManifest{}
+	if err := s.getJSON(s.DiskManifestKey(location, disk), manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (s *Store) getJSON(key string, v interface{}) error {
+	data, err := s.Get(key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (s *Store) putJSON(key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Put(key, data)
+}
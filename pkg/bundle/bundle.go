@@ -0,0 +1,143 @@
+package bundle
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// A bundle is a tar archive whose first file describes a VM, followed by a
+// file for each of its disks. Bundles in the qcow2 format are described by
+// ManifestFile; OVAs are described by an OVF descriptor.
+const ManifestFile = "manifest.json"
+
+// Manifest describes the VM of a bundle
+type Manifest struct {
+	VirtualMachineName string                   `json:"vm_name"`
+	VirtualMachineSpec vmapi.VirtualMachineSpec `json:"vm_spec"`
+	Disks              []Disk                   `json:"disks"`
+}
+
+// Disk is a disk of a bundle
+type Disk struct {
+	Name string `json:"name"`
+	// File is the disk's file within the bundle
+	File string `json:"file"`
+	// CapacityBytes is the size of the disk seen by the guest
+	CapacityBytes int64 `json:"capacity_bytes"`
+	// SizeBytes is the size of the disk's file
+	SizeBytes int64 `json:"size_bytes,omitempty"`
+}
+
+// Validate checks that every disk of the manifest names its file within the
+// bundle
+func (m *Manifest) Validate() error {
+	for _, disk := range m.Disks {
+		if disk.Name == "" || disk.File == "" {
+			return fmt.Errorf("bundle has a disk without a name or file")
+		}
+	}
+	return nil
+}
+
+// Read reads the manifest from the descriptor at the start of a bundle
+func Read(r io.Reader) (*Manifest, error) {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("bundle has no descriptor")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		switch name := path.Base(header.Name); {
+		case name == ManifestFile:
+			manifest := &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", ManifestFile, err)
+			}
+			return manifest, nil
+		case strings.HasSuffix(strings.ToLower(name), ".ovf"):
+			return ParseOVF(tr)
+		default:
+			return nil, fmt.Errorf("bundle must start with %s or an OVF descriptor, found %s", ManifestFile, header.Name)
+		}
+	}
+}
+
+// Open skips to the named file of a bundle and returns its content and size
+func Open(r io.Reader, file string) (io.Reader, int64, error) {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, 0, fmt.Errorf("bundle has no file %s", file)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if path.Clean(header.Name) == path.Clean(file) {
+			return tr, header.Size, nil
+		}
+	}
+}
+
+// Write writes a bundle of the descriptor followed by the files of disks,
+// read from dir
+func Write(w io.Writer, descriptorName string, descriptor []byte, dir string, disks []Disk) error {
+	tw := tar.NewWriter(w)
+	err := tw.WriteHeader(&tar.Header{
+		Name:     descriptorName,
+		Mode:     0644,
+		Size:     int64(len(descriptor)),
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := tw.Write(descriptor); err != nil {
+		return err
+	}
+	for _, disk := range disks {
+		if err := writeFile(tw, filepath.Join(dir, disk.File), disk.File); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func writeFile(tw *tar.Writer, filename, name string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	err = tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
@@ -0,0 +1,131 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func writeTar(t *testing.T, files ...string) *bytes.Buffer {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for i := 0; i < len(files); i += 2 {
+		header := &tar.Header{Name: files[i], Mode: 0644, Size: int64(len(files[i+1])), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(header.Name, "/") {
+			header.Typeflag = tar.TypeDir
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(files[i+1]))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestWriteReadOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	disks := []Disk{
+		{Name: "root", File: "root.qcow2", CapacityBytes: 1 << 30},
+		{Name: "data", File: "data.qcow2", CapacityBytes: 1 << 20},
+	}
+	for _, disk := range disks {
+		if err := ioutil.WriteFile(filepath.Join(dir, disk.File), []byte("image of "+disk.Name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest := &Manifest{
+		VirtualMachineName: "vm1",
+		VirtualMachineSpec: vmapi.VirtualMachineSpec{CpuMillis: 1000, MemoryMB: 512},
+		Disks:              disks,
+	}
+	descriptor, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, ManifestFile, descriptor, dir, disks); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	read, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.VirtualMachineName != "vm1" || read.VirtualMachineSpec.MemoryMB != 512 || len(read.Disks) != 2 || read.Disks[1] != disks[1] {
+		t.Errorf("got manifest %+v", read)
+	}
+
+	for _, disk := range disks {
+		r, size, err := Open(bytes.NewReader(data), "./"+disk.File)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "image of "+disk.Name || size != int64(len(content)) {
+			t.Errorf("got %d bytes %q of %s", size, content, disk.File)
+		}
+	}
+	if _, _, err := Open(bytes.NewReader(data), "missing.qcow2"); err == nil {
+		t.Error("opened missing file")
+	}
+}
+
+func TestReadOVA(t *testing.T) {
+	ova := writeTar(t, "dir/", "", "Web Server.OVF", vsphereOVF, "Web Server-disk1.vmdk", "data")
+	manifest, err := Read(ova)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.VirtualMachineName != "web-server" || len(manifest.Disks) != 2 {
+		t.Errorf("got manifest %+v", manifest)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		bundle  *bytes.Buffer
+		wantErr string
+	}{
+		{"empty", writeTar(t), "no descriptor"},
+		{"disk first", writeTar(t, "root.qcow2", "data", ManifestFile, "{}"), "must start with"},
+		{"invalid manifest", writeTar(t, ManifestFile, "{"), "invalid manifest.json"},
+		{"not a tar", bytes.NewBufferString(strings.Repeat("x", 1024)), ""},
+	}
+	for _, test := range tests {
+		_, err := Read(test.bundle)
+		if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("%s: got error %v, want %q", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Manifest{Disks: []Disk{{Name: "root", File: "root.qcow2"}}}).Validate(); err != nil {
+		t.Error(err)
+	}
+	for _, disk := range []Disk{{Name: "root"}, {File: "root.qcow2"}} {
+		if err := (&Manifest{Disks: []Disk{disk}}).Validate(); err == nil {
+			t.Errorf("disk %+v is valid", disk)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package bundle
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// CIM resource types of the hardware items of OVF descriptors
+const (
+	resourceProcessor      = 3
+	resourceMemory         = 4
+	resourceSCSIController = 6
+	resourceDisk           = 17
+)
+
+// vmdkFormat identifies streamOptimized VMDK disks, the format of OVA disks
+const vmdkFormat = "http://www.vmware.com/interfaces/specifications/vmdk.html#streamOptimized"
+
+// The descriptor is parsed by local names, as Go matches elements and
+// attributes in any namespace unless told otherwise
+
+type ovfEnvelope struct {
+	References    []ovfFile         `xml:"References>File"`
+	Disks         []ovfDisk         `xml:"DiskSection>Disk"`
+	VirtualSystem *ovfVirtualSystem `xml:"VirtualSystem"`
+	Collection    *struct{}         `xml:"VirtualSystemCollection"`
+}
+
+type ovfFile struct {
+	ID          string `xml:"id,attr"`
+	Href        string `xml:"href,attr"`
+	Compression string `xml:"compression,attr"`
+	ChunkSize   string `xml:"chunkSize,attr"`
+}
+
+type ovfDisk struct {
+	ID            string `xml:"diskId,attr"`
+	FileRef       string `xml:"fileRef,attr"`
+	Capacity      string `xml:"capacity,attr"`
+	CapacityUnits string `xml:"capacityAllocationUnits,attr"`
+}
+
+type ovfVirtualSystem struct {
+	ID       string `xml:"id,attr"`
+	Name     string `xml:"Name"`
+	Hardware []struct {
+		Items []ovfItem `xml:"Item"`
+	} `xml:"VirtualHardwareSection"`
+}
+
+type ovfItem struct {
+	ResourceType    int    `xml:"ResourceType"`
+	VirtualQuantity int64  `xml:"VirtualQuantity"`
+	AllocationUnits string `xml:"AllocationUnits"`
+	HostResource    string `xml:"HostResource"`
+}
+
+// ParseOVF maps an OVF descriptor to a manifest. The processors and memory
+// of the first hardware section size the VM, and its disks are named after
+// their disk IDs.
+func ParseOVF(r io.Reader) (*Manifest, error) {
+	envelope := &ovfEnvelope{}
+	if err := xml.NewDecoder(r).Decode(envelope); err != nil {
+		return nil, fmt.Errorf("invalid OVF descriptor: %v", err)
+	}
+	if envelope.Collection != nil {
+		return nil, fmt.Errorf("OVF descriptors of several virtual systems are not supported")
+	}
+	system := envelope.VirtualSystem
+	if system == nil || len(system.Hardware) == 0 {
+		return nil, fmt.Errorf("OVF descriptor has no virtual hardware")
+	}
+
+	name := system.Name
+	if name == "" {
+		name = system.ID
+	}
+	manifest := &Manifest{
+		VirtualMachineName: dnsLabel(name, "vm"),
+	}
+	spec := &manifest.VirtualMachineSpec
+	spec.CpuMillis = 1000
+	names := map[string]bool{}
+	for _, item := range system.Hardware[0].Items {
+		switch item.ResourceType {
+		case resourceProcessor:
+			if item.VirtualQuantity > 0 {
+				spec.CpuMillis = int32(item.VirtualQuantity * 1000)
+			}
+
+		case resourceMemory:
+			units := item.AllocationUnits
+			if units == "" {
+				units = "byte * 2^20"
+			}
+			multiplier, err := parseUnits(units)
+			if err != nil {
+				return nil, fmt.Errorf("memory: %v", err)
+			}
+			spec.MemoryMB = int32(item.VirtualQuantity * multiplier >> 20)
+
+		case resourceDisk:
+			disk, err := envelope.disk(item.HostResource)
+			if err != nil {
+				return nil, err
+			}
+			disk.Name = dnsLabel(disk.Name, fmt.Sprintf("disk%d", len(manifest.Disks)))
+			if names[disk.Name] {
+				disk.Name = fmt.Sprintf("%s-%d", disk.Name, len(manifest.Disks))
+			}
+			names[disk.Name] = true
+			manifest.Disks = append(manifest.Disks, *disk)
+			spec.Disks = append(spec.Disks, vmapi.Disk{Name: disk.Name})
+		}
+	}
+	if spec.MemoryMB == 0 {
+		return nil, fmt.Errorf("OVF descriptor has no memory")
+	}
+	if len(manifest.Disks) == 0 {
+		return nil, fmt.Errorf("OVF descriptor has no disks")
+	}
+	return manifest, nil
+}
+
+// disk resolves the host resource of a disk item, either ovf:/disk/<id> or
+// ovf:/file/<id>, to the disk and its file
+func (e *ovfEnvelope) disk(hostResource string) (*Disk, error) {
+	resource := strings.TrimPrefix(strings.TrimPrefix(hostResource, "ovf:"), "/")
+	fileID := ""
+	disk := &Disk{}
+	switch {
+	case strings.HasPrefix(resource, "disk/"):
+		id := strings.TrimPrefix(resource, "disk/")
+		var found *ovfDisk
+		for i := range e.Disks {
+			if e.Disks[i].ID == id {
+				found = &e.Disks[i]
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("OVF descriptor has no disk %s", id)
+		}
+		if found.FileRef == "" {
+			return nil, fmt.Errorf("disk %s is empty, which is not supported", id)
+		}
+		capacity, err := strconv.ParseInt(found.Capacity, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("disk %s: invalid capacity %q", id, found.Capacity)
+		}
+		multiplier, err := parseUnits(found.CapacityUnits)
+		if err != nil {
+			return nil, fmt.Errorf("disk %s: %v", id, err)
+		}
+		disk.Name = id
+		disk.CapacityBytes = capacity * multiplier
+		fileID = found.FileRef
+	case strings.HasPrefix(resource, "file/"):
+		fileID = strings.TrimPrefix(resource, "file/")
+		disk.Name = fileID
+	default:
+		return nil, fmt.Errorf("unsupported disk host resource %q", hostResource)
+	}
+
+	for _, file := range e.References {
+		if file.ID != fileID {
+			continue
+		}
+		if file.Compression != "" && file.Compression != "identity" {
+			return nil, fmt.Errorf("file %s is compressed, which is not supported", file.Href)
+		}
+		if file.ChunkSize != "" {
+			return nil, fmt.Errorf("file %s is chunked, which is not supported", file.Href)
+		}
+		disk.File = file.Href
+		return disk, nil
+	}
+	return nil, fmt.Errorf("OVF descriptor has no file %s", fileID)
+}
+
+var unitsPattern = regexp.MustCompile(`^byte\s*\*\s*(\d+)\s*\^\s*(\d+)$`)
+
+// parseUnits returns the bytes in an allocation unit, such as byte * 2^20.
+// The names used by older descriptors are accepted too.
+func parseUnits(units string) (int64, error) {
+	units = strings.TrimSpace(units)
+	switch strings.ToLower(units) {
+	case "", "byte", "bytes":
+		return 1, nil
+	case "kb", "kilobytes":
+		return 1 << 10, nil
+	case "mb", "megabytes":
+		return 1 << 20, nil
+	case "gb", "gigabytes":
+		return 1 << 30, nil
+	}
+	match := unitsPattern.FindStringSubmatch(units)
+	if match == nil {
+		return 0, fmt.Errorf("unsupported allocation units %q", units)
+	}
+	base, _ := strconv.Atoi(match[1])
+	exponent, _ := strconv.Atoi(match[2])
+	multiplier := math.Pow(float64(base), float64(exponent))
+	if multiplier < 1 || multiplier > 1<<50 {
+		return 0, fmt.Errorf("unsupported allocation units %q", units)
+	}
+	return int64(multiplier), nil
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// dnsLabel turns name into a valid DNS label, as Kubernetes names must be,
+// falling back to fallback if nothing is left of it
+func dnsLabel(name, fallback string) string {
+	label := invalidLabelChars.ReplaceAllString(strings.ToLower(name), "-")
+	if len(label) > 40 {
+		label = label[:40]
+	}
+	label = strings.Trim(label, "-")
+	if label == "" {
+		return fallback
+	}
+	return label
+}
+
+// The descriptor is written with literal prefixes, which Go doesn't
+// generate for namespaces itself
+
+type ovfOutEnvelope struct {
+	XMLName       xml.Name     `xml:"Envelope"`
+	Xmlns         string       `xml:"xmlns,attr"`
+	XmlnsOVF      string       `xml:"xmlns:ovf,attr"`
+	XmlnsRASD     string       `xml:"xmlns:rasd,attr"`
+	XmlnsVSSD     string       `xml:"xmlns:vssd,attr"`
+	References    []ovfOutFile `xml:"References>File"`
+	DiskSection   ovfOutDiskSection
+	VirtualSystem ovfOutVirtualSystem
+}
+
+type ovfOutFile struct {
+	ID   string `xml:"ovf:id,attr"`
+	Href string `xml:"ovf:href,attr"`
+	Size int64  `xml:"ovf:size,attr"`
+}
+
+type ovfOutDiskSection struct {
+	Info  string
+	Disks []ovfOutDisk `xml:"Disk"`
+}
+
+type ovfOutDisk struct {
+	ID            string `xml:"ovf:diskId,attr"`
+	FileRef       string `xml:"ovf:fileRef,attr"`
+	Capacity      int64  `xml:"ovf:capacity,attr"`
+	CapacityUnits string `xml:"ovf:capacityAllocationUnits,attr"`
+	Format        string `xml:"ovf:format,attr"`
+}
+
+type ovfOutVirtualSystem struct {
+	ID       string `xml:"ovf:id,attr"`
+	Info     string
+	Name     string
+	OS       ovfOutOS       `xml:"OperatingSystemSection"`
+	Hardware ovfOutHardware `xml:"VirtualHardwareSection"`
+}
+
+type ovfOutOS struct {
+	ID   int `xml:"ovf:id,attr"`
+	Info string
+}
+
+type ovfOutHardware struct {
+	Info   string
+	System struct {
+		ElementName             string `xml:"vssd:ElementName"`
+		InstanceID              int    `xml:"vssd:InstanceID"`
+		VirtualSystemIdentifier string `xml:"vssd:VirtualSystemIdentifier"`
+		VirtualSystemType       string `xml:"vssd:VirtualSystemType"`
+	}
+	Items []ovfOutItem `xml:"Item"`
+}
+
+// ovfOutItem has its elements in the order of the CIM schema
+type ovfOutItem struct {
+	AddressOnParent string `xml:"rasd:AddressOnParent,omitempty"`
+	AllocationUnits string `xml:"rasd:AllocationUnits,omitempty"`
+	ElementName     string `xml:"rasd:ElementName"`
+	HostResource    string `xml:"rasd:HostResource,omitempty"`
+	InstanceID      int    `xml:"rasd:InstanceID"`
+	Parent          int    `xml:"rasd:Parent,omitempty"`
+	ResourceSubType string `xml:"rasd:ResourceSubType,omitempty"`
+	ResourceType    int    `xml:"rasd:ResourceType"`
+	VirtualQuantity int64  `xml:"rasd:VirtualQuantity,omitempty"`
+}
+
+// OVF returns the OVF descriptor of an OVA with the manifest's VM. Disks are
+// streamOptimized VMDKs on a SCSI controller, whose SizeBytes must be set.
+func OVF(manifest *Manifest) ([]byte, error) {
+	spec := &manifest.VirtualMachineSpec
+	if spec.CpuMillis <= 0 || spec.MemoryMB <= 0 {
+		return nil, fmt.Errorf("vm %s has no cpu_milli or memory_mb", manifest.VirtualMachineName)
+	}
+	envelope := &ovfOutEnvelope{
+		Xmlns:     "http://schemas.dmtf.org/ovf/envelope/1",
+		XmlnsOVF:  "http://schemas.dmtf.org/ovf/envelope/1",
+		XmlnsRASD: "http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData",
+		XmlnsVSSD: "http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_VirtualSystemSettingData",
+		DiskSection: ovfOutDiskSection{
+			Info: "Virtual disks",
+		},
+		VirtualSystem: ovfOutVirtualSystem{
+			ID:   manifest.VirtualMachineName,
+			Info: "A virtual machine",
+			Name: manifest.VirtualMachineName,
+			// Other
+			OS: ovfOutOS{ID: 1, Info: "The guest operating system"},
+		},
+	}
+
+	// Guests get whole vCPUs
+	vcpus := (int64(spec.CpuMillis) + 999) / 1000
+	hardware := &envelope.VirtualSystem.Hardware
+	hardware.Info = "Virtual hardware requirements"
+	hardware.System.ElementName = "Virtual Hardware Family"
+	hardware.System.VirtualSystemIdentifier = manifest.VirtualMachineName
+	hardware.System.VirtualSystemType = "vmx-07"
+	hardware.Items = []ovfOutItem{
+		ovfOutItem{
+			AllocationUnits: "hertz * 10^6",
+			ElementName:     fmt.Sprintf("%d virtual CPU(s)", vcpus),
+			InstanceID:      1,
+			ResourceType:    resourceProcessor,
+			VirtualQuantity: vcpus,
+		},
+		ovfOutItem{
+			AllocationUnits: "byte * 2^20",
+			ElementName:     fmt.Sprintf("%dMB of memory", spec.MemoryMB),
+			InstanceID:      2,
+			ResourceType:    resourceMemory,
+			VirtualQuantity: int64(spec.MemoryMB),
+		},
+		ovfOutItem{
+			ElementName:     "SCSI Controller 0",
+			InstanceID:      3,
+			ResourceSubType: "lsilogic",
+			ResourceType:    resourceSCSIController,
+		},
+	}
+
+	for i, disk := range manifest.Disks {
+		fileID := fmt.Sprintf("file%d", i+1)
+		diskID := disk.Name
+		envelope.References = append(envelope.References, ovfOutFile{
+			ID:   fileID,
+			Href: disk.File,
+			Size: disk.SizeBytes,
+		})
+		envelope.DiskSection.Disks = append(envelope.DiskSection.Disks, ovfOutDisk{
+			ID:            diskID,
+			FileRef:       fileID,
+			Capacity:      disk.CapacityBytes,
+			CapacityUnits: "byte",
+			Format:        vmdkFormat,
+		})
+		hardware.Items = append(hardware.Items, ovfOutItem{
+			AddressOnParent: strconv.Itoa(i),
+			ElementName:     fmt.Sprintf("Hard disk %d", i+1),
+			HostResource:    "ovf:/disk/" + diskID,
+			InstanceID:      4 + i,
+			Parent:          3,
+			ResourceType:    resourceDisk,
+		})
+	}
+
+	out, err := xml.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
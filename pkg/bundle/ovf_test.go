@@ -0,0 +1,221 @@
+package bundle
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// vsphereOVF is trimmed from an OVA exported by vSphere
+const vsphereOVF = `<?xml version="1.0" encoding="UTF-8"?>
+<Envelope vmw:buildId="build-3620759" xmlns="http://schemas.dmtf.org/ovf/envelope/1" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1" xmlns:rasd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData" xmlns:vmw="http://www.vmware.com/schema/ovf">
+  <References>
+    <File ovf:href="Web Server-disk1.vmdk" ovf:id="file1" ovf:size="1214500352"/>
+    <File ovf:href="Web Server-disk2.vmdk" ovf:id="file2" ovf:size="68096"/>
+  </References>
+  <DiskSection>
+    <Info>Virtual disk information</Info>
+    <Disk ovf:capacity="16" ovf:capacityAllocationUnits="byte * 2^30" ovf:diskId="vmdisk1" ovf:fileRef="file1" ovf:format="http://www.vmware.com/interfaces/specifications/vmdk.html#streamOptimized"/>
+    <Disk ovf:capacity="1073741824" ovf:diskId="vmdisk2" ovf:fileRef="file2" ovf:format="http://www.vmware.com/interfaces/specifications/vmdk.html#streamOptimized"/>
+  </DiskSection>
+  <VirtualSystem ovf:id="web-01">
+    <Info>A virtual machine</Info>
+    <Name>Web Server</Name>
+    <VirtualHardwareSection>
+      <Info>Virtual hardware requirements</Info>
+      <Item>
+        <rasd:AllocationUnits>hertz * 10^6</rasd:AllocationUnits>
+        <rasd:ElementName>2 virtual CPU(s)</rasd:ElementName>
+        <rasd:InstanceID>1</rasd:InstanceID>
+        <rasd:ResourceType>3</rasd:ResourceType>
+        <rasd:VirtualQuantity>2</rasd:VirtualQuantity>
+      </Item>
+      <Item>
+        <rasd:AllocationUnits>byte * 2^30</rasd:AllocationUnits>
+        <rasd:ElementName>4GB of memory</rasd:ElementName>
+        <rasd:InstanceID>2</rasd:InstanceID>
+        <rasd:ResourceType>4</rasd:ResourceType>
+        <rasd:VirtualQuantity>4</rasd:VirtualQuantity>
+      </Item>
+      <Item>
+        <rasd:ElementName>SCSI controller 0</rasd:ElementName>
+        <rasd:InstanceID>3</rasd:InstanceID>
+        <rasd:ResourceSubType>lsilogic</rasd:ResourceSubType>
+        <rasd:ResourceType>6</rasd:ResourceType>
+      </Item>
+      <Item>
+        <rasd:AddressOnParent>0</rasd:AddressOnParent>
+        <rasd:ElementName>Hard disk 1</rasd:ElementName>
+        <rasd:HostResource>ovf:/disk/vmdisk1</rasd:HostResource>
+        <rasd:InstanceID>4</rasd:InstanceID>
+        <rasd:Parent>3</rasd:Parent>
+        <rasd:ResourceType>17</rasd:ResourceType>
+      </Item>
+      <Item>
+        <rasd:AddressOnParent>1</rasd:AddressOnParent>
+        <rasd:ElementName>Hard disk 2</rasd:ElementName>
+        <rasd:HostResource>ovf:/disk/vmdisk2</rasd:HostResource>
+        <rasd:InstanceID>5</rasd:InstanceID>
+        <rasd:Parent>3</rasd:Parent>
+        <rasd:ResourceType>17</rasd:ResourceType>
+      </Item>
+    </VirtualHardwareSection>
+  </VirtualSystem>
+</Envelope>`
+
+func TestParseOVF(t *testing.T) {
+	manifest, err := ParseOVF(strings.NewReader(vsphereOVF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.VirtualMachineName != "web-server" {
+		t.Errorf("got name %q", manifest.VirtualMachineName)
+	}
+	spec := manifest.VirtualMachineSpec
+	if spec.CpuMillis != 2000 || spec.MemoryMB != 4096 {
+		t.Errorf("got cpu_milli %d and memory_mb %d, want 2000 and 4096", spec.CpuMillis, spec.MemoryMB)
+	}
+	want := []Disk{
+		{Name: "vmdisk1", File: "Web Server-disk1.vmdk", CapacityBytes: 16 << 30},
+		{Name: "vmdisk2", File: "Web Server-disk2.vmdk", CapacityBytes: 1 << 30},
+	}
+	if len(manifest.Disks) != len(want) {
+		t.Fatalf("got disks %+v, want %+v", manifest.Disks, want)
+	}
+	for i := range want {
+		if manifest.Disks[i] != want[i] {
+			t.Errorf("got disk %+v, want %+v", manifest.Disks[i], want[i])
+		}
+		if spec.Disks[i].Name != want[i].Name {
+			t.Errorf("got spec disk %q, want %q", spec.Disks[i].Name, want[i].Name)
+		}
+	}
+}
+
+func TestParseOVFErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		replace []string
+		wantErr string
+	}{
+		{"collection", []string{"<VirtualSystem ovf:id", "<VirtualSystemCollection><VirtualSystem ovf:id",
+			"</VirtualSystem>", "</VirtualSystem></VirtualSystemCollection>"}, "several virtual systems"},
+		{"compressed", []string{`ovf:id="file1"`, `ovf:id="file1" ovf:compression="gzip"`}, "compressed"},
+		{"chunked", []string{`ovf:id="file2"`, `ovf:id="file2" ovf:chunkSize="1000"`}, "chunked"},
+		{"missing disk", []string{"ovf:/disk/vmdisk2", "ovf:/disk/vmdisk3"}, "no disk vmdisk3"},
+		{"missing file", []string{`ovf:fileRef="file2"`, `ovf:fileRef="file3"`}, "no file file3"},
+		{"empty disk", []string{`ovf:fileRef="file2"`, ``}, "empty"},
+		{"bad capacity", []string{`ovf:capacity="16"`, `ovf:capacity="lots"`}, "invalid capacity"},
+		{"bad units", []string{"byte * 2^30</rasd:AllocationUnits>", "furlongs</rasd:AllocationUnits>"}, "allocation units"},
+		{"no memory", []string{"<rasd:ResourceType>4</rasd:ResourceType>", "<rasd:ResourceType>5</rasd:ResourceType>"}, "no memory"},
+		{"no disks", []string{"<rasd:ResourceType>17</rasd:ResourceType>", "<rasd:ResourceType>15</rasd:ResourceType>"}, "no disks"},
+		{"invalid xml", []string{"</Envelope>", ""}, "invalid OVF"},
+	}
+	for _, test := range tests {
+		descriptor := strings.NewReplacer(test.replace...).Replace(vsphereOVF)
+		_, err := ParseOVF(strings.NewReader(descriptor))
+		if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("%s: got error %v, want %q", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestParseOVFFileResources(t *testing.T) {
+	descriptor := strings.NewReplacer(
+		"ovf:/disk/vmdisk1", "ovf:/file/file1",
+		"ovf:/disk/vmdisk2", "ovf:/file/file1",
+	).Replace(vsphereOVF)
+	manifest, err := ParseOVF(strings.NewReader(descriptor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Disks named alike are told apart
+	if len(manifest.Disks) != 2 || manifest.Disks[0].Name != "file1" || manifest.Disks[1].Name != "file1-1" {
+		t.Errorf("got disks %+v", manifest.Disks)
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		units   string
+		want    int64
+		wantErr bool
+	}{
+		{"", 1, false},
+		{"byte", 1, false},
+		{"KB", 1 << 10, false},
+		{"megabytes", 1 << 20, false},
+		{"GB", 1 << 30, false},
+		{"byte * 2^20", 1 << 20, false},
+		{"byte*2^30", 1 << 30, false},
+		{" byte * 10^3 ", 1000, false},
+		{"byte * 2^60", 0, true},
+		{"byte * 0^1", 0, true},
+		{"hertz * 10^6", 0, true},
+		{"bytes * 2^20", 0, true},
+	}
+	for _, test := range tests {
+		got, err := parseUnits(test.units)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("parseUnits(%q) = %d, %v, want %d, error %v", test.units, got, err, test.want, test.wantErr)
+		}
+	}
+}
+
+func TestDNSLabel(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Web Server", "web-server"},
+		{"--db_01--", "db-01"},
+		{"日本", "vm"},
+		{strings.Repeat("a", 50), strings.Repeat("a", 40)},
+	}
+	for _, test := range tests {
+		if got := dnsLabel(test.name, "vm"); got != test.want {
+			t.Errorf("dnsLabel(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestOVFRoundTrip(t *testing.T) {
+	manifest := &Manifest{
+		VirtualMachineName: "vm1",
+		VirtualMachineSpec: vmapi.VirtualMachineSpec{CpuMillis: 1500, MemoryMB: 2048},
+		Disks: []Disk{
+			{Name: "root", File: "root.vmdk", CapacityBytes: 10 << 30, SizeBytes: 1 << 20},
+			{Name: "data", File: "data.vmdk", CapacityBytes: 1 << 30, SizeBytes: 1 << 10},
+		},
+	}
+	descriptor, err := OVF(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseOVF(bytes.NewReader(descriptor))
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, descriptor)
+	}
+	if parsed.VirtualMachineName != "vm1" {
+		t.Errorf("got name %q", parsed.VirtualMachineName)
+	}
+	// Guests get whole vCPUs
+	if parsed.VirtualMachineSpec.CpuMillis != 2000 || parsed.VirtualMachineSpec.MemoryMB != 2048 {
+		t.Errorf("got cpu_milli %d and memory_mb %d", parsed.VirtualMachineSpec.CpuMillis, parsed.VirtualMachineSpec.MemoryMB)
+	}
+	if len(parsed.Disks) != 2 {
+		t.Fatalf("got disks %+v", parsed.Disks)
+	}
+	for i, disk := range parsed.Disks {
+		want := manifest.Disks[i]
+		want.SizeBytes = 0
+		if disk != want {
+			t.Errorf("got disk %+v, want %+v", disk, want)
+		}
+	}
+
+	if _, err := OVF(&Manifest{VirtualMachineName: "vm1"}); err == nil {
+		t.Error("got no error for a vm without size")
+	}
+}
@@ -29,6 +29,62 @@ func (c *FakeVirtualmachineV1alpha1) VirtualMachines(namespace string) v1alpha1.
 	return &FakeVirtualMachines{c, namespace}
 }
 
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineBackups(namespace string) v1alpha1.VirtualMachineBackupInterface {
+	return &FakeVirtualMachineBackups{c, namespace}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineBackupRestores(namespace string) v1alpha1.VirtualMachineBackupRestoreInterface {
+	return &FakeVirtualMachineBackupRestores{c, namespace}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineBackupTargets(namespace string) v1alpha1.VirtualMachineBackupTargetInterface {
+	return &FakeVirtualMachineBackupTargets{c, namespace}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineClones(namespace string) v1alpha1.VirtualMachineCloneInterface {
+	return &FakeVirtualMachineClones{c, namespace}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineExports(namespace string) v1alpha1.VirtualMachineExportInterface {
+	return &FakeVirtualMachineExports{c, namespace}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineImageUploads(namespace string) v1alpha1.VirtualMachineImageUploadInterface {
+	return &FakeVirtualMachineImageUploads{c, namespace}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineImports(namespace string) v1alpha1.VirtualMachineImportInterface {
+	return &FakeVirtualMachineImports{c, namespace}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineInstanceTypes() v1alpha1.VirtualMachineInstanceTypeInterface {
+	return &FakeVirtualMachineInstanceTypes{c}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineMigrations(namespace string) v1alpha1.VirtualMachineMigrationInterface {
+	return &FakeVirtualMachineMigrations{c, namespace}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachinePreferences(namespace string) v1alpha1.VirtualMachinePreferenceInterface {
+	return &FakeVirtualMachinePreferences{c, namespace}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineQuotas(namespace string) v1alpha1.VirtualMachineQuotaInterface {
+	return &FakeVirtualMachineQuotas{c, namespace}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineRestores(namespace string) v1alpha1.VirtualMachineRestoreInterface {
+	return &FakeVirtualMachineRestores{c, namespace}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineSnapshots(namespace string) v1alpha1.VirtualMachineSnapshotInterface {
+	return &FakeVirtualMachineSnapshots{c, namespace}
+}
+
+func (c *FakeVirtualmachineV1alpha1) VirtualMachineVolumeAttachments(namespace string) v1alpha1.VirtualMachineVolumeAttachmentInterface {
+	return &FakeVirtualMachineVolumeAttachments{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeVirtualmachineV1alpha1) RESTClient() rest.Interface {
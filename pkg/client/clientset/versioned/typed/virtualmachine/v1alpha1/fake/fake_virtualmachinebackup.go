@@ -0,0 +1,125 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVirtualMachineBackups implements VirtualMachineBackupInterface
+type FakeVirtualMachineBackups struct {
+	Fake *FakeVirtualmachineV1alpha1
+	ns   string
+}
+
+var virtualmachinebackupsResource = schema.GroupVersionResource{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Resource: "virtualmachinebackups"}
+
+var virtualmachinebackupsKind = schema.GroupVersionKind{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Kind: "VirtualMachineBackup"}
+
+// Get takes name of the virtualMachineBackup, and returns the corresponding virtualMachineBackup object, and an error if there is any.
+func (c *FakeVirtualMachineBackups) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(virtualmachinebackupsResource, c.ns, name), &v1alpha1.VirtualMachineBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineBackup), err
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineBackups that match those selectors.
+func (c *FakeVirtualMachineBackups) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineBackupList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(virtualmachinebackupsResource, virtualmachinebackupsKind, c.ns, opts), &v1alpha1.VirtualMachineBackupList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VirtualMachineBackupList{}
+	for _, item := range obj.(*v1alpha1.VirtualMachineBackupList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineBackups.
+func (c *FakeVirtualMachineBackups) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(virtualmachinebackupsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a virtualMachineBackup and creates it.  Returns the server's representation of the virtualMachineBackup, and an error, if there is any.
+func (c *FakeVirtualMachineBackups) Create(virtualMachineBackup *v1alpha1.VirtualMachineBackup) (result *v1alpha1.VirtualMachineBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(virtualmachinebackupsResource, c.ns, virtualMachineBackup), &v1alpha1.VirtualMachineBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineBackup), err
+}
+
+// Update takes the representation of a virtualMachineBackup and updates it. Returns the server's representation of the virtualMachineBackup, and an error, if there is any.
+func (c *FakeVirtualMachineBackups) Update(virtualMachineBackup *v1alpha1.VirtualMachineBackup) (result *v1alpha1.VirtualMachineBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(virtualmachinebackupsResource, c.ns, virtualMachineBackup), &v1alpha1.VirtualMachineBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineBackup), err
+}
+
+// Delete takes name of the virtualMachineBackup and deletes it. Returns an error if one occurs.
+func (c *FakeVirtualMachineBackups) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(virtualmachinebackupsResource, c.ns, name), &v1alpha1.VirtualMachineBackup{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVirtualMachineBackups) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(virtualmachinebackupsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VirtualMachineBackupList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched virtualMachineBackup.
+func (c *FakeVirtualMachineBackups) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(virtualmachinebackupsResource, c.ns, name, data, subresources...), &v1alpha1.VirtualMachineBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineBackup), err
+}
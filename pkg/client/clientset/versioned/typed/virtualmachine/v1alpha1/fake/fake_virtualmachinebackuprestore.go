@@ -0,0 +1,125 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVirtualMachineBackupRestores implements VirtualMachineBackupRestoreInterface
+type FakeVirtualMachineBackupRestores struct {
+	Fake *FakeVirtualmachineV1alpha1
+	ns   string
+}
+
+var virtualmachinebackuprestoresResource = schema.GroupVersionResource{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Resource: "virtualmachinebackuprestores"}
+
+var virtualmachinebackuprestoresKind = schema.GroupVersionKind{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Kind: "VirtualMachineBackupRestore"}
+
+// Get takes name of the virtualMachineBackupRestore, and returns the corresponding virtualMachineBackupRestore object, and an error if there is any.
+func (c *FakeVirtualMachineBackupRestores) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineBackupRestore, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(virtualmachinebackuprestoresResource, c.ns, name), &v1alpha1.VirtualMachineBackupRestore{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineBackupRestore), err
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineBackupRestores that match those selectors.
+func (c *FakeVirtualMachineBackupRestores) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineBackupRestoreList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(virtualmachinebackuprestoresResource, virtualmachinebackuprestoresKind, c.ns, opts), &v1alpha1.VirtualMachineBackupRestoreList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VirtualMachineBackupRestoreList{}
+	for _, item := range obj.(*v1alpha1.VirtualMachineBackupRestoreList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineBackupRestores.
+func (c *FakeVirtualMachineBackupRestores) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(virtualmachinebackuprestoresResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a virtualMachineBackupRestore and creates it.  Returns the server's representation of the virtualMachineBackupRestore, and an error, if there is any.
+func (c *FakeVirtualMachineBackupRestores) Create(virtualMachineBackupRestore *v1alpha1.VirtualMachineBackupRestore) (result *v1alpha1.VirtualMachineBackupRestore, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(virtualmachinebackuprestoresResource, c.ns, virtualMachineBackupRestore), &v1alpha1.VirtualMachineBackupRestore{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineBackupRestore), err
+}
+
+// Update takes the representation of a virtualMachineBackupRestore and updates it. Returns the server's representation of the virtualMachineBackupRestore, and an error, if there is any.
+func (c *FakeVirtualMachineBackupRestores) Update(virtualMachineBackupRestore *v1alpha1.VirtualMachineBackupRestore) (result *v1alpha1.VirtualMachineBackupRestore, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(virtualmachinebackuprestoresResource, c.ns, virtualMachineBackupRestore), &v1alpha1.VirtualMachineBackupRestore{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineBackupRestore), err
+}
+
+// Delete takes name of the virtualMachineBackupRestore and deletes it. Returns an error if one occurs.
+func (c *FakeVirtualMachineBackupRestores) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(virtualmachinebackuprestoresResource, c.ns, name), &v1alpha1.VirtualMachineBackupRestore{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVirtualMachineBackupRestores) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(virtualmachinebackuprestoresResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VirtualMachineBackupRestoreList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched virtualMachineBackupRestore.
+func (c *FakeVirtualMachineBackupRestores) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineBackupRestore, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(virtualmachinebackuprestoresResource, c.ns, name, data, subresources...), &v1alpha1.VirtualMachineBackupRestore{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineBackupRestore), err
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVirtualMachineBackupTargets implements VirtualMachineBackupTargetInterface
+type FakeVirtualMachineBackupTargets struct {
+	Fake *FakeVirtualmachineV1alpha1
+	ns   string
+}
+
+var virtualmachinebackuptargetsResource = schema.GroupVersionResource{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Resource: "virtualmachinebackuptargets"}
+
+var virtualmachinebackuptargetsKind = schema.GroupVersionKind{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Kind: "VirtualMachineBackupTarget"}
+
+// Get takes name of the virtualMachineBackupTarget, and returns the corresponding virtualMachineBackupTarget object, and an error if there is any.
+func (c *FakeVirtualMachineBackupTargets) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineBackupTarget, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(virtualmachinebackuptargetsResource, c.ns, name), &v1alpha1.VirtualMachineBackupTarget{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineBackupTarget), err
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineBackupTargets that match those selectors.
+func (c *FakeVirtualMachineBackupTargets) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineBackupTargetList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(virtualmachinebackuptargetsResource, virtualmachinebackuptargetsKind, c.ns, opts), &v1alpha1.VirtualMachineBackupTargetList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VirtualMachineBackupTargetList{}
+	for _, item := range obj.(*v1alpha1.VirtualMachineBackupTargetList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineBackupTargets.
+func (c *FakeVirtualMachineBackupTargets) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(virtualmachinebackuptargetsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a virtualMachineBackupTarget and creates it.  Returns the server's representation of the virtualMachineBackupTarget, and an error, if there is any.
+func (c *FakeVirtualMachineBackupTargets) Create(virtualMachineBackupTarget *v1alpha1.VirtualMachineBackupTarget) (result *v1alpha1.VirtualMachineBackupTarget, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(virtualmachinebackuptargetsResource, c.ns, virtualMachineBackupTarget), &v1alpha1.VirtualMachineBackupTarget{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineBackupTarget), err
+}
+
+// Update takes the representation of a virtualMachineBackupTarget and updates it. Returns the server's representation of the virtualMachineBackupTarget, and an error, if there is any.
+func (c *FakeVirtualMachineBackupTargets) Update(virtualMachineBackupTarget *v1alpha1.VirtualMachineBackupTarget) (result *v1alpha1.VirtualMachineBackupTarget, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(virtualmachinebackuptargetsResource, c.ns, virtualMachineBackupTarget), &v1alpha1.VirtualMachineBackupTarget{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineBackupTarget), err
+}
+
+// Delete takes name of the virtualMachineBackupTarget and deletes it. Returns an error if one occurs.
+func (c *FakeVirtualMachineBackupTargets) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(virtualmachinebackuptargetsResource, c.ns, name), &v1alpha1.VirtualMachineBackupTarget{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVirtualMachineBackupTargets) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(virtualmachinebackuptargetsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VirtualMachineBackupTargetList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched virtualMachineBackupTarget.
+func (c *FakeVirtualMachineBackupTargets) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineBackupTarget, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(virtualmachinebackuptargetsResource, c.ns, name, data, subresources...), &v1alpha1.VirtualMachineBackupTarget{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineBackupTarget), err
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVirtualMachineClones implements VirtualMachineCloneInterface
+type FakeVirtualMachineClones struct {
+	Fake *FakeVirtualmachineV1alpha1
+	ns   string
+}
+
+var virtualmachineclonesResource = schema.GroupVersionResource{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Resource: "virtualmachineclones"}
+
+var virtualmachineclonesKind = schema.GroupVersionKind{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Kind: "VirtualMachineClone"}
+
+// Get takes name of the virtualMachineClone, and returns the corresponding virtualMachineClone object, and an error if there is any.
+func (c *FakeVirtualMachineClones) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineClone, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(virtualmachineclonesResource, c.ns, name), &v1alpha1.VirtualMachineClone{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineClone), err
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineClones that match those selectors.
+func (c *FakeVirtualMachineClones) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineCloneList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(virtualmachineclonesResource, virtualmachineclonesKind, c.ns, opts), &v1alpha1.VirtualMachineCloneList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VirtualMachineCloneList{}
+	for _, item := range obj.(*v1alpha1.VirtualMachineCloneList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineClones.
+func (c *FakeVirtualMachineClones) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(virtualmachineclonesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a virtualMachineClone and creates it.  Returns the server's representation of the virtualMachineClone, and an error, if there is any.
+func (c *FakeVirtualMachineClones) Create(virtualMachineClone *v1alpha1.VirtualMachineClone) (result *v1alpha1.VirtualMachineClone, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(virtualmachineclonesResource, c.ns, virtualMachineClone), &v1alpha1.VirtualMachineClone{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineClone), err
+}
+
+// Update takes the representation of a virtualMachineClone and updates it. Returns the server's representation of the virtualMachineClone, and an error, if there is any.
+func (c *FakeVirtualMachineClones) Update(virtualMachineClone *v1alpha1.VirtualMachineClone) (result *v1alpha1.VirtualMachineClone, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(virtualmachineclonesResource, c.ns, virtualMachineClone), &v1alpha1.VirtualMachineClone{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineClone), err
+}
+
+// Delete takes name of the virtualMachineClone and deletes it. Returns an error if one occurs.
+func (c *FakeVirtualMachineClones) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(virtualmachineclonesResource, c.ns, name), &v1alpha1.VirtualMachineClone{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVirtualMachineClones) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(virtualmachineclonesResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VirtualMachineCloneList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched virtualMachineClone.
+func (c *FakeVirtualMachineClones) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineClone, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(virtualmachineclonesResource, c.ns, name, data, subresources...), &v1alpha1.VirtualMachineClone{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineClone), err
+}
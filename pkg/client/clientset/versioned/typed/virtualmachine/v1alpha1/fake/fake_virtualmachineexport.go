@@ -0,0 +1,125 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVirtualMachineExports implements VirtualMachineExportInterface
+type FakeVirtualMachineExports struct {
+	Fake *FakeVirtualmachineV1alpha1
+	ns   string
+}
+
+var virtualmachineexportsResource = schema.GroupVersionResource{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Resource: "virtualmachineexports"}
+
+var virtualmachineexportsKind = schema.GroupVersionKind{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Kind: "VirtualMachineExport"}
+
+// Get takes name of the virtualMachineExport, and returns the corresponding virtualMachineExport object, and an error if there is any.
+func (c *FakeVirtualMachineExports) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineExport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(virtualmachineexportsResource, c.ns, name), &v1alpha1.VirtualMachineExport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineExport), err
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineExports that match those selectors.
+func (c *FakeVirtualMachineExports) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineExportList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(virtualmachineexportsResource, virtualmachineexportsKind, c.ns, opts), &v1alpha1.VirtualMachineExportList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VirtualMachineExportList{}
+	for _, item := range obj.(*v1alpha1.VirtualMachineExportList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineExports.
+func (c *FakeVirtualMachineExports) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(virtualmachineexportsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a virtualMachineExport and creates it.  Returns the server's representation of the virtualMachineExport, and an error, if there is any.
+func (c *FakeVirtualMachineExports) Create(virtualMachineExport *v1alpha1.VirtualMachineExport) (result *v1alpha1.VirtualMachineExport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(virtualmachineexportsResource, c.ns, virtualMachineExport), &v1alpha1.VirtualMachineExport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineExport), err
+}
+
+// Update takes the representation of a virtualMachineExport and updates it. Returns the server's representation of the virtualMachineExport, and an error, if there is any.
+func (c *FakeVirtualMachineExports) Update(virtualMachineExport *v1alpha1.VirtualMachineExport) (result *v1alpha1.VirtualMachineExport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(virtualmachineexportsResource, c.ns, virtualMachineExport), &v1alpha1.VirtualMachineExport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineExport), err
+}
+
+// Delete takes name of the virtualMachineExport and deletes it. Returns an error if one occurs.
+func (c *FakeVirtualMachineExports) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(virtualmachineexportsResource, c.ns, name), &v1alpha1.VirtualMachineExport{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVirtualMachineExports) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(virtualmachineexportsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VirtualMachineExportList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched virtualMachineExport.
+func (c *FakeVirtualMachineExports) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineExport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(virtualmachineexportsResource, c.ns, name, data, subresources...), &v1alpha1.VirtualMachineExport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineExport), err
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVirtualMachineImageUploads implements VirtualMachineImageUploadInterface
+type FakeVirtualMachineImageUploads struct {
+	Fake *FakeVirtualmachineV1alpha1
+	ns   string
+}
+
+var virtualmachineimageuploadsResource = schema.GroupVersionResource{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Resource: "virtualmachineimageuploads"}
+
+var virtualmachineimageuploadsKind = schema.GroupVersionKind{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Kind: "VirtualMachineImageUpload"}
+
+// Get takes name of the virtualMachineImageUpload, and returns the corresponding virtualMachineImageUpload object, and an error if there is any.
+func (c *FakeVirtualMachineImageUploads) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineImageUpload, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(virtualmachineimageuploadsResource, c.ns, name), &v1alpha1.VirtualMachineImageUpload{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineImageUpload), err
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineImageUploads that match those selectors.
+func (c *FakeVirtualMachineImageUploads) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineImageUploadList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(virtualmachineimageuploadsResource, virtualmachineimageuploadsKind, c.ns, opts), &v1alpha1.VirtualMachineImageUploadList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VirtualMachineImageUploadList{}
+	for _, item := range obj.(*v1alpha1.VirtualMachineImageUploadList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineImageUploads.
+func (c *FakeVirtualMachineImageUploads) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(virtualmachineimageuploadsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a virtualMachineImageUpload and creates it.  Returns the server's representation of the virtualMachineImageUpload, and an error, if there is any.
+func (c *FakeVirtualMachineImageUploads) Create(virtualMachineImageUpload *v1alpha1.VirtualMachineImageUpload) (result *v1alpha1.VirtualMachineImageUpload, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(virtualmachineimageuploadsResource, c.ns, virtualMachineImageUpload), &v1alpha1.VirtualMachineImageUpload{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineImageUpload), err
+}
+
+// Update takes the representation of a virtualMachineImageUpload and updates it. Returns the server's representation of the virtualMachineImageUpload, and an error, if there is any.
+func (c *FakeVirtualMachineImageUploads) Update(virtualMachineImageUpload *v1alpha1.VirtualMachineImageUpload) (result *v1alpha1.VirtualMachineImageUpload, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(virtualmachineimageuploadsResource, c.ns, virtualMachineImageUpload), &v1alpha1.VirtualMachineImageUpload{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineImageUpload), err
+}
+
+// Delete takes name of the virtualMachineImageUpload and deletes it. Returns an error if one occurs.
+func (c *FakeVirtualMachineImageUploads) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(virtualmachineimageuploadsResource, c.ns, name), &v1alpha1.VirtualMachineImageUpload{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVirtualMachineImageUploads) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(virtualmachineimageuploadsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VirtualMachineImageUploadList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched virtualMachineImageUpload.
+func (c *FakeVirtualMachineImageUploads) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineImageUpload, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(virtualmachineimageuploadsResource, c.ns, name, data, subresources...), &v1alpha1.VirtualMachineImageUpload{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineImageUpload), err
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVirtualMachineImports implements VirtualMachineImportInterface
+type FakeVirtualMachineImports struct {
+	Fake *FakeVirtualmachineV1alpha1
+	ns   string
+}
+
+var virtualmachineimportsResource = schema.GroupVersionResource{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Resource: "virtualmachineimports"}
+
+var virtualmachineimportsKind = schema.GroupVersionKind{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Kind: "VirtualMachineImport"}
+
+// Get takes name of the virtualMachineImport, and returns the corresponding virtualMachineImport object, and an error if there is any.
+func (c *FakeVirtualMachineImports) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineImport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(virtualmachineimportsResource, c.ns, name), &v1alpha1.VirtualMachineImport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineImport), err
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineImports that match those selectors.
+func (c *FakeVirtualMachineImports) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineImportList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(virtualmachineimportsResource, virtualmachineimportsKind, c.ns, opts), &v1alpha1.VirtualMachineImportList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VirtualMachineImportList{}
+	for _, item := range obj.(*v1alpha1.VirtualMachineImportList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineImports.
+func (c *FakeVirtualMachineImports) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(virtualmachineimportsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a virtualMachineImport and creates it.  Returns the server's representation of the virtualMachineImport, and an error, if there is any.
+func (c *FakeVirtualMachineImports) Create(virtualMachineImport *v1alpha1.VirtualMachineImport) (result *v1alpha1.VirtualMachineImport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(virtualmachineimportsResource, c.ns, virtualMachineImport), &v1alpha1.VirtualMachineImport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineImport), err
+}
+
+// Update takes the representation of a virtualMachineImport and updates it. Returns the server's representation of the virtualMachineImport, and an error, if there is any.
+func (c *FakeVirtualMachineImports) Update(virtualMachineImport *v1alpha1.VirtualMachineImport) (result *v1alpha1.VirtualMachineImport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(virtualmachineimportsResource, c.ns, virtualMachineImport), &v1alpha1.VirtualMachineImport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineImport), err
+}
+
+// Delete takes name of the virtualMachineImport and deletes it. Returns an error if one occurs.
+func (c *FakeVirtualMachineImports) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(virtualmachineimportsResource, c.ns, name), &v1alpha1.VirtualMachineImport{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVirtualMachineImports) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(virtualmachineimportsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VirtualMachineImportList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched virtualMachineImport.
+func (c *FakeVirtualMachineImports) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineImport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(virtualmachineimportsResource, c.ns, name, data, subresources...), &v1alpha1.VirtualMachineImport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineImport), err
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVirtualMachineInstanceTypes implements VirtualMachineInstanceTypeInterface
+type FakeVirtualMachineInstanceTypes struct {
+	Fake *FakeVirtualmachineV1alpha1
+}
+
+var virtualmachineinstancetypesResource = schema.GroupVersionResource{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Resource: "virtualmachineinstancetypes"}
+
+var virtualmachineinstancetypesKind = schema.GroupVersionKind{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Kind: "VirtualMachineInstanceType"}
+
+// Get takes name of the virtualMachineInstanceType, and returns the corresponding virtualMachineInstanceType object, and an error if there is any.
+func (c *FakeVirtualMachineInstanceTypes) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineInstanceType, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(virtualmachineinstancetypesResource, name), &v1alpha1.VirtualMachineInstanceType{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineInstanceType), err
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineInstanceTypes that match those selectors.
+func (c *FakeVirtualMachineInstanceTypes) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineInstanceTypeList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(virtualmachineinstancetypesResource, virtualmachineinstancetypesKind, opts), &v1alpha1.VirtualMachineInstanceTypeList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VirtualMachineInstanceTypeList{}
+	for _, item := range obj.(*v1alpha1.VirtualMachineInstanceTypeList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineInstanceTypes.
+func (c *FakeVirtualMachineInstanceTypes) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(virtualmachineinstancetypesResource, opts))
+
+}
+
+// Create takes the representation of a virtualMachineInstanceType and creates it.  Returns the server's representation of the virtualMachineInstanceType, and an error, if there is any.
+func (c *FakeVirtualMachineInstanceTypes) Create(virtualMachineInstanceType *v1alpha1.VirtualMachineInstanceType) (result *v1alpha1.VirtualMachineInstanceType, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(virtualmachineinstancetypesResource, virtualMachineInstanceType), &v1alpha1.VirtualMachineInstanceType{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineInstanceType), err
+}
+
+// Update takes the representation of a virtualMachineInstanceType and updates it. Returns the server's representation of the virtualMachineInstanceType, and an error, if there is any.
+func (c *FakeVirtualMachineInstanceTypes) Update(virtualMachineInstanceType *v1alpha1.VirtualMachineInstanceType) (result *v1alpha1.VirtualMachineInstanceType, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(virtualmachineinstancetypesResource, virtualMachineInstanceType), &v1alpha1.VirtualMachineInstanceType{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineInstanceType), err
+}
+
+// Delete takes name of the virtualMachineInstanceType and deletes it. Returns an error if one occurs.
+func (c *FakeVirtualMachineInstanceTypes) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(virtualmachineinstancetypesResource, name), &v1alpha1.VirtualMachineInstanceType{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVirtualMachineInstanceTypes) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(virtualmachineinstancetypesResource, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VirtualMachineInstanceTypeList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched virtualMachineInstanceType.
+func (c *FakeVirtualMachineInstanceTypes) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineInstanceType, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(virtualmachineinstancetypesResource, name, data, subresources...), &v1alpha1.VirtualMachineInstanceType{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineInstanceType), err
+}
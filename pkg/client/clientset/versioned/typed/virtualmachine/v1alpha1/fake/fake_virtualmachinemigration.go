@@ -0,0 +1,125 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVirtualMachineMigrations implements VirtualMachineMigrationInterface
+type FakeVirtualMachineMigrations struct {
+	Fake *FakeVirtualmachineV1alpha1
+	ns   string
+}
+
+var virtualmachinemigrationsResource = schema.GroupVersionResource{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Resource: "virtualmachinemigrations"}
+
+var virtualmachinemigrationsKind = schema.GroupVersionKind{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Kind: "VirtualMachineMigration"}
+
+// Get takes name of the virtualMachineMigration, and returns the corresponding virtualMachineMigration object, and an error if there is any.
+func (c *FakeVirtualMachineMigrations) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineMigration, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(virtualmachinemigrationsResource, c.ns, name), &v1alpha1.VirtualMachineMigration{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineMigration), err
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineMigrations that match those selectors.
+func (c *FakeVirtualMachineMigrations) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineMigrationList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(virtualmachinemigrationsResource, virtualmachinemigrationsKind, c.ns, opts), &v1alpha1.VirtualMachineMigrationList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VirtualMachineMigrationList{}
+	for _, item := range obj.(*v1alpha1.VirtualMachineMigrationList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineMigrations.
+func (c *FakeVirtualMachineMigrations) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(virtualmachinemigrationsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a virtualMachineMigration and creates it.  Returns the server's representation of the virtualMachineMigration, and an error, if there is any.
+func (c *FakeVirtualMachineMigrations) Create(virtualMachineMigration *v1alpha1.VirtualMachineMigration) (result *v1alpha1.VirtualMachineMigration, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(virtualmachinemigrationsResource, c.ns, virtualMachineMigration), &v1alpha1.VirtualMachineMigration{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineMigration), err
+}
+
+// Update takes the representation of a virtualMachineMigration and updates it. Returns the server's representation of the virtualMachineMigration, and an error, if there is any.
+func (c *FakeVirtualMachineMigrations) Update(virtualMachineMigration *v1alpha1.VirtualMachineMigration) (result *v1alpha1.VirtualMachineMigration, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(virtualmachinemigrationsResource, c.ns, virtualMachineMigration), &v1alpha1.VirtualMachineMigration{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineMigration), err
+}
+
+// Delete takes name of the virtualMachineMigration and deletes it. Returns an error if one occurs.
+func (c *FakeVirtualMachineMigrations) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(virtualmachinemigrationsResource, c.ns, name), &v1alpha1.VirtualMachineMigration{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVirtualMachineMigrations) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(virtualmachinemigrationsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VirtualMachineMigrationList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched virtualMachineMigration.
+func (c *FakeVirtualMachineMigrations) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineMigration, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(virtualmachinemigrationsResource, c.ns, name, data, subresources...), &v1alpha1.VirtualMachineMigration{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineMigration), err
+}
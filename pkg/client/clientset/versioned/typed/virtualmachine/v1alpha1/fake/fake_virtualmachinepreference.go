@@ -0,0 +1,125 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVirtualMachinePreferences implements VirtualMachinePreferenceInterface
+type FakeVirtualMachinePreferences struct {
+	Fake *FakeVirtualmachineV1alpha1
+	ns   string
+}
+
+var virtualmachinepreferencesResource = schema.GroupVersionResource{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Resource: "virtualmachinepreferences"}
+
+var virtualmachinepreferencesKind = schema.GroupVersionKind{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Kind: "VirtualMachinePreference"}
+
+// Get takes name of the virtualMachinePreference, and returns the corresponding virtualMachinePreference object, and an error if there is any.
+func (c *FakeVirtualMachinePreferences) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachinePreference, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(virtualmachinepreferencesResource, c.ns, name), &v1alpha1.VirtualMachinePreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachinePreference), err
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachinePreferences that match those selectors.
+func (c *FakeVirtualMachinePreferences) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachinePreferenceList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(virtualmachinepreferencesResource, virtualmachinepreferencesKind, c.ns, opts), &v1alpha1.VirtualMachinePreferenceList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VirtualMachinePreferenceList{}
+	for _, item := range obj.(*v1alpha1.VirtualMachinePreferenceList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachinePreferences.
+func (c *FakeVirtualMachinePreferences) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(virtualmachinepreferencesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a virtualMachinePreference and creates it.  Returns the server's representation of the virtualMachinePreference, and an error, if there is any.
+func (c *FakeVirtualMachinePreferences) Create(virtualMachinePreference *v1alpha1.VirtualMachinePreference) (result *v1alpha1.VirtualMachinePreference, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(virtualmachinepreferencesResource, c.ns, virtualMachinePreference), &v1alpha1.VirtualMachinePreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachinePreference), err
+}
+
+// Update takes the representation of a virtualMachinePreference and updates it. Returns the server's representation of the virtualMachinePreference, and an error, if there is any.
+func (c *FakeVirtualMachinePreferences) Update(virtualMachinePreference *v1alpha1.VirtualMachinePreference) (result *v1alpha1.VirtualMachinePreference, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(virtualmachinepreferencesResource, c.ns, virtualMachinePreference), &v1alpha1.VirtualMachinePreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachinePreference), err
+}
+
+// Delete takes name of the virtualMachinePreference and deletes it. Returns an error if one occurs.
+func (c *FakeVirtualMachinePreferences) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(virtualmachinepreferencesResource, c.ns, name), &v1alpha1.VirtualMachinePreference{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVirtualMachinePreferences) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(virtualmachinepreferencesResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VirtualMachinePreferenceList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched virtualMachinePreference.
+func (c *FakeVirtualMachinePreferences) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachinePreference, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(virtualmachinepreferencesResource, c.ns, name, data, subresources...), &v1alpha1.VirtualMachinePreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachinePreference), err
+}
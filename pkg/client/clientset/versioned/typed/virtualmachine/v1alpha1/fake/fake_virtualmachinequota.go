@@ -0,0 +1,125 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVirtualMachineQuotas implements VirtualMachineQuotaInterface
+type FakeVirtualMachineQuotas struct {
+	Fake *FakeVirtualmachineV1alpha1
+	ns   string
+}
+
+var virtualmachinequotasResource = schema.GroupVersionResource{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Resource: "virtualmachinequotas"}
+
+var virtualmachinequotasKind = schema.GroupVersionKind{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Kind: "VirtualMachineQuota"}
+
+// Get takes name of the virtualMachineQuota, and returns the corresponding virtualMachineQuota object, and an error if there is any.
+func (c *FakeVirtualMachineQuotas) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(virtualmachinequotasResource, c.ns, name), &v1alpha1.VirtualMachineQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineQuota), err
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineQuotas that match those selectors.
+func (c *FakeVirtualMachineQuotas) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineQuotaList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(virtualmachinequotasResource, virtualmachinequotasKind, c.ns, opts), &v1alpha1.VirtualMachineQuotaList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VirtualMachineQuotaList{}
+	for _, item := range obj.(*v1alpha1.VirtualMachineQuotaList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineQuotas.
+func (c *FakeVirtualMachineQuotas) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(virtualmachinequotasResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a virtualMachineQuota and creates it.  Returns the server's representation of the virtualMachineQuota, and an error, if there is any.
+func (c *FakeVirtualMachineQuotas) Create(virtualMachineQuota *v1alpha1.VirtualMachineQuota) (result *v1alpha1.VirtualMachineQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(virtualmachinequotasResource, c.ns, virtualMachineQuota), &v1alpha1.VirtualMachineQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineQuota), err
+}
+
+// Update takes the representation of a virtualMachineQuota and updates it. Returns the server's representation of the virtualMachineQuota, and an error, if there is any.
+func (c *FakeVirtualMachineQuotas) Update(virtualMachineQuota *v1alpha1.VirtualMachineQuota) (result *v1alpha1.VirtualMachineQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(virtualmachinequotasResource, c.ns, virtualMachineQuota), &v1alpha1.VirtualMachineQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineQuota), err
+}
+
+// Delete takes name of the virtualMachineQuota and deletes it. Returns an error if one occurs.
+func (c *FakeVirtualMachineQuotas) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(virtualmachinequotasResource, c.ns, name), &v1alpha1.VirtualMachineQuota{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVirtualMachineQuotas) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(virtualmachinequotasResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VirtualMachineQuotaList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched virtualMachineQuota.
+func (c *FakeVirtualMachineQuotas) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(virtualmachinequotasResource, c.ns, name, data, subresources...), &v1alpha1.VirtualMachineQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineQuota), err
+}
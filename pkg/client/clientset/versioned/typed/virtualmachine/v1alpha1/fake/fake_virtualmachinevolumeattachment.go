@@ -0,0 +1,125 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVirtualMachineVolumeAttachments implements VirtualMachineVolumeAttachmentInterface
+type FakeVirtualMachineVolumeAttachments struct {
+	Fake *FakeVirtualmachineV1alpha1
+	ns   string
+}
+
+var virtualmachinevolumeattachmentsResource = schema.GroupVersionResource{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Resource: "virtualmachinevolumeattachments"}
+
+var virtualmachinevolumeattachmentsKind = schema.GroupVersionKind{Group: "virtualmachine.rancher.com", Version: "v1alpha1", Kind: "VirtualMachineVolumeAttachment"}
+
+// Get takes name of the virtualMachineVolumeAttachment, and returns the corresponding virtualMachineVolumeAttachment object, and an error if there is any.
+func (c *FakeVirtualMachineVolumeAttachments) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineVolumeAttachment, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(virtualmachinevolumeattachmentsResource, c.ns, name), &v1alpha1.VirtualMachineVolumeAttachment{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineVolumeAttachment), err
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineVolumeAttachments that match those selectors.
+func (c *FakeVirtualMachineVolumeAttachments) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineVolumeAttachmentList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(virtualmachinevolumeattachmentsResource, virtualmachinevolumeattachmentsKind, c.ns, opts), &v1alpha1.VirtualMachineVolumeAttachmentList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VirtualMachineVolumeAttachmentList{}
+	for _, item := range obj.(*v1alpha1.VirtualMachineVolumeAttachmentList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineVolumeAttachments.
+func (c *FakeVirtualMachineVolumeAttachments) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(virtualmachinevolumeattachmentsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a virtualMachineVolumeAttachment and creates it.  Returns the server's representation of the virtualMachineVolumeAttachment, and an error, if there is any.
+func (c *FakeVirtualMachineVolumeAttachments) Create(virtualMachineVolumeAttachment *v1alpha1.VirtualMachineVolumeAttachment) (result *v1alpha1.VirtualMachineVolumeAttachment, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(virtualmachinevolumeattachmentsResource, c.ns, virtualMachineVolumeAttachment), &v1alpha1.VirtualMachineVolumeAttachment{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineVolumeAttachment), err
+}
+
+// Update takes the representation of a virtualMachineVolumeAttachment and updates it. Returns the server's representation of the virtualMachineVolumeAttachment, and an error, if there is any.
+func (c *FakeVirtualMachineVolumeAttachments) Update(virtualMachineVolumeAttachment *v1alpha1.VirtualMachineVolumeAttachment) (result *v1alpha1.VirtualMachineVolumeAttachment, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(virtualmachinevolumeattachmentsResource, c.ns, virtualMachineVolumeAttachment), &v1alpha1.VirtualMachineVolumeAttachment{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineVolumeAttachment), err
+}
+
+// Delete takes name of the virtualMachineVolumeAttachment and deletes it. Returns an error if one occurs.
+func (c *FakeVirtualMachineVolumeAttachments) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(virtualmachinevolumeattachmentsResource, c.ns, name), &v1alpha1.VirtualMachineVolumeAttachment{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVirtualMachineVolumeAttachments) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(virtualmachinevolumeattachmentsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VirtualMachineVolumeAttachmentList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched virtualMachineVolumeAttachment.
+func (c *FakeVirtualMachineVolumeAttachments) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineVolumeAttachment, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(virtualmachinevolumeattachmentsResource, c.ns, name, data, subresources...), &v1alpha1.VirtualMachineVolumeAttachment{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VirtualMachineVolumeAttachment), err
+}
@@ -16,3 +16,31 @@ limitations under the License.
 package v1alpha1
 
 type VirtualMachineExpansion interface{}
+
+type VirtualMachineBackupExpansion interface{}
+
+type VirtualMachineBackupRestoreExpansion interface{}
+
+type VirtualMachineBackupTargetExpansion interface{}
+
+type VirtualMachineCloneExpansion interface{}
+
+type VirtualMachineExportExpansion interface{}
+
+type VirtualMachineImageUploadExpansion interface{}
+
+type VirtualMachineImportExpansion interface{}
+
+type VirtualMachineInstanceTypeExpansion interface{}
+
+type VirtualMachineMigrationExpansion interface{}
+
+type VirtualMachinePreferenceExpansion interface{}
+
+type VirtualMachineQuotaExpansion interface{}
+
+type VirtualMachineRestoreExpansion interface{}
+
+type VirtualMachineSnapshotExpansion interface{}
+
+type VirtualMachineVolumeAttachmentExpansion interface{}
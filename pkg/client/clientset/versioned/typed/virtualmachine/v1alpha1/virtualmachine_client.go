@@ -25,6 +25,20 @@ import (
 type VirtualmachineV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	VirtualMachinesGetter
+	VirtualMachineBackupsGetter
+	VirtualMachineBackupRestoresGetter
+	VirtualMachineBackupTargetsGetter
+	VirtualMachineClonesGetter
+	VirtualMachineExportsGetter
+	VirtualMachineImageUploadsGetter
+	VirtualMachineImportsGetter
+	VirtualMachineInstanceTypesGetter
+	VirtualMachineMigrationsGetter
+	VirtualMachinePreferencesGetter
+	VirtualMachineQuotasGetter
+	VirtualMachineRestoresGetter
+	VirtualMachineSnapshotsGetter
+	VirtualMachineVolumeAttachmentsGetter
 }
 
 // VirtualmachineV1alpha1Client is used to interact with features provided by the virtualmachine.rancher.com group.
@@ -36,6 +50,62 @@ func (c *VirtualmachineV1alpha1Client) VirtualMachines(namespace string) Virtual
 	return newVirtualMachines(c, namespace)
 }
 
+func (c *VirtualmachineV1alpha1Client) VirtualMachineBackups(namespace string) VirtualMachineBackupInterface {
+	return newVirtualMachineBackups(c, namespace)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachineBackupRestores(namespace string) VirtualMachineBackupRestoreInterface {
+	return newVirtualMachineBackupRestores(c, namespace)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachineBackupTargets(namespace string) VirtualMachineBackupTargetInterface {
+	return newVirtualMachineBackupTargets(c, namespace)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachineClones(namespace string) VirtualMachineCloneInterface {
+	return newVirtualMachineClones(c, namespace)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachineExports(namespace string) VirtualMachineExportInterface {
+	return newVirtualMachineExports(c, namespace)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachineImageUploads(namespace string) VirtualMachineImageUploadInterface {
+	return newVirtualMachineImageUploads(c, namespace)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachineImports(namespace string) VirtualMachineImportInterface {
+	return newVirtualMachineImports(c, namespace)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachineInstanceTypes() VirtualMachineInstanceTypeInterface {
+	return newVirtualMachineInstanceTypes(c)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachineMigrations(namespace string) VirtualMachineMigrationInterface {
+	return newVirtualMachineMigrations(c, namespace)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachinePreferences(namespace string) VirtualMachinePreferenceInterface {
+	return newVirtualMachinePreferences(c, namespace)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachineQuotas(namespace string) VirtualMachineQuotaInterface {
+	return newVirtualMachineQuotas(c, namespace)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachineRestores(namespace string) VirtualMachineRestoreInterface {
+	return newVirtualMachineRestores(c, namespace)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachineSnapshots(namespace string) VirtualMachineSnapshotInterface {
+	return newVirtualMachineSnapshots(c, namespace)
+}
+
+func (c *VirtualmachineV1alpha1Client) VirtualMachineVolumeAttachments(namespace string) VirtualMachineVolumeAttachmentInterface {
+	return newVirtualMachineVolumeAttachments(c, namespace)
+}
+
 // NewForConfig creates a new VirtualmachineV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*VirtualmachineV1alpha1Client, error) {
 	config := *c
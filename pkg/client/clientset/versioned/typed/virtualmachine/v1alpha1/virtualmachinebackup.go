@@ -0,0 +1,154 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	scheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// VirtualMachineBackupsGetter has a method to return a VirtualMachineBackupInterface.
+// A group's client should implement this interface.
+type VirtualMachineBackupsGetter interface {
+	VirtualMachineBackups(namespace string) VirtualMachineBackupInterface
+}
+
+// VirtualMachineBackupInterface has methods to work with VirtualMachineBackup resources.
+type VirtualMachineBackupInterface interface {
+	Create(*v1alpha1.VirtualMachineBackup) (*v1alpha1.VirtualMachineBackup, error)
+	Update(*v1alpha1.VirtualMachineBackup) (*v1alpha1.VirtualMachineBackup, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.VirtualMachineBackup, error)
+	List(opts v1.ListOptions) (*v1alpha1.VirtualMachineBackupList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineBackup, err error)
+	VirtualMachineBackupExpansion
+}
+
+// virtualMachineBackups implements VirtualMachineBackupInterface
+type virtualMachineBackups struct {
+	client rest.Interface
+	ns     string
+}
+
+// newVirtualMachineBackups returns a VirtualMachineBackups
+func newVirtualMachineBackups(c *VirtualmachineV1alpha1Client, namespace string) *virtualMachineBackups {
+	return &virtualMachineBackups{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the virtualMachineBackup, and returns the corresponding virtualMachineBackup object, and an error if there is any.
+func (c *virtualMachineBackups) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineBackup, err error) {
+	result = &v1alpha1.VirtualMachineBackup{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinebackups").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineBackups that match those selectors.
+func (c *virtualMachineBackups) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineBackupList, err error) {
+	result = &v1alpha1.VirtualMachineBackupList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinebackups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineBackups.
+func (c *virtualMachineBackups) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinebackups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a virtualMachineBackup and creates it.  Returns the server's representation of the virtualMachineBackup, and an error, if there is any.
+func (c *virtualMachineBackups) Create(virtualMachineBackup *v1alpha1.VirtualMachineBackup) (result *v1alpha1.VirtualMachineBackup, err error) {
+	result = &v1alpha1.VirtualMachineBackup{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("virtualmachinebackups").
+		Body(virtualMachineBackup).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a virtualMachineBackup and updates it. Returns the server's representation of the virtualMachineBackup, and an error, if there is any.
+func (c *virtualMachineBackups) Update(virtualMachineBackup *v1alpha1.VirtualMachineBackup) (result *v1alpha1.VirtualMachineBackup, err error) {
+	result = &v1alpha1.VirtualMachineBackup{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("virtualmachinebackups").
+		Name(virtualMachineBackup.Name).
+		Body(virtualMachineBackup).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the virtualMachineBackup and deletes it. Returns an error if one occurs.
+func (c *virtualMachineBackups) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinebackups").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *virtualMachineBackups) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinebackups").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched virtualMachineBackup.
+func (c *virtualMachineBackups) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineBackup, err error) {
+	result = &v1alpha1.VirtualMachineBackup{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("virtualmachinebackups").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
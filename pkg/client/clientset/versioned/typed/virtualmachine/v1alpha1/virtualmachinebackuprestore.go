@@ -0,0 +1,154 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	scheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// VirtualMachineBackupRestoresGetter has a method to return a VirtualMachineBackupRestoreInterface.
+// A group's client should implement this interface.
+type VirtualMachineBackupRestoresGetter interface {
+	VirtualMachineBackupRestores(namespace string) VirtualMachineBackupRestoreInterface
+}
+
+// VirtualMachineBackupRestoreInterface has methods to work with VirtualMachineBackupRestore resources.
+type VirtualMachineBackupRestoreInterface interface {
+	Create(*v1alpha1.VirtualMachineBackupRestore) (*v1alpha1.VirtualMachineBackupRestore, error)
+	Update(*v1alpha1.VirtualMachineBackupRestore) (*v1alpha1.VirtualMachineBackupRestore, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.VirtualMachineBackupRestore, error)
+	List(opts v1.ListOptions) (*v1alpha1.VirtualMachineBackupRestoreList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineBackupRestore, err error)
+	VirtualMachineBackupRestoreExpansion
+}
+
+// virtualMachineBackupRestores implements VirtualMachineBackupRestoreInterface
+type virtualMachineBackupRestores struct {
+	client rest.Interface
+	ns     string
+}
+
+// newVirtualMachineBackupRestores returns a VirtualMachineBackupRestores
+func newVirtualMachineBackupRestores(c *VirtualmachineV1alpha1Client, namespace string) *virtualMachineBackupRestores {
+	return &virtualMachineBackupRestores{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the virtualMachineBackupRestore, and returns the corresponding virtualMachineBackupRestore object, and an error if there is any.
+func (c *virtualMachineBackupRestores) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineBackupRestore, err error) {
+	result = &v1alpha1.VirtualMachineBackupRestore{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuprestores").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineBackupRestores that match those selectors.
+func (c *virtualMachineBackupRestores) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineBackupRestoreList, err error) {
+	result = &v1alpha1.VirtualMachineBackupRestoreList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuprestores").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineBackupRestores.
+func (c *virtualMachineBackupRestores) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuprestores").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a virtualMachineBackupRestore and creates it.  Returns the server's representation of the virtualMachineBackupRestore, and an error, if there is any.
+func (c *virtualMachineBackupRestores) Create(virtualMachineBackupRestore *v1alpha1.VirtualMachineBackupRestore) (result *v1alpha1.VirtualMachineBackupRestore, err error) {
+	result = &v1alpha1.VirtualMachineBackupRestore{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuprestores").
+		Body(virtualMachineBackupRestore).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a virtualMachineBackupRestore and updates it. Returns the server's representation of the virtualMachineBackupRestore, and an error, if there is any.
+func (c *virtualMachineBackupRestores) Update(virtualMachineBackupRestore *v1alpha1.VirtualMachineBackupRestore) (result *v1alpha1.VirtualMachineBackupRestore, err error) {
+	result = &v1alpha1.VirtualMachineBackupRestore{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuprestores").
+		Name(virtualMachineBackupRestore.Name).
+		Body(virtualMachineBackupRestore).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the virtualMachineBackupRestore and deletes it. Returns an error if one occurs.
+func (c *virtualMachineBackupRestores) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuprestores").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *virtualMachineBackupRestores) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuprestores").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched virtualMachineBackupRestore.
+func (c *virtualMachineBackupRestores) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineBackupRestore, err error) {
+	result = &v1alpha1.VirtualMachineBackupRestore{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("virtualmachinebackuprestores").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
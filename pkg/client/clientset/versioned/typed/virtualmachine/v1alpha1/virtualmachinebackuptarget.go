@@ -0,0 +1,154 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	scheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// VirtualMachineBackupTargetsGetter has a method to return a VirtualMachineBackupTargetInterface.
+// A group's client should implement this interface.
+type VirtualMachineBackupTargetsGetter interface {
+	VirtualMachineBackupTargets(namespace string) VirtualMachineBackupTargetInterface
+}
+
+// VirtualMachineBackupTargetInterface has methods to work with VirtualMachineBackupTarget resources.
+type VirtualMachineBackupTargetInterface interface {
+	Create(*v1alpha1.VirtualMachineBackupTarget) (*v1alpha1.VirtualMachineBackupTarget, error)
+	Update(*v1alpha1.VirtualMachineBackupTarget) (*v1alpha1.VirtualMachineBackupTarget, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.VirtualMachineBackupTarget, error)
+	List(opts v1.ListOptions) (*v1alpha1.VirtualMachineBackupTargetList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineBackupTarget, err error)
+	VirtualMachineBackupTargetExpansion
+}
+
+// virtualMachineBackupTargets implements VirtualMachineBackupTargetInterface
+type virtualMachineBackupTargets struct {
+	client rest.Interface
+	ns     string
+}
+
+// newVirtualMachineBackupTargets returns a VirtualMachineBackupTargets
+func newVirtualMachineBackupTargets(c *VirtualmachineV1alpha1Client, namespace string) *virtualMachineBackupTargets {
+	return &virtualMachineBackupTargets{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the virtualMachineBackupTarget, and returns the corresponding virtualMachineBackupTarget object, and an error if there is any.
+func (c *virtualMachineBackupTargets) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineBackupTarget, err error) {
+	result = &v1alpha1.VirtualMachineBackupTarget{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuptargets").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineBackupTargets that match those selectors.
+func (c *virtualMachineBackupTargets) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineBackupTargetList, err error) {
+	result = &v1alpha1.VirtualMachineBackupTargetList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuptargets").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineBackupTargets.
+func (c *virtualMachineBackupTargets) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuptargets").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a virtualMachineBackupTarget and creates it.  Returns the server's representation of the virtualMachineBackupTarget, and an error, if there is any.
+func (c *virtualMachineBackupTargets) Create(virtualMachineBackupTarget *v1alpha1.VirtualMachineBackupTarget) (result *v1alpha1.VirtualMachineBackupTarget, err error) {
+	result = &v1alpha1.VirtualMachineBackupTarget{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuptargets").
+		Body(virtualMachineBackupTarget).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a virtualMachineBackupTarget and updates it. Returns the server's representation of the virtualMachineBackupTarget, and an error, if there is any.
+func (c *virtualMachineBackupTargets) Update(virtualMachineBackupTarget *v1alpha1.VirtualMachineBackupTarget) (result *v1alpha1.VirtualMachineBackupTarget, err error) {
+	result = &v1alpha1.VirtualMachineBackupTarget{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuptargets").
+		Name(virtualMachineBackupTarget.Name).
+		Body(virtualMachineBackupTarget).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the virtualMachineBackupTarget and deletes it. Returns an error if one occurs.
+func (c *virtualMachineBackupTargets) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuptargets").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *virtualMachineBackupTargets) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinebackuptargets").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched virtualMachineBackupTarget.
+func (c *virtualMachineBackupTargets) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineBackupTarget, err error) {
+	result = &v1alpha1.VirtualMachineBackupTarget{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("virtualmachinebackuptargets").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
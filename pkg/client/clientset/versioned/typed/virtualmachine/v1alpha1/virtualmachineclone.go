@@ -0,0 +1,154 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	scheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// VirtualMachineClonesGetter has a method to return a VirtualMachineCloneInterface.
+// A group's client should implement this interface.
+type VirtualMachineClonesGetter interface {
+	VirtualMachineClones(namespace string) VirtualMachineCloneInterface
+}
+
+// VirtualMachineCloneInterface has methods to work with VirtualMachineClone resources.
+type VirtualMachineCloneInterface interface {
+	Create(*v1alpha1.VirtualMachineClone) (*v1alpha1.VirtualMachineClone, error)
+	Update(*v1alpha1.VirtualMachineClone) (*v1alpha1.VirtualMachineClone, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.VirtualMachineClone, error)
+	List(opts v1.ListOptions) (*v1alpha1.VirtualMachineCloneList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineClone, err error)
+	VirtualMachineCloneExpansion
+}
+
+// virtualMachineClones implements VirtualMachineCloneInterface
+type virtualMachineClones struct {
+	client rest.Interface
+	ns     string
+}
+
+// newVirtualMachineClones returns a VirtualMachineClones
+func newVirtualMachineClones(c *VirtualmachineV1alpha1Client, namespace string) *virtualMachineClones {
+	return &virtualMachineClones{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the virtualMachineClone, and returns the corresponding virtualMachineClone object, and an error if there is any.
+func (c *virtualMachineClones) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineClone, err error) {
+	result = &v1alpha1.VirtualMachineClone{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachineclones").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineClones that match those selectors.
+func (c *virtualMachineClones) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineCloneList, err error) {
+	result = &v1alpha1.VirtualMachineCloneList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachineclones").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineClones.
+func (c *virtualMachineClones) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachineclones").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a virtualMachineClone and creates it.  Returns the server's representation of the virtualMachineClone, and an error, if there is any.
+func (c *virtualMachineClones) Create(virtualMachineClone *v1alpha1.VirtualMachineClone) (result *v1alpha1.VirtualMachineClone, err error) {
+	result = &v1alpha1.VirtualMachineClone{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("virtualmachineclones").
+		Body(virtualMachineClone).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a virtualMachineClone and updates it. Returns the server's representation of the virtualMachineClone, and an error, if there is any.
+func (c *virtualMachineClones) Update(virtualMachineClone *v1alpha1.VirtualMachineClone) (result *v1alpha1.VirtualMachineClone, err error) {
+	result = &v1alpha1.VirtualMachineClone{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("virtualmachineclones").
+		Name(virtualMachineClone.Name).
+		Body(virtualMachineClone).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the virtualMachineClone and deletes it. Returns an error if one occurs.
+func (c *virtualMachineClones) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachineclones").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *virtualMachineClones) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachineclones").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched virtualMachineClone.
+func (c *virtualMachineClones) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineClone, err error) {
+	result = &v1alpha1.VirtualMachineClone{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("virtualmachineclones").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	scheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// VirtualMachineExportsGetter has a method to return a VirtualMachineExportInterface.
+// A group's client should implement this interface.
+type VirtualMachineExportsGetter interface {
+	VirtualMachineExports(namespace string) VirtualMachineExportInterface
+}
+
+// VirtualMachineExportInterface has methods to work with VirtualMachineExport resources.
+type VirtualMachineExportInterface interface {
+	Create(*v1alpha1.VirtualMachineExport) (*v1alpha1.VirtualMachineExport, error)
+	Update(*v1alpha1.VirtualMachineExport) (*v1alpha1.VirtualMachineExport, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.VirtualMachineExport, error)
+	List(opts v1.ListOptions) (*v1alpha1.VirtualMachineExportList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineExport, err error)
+	VirtualMachineExportExpansion
+}
+
+// virtualMachineExports implements VirtualMachineExportInterface
+type virtualMachineExports struct {
+	client rest.Interface
+	ns     string
+}
+
+// newVirtualMachineExports returns a VirtualMachineExports
+func newVirtualMachineExports(c *VirtualmachineV1alpha1Client, namespace string) *virtualMachineExports {
+	return &virtualMachineExports{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the virtualMachineExport, and returns the corresponding virtualMachineExport object, and an error if there is any.
+func (c *virtualMachineExports) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineExport, err error) {
+	result = &v1alpha1.VirtualMachineExport{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachineexports").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineExports that match those selectors.
+func (c *virtualMachineExports) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineExportList, err error) {
+	result = &v1alpha1.VirtualMachineExportList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachineexports").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineExports.
+func (c *virtualMachineExports) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachineexports").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a virtualMachineExport and creates it.  Returns the server's representation of the virtualMachineExport, and an error, if there is any.
+func (c *virtualMachineExports) Create(virtualMachineExport *v1alpha1.VirtualMachineExport) (result *v1alpha1.VirtualMachineExport, err error) {
+	result = &v1alpha1.VirtualMachineExport{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("virtualmachineexports").
+		Body(virtualMachineExport).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a virtualMachineExport and updates it. Returns the server's representation of the virtualMachineExport, and an error, if there is any.
+func (c *virtualMachineExports) Update(virtualMachineExport *v1alpha1.VirtualMachineExport) (result *v1alpha1.VirtualMachineExport, err error) {
+	result = &v1alpha1.VirtualMachineExport{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("virtualmachineexports").
+		Name(virtualMachineExport.Name).
+		Body(virtualMachineExport).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the virtualMachineExport and deletes it. Returns an error if one occurs.
+func (c *virtualMachineExports) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachineexports").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *virtualMachineExports) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachineexports").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched virtualMachineExport.
+func (c *virtualMachineExports) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineExport, err error) {
+	result = &v1alpha1.VirtualMachineExport{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("virtualmachineexports").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	scheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// VirtualMachineImageUploadsGetter has a method to return a VirtualMachineImageUploadInterface.
+// A group's client should implement this interface.
+type VirtualMachineImageUploadsGetter interface {
+	VirtualMachineImageUploads(namespace string) VirtualMachineImageUploadInterface
+}
+
+// VirtualMachineImageUploadInterface has methods to work with VirtualMachineImageUpload resources.
+type VirtualMachineImageUploadInterface interface {
+	Create(*v1alpha1.VirtualMachineImageUpload) (*v1alpha1.VirtualMachineImageUpload, error)
+	Update(*v1alpha1.VirtualMachineImageUpload) (*v1alpha1.VirtualMachineImageUpload, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.VirtualMachineImageUpload, error)
+	List(opts v1.ListOptions) (*v1alpha1.VirtualMachineImageUploadList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineImageUpload, err error)
+	VirtualMachineImageUploadExpansion
+}
+
+// virtualMachineImageUploads implements VirtualMachineImageUploadInterface
+type virtualMachineImageUploads struct {
+	client rest.Interface
+	ns     string
+}
+
+// newVirtualMachineImageUploads returns a VirtualMachineImageUploads
+func newVirtualMachineImageUploads(c *VirtualmachineV1alpha1Client, namespace string) *virtualMachineImageUploads {
+	return &virtualMachineImageUploads{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the virtualMachineImageUpload, and returns the corresponding virtualMachineImageUpload object, and an error if there is any.
+func (c *virtualMachineImageUploads) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineImageUpload, err error) {
+	result = &v1alpha1.VirtualMachineImageUpload{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachineimageuploads").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineImageUploads that match those selectors.
+func (c *virtualMachineImageUploads) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineImageUploadList, err error) {
+	result = &v1alpha1.VirtualMachineImageUploadList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachineimageuploads").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineImageUploads.
+func (c *virtualMachineImageUploads) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachineimageuploads").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a virtualMachineImageUpload and creates it.  Returns the server's representation of the virtualMachineImageUpload, and an error, if there is any.
+func (c *virtualMachineImageUploads) Create(virtualMachineImageUpload *v1alpha1.VirtualMachineImageUpload) (result *v1alpha1.VirtualMachineImageUpload, err error) {
+	result = &v1alpha1.VirtualMachineImageUpload{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("virtualmachineimageuploads").
+		Body(virtualMachineImageUpload).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a virtualMachineImageUpload and updates it. Returns the server's representation of the virtualMachineImageUpload, and an error, if there is any.
+func (c *virtualMachineImageUploads) Update(virtualMachineImageUpload *v1alpha1.VirtualMachineImageUpload) (result *v1alpha1.VirtualMachineImageUpload, err error) {
+	result = &v1alpha1.VirtualMachineImageUpload{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("virtualmachineimageuploads").
+		Name(virtualMachineImageUpload.Name).
+		Body(virtualMachineImageUpload).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the virtualMachineImageUpload and deletes it. Returns an error if one occurs.
+func (c *virtualMachineImageUploads) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachineimageuploads").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *virtualMachineImageUploads) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachineimageuploads").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched virtualMachineImageUpload.
+func (c *virtualMachineImageUploads) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineImageUpload, err error) {
+	result = &v1alpha1.VirtualMachineImageUpload{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("virtualmachineimageuploads").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
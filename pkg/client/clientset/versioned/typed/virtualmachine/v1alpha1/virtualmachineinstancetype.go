@@ -0,0 +1,144 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	scheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// VirtualMachineInstanceTypesGetter has a method to return a VirtualMachineInstanceTypeInterface.
+// A group's client should implement this interface.
+type VirtualMachineInstanceTypesGetter interface {
+	VirtualMachineInstanceTypes() VirtualMachineInstanceTypeInterface
+}
+
+// VirtualMachineInstanceTypeInterface has methods to work with VirtualMachineInstanceType resources.
+type VirtualMachineInstanceTypeInterface interface {
+	Create(*v1alpha1.VirtualMachineInstanceType) (*v1alpha1.VirtualMachineInstanceType, error)
+	Update(*v1alpha1.VirtualMachineInstanceType) (*v1alpha1.VirtualMachineInstanceType, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.VirtualMachineInstanceType, error)
+	List(opts v1.ListOptions) (*v1alpha1.VirtualMachineInstanceTypeList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineInstanceType, err error)
+	VirtualMachineInstanceTypeExpansion
+}
+
+// virtualMachineInstanceTypes implements VirtualMachineInstanceTypeInterface
+type virtualMachineInstanceTypes struct {
+	client rest.Interface
+}
+
+// newVirtualMachineInstanceTypes returns a VirtualMachineInstanceTypes
+func newVirtualMachineInstanceTypes(c *VirtualmachineV1alpha1Client) *virtualMachineInstanceTypes {
+	return &virtualMachineInstanceTypes{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the virtualMachineInstanceType, and returns the corresponding virtualMachineInstanceType object, and an error if there is any.
+func (c *virtualMachineInstanceTypes) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineInstanceType, err error) {
+	result = &v1alpha1.VirtualMachineInstanceType{}
+	err = c.client.Get().
+		Resource("virtualmachineinstancetypes").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineInstanceTypes that match those selectors.
+func (c *virtualMachineInstanceTypes) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineInstanceTypeList, err error) {
+	result = &v1alpha1.VirtualMachineInstanceTypeList{}
+	err = c.client.Get().
+		Resource("virtualmachineinstancetypes").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineInstanceTypes.
+func (c *virtualMachineInstanceTypes) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Resource("virtualmachineinstancetypes").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a virtualMachineInstanceType and creates it.  Returns the server's representation of the virtualMachineInstanceType, and an error, if there is any.
+func (c *virtualMachineInstanceTypes) Create(virtualMachineInstanceType *v1alpha1.VirtualMachineInstanceType) (result *v1alpha1.VirtualMachineInstanceType, err error) {
+	result = &v1alpha1.VirtualMachineInstanceType{}
+	err = c.client.Post().
+		Resource("virtualmachineinstancetypes").
+		Body(virtualMachineInstanceType).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a virtualMachineInstanceType and updates it. Returns the server's representation of the virtualMachineInstanceType, and an error, if there is any.
+func (c *virtualMachineInstanceTypes) Update(virtualMachineInstanceType *v1alpha1.VirtualMachineInstanceType) (result *v1alpha1.VirtualMachineInstanceType, err error) {
+	result = &v1alpha1.VirtualMachineInstanceType{}
+	err = c.client.Put().
+		Resource("virtualmachineinstancetypes").
+		Name(virtualMachineInstanceType.Name).
+		Body(virtualMachineInstanceType).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the virtualMachineInstanceType and deletes it. Returns an error if one occurs.
+func (c *virtualMachineInstanceTypes) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("virtualmachineinstancetypes").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *virtualMachineInstanceTypes) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Resource("virtualmachineinstancetypes").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched virtualMachineInstanceType.
+func (c *virtualMachineInstanceTypes) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineInstanceType, err error) {
+	result = &v1alpha1.VirtualMachineInstanceType{}
+	err = c.client.Patch(pt).
+		Resource("virtualmachineinstancetypes").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
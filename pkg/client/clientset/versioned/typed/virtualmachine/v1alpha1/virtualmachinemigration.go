@@ -0,0 +1,154 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	scheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// VirtualMachineMigrationsGetter has a method to return a VirtualMachineMigrationInterface.
+// A group's client should implement this interface.
+type VirtualMachineMigrationsGetter interface {
+	VirtualMachineMigrations(namespace string) VirtualMachineMigrationInterface
+}
+
+// VirtualMachineMigrationInterface has methods to work with VirtualMachineMigration resources.
+type VirtualMachineMigrationInterface interface {
+	Create(*v1alpha1.VirtualMachineMigration) (*v1alpha1.VirtualMachineMigration, error)
+	Update(*v1alpha1.VirtualMachineMigration) (*v1alpha1.VirtualMachineMigration, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.VirtualMachineMigration, error)
+	List(opts v1.ListOptions) (*v1alpha1.VirtualMachineMigrationList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineMigration, err error)
+	VirtualMachineMigrationExpansion
+}
+
+// virtualMachineMigrations implements VirtualMachineMigrationInterface
+type virtualMachineMigrations struct {
+	client rest.Interface
+	ns     string
+}
+
+// newVirtualMachineMigrations returns a VirtualMachineMigrations
+func newVirtualMachineMigrations(c *VirtualmachineV1alpha1Client, namespace string) *virtualMachineMigrations {
+	return &virtualMachineMigrations{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the virtualMachineMigration, and returns the corresponding virtualMachineMigration object, and an error if there is any.
+func (c *virtualMachineMigrations) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineMigration, err error) {
+	result = &v1alpha1.VirtualMachineMigration{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinemigrations").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineMigrations that match those selectors.
+func (c *virtualMachineMigrations) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineMigrationList, err error) {
+	result = &v1alpha1.VirtualMachineMigrationList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinemigrations").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineMigrations.
+func (c *virtualMachineMigrations) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinemigrations").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a virtualMachineMigration and creates it.  Returns the server's representation of the virtualMachineMigration, and an error, if there is any.
+func (c *virtualMachineMigrations) Create(virtualMachineMigration *v1alpha1.VirtualMachineMigration) (result *v1alpha1.VirtualMachineMigration, err error) {
+	result = &v1alpha1.VirtualMachineMigration{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("virtualmachinemigrations").
+		Body(virtualMachineMigration).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a virtualMachineMigration and updates it. Returns the server's representation of the virtualMachineMigration, and an error, if there is any.
+func (c *virtualMachineMigrations) Update(virtualMachineMigration *v1alpha1.VirtualMachineMigration) (result *v1alpha1.VirtualMachineMigration, err error) {
+	result = &v1alpha1.VirtualMachineMigration{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("virtualmachinemigrations").
+		Name(virtualMachineMigration.Name).
+		Body(virtualMachineMigration).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the virtualMachineMigration and deletes it. Returns an error if one occurs.
+func (c *virtualMachineMigrations) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinemigrations").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *virtualMachineMigrations) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinemigrations").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched virtualMachineMigration.
+func (c *virtualMachineMigrations) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineMigration, err error) {
+	result = &v1alpha1.VirtualMachineMigration{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("virtualmachinemigrations").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	scheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// VirtualMachinePreferencesGetter has a method to return a VirtualMachinePreferenceInterface.
+// A group's client should implement this interface.
+type VirtualMachinePreferencesGetter interface {
+	VirtualMachinePreferences(namespace string) VirtualMachinePreferenceInterface
+}
+
+// VirtualMachinePreferenceInterface has methods to work with VirtualMachinePreference resources.
+type VirtualMachinePreferenceInterface interface {
+	Create(*v1alpha1.VirtualMachinePreference) (*v1alpha1.VirtualMachinePreference, error)
+	Update(*v1alpha1.VirtualMachinePreference) (*v1alpha1.VirtualMachinePreference, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.VirtualMachinePreference, error)
+	List(opts v1.ListOptions) (*v1alpha1.VirtualMachinePreferenceList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachinePreference, err error)
+	VirtualMachinePreferenceExpansion
+}
+
+// virtualMachinePreferences implements VirtualMachinePreferenceInterface
+type virtualMachinePreferences struct {
+	client rest.Interface
+	ns     string
+}
+
+// newVirtualMachinePreferences returns a VirtualMachinePreferences
+func newVirtualMachinePreferences(c *VirtualmachineV1alpha1Client, namespace string) *virtualMachinePreferences {
+	return &virtualMachinePreferences{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the virtualMachinePreference, and returns the corresponding virtualMachinePreference object, and an error if there is any.
+func (c *virtualMachinePreferences) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachinePreference, err error) {
+	result = &v1alpha1.VirtualMachinePreference{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinepreferences").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachinePreferences that match those selectors.
+func (c *virtualMachinePreferences) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachinePreferenceList, err error) {
+	result = &v1alpha1.VirtualMachinePreferenceList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinepreferences").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachinePreferences.
+func (c *virtualMachinePreferences) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinepreferences").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a virtualMachinePreference and creates it.  Returns the server's representation of the virtualMachinePreference, and an error, if there is any.
+func (c *virtualMachinePreferences) Create(virtualMachinePreference *v1alpha1.VirtualMachinePreference) (result *v1alpha1.VirtualMachinePreference, err error) {
+	result = &v1alpha1.VirtualMachinePreference{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("virtualmachinepreferences").
+		Body(virtualMachinePreference).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a virtualMachinePreference and updates it. Returns the server's representation of the virtualMachinePreference, and an error, if there is any.
+func (c *virtualMachinePreferences) Update(virtualMachinePreference *v1alpha1.VirtualMachinePreference) (result *v1alpha1.VirtualMachinePreference, err error) {
+	result = &v1alpha1.VirtualMachinePreference{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("virtualmachinepreferences").
+		Name(virtualMachinePreference.Name).
+		Body(virtualMachinePreference).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the virtualMachinePreference and deletes it. Returns an error if one occurs.
+func (c *virtualMachinePreferences) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinepreferences").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *virtualMachinePreferences) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinepreferences").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched virtualMachinePreference.
+func (c *virtualMachinePreferences) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachinePreference, err error) {
+	result = &v1alpha1.VirtualMachinePreference{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("virtualmachinepreferences").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
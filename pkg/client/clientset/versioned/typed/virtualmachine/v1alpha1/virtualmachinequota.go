@@ -0,0 +1,154 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	scheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// VirtualMachineQuotasGetter has a method to return a VirtualMachineQuotaInterface.
+// A group's client should implement this interface.
+type VirtualMachineQuotasGetter interface {
+	VirtualMachineQuotas(namespace string) VirtualMachineQuotaInterface
+}
+
+// VirtualMachineQuotaInterface has methods to work with VirtualMachineQuota resources.
+type VirtualMachineQuotaInterface interface {
+	Create(*v1alpha1.VirtualMachineQuota) (*v1alpha1.VirtualMachineQuota, error)
+	Update(*v1alpha1.VirtualMachineQuota) (*v1alpha1.VirtualMachineQuota, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.VirtualMachineQuota, error)
+	List(opts v1.ListOptions) (*v1alpha1.VirtualMachineQuotaList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineQuota, err error)
+	VirtualMachineQuotaExpansion
+}
+
+// virtualMachineQuotas implements VirtualMachineQuotaInterface
+type virtualMachineQuotas struct {
+	client rest.Interface
+	ns     string
+}
+
+// newVirtualMachineQuotas returns a VirtualMachineQuotas
+func newVirtualMachineQuotas(c *VirtualmachineV1alpha1Client, namespace string) *virtualMachineQuotas {
+	return &virtualMachineQuotas{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the virtualMachineQuota, and returns the corresponding virtualMachineQuota object, and an error if there is any.
+func (c *virtualMachineQuotas) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineQuota, err error) {
+	result = &v1alpha1.VirtualMachineQuota{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinequotas").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineQuotas that match those selectors.
+func (c *virtualMachineQuotas) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineQuotaList, err error) {
+	result = &v1alpha1.VirtualMachineQuotaList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinequotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineQuotas.
+func (c *virtualMachineQuotas) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinequotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a virtualMachineQuota and creates it.  Returns the server's representation of the virtualMachineQuota, and an error, if there is any.
+func (c *virtualMachineQuotas) Create(virtualMachineQuota *v1alpha1.VirtualMachineQuota) (result *v1alpha1.VirtualMachineQuota, err error) {
+	result = &v1alpha1.VirtualMachineQuota{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("virtualmachinequotas").
+		Body(virtualMachineQuota).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a virtualMachineQuota and updates it. Returns the server's representation of the virtualMachineQuota, and an error, if there is any.
+func (c *virtualMachineQuotas) Update(virtualMachineQuota *v1alpha1.VirtualMachineQuota) (result *v1alpha1.VirtualMachineQuota, err error) {
+	result = &v1alpha1.VirtualMachineQuota{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("virtualmachinequotas").
+		Name(virtualMachineQuota.Name).
+		Body(virtualMachineQuota).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the virtualMachineQuota and deletes it. Returns an error if one occurs.
+func (c *virtualMachineQuotas) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinequotas").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *virtualMachineQuotas) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinequotas").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched virtualMachineQuota.
+func (c *virtualMachineQuotas) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineQuota, err error) {
+	result = &v1alpha1.VirtualMachineQuota{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("virtualmachinequotas").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
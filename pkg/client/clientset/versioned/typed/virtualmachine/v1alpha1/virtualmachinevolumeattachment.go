@@ -0,0 +1,154 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	scheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// VirtualMachineVolumeAttachmentsGetter has a method to return a VirtualMachineVolumeAttachmentInterface.
+// A group's client should implement this interface.
+type VirtualMachineVolumeAttachmentsGetter interface {
+	VirtualMachineVolumeAttachments(namespace string) VirtualMachineVolumeAttachmentInterface
+}
+
+// VirtualMachineVolumeAttachmentInterface has methods to work with VirtualMachineVolumeAttachment resources.
+type VirtualMachineVolumeAttachmentInterface interface {
+	Create(*v1alpha1.VirtualMachineVolumeAttachment) (*v1alpha1.VirtualMachineVolumeAttachment, error)
+	Update(*v1alpha1.VirtualMachineVolumeAttachment) (*v1alpha1.VirtualMachineVolumeAttachment, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.VirtualMachineVolumeAttachment, error)
+	List(opts v1.ListOptions) (*v1alpha1.VirtualMachineVolumeAttachmentList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineVolumeAttachment, err error)
+	VirtualMachineVolumeAttachmentExpansion
+}
+
+// virtualMachineVolumeAttachments implements VirtualMachineVolumeAttachmentInterface
+type virtualMachineVolumeAttachments struct {
+	client rest.Interface
+	ns     string
+}
+
+// newVirtualMachineVolumeAttachments returns a VirtualMachineVolumeAttachments
+func newVirtualMachineVolumeAttachments(c *VirtualmachineV1alpha1Client, namespace string) *virtualMachineVolumeAttachments {
+	return &virtualMachineVolumeAttachments{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the virtualMachineVolumeAttachment, and returns the corresponding virtualMachineVolumeAttachment object, and an error if there is any.
+func (c *virtualMachineVolumeAttachments) Get(name string, options v1.GetOptions) (result *v1alpha1.VirtualMachineVolumeAttachment, err error) {
+	result = &v1alpha1.VirtualMachineVolumeAttachment{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinevolumeattachments").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of VirtualMachineVolumeAttachments that match those selectors.
+func (c *virtualMachineVolumeAttachments) List(opts v1.ListOptions) (result *v1alpha1.VirtualMachineVolumeAttachmentList, err error) {
+	result = &v1alpha1.VirtualMachineVolumeAttachmentList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinevolumeattachments").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested virtualMachineVolumeAttachments.
+func (c *virtualMachineVolumeAttachments) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("virtualmachinevolumeattachments").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a virtualMachineVolumeAttachment and creates it.  Returns the server's representation of the virtualMachineVolumeAttachment, and an error, if there is any.
+func (c *virtualMachineVolumeAttachments) Create(virtualMachineVolumeAttachment *v1alpha1.VirtualMachineVolumeAttachment) (result *v1alpha1.VirtualMachineVolumeAttachment, err error) {
+	result = &v1alpha1.VirtualMachineVolumeAttachment{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("virtualmachinevolumeattachments").
+		Body(virtualMachineVolumeAttachment).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a virtualMachineVolumeAttachment and updates it. Returns the server's representation of the virtualMachineVolumeAttachment, and an error, if there is any.
+func (c *virtualMachineVolumeAttachments) Update(virtualMachineVolumeAttachment *v1alpha1.VirtualMachineVolumeAttachment) (result *v1alpha1.VirtualMachineVolumeAttachment, err error) {
+	result = &v1alpha1.VirtualMachineVolumeAttachment{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("virtualmachinevolumeattachments").
+		Name(virtualMachineVolumeAttachment.Name).
+		Body(virtualMachineVolumeAttachment).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the virtualMachineVolumeAttachment and deletes it. Returns an error if one occurs.
+func (c *virtualMachineVolumeAttachments) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinevolumeattachments").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *virtualMachineVolumeAttachments) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("virtualmachinevolumeattachments").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched virtualMachineVolumeAttachment.
+func (c *virtualMachineVolumeAttachments) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.VirtualMachineVolumeAttachment, err error) {
+	result = &v1alpha1.VirtualMachineVolumeAttachment{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("virtualmachinevolumeattachments").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -54,6 +54,34 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=Virtualmachine, Version=V1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachines"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachines().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachinebackups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineBackups().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachinebackuprestores"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineBackupRestores().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachinebackuptargets"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineBackupTargets().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachineclones"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineClones().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachineexports"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineExports().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachineimageuploads"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineImageUploads().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachineimports"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineImports().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachineinstancetypes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineInstanceTypes().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachinemigrations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineMigrations().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachinepreferences"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachinePreferences().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachinequotas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineQuotas().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachinerestores"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineRestores().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachinesnapshots"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineSnapshots().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("virtualmachinevolumeattachments"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Virtualmachine().V1alpha1().VirtualMachineVolumeAttachments().Informer()}, nil
 
 	}
 
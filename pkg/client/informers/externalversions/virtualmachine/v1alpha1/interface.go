@@ -26,6 +26,34 @@ import (
 type Interface interface {
 	// VirtualMachines returns a VirtualMachineInformer.
 	VirtualMachines() VirtualMachineInformer
+	// VirtualMachineBackups returns a VirtualMachineBackupInformer.
+	VirtualMachineBackups() VirtualMachineBackupInformer
+	// VirtualMachineBackupRestores returns a VirtualMachineBackupRestoreInformer.
+	VirtualMachineBackupRestores() VirtualMachineBackupRestoreInformer
+	// VirtualMachineBackupTargets returns a VirtualMachineBackupTargetInformer.
+	VirtualMachineBackupTargets() VirtualMachineBackupTargetInformer
+	// VirtualMachineClones returns a VirtualMachineCloneInformer.
+	VirtualMachineClones() VirtualMachineCloneInformer
+	// VirtualMachineExports returns a VirtualMachineExportInformer.
+	VirtualMachineExports() VirtualMachineExportInformer
+	// VirtualMachineImageUploads returns a VirtualMachineImageUploadInformer.
+	VirtualMachineImageUploads() VirtualMachineImageUploadInformer
+	// VirtualMachineImports returns a VirtualMachineImportInformer.
+	VirtualMachineImports() VirtualMachineImportInformer
+	// VirtualMachineInstanceTypes returns a VirtualMachineInstanceTypeInformer.
+	VirtualMachineInstanceTypes() VirtualMachineInstanceTypeInformer
+	// VirtualMachineMigrations returns a VirtualMachineMigrationInformer.
+	VirtualMachineMigrations() VirtualMachineMigrationInformer
+	// VirtualMachinePreferences returns a VirtualMachinePreferenceInformer.
+	VirtualMachinePreferences() VirtualMachinePreferenceInformer
+	// VirtualMachineQuotas returns a VirtualMachineQuotaInformer.
+	VirtualMachineQuotas() VirtualMachineQuotaInformer
+	// VirtualMachineRestores returns a VirtualMachineRestoreInformer.
+	VirtualMachineRestores() VirtualMachineRestoreInformer
+	// VirtualMachineSnapshots returns a VirtualMachineSnapshotInformer.
+	VirtualMachineSnapshots() VirtualMachineSnapshotInformer
+	// VirtualMachineVolumeAttachments returns a VirtualMachineVolumeAttachmentInformer.
+	VirtualMachineVolumeAttachments() VirtualMachineVolumeAttachmentInformer
 }
 
 type version struct {
@@ -41,3 +69,73 @@ func New(f internalinterfaces.SharedInformerFactory) Interface {
 func (v *version) VirtualMachines() VirtualMachineInformer {
 	return &virtualMachineInformer{factory: v.SharedInformerFactory}
 }
+
+// VirtualMachineBackups returns a VirtualMachineBackupInformer.
+func (v *version) VirtualMachineBackups() VirtualMachineBackupInformer {
+	return &virtualMachineBackupInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachineBackupRestores returns a VirtualMachineBackupRestoreInformer.
+func (v *version) VirtualMachineBackupRestores() VirtualMachineBackupRestoreInformer {
+	return &virtualMachineBackupRestoreInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachineBackupTargets returns a VirtualMachineBackupTargetInformer.
+func (v *version) VirtualMachineBackupTargets() VirtualMachineBackupTargetInformer {
+	return &virtualMachineBackupTargetInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachineClones returns a VirtualMachineCloneInformer.
+func (v *version) VirtualMachineClones() VirtualMachineCloneInformer {
+	return &virtualMachineCloneInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachineExports returns a VirtualMachineExportInformer.
+func (v *version) VirtualMachineExports() VirtualMachineExportInformer {
+	return &virtualMachineExportInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachineImageUploads returns a VirtualMachineImageUploadInformer.
+func (v *version) VirtualMachineImageUploads() VirtualMachineImageUploadInformer {
+	return &virtualMachineImageUploadInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachineImports returns a VirtualMachineImportInformer.
+func (v *version) VirtualMachineImports() VirtualMachineImportInformer {
+	return &virtualMachineImportInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachineInstanceTypes returns a VirtualMachineInstanceTypeInformer.
+func (v *version) VirtualMachineInstanceTypes() VirtualMachineInstanceTypeInformer {
+	return &virtualMachineInstanceTypeInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachineMigrations returns a VirtualMachineMigrationInformer.
+func (v *version) VirtualMachineMigrations() VirtualMachineMigrationInformer {
+	return &virtualMachineMigrationInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachinePreferences returns a VirtualMachinePreferenceInformer.
+func (v *version) VirtualMachinePreferences() VirtualMachinePreferenceInformer {
+	return &virtualMachinePreferenceInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachineQuotas returns a VirtualMachineQuotaInformer.
+func (v *version) VirtualMachineQuotas() VirtualMachineQuotaInformer {
+	return &virtualMachineQuotaInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachineRestores returns a VirtualMachineRestoreInformer.
+func (v *version) VirtualMachineRestores() VirtualMachineRestoreInformer {
+	return &virtualMachineRestoreInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachineSnapshots returns a VirtualMachineSnapshotInformer.
+func (v *version) VirtualMachineSnapshots() VirtualMachineSnapshotInformer {
+	return &virtualMachineSnapshotInformer{factory: v.SharedInformerFactory}
+}
+
+// VirtualMachineVolumeAttachments returns a VirtualMachineVolumeAttachmentInformer.
+func (v *version) VirtualMachineVolumeAttachments() VirtualMachineVolumeAttachmentInformer {
+	return &virtualMachineVolumeAttachmentInformer{factory: v.SharedInformerFactory}
+}
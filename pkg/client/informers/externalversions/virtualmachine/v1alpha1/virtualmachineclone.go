@@ -0,0 +1,73 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was automatically generated by informer-gen
+
+package v1alpha1
+
+import (
+	ranchervm_v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	versioned "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/llparse/kube-crd-skel/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+	time "time"
+)
+
+// VirtualMachineCloneInformer provides access to a shared informer and lister for
+// VirtualMachineClones.
+type VirtualMachineCloneInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.VirtualMachineCloneLister
+}
+
+type virtualMachineCloneInformer struct {
+	factory internalinterfaces.SharedInformerFactory
+}
+
+// NewVirtualMachineCloneInformer constructs a new informer for VirtualMachineClone type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewVirtualMachineCloneInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				return client.VirtualmachineV1alpha1().VirtualMachineClones(namespace).List(options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				return client.VirtualmachineV1alpha1().VirtualMachineClones(namespace).Watch(options)
+			},
+		},
+		&ranchervm_v1alpha1.VirtualMachineClone{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func defaultVirtualMachineCloneInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewVirtualMachineCloneInformer(client, v1.NamespaceAll, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+}
+
+func (f *virtualMachineCloneInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&ranchervm_v1alpha1.VirtualMachineClone{}, defaultVirtualMachineCloneInformer)
+}
+
+func (f *virtualMachineCloneInformer) Lister() v1alpha1.VirtualMachineCloneLister {
+	return v1alpha1.NewVirtualMachineCloneLister(f.Informer().GetIndexer())
+}
@@ -25,3 +25,111 @@ type VirtualMachineListerExpansion interface{}
 // VirtualMachineNamespaceListerExpansion allows custom methods to be added to
 // VirtualMachineNamespaceLister.
 type VirtualMachineNamespaceListerExpansion interface{}
+
+// VirtualMachineBackupListerExpansion allows custom methods to be added to
+// VirtualMachineBackupLister.
+type VirtualMachineBackupListerExpansion interface{}
+
+// VirtualMachineBackupNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachineBackupNamespaceLister.
+type VirtualMachineBackupNamespaceListerExpansion interface{}
+
+// VirtualMachineBackupRestoreListerExpansion allows custom methods to be added to
+// VirtualMachineBackupRestoreLister.
+type VirtualMachineBackupRestoreListerExpansion interface{}
+
+// VirtualMachineBackupRestoreNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachineBackupRestoreNamespaceLister.
+type VirtualMachineBackupRestoreNamespaceListerExpansion interface{}
+
+// VirtualMachineBackupTargetListerExpansion allows custom methods to be added to
+// VirtualMachineBackupTargetLister.
+type VirtualMachineBackupTargetListerExpansion interface{}
+
+// VirtualMachineBackupTargetNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachineBackupTargetNamespaceLister.
+type VirtualMachineBackupTargetNamespaceListerExpansion interface{}
+
+// VirtualMachineCloneListerExpansion allows custom methods to be added to
+// VirtualMachineCloneLister.
+type VirtualMachineCloneListerExpansion interface{}
+
+// VirtualMachineCloneNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachineCloneNamespaceLister.
+type VirtualMachineCloneNamespaceListerExpansion interface{}
+
+// VirtualMachineExportListerExpansion allows custom methods to be added to
+// VirtualMachineExportLister.
+type VirtualMachineExportListerExpansion interface{}
+
+// VirtualMachineExportNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachineExportNamespaceLister.
+type VirtualMachineExportNamespaceListerExpansion interface{}
+
+// VirtualMachineImageUploadListerExpansion allows custom methods to be added to
+// VirtualMachineImageUploadLister.
+type VirtualMachineImageUploadListerExpansion interface{}
+
+// VirtualMachineImageUploadNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachineImageUploadNamespaceLister.
+type VirtualMachineImageUploadNamespaceListerExpansion interface{}
+
+// VirtualMachineImportListerExpansion allows custom methods to be added to
+// VirtualMachineImportLister.
+type VirtualMachineImportListerExpansion interface{}
+
+// VirtualMachineImportNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachineImportNamespaceLister.
+type VirtualMachineImportNamespaceListerExpansion interface{}
+
+// VirtualMachineInstanceTypeListerExpansion allows custom methods to be added to
+// VirtualMachineInstanceTypeLister.
+type VirtualMachineInstanceTypeListerExpansion interface{}
+
+// VirtualMachineMigrationListerExpansion allows custom methods to be added to
+// VirtualMachineMigrationLister.
+type VirtualMachineMigrationListerExpansion interface{}
+
+// VirtualMachineMigrationNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachineMigrationNamespaceLister.
+type VirtualMachineMigrationNamespaceListerExpansion interface{}
+
+// VirtualMachinePreferenceListerExpansion allows custom methods to be added to
+// VirtualMachinePreferenceLister.
+type VirtualMachinePreferenceListerExpansion interface{}
+
+// VirtualMachinePreferenceNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachinePreferenceNamespaceLister.
+type VirtualMachinePreferenceNamespaceListerExpansion interface{}
+
+// VirtualMachineQuotaListerExpansion allows custom methods to be added to
+// VirtualMachineQuotaLister.
+type VirtualMachineQuotaListerExpansion interface{}
+
+// VirtualMachineQuotaNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachineQuotaNamespaceLister.
+type VirtualMachineQuotaNamespaceListerExpansion interface{}
+
+// VirtualMachineRestoreListerExpansion allows custom methods to be added to
+// VirtualMachineRestoreLister.
+type VirtualMachineRestoreListerExpansion interface{}
+
+// VirtualMachineRestoreNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachineRestoreNamespaceLister.
+type VirtualMachineRestoreNamespaceListerExpansion interface{}
+
+// VirtualMachineSnapshotListerExpansion allows custom methods to be added to
+// VirtualMachineSnapshotLister.
+type VirtualMachineSnapshotListerExpansion interface{}
+
+// VirtualMachineSnapshotNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachineSnapshotNamespaceLister.
+type VirtualMachineSnapshotNamespaceListerExpansion interface{}
+
+// VirtualMachineVolumeAttachmentListerExpansion allows custom methods to be added to
+// VirtualMachineVolumeAttachmentLister.
+type VirtualMachineVolumeAttachmentListerExpansion interface{}
+
+// VirtualMachineVolumeAttachmentNamespaceListerExpansion allows custom methods to be added to
+// VirtualMachineVolumeAttachmentNamespaceLister.
+type VirtualMachineVolumeAttachmentNamespaceListerExpansion interface{}
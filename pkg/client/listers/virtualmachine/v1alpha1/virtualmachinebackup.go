@@ -0,0 +1,94 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was automatically generated by lister-gen
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// VirtualMachineBackupLister helps list VirtualMachineBackups.
+type VirtualMachineBackupLister interface {
+	// List lists all VirtualMachineBackups in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineBackup, err error)
+	// VirtualMachineBackups returns an object that can list and get VirtualMachineBackups.
+	VirtualMachineBackups(namespace string) VirtualMachineBackupNamespaceLister
+	VirtualMachineBackupListerExpansion
+}
+
+// virtualMachineBackupLister implements the VirtualMachineBackupLister interface.
+type virtualMachineBackupLister struct {
+	indexer cache.Indexer
+}
+
+// NewVirtualMachineBackupLister returns a new VirtualMachineBackupLister.
+func NewVirtualMachineBackupLister(indexer cache.Indexer) VirtualMachineBackupLister {
+	return &virtualMachineBackupLister{indexer: indexer}
+}
+
+// List lists all VirtualMachineBackups in the indexer.
+func (s *virtualMachineBackupLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineBackup, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineBackup))
+	})
+	return ret, err
+}
+
+// VirtualMachineBackups returns an object that can list and get VirtualMachineBackups.
+func (s *virtualMachineBackupLister) VirtualMachineBackups(namespace string) VirtualMachineBackupNamespaceLister {
+	return virtualMachineBackupNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// VirtualMachineBackupNamespaceLister helps list and get VirtualMachineBackups.
+type VirtualMachineBackupNamespaceLister interface {
+	// List lists all VirtualMachineBackups in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineBackup, err error)
+	// Get retrieves the VirtualMachineBackup from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.VirtualMachineBackup, error)
+	VirtualMachineBackupNamespaceListerExpansion
+}
+
+// virtualMachineBackupNamespaceLister implements the VirtualMachineBackupNamespaceLister
+// interface.
+type virtualMachineBackupNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all VirtualMachineBackups in the indexer for a given namespace.
+func (s virtualMachineBackupNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineBackup, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineBackup))
+	})
+	return ret, err
+}
+
+// Get retrieves the VirtualMachineBackup from the indexer for a given namespace and name.
+func (s virtualMachineBackupNamespaceLister) Get(name string) (*v1alpha1.VirtualMachineBackup, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("virtualmachinebackup"), name)
+	}
+	return obj.(*v1alpha1.VirtualMachineBackup), nil
+}
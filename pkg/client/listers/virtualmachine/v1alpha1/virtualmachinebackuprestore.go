@@ -0,0 +1,94 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was automatically generated by lister-gen
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// VirtualMachineBackupRestoreLister helps list VirtualMachineBackupRestores.
+type VirtualMachineBackupRestoreLister interface {
+	// List lists all VirtualMachineBackupRestores in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineBackupRestore, err error)
+	// VirtualMachineBackupRestores returns an object that can list and get VirtualMachineBackupRestores.
+	VirtualMachineBackupRestores(namespace string) VirtualMachineBackupRestoreNamespaceLister
+	VirtualMachineBackupRestoreListerExpansion
+}
+
+// virtualMachineBackupRestoreLister implements the VirtualMachineBackupRestoreLister interface.
+type virtualMachineBackupRestoreLister struct {
+	indexer cache.Indexer
+}
+
+// NewVirtualMachineBackupRestoreLister returns a new VirtualMachineBackupRestoreLister.
+func NewVirtualMachineBackupRestoreLister(indexer cache.Indexer) VirtualMachineBackupRestoreLister {
+	return &virtualMachineBackupRestoreLister{indexer: indexer}
+}
+
+// List lists all VirtualMachineBackupRestores in the indexer.
+func (s *virtualMachineBackupRestoreLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineBackupRestore, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineBackupRestore))
+	})
+	return ret, err
+}
+
+// VirtualMachineBackupRestores returns an object that can list and get VirtualMachineBackupRestores.
+func (s *virtualMachineBackupRestoreLister) VirtualMachineBackupRestores(namespace string) VirtualMachineBackupRestoreNamespaceLister {
+	return virtualMachineBackupRestoreNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// VirtualMachineBackupRestoreNamespaceLister helps list and get VirtualMachineBackupRestores.
+type VirtualMachineBackupRestoreNamespaceLister interface {
+	// List lists all VirtualMachineBackupRestores in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineBackupRestore, err error)
+	// Get retrieves the VirtualMachineBackupRestore from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.VirtualMachineBackupRestore, error)
+	VirtualMachineBackupRestoreNamespaceListerExpansion
+}
+
+// virtualMachineBackupRestoreNamespaceLister implements the VirtualMachineBackupRestoreNamespaceLister
+// interface.
+type virtualMachineBackupRestoreNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all VirtualMachineBackupRestores in the indexer for a given namespace.
+func (s virtualMachineBackupRestoreNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineBackupRestore, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineBackupRestore))
+	})
+	return ret, err
+}
+
+// Get retrieves the VirtualMachineBackupRestore from the indexer for a given namespace and name.
+func (s virtualMachineBackupRestoreNamespaceLister) Get(name string) (*v1alpha1.VirtualMachineBackupRestore, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("virtualmachinebackuprestore"), name)
+	}
+	return obj.(*v1alpha1.VirtualMachineBackupRestore), nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was automatically generated by lister-gen
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// VirtualMachineBackupTargetLister helps list VirtualMachineBackupTargets.
+type VirtualMachineBackupTargetLister interface {
+	// List lists all VirtualMachineBackupTargets in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineBackupTarget, err error)
+	// VirtualMachineBackupTargets returns an object that can list and get VirtualMachineBackupTargets.
+	VirtualMachineBackupTargets(namespace string) VirtualMachineBackupTargetNamespaceLister
+	VirtualMachineBackupTargetListerExpansion
+}
+
+// virtualMachineBackupTargetLister implements the VirtualMachineBackupTargetLister interface.
+type virtualMachineBackupTargetLister struct {
+	indexer cache.Indexer
+}
+
+// NewVirtualMachineBackupTargetLister returns a new VirtualMachineBackupTargetLister.
+func NewVirtualMachineBackupTargetLister(indexer cache.Indexer) VirtualMachineBackupTargetLister {
+	return &virtualMachineBackupTargetLister{indexer: indexer}
+}
+
+// List lists all VirtualMachineBackupTargets in the indexer.
+func (s *virtualMachineBackupTargetLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineBackupTarget, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineBackupTarget))
+	})
+	return ret, err
+}
+
+// VirtualMachineBackupTargets returns an object that can list and get VirtualMachineBackupTargets.
+func (s *virtualMachineBackupTargetLister) VirtualMachineBackupTargets(namespace string) VirtualMachineBackupTargetNamespaceLister {
+	return virtualMachineBackupTargetNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// VirtualMachineBackupTargetNamespaceLister helps list and get VirtualMachineBackupTargets.
+type VirtualMachineBackupTargetNamespaceLister interface {
+	// List lists all VirtualMachineBackupTargets in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineBackupTarget, err error)
+	// Get retrieves the VirtualMachineBackupTarget from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.VirtualMachineBackupTarget, error)
+	VirtualMachineBackupTargetNamespaceListerExpansion
+}
+
+// virtualMachineBackupTargetNamespaceLister implements the VirtualMachineBackupTargetNamespaceLister
+// interface.
+type virtualMachineBackupTargetNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all VirtualMachineBackupTargets in the indexer for a given namespace.
+func (s virtualMachineBackupTargetNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineBackupTarget, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineBackupTarget))
+	})
+	return ret, err
+}
+
+// Get retrieves the VirtualMachineBackupTarget from the indexer for a given namespace and name.
+func (s virtualMachineBackupTargetNamespaceLister) Get(name string) (*v1alpha1.VirtualMachineBackupTarget, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("virtualmachinebackuptarget"), name)
+	}
+	return obj.(*v1alpha1.VirtualMachineBackupTarget), nil
+}
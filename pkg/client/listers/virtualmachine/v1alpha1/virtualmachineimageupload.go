@@ -0,0 +1,94 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was automatically generated by lister-gen
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// VirtualMachineImageUploadLister helps list VirtualMachineImageUploads.
+type VirtualMachineImageUploadLister interface {
+	// List lists all VirtualMachineImageUploads in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineImageUpload, err error)
+	// VirtualMachineImageUploads returns an object that can list and get VirtualMachineImageUploads.
+	VirtualMachineImageUploads(namespace string) VirtualMachineImageUploadNamespaceLister
+	VirtualMachineImageUploadListerExpansion
+}
+
+// virtualMachineImageUploadLister implements the VirtualMachineImageUploadLister interface.
+type virtualMachineImageUploadLister struct {
+	indexer cache.Indexer
+}
+
+// NewVirtualMachineImageUploadLister returns a new VirtualMachineImageUploadLister.
+func NewVirtualMachineImageUploadLister(indexer cache.Indexer) VirtualMachineImageUploadLister {
+	return &virtualMachineImageUploadLister{indexer: indexer}
+}
+
+// List lists all VirtualMachineImageUploads in the indexer.
+func (s *virtualMachineImageUploadLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineImageUpload, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineImageUpload))
+	})
+	return ret, err
+}
+
+// VirtualMachineImageUploads returns an object that can list and get VirtualMachineImageUploads.
+func (s *virtualMachineImageUploadLister) VirtualMachineImageUploads(namespace string) VirtualMachineImageUploadNamespaceLister {
+	return virtualMachineImageUploadNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// VirtualMachineImageUploadNamespaceLister helps list and get VirtualMachineImageUploads.
+type VirtualMachineImageUploadNamespaceLister interface {
+	// List lists all VirtualMachineImageUploads in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineImageUpload, err error)
+	// Get retrieves the VirtualMachineImageUpload from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.VirtualMachineImageUpload, error)
+	VirtualMachineImageUploadNamespaceListerExpansion
+}
+
+// virtualMachineImageUploadNamespaceLister implements the VirtualMachineImageUploadNamespaceLister
+// interface.
+type virtualMachineImageUploadNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all VirtualMachineImageUploads in the indexer for a given namespace.
+func (s virtualMachineImageUploadNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineImageUpload, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineImageUpload))
+	})
+	return ret, err
+}
+
+// Get retrieves the VirtualMachineImageUpload from the indexer for a given namespace and name.
+func (s virtualMachineImageUploadNamespaceLister) Get(name string) (*v1alpha1.VirtualMachineImageUpload, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("virtualmachineimageupload"), name)
+	}
+	return obj.(*v1alpha1.VirtualMachineImageUpload), nil
+}
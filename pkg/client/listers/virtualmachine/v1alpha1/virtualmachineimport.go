@@ -0,0 +1,94 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was automatically generated by lister-gen
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// VirtualMachineImportLister helps list VirtualMachineImports.
+type VirtualMachineImportLister interface {
+	// List lists all VirtualMachineImports in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineImport, err error)
+	// VirtualMachineImports returns an object that can list and get VirtualMachineImports.
+	VirtualMachineImports(namespace string) VirtualMachineImportNamespaceLister
+	VirtualMachineImportListerExpansion
+}
+
+// virtualMachineImportLister implements the VirtualMachineImportLister interface.
+type virtualMachineImportLister struct {
+	indexer cache.Indexer
+}
+
+// NewVirtualMachineImportLister returns a new VirtualMachineImportLister.
+func NewVirtualMachineImportLister(indexer cache.Indexer) VirtualMachineImportLister {
+	return &virtualMachineImportLister{indexer: indexer}
+}
+
+// List lists all VirtualMachineImports in the indexer.
+func (s *virtualMachineImportLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineImport, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineImport))
+	})
+	return ret, err
+}
+
+// VirtualMachineImports returns an object that can list and get VirtualMachineImports.
+func (s *virtualMachineImportLister) VirtualMachineImports(namespace string) VirtualMachineImportNamespaceLister {
+	return virtualMachineImportNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// VirtualMachineImportNamespaceLister helps list and get VirtualMachineImports.
+type VirtualMachineImportNamespaceLister interface {
+	// List lists all VirtualMachineImports in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineImport, err error)
+	// Get retrieves the VirtualMachineImport from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.VirtualMachineImport, error)
+	VirtualMachineImportNamespaceListerExpansion
+}
+
+// virtualMachineImportNamespaceLister implements the VirtualMachineImportNamespaceLister
+// interface.
+type virtualMachineImportNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all VirtualMachineImports in the indexer for a given namespace.
+func (s virtualMachineImportNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineImport, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineImport))
+	})
+	return ret, err
+}
+
+// Get retrieves the VirtualMachineImport from the indexer for a given namespace and name.
+func (s virtualMachineImportNamespaceLister) Get(name string) (*v1alpha1.VirtualMachineImport, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("virtualmachineimport"), name)
+	}
+	return obj.(*v1alpha1.VirtualMachineImport), nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was automatically generated by lister-gen
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// VirtualMachineInstanceTypeLister helps list VirtualMachineInstanceTypes.
+type VirtualMachineInstanceTypeLister interface {
+	// List lists all VirtualMachineInstanceTypes in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineInstanceType, err error)
+	// Get retrieves the VirtualMachineInstanceType from the index for a given name.
+	Get(name string) (*v1alpha1.VirtualMachineInstanceType, error)
+	VirtualMachineInstanceTypeListerExpansion
+}
+
+// virtualMachineInstanceTypeLister implements the VirtualMachineInstanceTypeLister interface.
+type virtualMachineInstanceTypeLister struct {
+	indexer cache.Indexer
+}
+
+// NewVirtualMachineInstanceTypeLister returns a new VirtualMachineInstanceTypeLister.
+func NewVirtualMachineInstanceTypeLister(indexer cache.Indexer) VirtualMachineInstanceTypeLister {
+	return &virtualMachineInstanceTypeLister{indexer: indexer}
+}
+
+// List lists all VirtualMachineInstanceTypes in the indexer.
+func (s *virtualMachineInstanceTypeLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineInstanceType, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineInstanceType))
+	})
+	return ret, err
+}
+
+// Get retrieves the VirtualMachineInstanceType from the index for a given name.
+func (s *virtualMachineInstanceTypeLister) Get(name string) (*v1alpha1.VirtualMachineInstanceType, error) {
+	key := &v1alpha1.VirtualMachineInstanceType{ObjectMeta: v1.ObjectMeta{Name: name}}
+	obj, exists, err := s.indexer.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("virtualmachineinstancetype"), name)
+	}
+	return obj.(*v1alpha1.VirtualMachineInstanceType), nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was automatically generated by lister-gen
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// VirtualMachinePreferenceLister helps list VirtualMachinePreferences.
+type VirtualMachinePreferenceLister interface {
+	// List lists all VirtualMachinePreferences in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachinePreference, err error)
+	// VirtualMachinePreferences returns an object that can list and get VirtualMachinePreferences.
+	VirtualMachinePreferences(namespace string) VirtualMachinePreferenceNamespaceLister
+	VirtualMachinePreferenceListerExpansion
+}
+
+// virtualMachinePreferenceLister implements the VirtualMachinePreferenceLister interface.
+type virtualMachinePreferenceLister struct {
+	indexer cache.Indexer
+}
+
+// NewVirtualMachinePreferenceLister returns a new VirtualMachinePreferenceLister.
+func NewVirtualMachinePreferenceLister(indexer cache.Indexer) VirtualMachinePreferenceLister {
+	return &virtualMachinePreferenceLister{indexer: indexer}
+}
+
+// List lists all VirtualMachinePreferences in the indexer.
+func (s *virtualMachinePreferenceLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachinePreference, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachinePreference))
+	})
+	return ret, err
+}
+
+// VirtualMachinePreferences returns an object that can list and get VirtualMachinePreferences.
+func (s *virtualMachinePreferenceLister) VirtualMachinePreferences(namespace string) VirtualMachinePreferenceNamespaceLister {
+	return virtualMachinePreferenceNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// VirtualMachinePreferenceNamespaceLister helps list and get VirtualMachinePreferences.
+type VirtualMachinePreferenceNamespaceLister interface {
+	// List lists all VirtualMachinePreferences in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachinePreference, err error)
+	// Get retrieves the VirtualMachinePreference from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.VirtualMachinePreference, error)
+	VirtualMachinePreferenceNamespaceListerExpansion
+}
+
+// virtualMachinePreferenceNamespaceLister implements the VirtualMachinePreferenceNamespaceLister
+// interface.
+type virtualMachinePreferenceNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all VirtualMachinePreferences in the indexer for a given namespace.
+func (s virtualMachinePreferenceNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachinePreference, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachinePreference))
+	})
+	return ret, err
+}
+
+// Get retrieves the VirtualMachinePreference from the indexer for a given namespace and name.
+func (s virtualMachinePreferenceNamespaceLister) Get(name string) (*v1alpha1.VirtualMachinePreference, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("virtualmachinepreference"), name)
+	}
+	return obj.(*v1alpha1.VirtualMachinePreference), nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was automatically generated by lister-gen
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// VirtualMachineQuotaLister helps list VirtualMachineQuotas.
+type VirtualMachineQuotaLister interface {
+	// List lists all VirtualMachineQuotas in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineQuota, err error)
+	// VirtualMachineQuotas returns an object that can list and get VirtualMachineQuotas.
+	VirtualMachineQuotas(namespace string) VirtualMachineQuotaNamespaceLister
+	VirtualMachineQuotaListerExpansion
+}
+
+// virtualMachineQuotaLister implements the VirtualMachineQuotaLister interface.
+type virtualMachineQuotaLister struct {
+	indexer cache.Indexer
+}
+
+// NewVirtualMachineQuotaLister returns a new VirtualMachineQuotaLister.
+func NewVirtualMachineQuotaLister(indexer cache.Indexer) VirtualMachineQuotaLister {
+	return &virtualMachineQuotaLister{indexer: indexer}
+}
+
+// List lists all VirtualMachineQuotas in the indexer.
+func (s *virtualMachineQuotaLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineQuota, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineQuota))
+	})
+	return ret, err
+}
+
+// VirtualMachineQuotas returns an object that can list and get VirtualMachineQuotas.
+func (s *virtualMachineQuotaLister) VirtualMachineQuotas(namespace string) VirtualMachineQuotaNamespaceLister {
+	return virtualMachineQuotaNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// VirtualMachineQuotaNamespaceLister helps list and get VirtualMachineQuotas.
+type VirtualMachineQuotaNamespaceLister interface {
+	// List lists all VirtualMachineQuotas in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineQuota, err error)
+	// Get retrieves the VirtualMachineQuota from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.VirtualMachineQuota, error)
+	VirtualMachineQuotaNamespaceListerExpansion
+}
+
+// virtualMachineQuotaNamespaceLister implements the VirtualMachineQuotaNamespaceLister
+// interface.
+type virtualMachineQuotaNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all VirtualMachineQuotas in the indexer for a given namespace.
+func (s virtualMachineQuotaNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineQuota, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineQuota))
+	})
+	return ret, err
+}
+
+// Get retrieves the VirtualMachineQuota from the indexer for a given namespace and name.
+func (s virtualMachineQuotaNamespaceLister) Get(name string) (*v1alpha1.VirtualMachineQuota, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("virtualmachinequota"), name)
+	}
+	return obj.(*v1alpha1.VirtualMachineQuota), nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 Rancher Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was automatically generated by lister-gen
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// VirtualMachineVolumeAttachmentLister helps list VirtualMachineVolumeAttachments.
+type VirtualMachineVolumeAttachmentLister interface {
+	// List lists all VirtualMachineVolumeAttachments in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineVolumeAttachment, err error)
+	// VirtualMachineVolumeAttachments returns an object that can list and get VirtualMachineVolumeAttachments.
+	VirtualMachineVolumeAttachments(namespace string) VirtualMachineVolumeAttachmentNamespaceLister
+	VirtualMachineVolumeAttachmentListerExpansion
+}
+
+// virtualMachineVolumeAttachmentLister implements the VirtualMachineVolumeAttachmentLister interface.
+type virtualMachineVolumeAttachmentLister struct {
+	indexer cache.Indexer
+}
+
+// NewVirtualMachineVolumeAttachmentLister returns a new VirtualMachineVolumeAttachmentLister.
+func NewVirtualMachineVolumeAttachmentLister(indexer cache.Indexer) VirtualMachineVolumeAttachmentLister {
+	return &virtualMachineVolumeAttachmentLister{indexer: indexer}
+}
+
+// List lists all VirtualMachineVolumeAttachments in the indexer.
+func (s *virtualMachineVolumeAttachmentLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineVolumeAttachment, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineVolumeAttachment))
+	})
+	return ret, err
+}
+
+// VirtualMachineVolumeAttachments returns an object that can list and get VirtualMachineVolumeAttachments.
+func (s *virtualMachineVolumeAttachmentLister) VirtualMachineVolumeAttachments(namespace string) VirtualMachineVolumeAttachmentNamespaceLister {
+	return virtualMachineVolumeAttachmentNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// VirtualMachineVolumeAttachmentNamespaceLister helps list and get VirtualMachineVolumeAttachments.
+type VirtualMachineVolumeAttachmentNamespaceLister interface {
+	// List lists all VirtualMachineVolumeAttachments in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineVolumeAttachment, err error)
+	// Get retrieves the VirtualMachineVolumeAttachment from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.VirtualMachineVolumeAttachment, error)
+	VirtualMachineVolumeAttachmentNamespaceListerExpansion
+}
+
+// virtualMachineVolumeAttachmentNamespaceLister implements the VirtualMachineVolumeAttachmentNamespaceLister
+// interface.
+type virtualMachineVolumeAttachmentNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all VirtualMachineVolumeAttachments in the indexer for a given namespace.
+func (s virtualMachineVolumeAttachmentNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.VirtualMachineVolumeAttachment, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VirtualMachineVolumeAttachment))
+	})
+	return ret, err
+}
+
+// Get retrieves the VirtualMachineVolumeAttachment from the indexer for a given namespace and name.
+func (s virtualMachineVolumeAttachmentNamespaceLister) Get(name string) (*v1alpha1.VirtualMachineVolumeAttachment, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("virtualmachinevolumeattachment"), name)
+	}
+	return obj.(*v1alpha1.VirtualMachineVolumeAttachment), nil
+}
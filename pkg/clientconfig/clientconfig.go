@@ -0,0 +1,14 @@
+package clientconfig
+
+import (
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// NewKubeClientConfig returns the client config shared by the controller
+// and vmctl. An explicit configPath is used if set. Otherwise $KUBECONFIG and
+// ~/.kube/config are tried before falling back to the in-cluster config.
+func NewKubeClientConfig(configPath string) clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = configPath
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{})
+}
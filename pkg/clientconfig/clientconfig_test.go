@@ -0,0 +1,73 @@
+package clientconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeKubeconfig(t *testing.T, dir, name, server string) string {
+	path := filepath.Join(dir, name)
+	data := `apiVersion: v1
+kind: Config
+clusters:
+- name: c
+  cluster:
+    server: ` + server + `
+users:
+- name: u
+  user:
+    token: t
+contexts:
+- name: ctx
+  context:
+    cluster: c
+    user: u
+    namespace: vms
+current-context: ctx
+`
+	if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewKubeClientConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clientconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	explicit := writeKubeconfig(t, dir, "explicit", "https://explicit:6443")
+	env := writeKubeconfig(t, dir, "env", "https://env:6443")
+	defer os.Setenv("KUBECONFIG", os.Getenv("KUBECONFIG"))
+	os.Setenv("KUBECONFIG", env)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{explicit, "https://explicit:6443"},
+		{"", "https://env:6443"},
+	}
+	for _, test := range tests {
+		clientConfig := NewKubeClientConfig(test.path)
+		config, err := clientConfig.ClientConfig()
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.path, err)
+			continue
+		}
+		if config.Host != test.want {
+			t.Errorf("%q: got host %s, want %s", test.path, config.Host, test.want)
+		}
+		if ns, _, err := clientConfig.Namespace(); err != nil || ns != "vms" {
+			t.Errorf("%q: got namespace %q, %v", test.path, ns, err)
+		}
+	}
+
+	if _, err := NewKubeClientConfig(filepath.Join(dir, "missing")).ClientConfig(); err == nil {
+		t.Errorf("expected error for a missing explicit kube config")
+	}
+}
@@ -0,0 +1,109 @@
+package console
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+)
+
+// bearerTokenProtocolPrefix prefixes the base64url encoded bearer token in
+// a websocket subprotocol, following the apiserver's convention. Browsers
+// can't set headers on websocket requests, and tokens in query strings end
+// up in access logs.
+const bearerTokenProtocolPrefix = "base64url.bearer.authorization.k8s.io."
+
+// bearerToken returns the token from the Authorization header or the
+// websocket subprotocols
+func bearerToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	for _, header := range r.Header[http.CanonicalHeaderKey("Sec-WebSocket-Protocol")] {
+		for _, protocol := range strings.Split(header, ",") {
+			protocol = strings.TrimSpace(protocol)
+			if !strings.HasPrefix(protocol, bearerTokenProtocolPrefix) {
+				continue
+			}
+			token, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimPrefix(protocol, bearerTokenProtocolPrefix), "="))
+			if err == nil {
+				return string(token)
+			}
+		}
+	}
+	return ""
+}
+
+// allowedOrigin returns true if the request may come from its origin.
+// Browsers send the origin of the page making the request; other clients
+// don't send one and can't be driven by a foreign page.
+func (s *Server) allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range s.allowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// authorize authenticates the request's bearer token against the apiserver
+// and checks the user may perform verb on the subresource. On failure the
+// HTTP status to respond with is returned along with the error.
+func (s *Server) authorize(r *http.Request, verb, ns, resource, name, subresource string) (int, error) {
+	token := bearerToken(r)
+	if token == "" {
+		return http.StatusUnauthorized, fmt.Errorf("bearer token required")
+	}
+
+	review, err := s.kubeClient.AuthenticationV1().TokenReviews().Create(&authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token: token,
+		},
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !review.Status.Authenticated {
+		return http.StatusUnauthorized, fmt.Errorf("invalid bearer token")
+	}
+
+	user := review.Status.User
+	extra := map[string]authorizationv1.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	sar, err := s.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(&authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   ns,
+				Verb:        verb,
+				Group:       ranchervm.GroupName,
+				Resource:    resource,
+				Subresource: subresource,
+				Name:        name,
+			},
+			User:   user.Username,
+			Groups: user.Groups,
+			Extra:  extra,
+			UID:    user.UID,
+		},
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !sar.Status.Allowed {
+		return http.StatusForbidden, fmt.Errorf("user %q cannot %s %s/%s %s/%s", user.Username, verb, resource, subresource, ns, name)
+	}
+	return http.StatusOK, nil
+}
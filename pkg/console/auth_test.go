@@ -0,0 +1,79 @@
+package console
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+)
+
+func TestBearerToken(t *testing.T) {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte("secret-token"))
+	tests := []struct {
+		name   string
+		header http.Header
+		query  string
+		want   string
+	}{
+		{
+			name:   "authorization header",
+			header: http.Header{"Authorization": {"Bearer secret-token"}},
+			want:   "secret-token",
+		},
+		{
+			name:   "websocket subprotocol",
+			header: http.Header{"Sec-Websocket-Protocol": {"binary, " + bearerTokenProtocolPrefix + encoded}},
+			want:   "secret-token",
+		},
+		{
+			name:   "padded subprotocol",
+			header: http.Header{"Sec-Websocket-Protocol": {"binary", bearerTokenProtocolPrefix + base64.URLEncoding.EncodeToString([]byte("secret-token!"))}},
+			want:   "secret-token!",
+		},
+		{
+			name:   "invalid subprotocol encoding",
+			header: http.Header{"Sec-Websocket-Protocol": {bearerTokenProtocolPrefix + "not base64"}},
+		},
+		{
+			name:  "query parameter is ignored",
+			query: "token=secret-token",
+		},
+		{
+			name:   "basic auth is ignored",
+			header: http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}},
+		},
+	}
+
+	for _, test := range tests {
+		r, _ := http.NewRequest("GET", "http://console/?"+test.query, nil)
+		if test.header != nil {
+			r.Header = test.header
+		}
+		if got := bearerToken(r); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestAllowedOrigin(t *testing.T) {
+	s := &Server{allowedOrigins: []string{"https://rancher.example.com"}}
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"", true},
+		{"https://console.example.com:9500", true},
+		{"https://rancher.example.com", true},
+		{"https://evil.example.com", false},
+		{"https://console.example.com", false},
+		{"null", false},
+	}
+	for _, test := range tests {
+		r, _ := http.NewRequest("GET", "https://console.example.com:9500/", nil)
+		if test.origin != "" {
+			r.Header.Set("Origin", test.origin)
+		}
+		if got := s.allowedOrigin(r); got != test.want {
+			t.Errorf("%q: got %v, want %v", test.origin, got, test.want)
+		}
+	}
+}
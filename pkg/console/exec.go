@@ -0,0 +1,106 @@
+package console
+
+import (
+	"bytes"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// Exec runs command in the pod, connecting whichever of the streams in
+// options are set.
+func Exec(config *rest.Config, kubeClient kubernetes.Interface, pod *corev1.Pod, command []string, options remotecommand.StreamOptions) error {
+	req := kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Command: command,
+			Stdin:   options.Stdin != nil,
+			Stdout:  options.Stdout != nil,
+			Stderr:  options.Stderr != nil,
+			TTY:     options.Tty,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	return executor.Stream(options)
+}
+
+// ExecOutput runs command in the pod and returns its output. Errors include
+// whatever the command wrote to stderr.
+func ExecOutput(config *rest.Config, kubeClient kubernetes.Interface, pod *corev1.Pod, command []string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	err := Exec(config, kubeClient, pod, command, remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// GetLauncherPod returns the running pod hosting a VM
+func GetLauncherPod(kubeClient kubernetes.Interface, vm *vmapi.VirtualMachine) (*corev1.Pod, error) {
+	pods := []*corev1.Pod{}
+	// Both pods carry the VM's label during a migration; the status names
+	// the one running the guest
+	if vm.Status.PodName != "" {
+		pod, err := kubeClient.CoreV1().Pods(vm.Namespace).Get(vm.Status.PodName, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil {
+			pods = append(pods, pod)
+		}
+	} else {
+		list, err := kubeClient.CoreV1().Pods(vm.Namespace).List(metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(labels.Set{
+				ranchervm.LabelVMName: vm.Name,
+			}).String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			pods = append(pods, &list.Items[i])
+		}
+	}
+	if pod := runningPod(pods); pod != nil {
+		return pod, nil
+	}
+	return nil, fmt.Errorf("vm %s/%s is not running", vm.Namespace, vm.Name)
+}
+
+// LauncherRestarts returns how often the launcher of a pod restarted in
+// place, each time with a new guest
+func LauncherRestarts(pod *corev1.Pod) int {
+	restarts := 0
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += int(status.RestartCount)
+	}
+	return restarts
+}
+
+func runningPod(pods []*corev1.Pod) *corev1.Pod {
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			return pod
+		}
+	}
+	return nil
+}
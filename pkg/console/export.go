@@ -0,0 +1,85 @@
+package console
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+
+	"github.com/golang/glog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+// SecretServerToken is the key of the token export and upload pods require
+// of the requests proxied to them. The secret holding it is named after the
+// pod.
+const SecretServerToken = "token"
+
+// ExportPath is where the server serves the bundle of an export
+func ExportPath(export *vmapi.VirtualMachineExport) string {
+	return fmt.Sprintf("/apis/%s/namespaces/%s/virtualmachineexports/%s/download",
+		vmapi.SchemeGroupVersion.String(), export.Namespace, export.Name)
+}
+
+// serveExport proxies the download of an export's bundle to its pod
+func (s *Server) serveExport(w http.ResponseWriter, r *http.Request, ns, name string) {
+	export, err := s.exportLister.VirtualMachineExports(ns).Get(name)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			code = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	if export.Status.Phase != vmapi.ExportReady {
+		http.Error(w, fmt.Sprintf("export %s/%s is not ready", ns, name), http.StatusServiceUnavailable)
+		return
+	}
+	token, err := s.podToken(ns, export.Status.PodName)
+	if err != nil {
+		glog.V(2).Infof("error getting token of pod %s/%s: %v", ns, export.Status.PodName, err)
+		http.Error(w, fmt.Sprintf("export %s/%s is not ready", ns, name), http.StatusServiceUnavailable)
+		return
+	}
+	s.proxyToPod(w, r, ns, export.Status.PodName, launcher.ExportPort, token)
+}
+
+// podToken returns the token the named pod requires of proxied requests
+func (s *Server) podToken(ns, podName string) (string, error) {
+	secret, err := s.kubeClient.CoreV1().Secrets(ns).Get(podName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if len(secret.Data[SecretServerToken]) == 0 {
+		return "", fmt.Errorf("secret %s has no %s", secret.Name, SecretServerToken)
+	}
+	return string(secret.Data[SecretServerToken]), nil
+}
+
+// proxyToPod proxies the request to the root of the server on port of the
+// named pod, bearing the pod's token
+func (s *Server) proxyToPod(w http.ResponseWriter, r *http.Request, ns, podName string, port int, token string) {
+	pod, err := s.podLister.Pods(ns).Get(podName)
+	if err != nil || pod.Status.PodIP == "" {
+		http.Error(w, fmt.Sprintf("pod %s/%s is not running", ns, podName), http.StatusServiceUnavailable)
+		return
+	}
+
+	glog.V(3).Infof("Proxying %s %s to pod %s/%s", r.Method, r.URL.Path, ns, pod.Name)
+	proxy := httputil.NewSingleHostReverseProxy(&url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(port)),
+	})
+	// The user's bearer token is meant for us only
+	r.Header.Set("Authorization", "Bearer "+token)
+	r.URL.Path = "/"
+	r.URL.RawQuery = ""
+	proxy.ServeHTTP(w, r)
+}
@@ -0,0 +1,111 @@
+package console
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// podStream is a single forwarded connection to a port inside a pod
+type podStream struct {
+	conn   httpstream.Connection
+	data   httpstream.Stream
+	errors <-chan error
+}
+
+func (s *podStream) Read(p []byte) (int, error)  { return s.data.Read(p) }
+func (s *podStream) Write(p []byte) (int, error) { return s.data.Write(p) }
+
+// Close tears down the connection and returns any error the kubelet reported
+// while forwarding.
+func (s *podStream) Close() error {
+	s.data.Close()
+	s.conn.Close()
+	return <-s.errors
+}
+
+// DialPod opens a connection to port within the pod's network namespace via
+// the apiserver's portforward subresource.
+func DialPod(config *rest.Config, kubeClient kubernetes.Interface, pod *corev1.Pod, port int) (io.ReadWriteCloser, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, err
+	}
+	url := kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", url)
+	conn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := http.Header{}
+	headers.Set(corev1.StreamType, corev1.StreamTypeError)
+	headers.Set(corev1.PortHeader, strconv.Itoa(port))
+	headers.Set(corev1.PortForwardRequestIDHeader, "0")
+	errorStream, err := conn.CreateStream(headers)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error creating error stream for port %d: %v", port, err)
+	}
+	// we're not writing to this stream
+	errorStream.Close()
+
+	errorCh := make(chan error, 1)
+	go func() {
+		message, err := ioutil.ReadAll(errorStream)
+		switch {
+		case err != nil:
+			errorCh <- fmt.Errorf("error reading from error stream for port %d: %v", port, err)
+		case len(message) > 0:
+			errorCh <- fmt.Errorf("an error occurred forwarding port %d: %v", port, string(message))
+		}
+		close(errorCh)
+	}()
+
+	headers.Set(corev1.StreamType, corev1.StreamTypeData)
+	dataStream, err := conn.CreateStream(headers)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error creating forwarding stream for port %d: %v", port, err)
+	}
+
+	return &podStream{
+		conn:   conn,
+		data:   dataStream,
+		errors: errorCh,
+	}, nil
+}
+
+// Proxy copies between client and stream until either side is done, then
+// closes stream.
+func Proxy(client io.ReadWriter, stream io.ReadWriteCloser) {
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(client, stream)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(stream, client)
+		done <- struct{}{}
+	}()
+	<-done
+
+	if err := stream.Close(); err != nil {
+		glog.V(2).Infof("%v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package console
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"golang.org/x/net/websocket"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/remotecommand"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+// serveSerial streams the guest's serial console over a websocket. Passing
+// the log query parameter returns buffered output instead.
+func (s *Server) serveSerial(w http.ResponseWriter, r *http.Request, vm *vmapi.VirtualMachine, pod *corev1.Pod) {
+	log := r.URL.Query().Get("log") != ""
+
+	websocket.Server{
+		Handshake: negotiateBinary,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			ws.PayloadType = websocket.BinaryFrame
+
+			options := remotecommand.StreamOptions{
+				Stdout: ws,
+				Stderr: ws,
+			}
+			if !log {
+				options.Stdin = ws
+			}
+			// No TTY: the guest does its own echo and line editing
+			if err := Exec(s.config, s.kubeClient, pod, launcher.SerialCommand(log), options); err != nil {
+				glog.V(2).Infof("error streaming serial console of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+			}
+		},
+	}.ServeHTTP(w, r)
+}
@@ -0,0 +1,224 @@
+package console
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	vminformers "github.com/llparse/kube-crd-skel/pkg/client/informers/externalversions/virtualmachine/v1alpha1"
+	vmlisters "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
+)
+
+const (
+	// VNCPort is the port the launcher exposes the guest display on, within
+	// the pod's network namespace
+	VNCPort = 5900
+)
+
+// handlerFunc serves a console subresource of a running VM
+type handlerFunc func(w http.ResponseWriter, r *http.Request, vm *vmapi.VirtualMachine, pod *corev1.Pod)
+
+// Server proxies console connections to VMs, downloads of exported VMs to
+// their export pod and image uploads to their upload pod. Paths mirror the
+// apiserver's layout for subresources:
+//
+//	/apis/vm.rancher.com/v1alpha1/namespaces/<ns>/virtualmachines/<name>/<subresource>
+//	/apis/vm.rancher.com/v1alpha1/namespaces/<ns>/virtualmachineexports/<name>/download
+//	/apis/vm.rancher.com/v1alpha1/namespaces/<ns>/virtualmachineimageuploads/<name>/upload
+type Server struct {
+	config     *rest.Config
+	kubeClient kubernetes.Interface
+	// allowedOrigins may use the server from browsers besides its own
+	allowedOrigins []string
+
+	vmLister           vmlisters.VirtualMachineLister
+	vmListerSynced     cache.InformerSynced
+	exportLister       vmlisters.VirtualMachineExportLister
+	exportListerSynced cache.InformerSynced
+	uploadLister       vmlisters.VirtualMachineImageUploadLister
+	uploadListerSynced cache.InformerSynced
+	podLister          corelisters.PodLister
+	podListerSynced    cache.InformerSynced
+
+	handlers map[string]handlerFunc
+}
+
+func NewServer(
+	config *rest.Config,
+	kubeClient kubernetes.Interface,
+	allowedOrigins []string,
+	vmInformer vminformers.VirtualMachineInformer,
+	exportInformer vminformers.VirtualMachineExportInformer,
+	uploadInformer vminformers.VirtualMachineImageUploadInformer,
+	podInformer coreinformers.PodInformer,
+) *Server {
+
+	s := &Server{
+		config:             config,
+		kubeClient:         kubeClient,
+		allowedOrigins:     allowedOrigins,
+		vmLister:           vmInformer.Lister(),
+		vmListerSynced:     vmInformer.Informer().HasSynced,
+		exportLister:       exportInformer.Lister(),
+		exportListerSynced: exportInformer.Informer().HasSynced,
+		uploadLister:       uploadInformer.Lister(),
+		uploadListerSynced: uploadInformer.Informer().HasSynced,
+		podLister:          podInformer.Lister(),
+		podListerSynced:    podInformer.Informer().HasSynced,
+	}
+	s.handlers = map[string]handlerFunc{
+		"vnc":    s.serveVNC,
+		"serial": s.serveSerial,
+	}
+	return s
+}
+
+// Run serves until stopCh is closed. TLS is used if certFile and keyFile are
+// both set; bearer tokens are sent in the clear otherwise.
+func (s *Server) Run(addr, certFile, keyFile string, stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, s.vmListerSynced, s.exportListerSynced, s.uploadListerSynced, s.podListerSynced) {
+		return
+	}
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: s,
+	}
+
+	go func() {
+		<-stopCh
+		server.Close()
+	}()
+
+	glog.Infof("Starting console server on %s", addr)
+	var err error
+	if certFile != "" && keyFile != "" {
+		err = server.ListenAndServeTLS(certFile, keyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		glog.Errorf("console server failed: %v", err)
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ns, resource, name, subresource, err := parsePath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	// Pages on other sites must not drive the consoles of logged in users
+	if !s.allowedOrigin(r) {
+		http.Error(w, fmt.Sprintf("origin %q not allowed", r.Header.Get("Origin")), http.StatusForbidden)
+		return
+	}
+	if resource != "virtualmachines" {
+		var serve func(w http.ResponseWriter, r *http.Request, ns, name string)
+		var verb string
+		switch {
+		case resource == "virtualmachineexports" && subresource == "download":
+			serve, verb = s.serveExport, "get"
+		case resource == "virtualmachineimageuploads" && subresource == "upload":
+			// Uploads write the claim
+			serve, verb = s.serveUpload, "create"
+		default:
+			http.Error(w, fmt.Sprintf("unknown subresource %q", subresource), http.StatusNotFound)
+			return
+		}
+		if code, err := s.authorize(r, verb, ns, resource, name, subresource); err != nil {
+			http.Error(w, err.Error(), code)
+			return
+		}
+		serve(w, r, ns, name)
+		return
+	}
+	handler, ok := s.handlers[subresource]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown subresource %q", subresource), http.StatusNotFound)
+		return
+	}
+
+	if code, err := s.authorize(r, "get", ns, resource, name, subresource); err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	vm, err := s.vmLister.VirtualMachines(ns).Get(name)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			code = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	pod, err := s.launcherPod(vm)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	glog.V(3).Infof("Proxying %s console of vm %s/%s to pod %s", subresource, ns, name, pod.Name)
+	handler(w, r, vm, pod)
+}
+
+// resources are those the server serves subresources of
+var resources = map[string]bool{
+	"virtualmachines":            true,
+	"virtualmachineexports":      true,
+	"virtualmachineimageuploads": true,
+}
+
+func parsePath(path string) (ns, resource, name, subresource string, err error) {
+	prefix := "/apis/" + vmapi.SchemeGroupVersion.String() + "/"
+	if !strings.HasPrefix(path, prefix) {
+		return "", "", "", "", fmt.Errorf("path %q not found", path)
+	}
+	parts := strings.Split(strings.TrimPrefix(path, prefix), "/")
+	if len(parts) != 5 || parts[0] != "namespaces" || !resources[parts[2]] {
+		return "", "", "", "", fmt.Errorf("path %q not found", path)
+	}
+	return parts[1], parts[2], parts[3], parts[4], nil
+}
+
+// launcherPod returns the running pod hosting the VM
+func (s *Server) launcherPod(vm *vmapi.VirtualMachine) (*corev1.Pod, error) {
+	pods := []*corev1.Pod{}
+	// Both pods carry the VM's label during a migration; the status names
+	// the one running the guest
+	if vm.Status.PodName != "" {
+		pod, err := s.podLister.Pods(vm.Namespace).Get(vm.Status.PodName)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil {
+			pods = append(pods, pod)
+		}
+	} else {
+		var err error
+		pods, err = s.podLister.Pods(vm.Namespace).List(labels.SelectorFromSet(labels.Set{
+			ranchervm.LabelVMName: vm.Name,
+		}))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if pod := runningPod(pods); pod != nil {
+		return pod, nil
+	}
+	return nil, fmt.Errorf("vm %s/%s is not running", vm.Namespace, vm.Name)
+}
@@ -0,0 +1,42 @@
+package console
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/golang/glog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+// UploadPath is where the server accepts the image of an upload
+func UploadPath(upload *vmapi.VirtualMachineImageUpload) string {
+	return fmt.Sprintf("/apis/%s/namespaces/%s/virtualmachineimageuploads/%s/upload",
+		vmapi.SchemeGroupVersion.String(), upload.Namespace, upload.Name)
+}
+
+// serveUpload proxies the chunks and status requests of an upload to its pod
+func (s *Server) serveUpload(w http.ResponseWriter, r *http.Request, ns, name string) {
+	upload, err := s.uploadLister.VirtualMachineImageUploads(ns).Get(name)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			code = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	if upload.Status.Phase != vmapi.UploadReady {
+		http.Error(w, fmt.Sprintf("upload %s/%s is not ready", ns, name), http.StatusServiceUnavailable)
+		return
+	}
+	token, err := s.podToken(ns, upload.Status.PodName)
+	if err != nil {
+		glog.V(2).Infof("error getting token of pod %s/%s: %v", ns, upload.Status.PodName, err)
+		http.Error(w, fmt.Sprintf("upload %s/%s is not ready", ns, name), http.StatusServiceUnavailable)
+		return
+	}
+	s.proxyToPod(w, r, ns, upload.Status.PodName, launcher.UploadPort, token)
+}
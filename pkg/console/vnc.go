@@ -0,0 +1,46 @@
+package console
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"golang.org/x/net/websocket"
+	corev1 "k8s.io/api/core/v1"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// binarySubprotocol is requested by noVNC for raw RFB frames
+const binarySubprotocol = "binary"
+
+// serveVNC tunnels the guest's VNC socket over a websocket
+func (s *Server) serveVNC(w http.ResponseWriter, r *http.Request, vm *vmapi.VirtualMachine, pod *corev1.Pod) {
+	websocket.Server{
+		Handshake: negotiateBinary,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			ws.PayloadType = websocket.BinaryFrame
+
+			stream, err := DialPod(s.config, s.kubeClient, pod, VNCPort)
+			if err != nil {
+				glog.V(2).Infof("error connecting to vnc of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+				return
+			}
+			Proxy(ws, stream)
+		},
+	}.ServeHTTP(w, r)
+}
+
+// negotiateBinary accepts the binary subprotocol if the client offered it.
+// The origin was checked before the request was authorized; the bearer token
+// subprotocol is never echoed back.
+func negotiateBinary(config *websocket.Config, r *http.Request) error {
+	for _, protocol := range config.Protocol {
+		if protocol == binarySubprotocol {
+			config.Protocol = []string{binarySubprotocol}
+			return nil
+		}
+	}
+	config.Protocol = nil
+	return nil
+}
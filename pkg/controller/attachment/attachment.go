@@ -0,0 +1,356 @@
+package attachment
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/console"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+func (ctrl *AttachmentController) updateAttachment(attachment *vmapi.VirtualMachineVolumeAttachment) {
+	// Never mutate objects from the informer cache
+	original := attachment
+	attachment = attachment.DeepCopy()
+
+	if attachment.DeletionTimestamp != nil {
+		ctrl.detach(attachment)
+		return
+	}
+	if !hasFinalizer(attachment) {
+		attachment.Finalizers = append(attachment.Finalizers, ranchervm.FinalizerVolumeAttachment)
+		ctrl.updateAttachmentStatus(attachment)
+		return
+	}
+
+	vm, err := ctrl.vmLister.VirtualMachines(attachment.Namespace).Get(attachment.Spec.VirtualMachineName)
+	if apierrors.IsNotFound(err) {
+		ctrl.setStatus(original, attachment, vmapi.AttachmentPending, fmt.Sprintf("vm %s not found", attachment.Spec.VirtualMachineName))
+		return
+	}
+	if err != nil {
+		glog.V(2).Infof("error getting vm of volume attachment %s/%s: %v", attachment.Namespace, attachment.Name, err)
+		return
+	}
+	if err := ctrl.validateAttachment(attachment, vm); err != nil {
+		ctrl.setStatus(original, attachment, vmapi.AttachmentFailed, err.Error())
+		return
+	}
+
+	if !vm.Status.Running || vm.Status.PodName == "" {
+		// Disks are attached again once the VM runs
+		if hotpluggedDiskOf(vm, attachment) != nil {
+			vm = vm.DeepCopy()
+			removeHotpluggedDisk(vm, attachment)
+			ctrl.updateVMStatus(vm)
+		}
+		ctrl.setStatus(original, attachment, vmapi.AttachmentPending, "waiting for vm to run")
+		return
+	}
+	if ctrl.migrating(vm) {
+		ctrl.setStatus(original, attachment, vmapi.AttachmentPending, "waiting for vm migration to finish")
+		return
+	}
+
+	server, ok := ctrl.syncServerPod(attachment, vm)
+	if !ok {
+		ctrl.setStatus(original, attachment, vmapi.AttachmentPending, "waiting for disk server")
+		return
+	}
+
+	entry := hotpluggedDisk(vm, attachment.Spec.Disk.Name)
+	if entry == nil || entry.PodName != vm.Status.PodName || entry.Server != server.Status.PodIP || entry.ServerUID != server.UID {
+		if !ctrl.attach(attachment, vm, entry, server) {
+			ctrl.setStatus(original, attachment, vmapi.AttachmentPending, "attaching disk")
+			ctrl.attachmentQueue.AddAfter(attachment.Namespace+"/"+attachment.Name, retryInterval)
+			return
+		}
+	}
+	ctrl.setStatus(original, attachment, vmapi.AttachmentAttached, "")
+}
+
+// validateAttachment checks that the attached disk can't be confused with
+// any other disk of the VM. Of attachments with the same disk, the oldest
+// wins.
+func (ctrl *AttachmentController) validateAttachment(attachment *vmapi.VirtualMachineVolumeAttachment, vm *vmapi.VirtualMachine) error {
+	disk := attachment.Spec.Disk
+	if errs := validation.IsDNS1123Label("disk-" + disk.Name); len(errs) > 0 {
+		return fmt.Errorf("disk %q: invalid name: %s", disk.Name, strings.Join(errs, ", "))
+	}
+	if disk.ClaimName == "" {
+		return fmt.Errorf("disk %q: claim_name is required", disk.Name)
+	}
+	for _, d := range vm.Spec.Disks {
+		if d.Name == disk.Name {
+			return fmt.Errorf("vm %s already has a disk named %s", vm.Name, disk.Name)
+		}
+		if d.ClaimName == disk.ClaimName {
+			return fmt.Errorf("claim %s is disk %s of vm %s", disk.ClaimName, d.Name, vm.Name)
+		}
+	}
+
+	attachments, err := ctrl.attachmentLister.VirtualMachineVolumeAttachments(attachment.Namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, other := range attachments {
+		if other.UID == attachment.UID || !older(other, attachment) {
+			continue
+		}
+		if other.Spec.Disk.ClaimName == disk.ClaimName {
+			return fmt.Errorf("claim %s is attached by %s", disk.ClaimName, other.Name)
+		}
+		if other.Spec.VirtualMachineName == vm.Name && other.Spec.Disk.Name == disk.Name {
+			return fmt.Errorf("disk %s is attached by %s", disk.Name, other.Name)
+		}
+	}
+	return nil
+}
+
+// syncServerPod runs the disk server pod on the VM's node. Returns the pod
+// once it serves the disk.
+func (ctrl *AttachmentController) syncServerPod(attachment *vmapi.VirtualMachineVolumeAttachment, vm *vmapi.VirtualMachine) (*corev1.Pod, bool) {
+	pod, err := ctrl.podLister.Pods(attachment.Namespace).Get(serverPodName(attachment))
+	if apierrors.IsNotFound(err) {
+		// The server must not be reachable before it's locked down
+		policy := newServerPolicy(attachment)
+		if _, err := ctrl.kubeClient.NetworkingV1().NetworkPolicies(policy.Namespace).Create(policy); err != nil && !apierrors.IsAlreadyExists(err) {
+			glog.V(2).Infof("error creating network policy %s/%s: %v", policy.Namespace, policy.Name, err)
+			ctrl.recorder.Eventf(attachment, corev1.EventTypeWarning, "FailedCreate", "Error creating disk server network policy: %v", err)
+			return nil, false
+		}
+		pod = ctrl.newServerPod(attachment, vm.Status.NodeName)
+		if _, err := ctrl.kubeClient.CoreV1().Pods(pod.Namespace).Create(pod); err != nil {
+			glog.V(2).Infof("error creating disk server pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			ctrl.recorder.Eventf(attachment, corev1.EventTypeWarning, "FailedCreate", "Error creating disk server pod: %v", err)
+		}
+		return nil, false
+	}
+	if err != nil {
+		glog.V(2).Infof("error getting disk server pod of volume attachment %s/%s: %v", attachment.Namespace, attachment.Name, err)
+		return nil, false
+	}
+	if pod.DeletionTimestamp != nil {
+		return nil, false
+	}
+
+	// The claim follows the VM to its node. Servers from before network
+	// policies were created for them are replaced too.
+	if pod.Spec.NodeSelector[hostnameLabel] != vm.Status.NodeName || pod.Labels[labelAttachment] != attachment.Name ||
+		pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+		if err := ctrl.kubeClient.CoreV1().Pods(pod.Namespace).Delete(pod.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error deleting disk server pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+		return nil, false
+	}
+	return pod, podReady(pod)
+}
+
+// attach plugs the disk into the VM's guest and records it in the VM's
+// status. A disk still attached from a previous server is unplugged first,
+// even if it wasn't recorded, since QEMU never reconnects to a new server.
+// Returns true on success.
+func (ctrl *AttachmentController) attach(attachment *vmapi.VirtualMachineVolumeAttachment, vm *vmapi.VirtualMachine, entry *vmapi.HotpluggedDiskStatus, server *corev1.Pod) bool {
+	disk := attachment.Spec.Disk
+	pod, err := ctrl.podLister.Pods(vm.Namespace).Get(vm.Status.PodName)
+	if err != nil {
+		glog.V(2).Infof("error getting pod of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		return false
+	}
+
+	if _, err := console.ExecOutput(ctrl.config, ctrl.kubeClient, pod, launcher.DetachDiskCommand(disk.Name)); err != nil {
+		glog.V(2).Infof("error detaching disk %s from vm %s/%s: %v", disk.Name, vm.Namespace, vm.Name, err)
+		ctrl.recorder.Eventf(attachment, corev1.EventTypeWarning, "FailedDetach", "Error detaching disk from previous server: %v", err)
+		return false
+	}
+	if _, err := console.ExecOutput(ctrl.config, ctrl.kubeClient, pod, launcher.AttachDiskCommand(disk.Name, server.Status.PodIP)); err != nil {
+		glog.V(2).Infof("error attaching disk %s to vm %s/%s: %v", disk.Name, vm.Namespace, vm.Name, err)
+		ctrl.recorder.Eventf(attachment, corev1.EventTypeWarning, "FailedAttach", "Error attaching disk: %v", err)
+		return false
+	}
+
+	vm = vm.DeepCopy()
+	removeHotpluggedDisk(vm, attachment)
+	vm.Status.HotpluggedDisks = append(vm.Status.HotpluggedDisks, vmapi.HotpluggedDiskStatus{
+		Name:       disk.Name,
+		ClaimName:  disk.ClaimName,
+		Attachment: attachment.Name,
+		PodName:    pod.Name,
+		Server:     server.Status.PodIP,
+		ServerUID:  server.UID,
+	})
+	if !ctrl.updateVMStatus(vm) {
+		return false
+	}
+	ctrl.recorder.Eventf(attachment, corev1.EventTypeNormal, "Attached", "Attached disk %s to vm %s", disk.Name, vm.Name)
+	return true
+}
+
+// detach unplugs the disk of a deleted attachment from the VM's guest, then
+// lets the attachment go
+func (ctrl *AttachmentController) detach(attachment *vmapi.VirtualMachineVolumeAttachment) {
+	if !hasFinalizer(attachment) {
+		return
+	}
+	key := attachment.Namespace + "/" + attachment.Name
+
+	vm, err := ctrl.vmLister.VirtualMachines(attachment.Namespace).Get(attachment.Spec.VirtualMachineName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		glog.V(2).Infof("error getting vm of volume attachment %s: %v", key, err)
+		return
+	}
+	if entry := hotpluggedDiskOf(vm, attachment); entry != nil {
+		if vm.Status.Running && entry.PodName == vm.Status.PodName {
+			pod, err := ctrl.podLister.Pods(vm.Namespace).Get(entry.PodName)
+			if err != nil && !apierrors.IsNotFound(err) {
+				glog.V(2).Infof("error getting pod of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+				return
+			}
+			if err == nil {
+				if _, err := console.ExecOutput(ctrl.config, ctrl.kubeClient, pod, launcher.DetachDiskCommand(entry.Name)); err != nil {
+					glog.V(2).Infof("error detaching disk %s from vm %s/%s: %v", entry.Name, vm.Namespace, vm.Name, err)
+					ctrl.recorder.Eventf(attachment, corev1.EventTypeWarning, "FailedDetach", "Error detaching disk: %v", err)
+					ctrl.attachmentQueue.AddAfter(key, retryInterval)
+					return
+				}
+			}
+		}
+		vm = vm.DeepCopy()
+		removeHotpluggedDisk(vm, attachment)
+		if !ctrl.updateVMStatus(vm) {
+			return
+		}
+		ctrl.recorder.Eventf(attachment, corev1.EventTypeNormal, "Detached", "Detached disk %s from vm %s", entry.Name, vm.Name)
+	}
+
+	// The disk server pod is garbage collected along with the attachment
+	var finalizers []string
+	for _, finalizer := range attachment.Finalizers {
+		if finalizer != ranchervm.FinalizerVolumeAttachment {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	attachment.Finalizers = finalizers
+	ctrl.updateAttachmentStatus(attachment)
+}
+
+// migrating returns true if the VM is being migrated, during which disks
+// can't be attached
+func (ctrl *AttachmentController) migrating(vm *vmapi.VirtualMachine) bool {
+	migrations, err := ctrl.migrationLister.VirtualMachineMigrations(vm.Namespace).List(labels.Everything())
+	if err != nil {
+		glog.V(2).Infof("error listing migrations: %v", err)
+		return true
+	}
+	for _, migration := range migrations {
+		if migration.Spec.VirtualMachineName != vm.Name {
+			continue
+		}
+		if migration.Status.Phase != vmapi.MigrationSucceeded && migration.Status.Phase != vmapi.MigrationFailed {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctrl *AttachmentController) setStatus(original, attachment *vmapi.VirtualMachineVolumeAttachment, phase vmapi.AttachmentPhase, message string) {
+	attachment.Status.Phase = phase
+	attachment.Status.Message = message
+	attachment.Status.ServerPod = ""
+	if phase != vmapi.AttachmentFailed {
+		attachment.Status.ServerPod = serverPodName(attachment)
+	}
+	if apiequality.Semantic.DeepEqual(original.Status, attachment.Status) {
+		return
+	}
+	ctrl.updateAttachmentStatus(attachment)
+}
+
+func (ctrl *AttachmentController) updateAttachmentStatus(attachment *vmapi.VirtualMachineVolumeAttachment) {
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineVolumeAttachments(attachment.Namespace).Update(attachment)
+	if err != nil {
+		glog.V(2).Infof("error updating volume attachment %s/%s: %v", attachment.Namespace, attachment.Name, err)
+	}
+}
+
+func (ctrl *AttachmentController) updateVMStatus(vm *vmapi.VirtualMachine) bool {
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachines(vm.Namespace).Update(vm)
+	if err != nil {
+		glog.V(2).Infof("error updating status of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		return false
+	}
+	return true
+}
+
+func hasFinalizer(attachment *vmapi.VirtualMachineVolumeAttachment) bool {
+	for _, finalizer := range attachment.Finalizers {
+		if finalizer == ranchervm.FinalizerVolumeAttachment {
+			return true
+		}
+	}
+	return false
+}
+
+// older orders attachments by creation, then name
+func older(a, b *vmapi.VirtualMachineVolumeAttachment) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+func hotpluggedDisk(vm *vmapi.VirtualMachine, name string) *vmapi.HotpluggedDiskStatus {
+	for i := range vm.Status.HotpluggedDisks {
+		if vm.Status.HotpluggedDisks[i].Name == name {
+			return &vm.Status.HotpluggedDisks[i]
+		}
+	}
+	return nil
+}
+
+// hotpluggedDiskOf returns the disk the attachment plugged into the VM
+func hotpluggedDiskOf(vm *vmapi.VirtualMachine, attachment *vmapi.VirtualMachineVolumeAttachment) *vmapi.HotpluggedDiskStatus {
+	if vm == nil {
+		return nil
+	}
+	entry := hotpluggedDisk(vm, attachment.Spec.Disk.Name)
+	if entry == nil || entry.Attachment != attachment.Name {
+		return nil
+	}
+	return entry
+}
+
+// removeHotpluggedDisk removes the disk the attachment plugged into the VM
+// from its status. Returns true if status was modified.
+func removeHotpluggedDisk(vm *vmapi.VirtualMachine, attachment *vmapi.VirtualMachineVolumeAttachment) bool {
+	var disks []vmapi.HotpluggedDiskStatus
+	for _, disk := range vm.Status.HotpluggedDisks {
+		if disk.Attachment != attachment.Name {
+			disks = append(disks, disk)
+		}
+	}
+	if len(disks) == len(vm.Status.HotpluggedDisks) {
+		return false
+	}
+	vm.Status.HotpluggedDisks = disks
+	return true
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+package attachment
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	vmclientset "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned"
+	vmscheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	vminformers "github.com/llparse/kube-crd-skel/pkg/client/informers/externalversions/virtualmachine/v1alpha1"
+	vmlisters "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
+)
+
+const (
+	// Failed attaches and detaches are retried after this long
+	retryInterval = 10 * time.Second
+)
+
+// AttachmentController hotplugs the disks of VirtualMachineVolumeAttachments
+// into running VMs
+type AttachmentController struct {
+	config     *rest.Config
+	vmClient   vmclientset.Interface
+	kubeClient kubernetes.Interface
+
+	vmLister               vmlisters.VirtualMachineLister
+	vmListerSynced         cache.InformerSynced
+	attachmentLister       vmlisters.VirtualMachineVolumeAttachmentLister
+	attachmentListerSynced cache.InformerSynced
+	migrationLister        vmlisters.VirtualMachineMigrationLister
+	migrationListerSynced  cache.InformerSynced
+	podLister              corelisters.PodLister
+	podListerSynced        cache.InformerSynced
+
+	attachmentQueue workqueue.RateLimitingInterface
+
+	recorder record.EventRecorder
+
+	launcherImage string
+}
+
+func NewAttachmentController(
+	config *rest.Config,
+	vmClient vmclientset.Interface,
+	kubeClient kubernetes.Interface,
+	vmInformer vminformers.VirtualMachineInformer,
+	attachmentInformer vminformers.VirtualMachineVolumeAttachmentInformer,
+	migrationInformer vminformers.VirtualMachineMigrationInformer,
+	podInformer coreinformers.PodInformer,
+	launcherImage string,
+) *AttachmentController {
+
+	ctrl := &AttachmentController{
+		config:          config,
+		vmClient:        vmClient,
+		kubeClient:      kubeClient,
+		attachmentQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachinevolumeattachment"),
+		launcherImage:   launcherImage,
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(glog.Infof)
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	ctrl.recorder = broadcaster.NewRecorder(vmscheme.Scheme, corev1.EventSource{Component: "vm-attachment-controller"})
+
+	attachmentInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueWork(ctrl.attachmentQueue, obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueWork(ctrl.attachmentQueue, newObj) },
+		},
+	)
+
+	// Disks are attached again as VMs restart or move to another pod
+	vmInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    ctrl.enqueueVMAttachments,
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueVMAttachments(newObj) },
+			DeleteFunc: ctrl.enqueueVMAttachments,
+		},
+	)
+
+	// Disk server pods are owned by their attachment
+	podInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueOwner(newObj) },
+			DeleteFunc: ctrl.enqueueOwner,
+		},
+	)
+
+	ctrl.vmLister = vmInformer.Lister()
+	ctrl.vmListerSynced = vmInformer.Informer().HasSynced
+
+	ctrl.attachmentLister = attachmentInformer.Lister()
+	ctrl.attachmentListerSynced = attachmentInformer.Informer().HasSynced
+
+	ctrl.migrationLister = migrationInformer.Lister()
+	ctrl.migrationListerSynced = migrationInformer.Informer().HasSynced
+
+	ctrl.podLister = podInformer.Lister()
+	ctrl.podListerSynced = podInformer.Informer().HasSynced
+
+	return ctrl
+}
+
+func (ctrl *AttachmentController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.attachmentQueue.ShutDown()
+
+	glog.Infof("Starting volume attachment controller")
+	defer glog.Infof("Shutting down volume attachment controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.vmListerSynced, ctrl.attachmentListerSynced, ctrl.migrationListerSynced, ctrl.podListerSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.attachmentWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (ctrl *AttachmentController) enqueueWork(queue workqueue.Interface, obj interface{}) {
+	// Beware of "xxx deleted" events
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	objName, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		glog.Errorf("failed to get key from object: %v", err)
+		return
+	}
+	glog.V(5).Infof("enqueued %q for sync", objName)
+	queue.Add(objName)
+}
+
+func (ctrl *AttachmentController) enqueueOwner(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	if ref := metav1.GetControllerOf(meta); ref != nil && ref.Kind == "VirtualMachineVolumeAttachment" {
+		ctrl.attachmentQueue.Add(meta.GetNamespace() + "/" + ref.Name)
+	}
+}
+
+func (ctrl *AttachmentController) enqueueVMAttachments(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	vm, ok := obj.(*vmapi.VirtualMachine)
+	if !ok {
+		return
+	}
+	attachments, err := ctrl.attachmentLister.VirtualMachineVolumeAttachments(vm.Namespace).List(labels.Everything())
+	if err != nil {
+		glog.V(2).Infof("error listing volume attachments of namespace %s: %v", vm.Namespace, err)
+		return
+	}
+	for _, attachment := range attachments {
+		if attachment.Spec.VirtualMachineName == vm.Name {
+			ctrl.enqueueWork(ctrl.attachmentQueue, attachment)
+		}
+	}
+}
+
+func (ctrl *AttachmentController) attachmentWorker() {
+	workFunc := func() bool {
+		keyObj, quit := ctrl.attachmentQueue.Get()
+		if quit {
+			return true
+		}
+		defer ctrl.attachmentQueue.Done(keyObj)
+		key := keyObj.(string)
+		glog.V(5).Infof("attachmentWorker[%s]", key)
+
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			glog.V(4).Infof("error getting name of volume attachment %q to get volume attachment from informer: %v", key, err)
+			return false
+		}
+		attachment, err := ctrl.attachmentLister.VirtualMachineVolumeAttachments(ns).Get(name)
+		if err == nil {
+			ctrl.updateAttachment(attachment)
+		} else if !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error getting volume attachment %q from informer: %v", key, err)
+		}
+		// Deleted attachments were detached before their finalizer was
+		// removed, and their disk server pods are garbage collected
+		return false
+	}
+	for {
+		if quit := workFunc(); quit {
+			glog.Infof("volume attachment worker queue shutting down")
+			return
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package attachment
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+const (
+	hostnameLabel = "kubernetes.io/hostname"
+
+	// labelAttachment is set on disk server pods to the name of their
+	// attachment
+	labelAttachment = ranchervm.GroupName + "/attachment"
+)
+
+func newAttachmentRef(attachment *vmapi.VirtualMachineVolumeAttachment) *metav1.OwnerReference {
+	return metav1.NewControllerRef(attachment, vmapi.SchemeGroupVersion.WithKind("VirtualMachineVolumeAttachment"))
+}
+
+func serverPodName(attachment *vmapi.VirtualMachineVolumeAttachment) string {
+	return attachment.Name + "-disk"
+}
+
+// newServerPolicy returns the NetworkPolicy letting only the VM's pod reach
+// the disk server, which serves NBD without authentication. It is owned by
+// the attachment.
+func newServerPolicy(attachment *vmapi.VirtualMachineVolumeAttachment) *networkingv1.NetworkPolicy {
+	protocol := corev1.ProtocolTCP
+	port := intstr.FromInt(launcher.NBDPort)
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            serverPodName(attachment),
+			Namespace:       attachment.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*newAttachmentRef(attachment)},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					labelAttachment: attachment.Name,
+				},
+			},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				networkingv1.NetworkPolicyIngressRule{
+					Ports: []networkingv1.NetworkPolicyPort{
+						networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port},
+					},
+					From: []networkingv1.NetworkPolicyPeer{
+						networkingv1.NetworkPolicyPeer{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"type":                "ranchervm",
+									ranchervm.LabelVMName: attachment.Spec.VirtualMachineName,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newServerPod returns the pod serving the attached disk to the VM. It runs
+// on the VM's node, keeping disk traffic off the network between nodes, and
+// is owned by the attachment.
+func (ctrl *AttachmentController) newServerPod(attachment *vmapi.VirtualMachineVolumeAttachment, nodeName string) *corev1.Pod {
+	disk := attachment.Spec.Disk
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serverPodName(attachment),
+			Namespace: attachment.Namespace,
+			Labels: map[string]string{
+				"type":          "ranchervm-disk",
+				labelAttachment: attachment.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{*newAttachmentRef(attachment)},
+		},
+		Spec: corev1.PodSpec{
+			// QEMU doesn't reconnect to a restarted server. A failed server
+			// is replaced by a new pod instead, and the disk attached again.
+			RestartPolicy: corev1.RestartPolicyNever,
+			NodeSelector: map[string]string{
+				hostnameLabel: nodeName,
+			},
+			// Tainted nodes run the VM already
+			Tolerations: []corev1.Toleration{
+				corev1.Toleration{Operator: corev1.TolerationOpExists},
+			},
+			Containers: []corev1.Container{
+				corev1.Container{
+					Name:    "disk-server",
+					Image:   ctrl.launcherImage,
+					Command: launcher.ServeDiskCommand(disk.Name),
+					Ports: []corev1.ContainerPort{
+						corev1.ContainerPort{
+							Name:          "nbd",
+							ContainerPort: launcher.NBDPort,
+							Protocol:      corev1.ProtocolTCP,
+						},
+					},
+					ReadinessProbe: &corev1.Probe{
+						Handler: corev1.Handler{
+							TCPSocket: &corev1.TCPSocketAction{
+								Port: intstr.FromString("nbd"),
+							},
+						},
+						PeriodSeconds: 2,
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						corev1.VolumeMount{
+							Name:      "disk",
+							MountPath: launcher.DiskDir + "/" + disk.Name,
+						},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				corev1.Volume{
+					Name: "disk",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: disk.ClaimName,
+						},
+					},
+				},
+			},
+		},
+	}
+}
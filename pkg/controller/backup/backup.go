@@ -0,0 +1,284 @@
+package backup
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	objectstore "github.com/llparse/kube-crd-skel/pkg/backup"
+)
+
+func newBackupRef(backup *vmapi.VirtualMachineBackup) *metav1.OwnerReference {
+	return metav1.NewControllerRef(backup, vmapi.SchemeGroupVersion.WithKind("VirtualMachineBackup"))
+}
+
+func (ctrl *BackupController) updateBackup(backup *vmapi.VirtualMachineBackup) {
+	switch backup.Status.Phase {
+	case vmapi.BackupComplete, vmapi.BackupFailed:
+		ctrl.deleteSnapshot(backup)
+		return
+	}
+
+	// Never mutate objects from the informer cache
+	original := backup
+	backup = backup.DeepCopy()
+	key := backup.Namespace + "/" + backup.Name
+
+	target, err := ctrl.targetLister.VirtualMachineBackupTargets(backup.Namespace).Get(backup.Spec.Target)
+	if err != nil {
+		ctrl.retryBackup(backup, fmt.Sprintf("error getting target %s: %v", backup.Spec.Target, err))
+		return
+	}
+
+	// Disks are uploaded from a copy snapshot owned by the backup, so that
+	// they are consistent and the VM only stops while they are copied
+	if backup.Status.SnapshotName == "" {
+		if _, err := ctrl.vmLister.VirtualMachines(backup.Namespace).Get(backup.Spec.VirtualMachineName); err != nil {
+			ctrl.failBackup(backup, fmt.Sprintf("error getting vm %s: %v", backup.Spec.VirtualMachineName, err))
+			return
+		}
+		parent, err := ctrl.latestBackup(backup)
+		if err != nil {
+			glog.V(2).Infof("error listing backups of namespace %s: %v", backup.Namespace, err)
+			return
+		}
+		snap := &vmapi.VirtualMachineSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "backup-" + backup.Name,
+				Namespace:       backup.Namespace,
+				OwnerReferences: []metav1.OwnerReference{*newBackupRef(backup)},
+			},
+			Spec: vmapi.VirtualMachineSnapshotSpec{
+				VirtualMachineName: backup.Spec.VirtualMachineName,
+				Method:             vmapi.SnapshotMethodCopy,
+			},
+		}
+		_, err = ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineSnapshots(backup.Namespace).Create(snap)
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			glog.V(2).Infof("error creating snapshot for backup %s/%s: %v", backup.Namespace, backup.Name, err)
+			return
+		}
+		backup.Status.Phase = vmapi.BackupInProgress
+		backup.Status.Location = key
+		backup.Status.SnapshotName = snap.Name
+		if parent != nil {
+			backup.Status.Parent = parent.Status.Location
+		}
+		// The resulting update event will requeue the backup
+		ctrl.updateBackupStatus(backup)
+		return
+	}
+
+	snap, err := ctrl.snapshotLister.VirtualMachineSnapshots(backup.Namespace).Get(backup.Status.SnapshotName)
+	if apierrors.IsNotFound(err) {
+		// The snapshot was just created
+		ctrl.backupQueue.AddAfter(key, pollInterval)
+		return
+	}
+	if err != nil {
+		glog.V(2).Infof("error getting snapshot %s/%s: %v", backup.Namespace, backup.Status.SnapshotName, err)
+		return
+	}
+	switch snap.Status.Phase {
+	case vmapi.SnapshotReady:
+	case vmapi.SnapshotFailed:
+		ctrl.failBackup(backup, fmt.Sprintf("snapshot %s failed: %s", snap.Name, snap.Status.Message))
+		return
+	default:
+		return
+	}
+
+	if backup.Status.Disks == nil {
+		backup.Status.Disks = []vmapi.DiskBackupStatus{}
+		for _, disk := range snap.Status.Disks {
+			backup.Status.Disks = append(backup.Status.Disks, vmapi.DiskBackupStatus{Name: disk.Name})
+		}
+		ctrl.updateBackupStatus(backup)
+		return
+	}
+
+	store, err := ctrl.store(target)
+	if err != nil {
+		ctrl.retryBackup(backup, err.Error())
+		return
+	}
+	backup.Status.Message = ""
+
+	ready := true
+	for i := range backup.Status.Disks {
+		disk := &backup.Status.Disks[i]
+		if disk.Ready {
+			continue
+		}
+		if err := ctrl.syncDiskBackup(backup, target, store, snap, disk); err != nil {
+			message := fmt.Sprintf("disk %s: %v", disk.Name, err)
+			if _, ok := err.(retryError); ok {
+				ctrl.retryBackup(backup, message)
+			} else {
+				ctrl.failBackup(backup, message)
+			}
+			return
+		}
+		ready = ready && disk.Ready
+	}
+	if !ready {
+		if !apiequality.Semantic.DeepEqual(original.Status, backup.Status) {
+			ctrl.updateBackupStatus(backup)
+		}
+		return
+	}
+
+	// Restores only see backups with a manifest, so it is written last
+	now := metav1.Now()
+	manifest := &objectstore.Manifest{
+		Location:           backup.Status.Location,
+		VirtualMachineName: backup.Spec.VirtualMachineName,
+		VirtualMachineSpec: *snap.Status.VirtualMachineSpec,
+		Disks:              []objectstore.ManifestDisk{},
+		Parent:             backup.Status.Parent,
+		CompletionTime:     now.Time,
+	}
+	for _, disk := range snap.Status.Disks {
+		manifest.Disks = append(manifest.Disks, objectstore.ManifestDisk{
+			Name:      disk.Name,
+			ClaimSpec: disk.ClaimSpec,
+		})
+	}
+	if err := store.PutManifest(manifest); err != nil {
+		glog.V(2).Infof("error storing manifest of backup %s/%s: %v", backup.Namespace, backup.Name, err)
+		ctrl.backupQueue.AddAfter(key, pollInterval)
+		return
+	}
+
+	backup.Status.Phase = vmapi.BackupComplete
+	backup.Status.CompletionTime = &now
+	ctrl.recorder.Eventf(backup, corev1.EventTypeNormal, "Complete", "Backed up vm %s to %s", backup.Spec.VirtualMachineName, target.Name)
+	ctrl.updateBackupStatus(backup)
+}
+
+// syncDiskBackup starts the job uploading a disk and reports whether it is
+// done. Errors that may pass are a retryError.
+func (ctrl *BackupController) syncDiskBackup(backup *vmapi.VirtualMachineBackup, target *vmapi.VirtualMachineBackupTarget,
+	store *objectstore.Store, snap *vmapi.VirtualMachineSnapshot, disk *vmapi.DiskBackupStatus) error {
+
+	var source *vmapi.DiskSnapshotStatus
+	for i := range snap.Status.Disks {
+		if snap.Status.Disks[i].Name == disk.Name {
+			source = &snap.Status.Disks[i]
+		}
+	}
+	if source == nil {
+		return fmt.Errorf("disk is not in snapshot %s", snap.Name)
+	}
+
+	job, err := ctrl.jobLister.Jobs(backup.Namespace).Get(backup.Name + "-" + disk.Name)
+	if apierrors.IsNotFound(err) {
+		job = ctrl.newBackupJob(backup, target, disk.Name, source.ClaimName)
+		if _, err := ctrl.kubeClient.BatchV1().Jobs(backup.Namespace).Create(job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return retryError{err}
+		}
+		return nil
+	}
+	if err != nil {
+		return retryError{err}
+	}
+	complete, failed := jobStatus(job)
+	if failed {
+		return fmt.Errorf("backup job %s failed", job.Name)
+	}
+	if !complete {
+		return nil
+	}
+
+	// The job wrote the manifest before completing, so only a missing one
+	// fails the backup
+	manifest, err := store.DiskManifest(backup.Status.Location, disk.Name)
+	if objectstore.IsNotFound(err) {
+		return fmt.Errorf("job %s wrote no manifest", job.Name)
+	}
+	if err != nil {
+		return retryError{fmt.Errorf("error reading manifest: %v", err)}
+	}
+	disk.SizeMB = manifest.SizeBytes >> 20
+	disk.Chunks = 0
+	for _, sum := range manifest.Chunks {
+		if sum != "" {
+			disk.Chunks++
+		}
+	}
+	disk.UploadedChunks = manifest.UploadedChunks
+	disk.Ready = true
+	return nil
+}
+
+// deleteSnapshot deletes the snapshot of a finished backup, along with its
+// claims
+func (ctrl *BackupController) deleteSnapshot(backup *vmapi.VirtualMachineBackup) {
+	if backup.Status.SnapshotName == "" {
+		return
+	}
+	if _, err := ctrl.snapshotLister.VirtualMachineSnapshots(backup.Namespace).Get(backup.Status.SnapshotName); err != nil {
+		return
+	}
+	err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineSnapshots(backup.Namespace).Delete(backup.Status.SnapshotName, &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		glog.V(2).Infof("error deleting snapshot %s/%s: %v", backup.Namespace, backup.Status.SnapshotName, err)
+	}
+}
+
+// latestBackup returns the last complete backup of the same VM to the same
+// target, if any
+func (ctrl *BackupController) latestBackup(backup *vmapi.VirtualMachineBackup) (*vmapi.VirtualMachineBackup, error) {
+	backups, err := ctrl.backupLister.VirtualMachineBackups(backup.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var latest *vmapi.VirtualMachineBackup
+	for _, b := range backups {
+		if b.Name == backup.Name || b.Status.Phase != vmapi.BackupComplete || b.Status.CompletionTime == nil ||
+			b.Spec.VirtualMachineName != backup.Spec.VirtualMachineName || b.Spec.Target != backup.Spec.Target {
+			continue
+		}
+		if latest == nil || latest.Status.CompletionTime.Before(b.Status.CompletionTime) {
+			latest = b
+		}
+	}
+	return latest, nil
+}
+
+// retryError is an error that may pass, such as an unreachable target or
+// API server, after which backups and restores are retried rather than
+// failed
+type retryError struct{ error }
+
+// retryBackup records an error the backup is retried after, with an event
+// when it changes
+func (ctrl *BackupController) retryBackup(backup *vmapi.VirtualMachineBackup, message string) {
+	glog.V(2).Infof("retrying backup %s/%s: %s", backup.Namespace, backup.Name, message)
+	if backup.Status.Message != message {
+		backup.Status.Message = message
+		ctrl.recorder.Event(backup, corev1.EventTypeWarning, "Retrying", message)
+		ctrl.updateBackupStatus(backup)
+	}
+	ctrl.backupQueue.AddAfter(backup.Namespace+"/"+backup.Name, pollInterval)
+}
+
+func (ctrl *BackupController) failBackup(backup *vmapi.VirtualMachineBackup, message string) {
+	backup.Status.Phase = vmapi.BackupFailed
+	backup.Status.Message = message
+	ctrl.recorder.Event(backup, corev1.EventTypeWarning, "Failed", message)
+	ctrl.updateBackupStatus(backup)
+}
+
+func (ctrl *BackupController) updateBackupStatus(backup *vmapi.VirtualMachineBackup) {
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineBackups(backup.Namespace).Update(backup)
+	if err != nil {
+		glog.V(2).Infof("error updating status of backup %s/%s: %v", backup.Namespace, backup.Name, err)
+	}
+}
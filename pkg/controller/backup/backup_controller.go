@@ -0,0 +1,259 @@
+package backup
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	batchinformers "k8s.io/client-go/informers/batch/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	vmclientset "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned"
+	vmscheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	vminformers "github.com/llparse/kube-crd-skel/pkg/client/informers/externalversions/virtualmachine/v1alpha1"
+	vmlisters "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
+)
+
+const (
+	// LabelBackupRestore is set on claims created for a backup restore to
+	// its name
+	LabelBackupRestore = "vm.rancher.com/backup-restore"
+
+	// Snapshots and the object store are polled while they aren't ready
+	pollInterval = 5 * time.Second
+)
+
+// BackupController uploads VirtualMachineBackups to their target and carries
+// out VirtualMachineBackupRestores
+type BackupController struct {
+	vmClient   vmclientset.Interface
+	kubeClient kubernetes.Interface
+
+	vmLister             vmlisters.VirtualMachineLister
+	vmListerSynced       cache.InformerSynced
+	snapshotLister       vmlisters.VirtualMachineSnapshotLister
+	snapshotListerSynced cache.InformerSynced
+	targetLister         vmlisters.VirtualMachineBackupTargetLister
+	targetListerSynced   cache.InformerSynced
+	backupLister         vmlisters.VirtualMachineBackupLister
+	backupListerSynced   cache.InformerSynced
+	restoreLister        vmlisters.VirtualMachineBackupRestoreLister
+	restoreListerSynced  cache.InformerSynced
+	pvcLister            corelisters.PersistentVolumeClaimLister
+	pvcListerSynced      cache.InformerSynced
+	jobLister            batchlisters.JobLister
+	jobListerSynced      cache.InformerSynced
+
+	backupQueue  workqueue.RateLimitingInterface
+	restoreQueue workqueue.RateLimitingInterface
+
+	recorder record.EventRecorder
+
+	launcherImage string
+}
+
+func NewBackupController(
+	vmClient vmclientset.Interface,
+	kubeClient kubernetes.Interface,
+	vmInformer vminformers.VirtualMachineInformer,
+	snapshotInformer vminformers.VirtualMachineSnapshotInformer,
+	targetInformer vminformers.VirtualMachineBackupTargetInformer,
+	backupInformer vminformers.VirtualMachineBackupInformer,
+	restoreInformer vminformers.VirtualMachineBackupRestoreInformer,
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+	jobInformer batchinformers.JobInformer,
+	launcherImage string,
+) *BackupController {
+
+	ctrl := &BackupController{
+		vmClient:      vmClient,
+		kubeClient:    kubeClient,
+		backupQueue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachinebackup"),
+		restoreQueue:  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachinebackuprestore"),
+		launcherImage: launcherImage,
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(glog.Infof)
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	ctrl.recorder = broadcaster.NewRecorder(vmscheme.Scheme, corev1.EventSource{Component: "vm-backup-controller"})
+
+	backupInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueWork(ctrl.backupQueue, obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueWork(ctrl.backupQueue, newObj) },
+		},
+	)
+
+	restoreInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueWork(ctrl.restoreQueue, obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueWork(ctrl.restoreQueue, newObj) },
+		},
+	)
+
+	// Backups own the snapshot they upload, and backups and restores own
+	// their jobs
+	snapshotInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueOwner(newObj) },
+		},
+	)
+	jobInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueOwner(newObj) },
+		},
+	)
+
+	ctrl.vmLister = vmInformer.Lister()
+	ctrl.vmListerSynced = vmInformer.Informer().HasSynced
+
+	ctrl.snapshotLister = snapshotInformer.Lister()
+	ctrl.snapshotListerSynced = snapshotInformer.Informer().HasSynced
+
+	ctrl.targetLister = targetInformer.Lister()
+	ctrl.targetListerSynced = targetInformer.Informer().HasSynced
+
+	ctrl.backupLister = backupInformer.Lister()
+	ctrl.backupListerSynced = backupInformer.Informer().HasSynced
+
+	ctrl.restoreLister = restoreInformer.Lister()
+	ctrl.restoreListerSynced = restoreInformer.Informer().HasSynced
+
+	ctrl.pvcLister = pvcInformer.Lister()
+	ctrl.pvcListerSynced = pvcInformer.Informer().HasSynced
+
+	ctrl.jobLister = jobInformer.Lister()
+	ctrl.jobListerSynced = jobInformer.Informer().HasSynced
+
+	return ctrl
+}
+
+func (ctrl *BackupController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.backupQueue.ShutDown()
+	defer ctrl.restoreQueue.ShutDown()
+
+	glog.Infof("Starting backup controller")
+	defer glog.Infof("Shutting down backup controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.vmListerSynced, ctrl.snapshotListerSynced, ctrl.targetListerSynced,
+		ctrl.backupListerSynced, ctrl.restoreListerSynced, ctrl.pvcListerSynced, ctrl.jobListerSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.backupWorker, time.Second, stopCh)
+		go wait.Until(ctrl.restoreWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (ctrl *BackupController) enqueueWork(queue workqueue.Interface, obj interface{}) {
+	// Beware of "xxx deleted" events
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	objName, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		glog.Errorf("failed to get key from object: %v", err)
+		return
+	}
+	glog.V(5).Infof("enqueued %q for sync", objName)
+	queue.Add(objName)
+}
+
+func (ctrl *BackupController) enqueueOwner(obj interface{}) {
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	ref := metav1.GetControllerOf(meta)
+	if ref == nil {
+		return
+	}
+	switch ref.Kind {
+	case "VirtualMachineBackup":
+		ctrl.backupQueue.Add(meta.GetNamespace() + "/" + ref.Name)
+	case "VirtualMachineBackupRestore":
+		ctrl.restoreQueue.Add(meta.GetNamespace() + "/" + ref.Name)
+	}
+}
+
+func (ctrl *BackupController) backupWorker() {
+	workFunc := func() bool {
+		keyObj, quit := ctrl.backupQueue.Get()
+		if quit {
+			return true
+		}
+		defer ctrl.backupQueue.Done(keyObj)
+		key := keyObj.(string)
+		glog.V(5).Infof("backupWorker[%s]", key)
+
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			glog.V(4).Infof("error getting name of backup %q to get backup from informer: %v", key, err)
+			return false
+		}
+		backup, err := ctrl.backupLister.VirtualMachineBackups(ns).Get(name)
+		if err == nil {
+			ctrl.updateBackup(backup)
+			return false
+		}
+		if !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error getting backup %q from informer: %v", key, err)
+		}
+		// Dependents of deleted backups are garbage collected, while their
+		// data stays in the target
+		return false
+	}
+	for {
+		if quit := workFunc(); quit {
+			glog.Infof("backup worker queue shutting down")
+			return
+		}
+	}
+}
+
+func (ctrl *BackupController) restoreWorker() {
+	workFunc := func() bool {
+		keyObj, quit := ctrl.restoreQueue.Get()
+		if quit {
+			return true
+		}
+		defer ctrl.restoreQueue.Done(keyObj)
+		key := keyObj.(string)
+		glog.V(5).Infof("restoreWorker[%s]", key)
+
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			glog.V(4).Infof("error getting name of backup restore %q to get backup restore from informer: %v", key, err)
+			return false
+		}
+		restore, err := ctrl.restoreLister.VirtualMachineBackupRestores(ns).Get(name)
+		if err == nil {
+			ctrl.updateRestore(restore)
+			return false
+		}
+		if !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error getting backup restore %q from informer: %v", key, err)
+		}
+		return false
+	}
+	for {
+		if quit := workFunc(); quit {
+			glog.Infof("backup restore worker queue shutting down")
+			return
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package backup
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	objectstore "github.com/llparse/kube-crd-skel/pkg/backup"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+const (
+	// The keys of a target's credentials secret
+	SecretAccessKeyID     = "access_key_id"
+	SecretSecretAccessKey = "secret_access_key"
+
+	diskDir = "/disk"
+
+	// Jobs retry transient errors of the object store
+	jobBackoffLimit = 5
+)
+
+// store returns the object store of a target
+func (ctrl *BackupController) store(target *vmapi.VirtualMachineBackupTarget) (*objectstore.Store, error) {
+	secret, err := ctrl.kubeClient.CoreV1().Secrets(target.Namespace).Get(target.Spec.CredentialsSecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting credentials of target %s: %v", target.Name, err)
+	}
+	for _, key := range []string{SecretAccessKeyID, SecretSecretAccessKey} {
+		if len(secret.Data[key]) == 0 {
+			return nil, fmt.Errorf("secret %s has no %s", secret.Name, key)
+		}
+	}
+	s3, err := objectstore.NewS3(target.Spec.Endpoint, target.Spec.Bucket, target.Spec.Region,
+		string(secret.Data[SecretAccessKeyID]), string(secret.Data[SecretSecretAccessKey]))
+	if err != nil {
+		return nil, fmt.Errorf("target %s: %v", target.Name, err)
+	}
+	return &objectstore.Store{S3: s3, Prefix: target.Spec.Prefix}, nil
+}
+
+// targetEnv configures the launcher's access to a target, reading the
+// credentials from the target's secret
+func targetEnv(target *vmapi.VirtualMachineBackupTarget) []corev1.EnvVar {
+	secretRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: target.Spec.CredentialsSecret},
+				Key:                  key,
+			},
+		}
+	}
+	return []corev1.EnvVar{
+		corev1.EnvVar{Name: objectstore.EnvEndpoint, Value: target.Spec.Endpoint},
+		corev1.EnvVar{Name: objectstore.EnvBucket, Value: target.Spec.Bucket},
+		corev1.EnvVar{Name: objectstore.EnvRegion, Value: target.Spec.Region},
+		corev1.EnvVar{Name: objectstore.EnvPrefix, Value: target.Spec.Prefix},
+		corev1.EnvVar{Name: objectstore.EnvAccessKeyID, ValueFrom: secretRef(SecretAccessKeyID)},
+		corev1.EnvVar{Name: objectstore.EnvSecretAccessKey, ValueFrom: secretRef(SecretSecretAccessKey)},
+	}
+}
+
+// newTransferJob returns a Job running the launcher command against the disk
+// image in claim, with access to the target
+func (ctrl *BackupController) newTransferJob(ns, name string, owner *metav1.OwnerReference,
+	target *vmapi.VirtualMachineBackupTarget, claimName string, readOnly bool, command []string) *batchv1.Job {
+
+	backoffLimit := int32(jobBackoffLimit)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       ns,
+			OwnerReferences: []metav1.OwnerReference{*owner},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						corev1.Container{
+							Name:    "transfer",
+							Image:   ctrl.launcherImage,
+							Command: command,
+							Env:     targetEnv(target),
+							VolumeMounts: []corev1.VolumeMount{
+								corev1.VolumeMount{
+									Name:      "disk",
+									MountPath: diskDir,
+									ReadOnly:  readOnly,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						corev1.Volume{
+							Name: "disk",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: claimName,
+									ReadOnly:  readOnly,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newBackupJob returns a Job uploading the disk image in claim
+func (ctrl *BackupController) newBackupJob(backup *vmapi.VirtualMachineBackup, target *vmapi.VirtualMachineBackupTarget,
+	disk, claimName string) *batchv1.Job {
+
+	command := launcher.BackupDiskCommand(diskDir+"/disk.img", backup.Status.Location, disk, backup.Status.Parent)
+	return ctrl.newTransferJob(backup.Namespace, backup.Name+"-"+disk, newBackupRef(backup), target, claimName, true, command)
+}
+
+// newRestoreJob returns a Job downloading a disk image to its claim
+func (ctrl *BackupController) newRestoreJob(restore *vmapi.VirtualMachineBackupRestore, target *vmapi.VirtualMachineBackupTarget,
+	disk vmapi.DiskRestoreStatus) *batchv1.Job {
+
+	command := launcher.RestoreDiskCommand(restore.Spec.Backup, disk.Name, diskDir+"/disk.img")
+	return ctrl.newTransferJob(restore.Namespace, restore.Name+"-"+disk.Name, newRestoreRef(restore), target, disk.ClaimName, false, command)
+}
+
+// newClaim returns a claim like the one described by spec, without binding
+// it to the original's volume
+func newClaim(ns, name string, labels map[string]string, spec corev1.PersistentVolumeClaimSpec) *corev1.PersistentVolumeClaim {
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels:    labels,
+		},
+		Spec: *spec.DeepCopy(),
+	}
+	claim.Spec.VolumeName = ""
+	claim.Spec.Selector = nil
+	return claim
+}
+
+// jobStatus returns whether job completed and whether it failed for good
+func jobStatus(job *batchv1.Job) (complete bool, failed bool) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			complete = true
+		case batchv1.JobFailed:
+			failed = true
+		}
+	}
+	return
+}
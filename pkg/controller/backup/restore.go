@@ -0,0 +1,233 @@
+package backup
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	objectstore "github.com/llparse/kube-crd-skel/pkg/backup"
+)
+
+// AnnotationBackupRestore is set on VMs created by a backup restore to the
+// restore's name
+const AnnotationBackupRestore = "vm.rancher.com/backup-restore"
+
+func newRestoreRef(restore *vmapi.VirtualMachineBackupRestore) *metav1.OwnerReference {
+	return metav1.NewControllerRef(restore, vmapi.SchemeGroupVersion.WithKind("VirtualMachineBackupRestore"))
+}
+
+func (ctrl *BackupController) updateRestore(restore *vmapi.VirtualMachineBackupRestore) {
+	switch restore.Status.Phase {
+	case vmapi.RestoreComplete, vmapi.RestoreFailed:
+		return
+	}
+
+	// Never mutate objects from the informer cache
+	original := restore
+	restore = restore.DeepCopy()
+
+	target, err := ctrl.targetLister.VirtualMachineBackupTargets(restore.Namespace).Get(restore.Spec.Target)
+	if err != nil {
+		ctrl.retryRestore(restore, fmt.Sprintf("error getting target %s: %v", restore.Spec.Target, err))
+		return
+	}
+	store, err := ctrl.store(target)
+	if err != nil {
+		ctrl.retryRestore(restore, err.Error())
+		return
+	}
+	manifest, err := store.Manifest(restore.Spec.Backup)
+	if objectstore.IsNotFound(err) {
+		ctrl.failRestore(restore, fmt.Sprintf("target %s has no complete backup %s", target.Name, restore.Spec.Backup))
+		return
+	}
+	if err != nil {
+		ctrl.retryRestore(restore, fmt.Sprintf("error reading backup %s: %v", restore.Spec.Backup, err))
+		return
+	}
+	restore.Status.Message = ""
+
+	vmName := restore.Spec.VirtualMachineName
+	if vmName == "" {
+		vmName = manifest.VirtualMachineName
+	}
+	if vm, err := ctrl.vmLister.VirtualMachines(restore.Namespace).Get(vmName); err == nil {
+		if vm.Annotations[AnnotationBackupRestore] != restore.Name {
+			ctrl.failRestore(restore, fmt.Sprintf("vm %s already exists", vmName))
+			return
+		}
+	}
+
+	if restore.Status.Phase != vmapi.RestoreInProgress {
+		restore.Status.Phase = vmapi.RestoreInProgress
+		restore.Status.VirtualMachineName = vmName
+		restore.Status.Disks = []vmapi.DiskRestoreStatus{}
+		for _, disk := range manifest.Disks {
+			restore.Status.Disks = append(restore.Status.Disks, vmapi.DiskRestoreStatus{
+				Name:      disk.Name,
+				ClaimName: vmName + "-" + disk.Name,
+			})
+		}
+		// The resulting update event will requeue the restore
+		ctrl.updateRestoreStatus(restore)
+		return
+	}
+
+	ready := true
+	for i := range restore.Status.Disks {
+		disk := &restore.Status.Disks[i]
+		if disk.Ready {
+			continue
+		}
+		var source *objectstore.ManifestDisk
+		for j := range manifest.Disks {
+			if manifest.Disks[j].Name == disk.Name {
+				source = &manifest.Disks[j]
+			}
+		}
+		if source == nil {
+			ctrl.failRestore(restore, fmt.Sprintf("disk %s is not in backup %s", disk.Name, restore.Spec.Backup))
+			return
+		}
+		if err := ctrl.syncDiskRestore(restore, target, source, disk); err != nil {
+			message := fmt.Sprintf("disk %s: %v", disk.Name, err)
+			if _, ok := err.(retryError); ok {
+				ctrl.retryRestore(restore, message)
+			} else {
+				ctrl.failRestore(restore, message)
+			}
+			return
+		}
+		ready = ready && disk.Ready
+	}
+	if !ready {
+		if !apiequality.Semantic.DeepEqual(original.Status, restore.Status) {
+			ctrl.updateRestoreStatus(restore)
+		}
+		return
+	}
+
+	vm := &vmapi.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vmName,
+			Namespace: restore.Namespace,
+			Annotations: map[string]string{
+				AnnotationBackupRestore: restore.Name,
+			},
+		},
+		Spec: restoredSpec(&manifest.VirtualMachineSpec, restore.Status.Disks, vmName != manifest.VirtualMachineName),
+	}
+	_, err = ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachines(vm.Namespace).Create(vm)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		glog.V(2).Infof("error creating vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		return
+	}
+
+	now := metav1.Now()
+	restore.Status.Phase = vmapi.RestoreComplete
+	restore.Status.CompletionTime = &now
+	ctrl.recorder.Eventf(restore, corev1.EventTypeNormal, "Complete", "Created vm %s", vm.Name)
+	ctrl.updateRestoreStatus(restore)
+}
+
+// syncDiskRestore creates the claim a disk is restored to and the job
+// downloading it, and reports whether the disk is restored. Claims are
+// labeled rather than owned, so that they outlive the restore. Errors that
+// may pass are a retryError.
+func (ctrl *BackupController) syncDiskRestore(restore *vmapi.VirtualMachineBackupRestore, target *vmapi.VirtualMachineBackupTarget,
+	source *objectstore.ManifestDisk, disk *vmapi.DiskRestoreStatus) error {
+
+	claim, err := ctrl.pvcLister.PersistentVolumeClaims(restore.Namespace).Get(disk.ClaimName)
+	if apierrors.IsNotFound(err) {
+		labels := map[string]string{
+			ranchervm.LabelVMName: restore.Status.VirtualMachineName,
+			LabelBackupRestore:    restore.Name,
+		}
+		claim = newClaim(restore.Namespace, disk.ClaimName, labels, source.ClaimSpec)
+		if restore.Spec.StorageClassName != nil {
+			claim.Spec.StorageClassName = restore.Spec.StorageClassName
+		}
+		if _, err := ctrl.kubeClient.CoreV1().PersistentVolumeClaims(restore.Namespace).Create(claim); err != nil && !apierrors.IsAlreadyExists(err) {
+			return retryError{err}
+		}
+	} else if err != nil {
+		return retryError{err}
+	} else if claim.Labels[LabelBackupRestore] != restore.Name {
+		return fmt.Errorf("claim %s already exists", claim.Name)
+	}
+
+	job, err := ctrl.jobLister.Jobs(restore.Namespace).Get(restore.Name + "-" + disk.Name)
+	if apierrors.IsNotFound(err) {
+		job = ctrl.newRestoreJob(restore, target, *disk)
+		if _, err := ctrl.kubeClient.BatchV1().Jobs(restore.Namespace).Create(job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return retryError{err}
+		}
+		return nil
+	}
+	if err != nil {
+		return retryError{err}
+	}
+	complete, failed := jobStatus(job)
+	if failed {
+		return fmt.Errorf("restore job %s failed", job.Name)
+	}
+	disk.Ready = complete
+	return nil
+}
+
+// restoredSpec returns a copy of the backed up spec using the restored
+// disks. The VM is created stopped, as the original may still run. Node
+// ports can't be shared, and VMs restored under another name get their own
+// MAC addresses; the guest otherwise keeps its identity.
+func restoredSpec(spec *vmapi.VirtualMachineSpec, disks []vmapi.DiskRestoreStatus, renamed bool) vmapi.VirtualMachineSpec {
+	restored := *spec.DeepCopy()
+	restored.Stopped = true
+	for i := range restored.Disks {
+		for _, disk := range disks {
+			if disk.Name == restored.Disks[i].Name {
+				restored.Disks[i].ClaimName = disk.ClaimName
+			}
+		}
+	}
+	for i := range restored.Ports {
+		restored.Ports[i].NodePort = 0
+	}
+	if renamed {
+		for i := range restored.Interfaces {
+			restored.Interfaces[i].MACAddress = ""
+		}
+	}
+	return restored
+}
+
+// retryRestore records an error the restore is retried after, with an event
+// when it changes
+func (ctrl *BackupController) retryRestore(restore *vmapi.VirtualMachineBackupRestore, message string) {
+	glog.V(2).Infof("retrying backup restore %s/%s: %s", restore.Namespace, restore.Name, message)
+	if restore.Status.Message != message {
+		restore.Status.Message = message
+		ctrl.recorder.Event(restore, corev1.EventTypeWarning, "Retrying", message)
+		ctrl.updateRestoreStatus(restore)
+	}
+	ctrl.restoreQueue.AddAfter(restore.Namespace+"/"+restore.Name, pollInterval)
+}
+
+func (ctrl *BackupController) failRestore(restore *vmapi.VirtualMachineBackupRestore, message string) {
+	restore.Status.Phase = vmapi.RestoreFailed
+	restore.Status.Message = message
+	ctrl.recorder.Event(restore, corev1.EventTypeWarning, "Failed", message)
+	ctrl.updateRestoreStatus(restore)
+}
+
+func (ctrl *BackupController) updateRestoreStatus(restore *vmapi.VirtualMachineBackupRestore) {
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineBackupRestores(restore.Namespace).Update(restore)
+	if err != nil {
+		glog.V(2).Infof("error updating status of backup restore %s/%s: %v", restore.Namespace, restore.Name, err)
+	}
+}
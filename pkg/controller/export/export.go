@@ -0,0 +1,314 @@
+package export
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/bundle"
+	"github.com/llparse/kube-crd-skel/pkg/console"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+func newExportRef(export *vmapi.VirtualMachineExport) *metav1.OwnerReference {
+	return metav1.NewControllerRef(export, vmapi.SchemeGroupVersion.WithKind("VirtualMachineExport"))
+}
+
+func exportPodName(export *vmapi.VirtualMachineExport) string {
+	return export.Name + "-export"
+}
+
+func (ctrl *ExportController) updateExport(export *vmapi.VirtualMachineExport) {
+	if export.Status.Phase == vmapi.ExportFailed {
+		return
+	}
+
+	// Never mutate objects from the informer cache
+	original := export
+	export = export.DeepCopy()
+	key := export.Namespace + "/" + export.Name
+
+	switch export.Spec.Format {
+	case "":
+		export.Spec.Format = vmapi.ExportFormatQcow2
+	case vmapi.ExportFormatQcow2, vmapi.ExportFormatOVA:
+	default:
+		ctrl.failExport(export, fmt.Sprintf("unsupported format %q", export.Spec.Format))
+		return
+	}
+
+	pod, err := ctrl.podLister.Pods(export.Namespace).Get(exportPodName(export))
+	if err != nil && !apierrors.IsNotFound(err) {
+		glog.V(2).Infof("error getting pod of export %s/%s: %v", export.Namespace, export.Name, err)
+		return
+	}
+
+	if pod == nil {
+		// Exports only leave Pending once their pod shows up in the cache
+		if export.Status.Phase == vmapi.ExportConverting || export.Status.Phase == vmapi.ExportReady {
+			ctrl.failExport(export, fmt.Sprintf("pod %s was deleted", exportPodName(export)))
+			return
+		}
+		vm, err := ctrl.vmLister.VirtualMachines(export.Namespace).Get(export.Spec.VirtualMachineName)
+		if err != nil {
+			ctrl.failExport(export, fmt.Sprintf("error getting vm %s: %v", export.Spec.VirtualMachineName, err))
+			return
+		}
+		// The disks must not change while they are converted
+		stopped, err := ctrl.vmStopped(vm)
+		if err != nil {
+			glog.V(2).Infof("error listing pods of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+			return
+		}
+		if !stopped {
+			export.Status.Phase = vmapi.ExportPending
+			export.Status.Message = fmt.Sprintf("waiting for vm %s to stop", vm.Name)
+			if !apiequality.Semantic.DeepEqual(original.Status, export.Status) {
+				ctrl.updateExportStatus(export)
+			}
+			ctrl.exportQueue.AddAfter(key, pollInterval)
+			return
+		}
+		// The VM can't start once the export names its pod, which is only
+		// created after the VM is seen stopped again
+		if export.Status.PodName == "" {
+			export.Status.Phase = vmapi.ExportPending
+			export.Status.PodName = exportPodName(export)
+			export.Status.Message = ""
+			ctrl.updateExportStatus(export)
+			return
+		}
+
+		pod, err = ctrl.newExportPod(export, vm)
+		if err != nil {
+			ctrl.failExport(export, err.Error())
+			return
+		}
+		if !ctrl.createServerSecret(export) {
+			return
+		}
+		if _, err := ctrl.kubeClient.CoreV1().Pods(export.Namespace).Create(pod); err != nil && !apierrors.IsAlreadyExists(err) {
+			glog.V(2).Infof("error creating pod for export %s/%s: %v", export.Namespace, export.Name, err)
+			return
+		}
+		export.Status.Phase = vmapi.ExportPending
+		export.Status.Message = ""
+		if !apiequality.Semantic.DeepEqual(original.Status, export.Status) {
+			ctrl.updateExportStatus(export)
+		}
+		return
+	}
+
+	export.Status.PodName = pod.Name
+	export.Status.Message = ""
+	switch {
+	case pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded:
+		ctrl.failExport(export, fmt.Sprintf("pod %s exited", pod.Name))
+		return
+	case podReady(pod):
+		if export.Status.Phase != vmapi.ExportReady {
+			ctrl.recorder.Eventf(export, corev1.EventTypeNormal, "Ready", "Exported vm %s", export.Spec.VirtualMachineName)
+		}
+		export.Status.Phase = vmapi.ExportReady
+		export.Status.Path = console.ExportPath(export)
+	case export.Status.Phase != vmapi.ExportReady:
+		export.Status.Phase = vmapi.ExportConverting
+	}
+	if !apiequality.Semantic.DeepEqual(original.Status, export.Status) {
+		ctrl.updateExportStatus(export)
+	}
+}
+
+// vmStopped returns whether the VM is stopped and its pods are gone
+func (ctrl *ExportController) vmStopped(vm *vmapi.VirtualMachine) (bool, error) {
+	if !vm.Spec.Stopped {
+		return false, nil
+	}
+	pods, err := ctrl.podLister.Pods(vm.Namespace).List(labels.Set{ranchervm.LabelVMName: vm.Name}.AsSelector())
+	if err != nil {
+		return false, err
+	}
+	return len(pods) == 0, nil
+}
+
+// createServerSecret creates the secret holding the token the export's pod
+// requires of the console server. It is named after the pod and owned by the
+// export. Returns false if the pod can't be created yet.
+func (ctrl *ExportController) createServerSecret(export *vmapi.VirtualMachineExport) bool {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		glog.V(2).Infof("error generating token for export %s/%s: %v", export.Namespace, export.Name, err)
+		return false
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            exportPodName(export),
+			Namespace:       export.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*newExportRef(export)},
+		},
+		Data: map[string][]byte{
+			console.SecretServerToken: []byte(hex.EncodeToString(token)),
+		},
+	}
+	_, err := ctrl.kubeClient.CoreV1().Secrets(export.Namespace).Create(secret)
+	if err == nil {
+		return true
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		glog.V(2).Infof("error creating secret for export %s/%s: %v", export.Namespace, export.Name, err)
+		return false
+	}
+	// A secret made by anyone else would give its token away
+	existing, err := ctrl.kubeClient.CoreV1().Secrets(export.Namespace).Get(secret.Name, metav1.GetOptions{})
+	if err != nil {
+		glog.V(2).Infof("error getting secret %s/%s: %v", export.Namespace, secret.Name, err)
+		return false
+	}
+	if ref := metav1.GetControllerOf(existing); ref == nil || ref.UID != export.UID {
+		ctrl.failExport(export, fmt.Sprintf("secret %s already exists", secret.Name))
+		return false
+	}
+	return true
+}
+
+// newExportPod returns the pod converting the VM's disks and serving the
+// bundle. Disks are mounted read-only where the launcher expects them, with
+// scratch space for the converted images alongside.
+func (ctrl *ExportController) newExportPod(export *vmapi.VirtualMachineExport, vm *vmapi.VirtualMachine) (*corev1.Pod, error) {
+	manifest := &bundle.Manifest{
+		VirtualMachineName: vm.Name,
+		VirtualMachineSpec: *vm.Spec.DeepCopy(),
+		Disks:              []bundle.Disk{},
+	}
+	// Instance types are exported by name, while OVAs need the VM's size
+	if export.Spec.Format == vmapi.ExportFormatOVA && vm.Spec.InstanceType != "" {
+		if vm.Status.InstanceType == nil {
+			return nil, fmt.Errorf("vm %s hasn't been started with instance type %s", vm.Name, vm.Spec.InstanceType)
+		}
+		manifest.VirtualMachineSpec.CpuMillis = vm.Status.InstanceType.Spec.CpuMillis
+		manifest.VirtualMachineSpec.MemoryMB = vm.Status.InstanceType.Spec.MemoryMB
+	}
+	for _, disk := range vm.Spec.Disks {
+		manifest.Disks = append(manifest.Disks, bundle.Disk{Name: disk.Name})
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      exportPodName(export),
+			Namespace: export.Namespace,
+			Labels: map[string]string{
+				LabelExport: export.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{*newExportRef(export)},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				corev1.Container{
+					Name:    "export",
+					Image:   ctrl.launcherImage,
+					Command: launcher.ExportCommand(string(export.Spec.Format)),
+					Env: []corev1.EnvVar{
+						corev1.EnvVar{Name: launcher.EnvBundleManifest, Value: string(data)},
+						corev1.EnvVar{
+							Name: launcher.EnvServerToken,
+							ValueFrom: &corev1.EnvVarSource{
+								SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: exportPodName(export)},
+									Key:                  console.SecretServerToken,
+								},
+							},
+						},
+					},
+					Ports: []corev1.ContainerPort{
+						corev1.ContainerPort{
+							Name:          "http",
+							ContainerPort: launcher.ExportPort,
+							Protocol:      corev1.ProtocolTCP,
+						},
+					},
+					ReadinessProbe: &corev1.Probe{
+						Handler: corev1.Handler{
+							HTTPGet: &corev1.HTTPGetAction{
+								Path: "/healthz",
+								Port: intstr.FromString("http"),
+							},
+						},
+						PeriodSeconds: 5,
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						corev1.VolumeMount{
+							Name:      "scratch",
+							MountPath: launcher.ScratchDir,
+						},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				corev1.Volume{
+					Name: "scratch",
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+			},
+		},
+	}
+	container := &pod.Spec.Containers[0]
+	for _, disk := range vm.Spec.Disks {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: "disk-" + disk.Name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: disk.ClaimName,
+					ReadOnly:  true,
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "disk-" + disk.Name,
+			MountPath: filepath.Join(launcher.DiskDir, disk.Name),
+			ReadOnly:  true,
+		})
+	}
+	return pod, nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func (ctrl *ExportController) failExport(export *vmapi.VirtualMachineExport, message string) {
+	export.Status.Phase = vmapi.ExportFailed
+	export.Status.Message = message
+	ctrl.recorder.Event(export, corev1.EventTypeWarning, "Failed", message)
+	ctrl.updateExportStatus(export)
+}
+
+func (ctrl *ExportController) updateExportStatus(export *vmapi.VirtualMachineExport) {
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineExports(export.Namespace).Update(export)
+	if err != nil {
+		glog.V(2).Infof("error updating status of export %s/%s: %v", export.Namespace, export.Name, err)
+	}
+}
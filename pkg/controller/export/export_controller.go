@@ -0,0 +1,257 @@
+package export
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	batchinformers "k8s.io/client-go/informers/batch/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	vmclientset "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned"
+	vmscheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	vminformers "github.com/llparse/kube-crd-skel/pkg/client/informers/externalversions/virtualmachine/v1alpha1"
+	vmlisters "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
+)
+
+const (
+	// LabelExport is set on export pods to the export's name
+	LabelExport = "vm.rancher.com/export"
+	// LabelImport is set on claims created for an import to its name
+	LabelImport = "vm.rancher.com/import"
+
+	// Exports wait for their VM to stop, and imports retry API errors, at
+	// this interval
+	pollInterval = 5 * time.Second
+)
+
+// ExportController serves VirtualMachineExports and carries out
+// VirtualMachineImports
+type ExportController struct {
+	vmClient   vmclientset.Interface
+	kubeClient kubernetes.Interface
+
+	vmLister           vmlisters.VirtualMachineLister
+	vmListerSynced     cache.InformerSynced
+	exportLister       vmlisters.VirtualMachineExportLister
+	exportListerSynced cache.InformerSynced
+	importLister       vmlisters.VirtualMachineImportLister
+	importListerSynced cache.InformerSynced
+	podLister          corelisters.PodLister
+	podListerSynced    cache.InformerSynced
+	pvcLister          corelisters.PersistentVolumeClaimLister
+	pvcListerSynced    cache.InformerSynced
+	jobLister          batchlisters.JobLister
+	jobListerSynced    cache.InformerSynced
+
+	exportQueue workqueue.RateLimitingInterface
+	importQueue workqueue.RateLimitingInterface
+
+	recorder record.EventRecorder
+
+	launcherImage string
+}
+
+func NewExportController(
+	vmClient vmclientset.Interface,
+	kubeClient kubernetes.Interface,
+	vmInformer vminformers.VirtualMachineInformer,
+	exportInformer vminformers.VirtualMachineExportInformer,
+	importInformer vminformers.VirtualMachineImportInformer,
+	podInformer coreinformers.PodInformer,
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+	jobInformer batchinformers.JobInformer,
+	launcherImage string,
+) *ExportController {
+
+	ctrl := &ExportController{
+		vmClient:      vmClient,
+		kubeClient:    kubeClient,
+		exportQueue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachineexport"),
+		importQueue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachineimport"),
+		launcherImage: launcherImage,
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(glog.Infof)
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	ctrl.recorder = broadcaster.NewRecorder(vmscheme.Scheme, corev1.EventSource{Component: "vm-export-controller"})
+
+	exportInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueWork(ctrl.exportQueue, obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueWork(ctrl.exportQueue, newObj) },
+		},
+	)
+
+	importInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueWork(ctrl.importQueue, obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueWork(ctrl.importQueue, newObj) },
+		},
+	)
+
+	// Exports own their pod, and imports own their jobs
+	podInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueOwner(newObj) },
+			DeleteFunc: func(obj interface{}) { ctrl.enqueueOwner(obj) },
+		},
+	)
+	jobInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueOwner(newObj) },
+		},
+	)
+
+	ctrl.vmLister = vmInformer.Lister()
+	ctrl.vmListerSynced = vmInformer.Informer().HasSynced
+
+	ctrl.exportLister = exportInformer.Lister()
+	ctrl.exportListerSynced = exportInformer.Informer().HasSynced
+
+	ctrl.importLister = importInformer.Lister()
+	ctrl.importListerSynced = importInformer.Informer().HasSynced
+
+	ctrl.podLister = podInformer.Lister()
+	ctrl.podListerSynced = podInformer.Informer().HasSynced
+
+	ctrl.pvcLister = pvcInformer.Lister()
+	ctrl.pvcListerSynced = pvcInformer.Informer().HasSynced
+
+	ctrl.jobLister = jobInformer.Lister()
+	ctrl.jobListerSynced = jobInformer.Informer().HasSynced
+
+	return ctrl
+}
+
+func (ctrl *ExportController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.exportQueue.ShutDown()
+	defer ctrl.importQueue.ShutDown()
+
+	glog.Infof("Starting export controller")
+	defer glog.Infof("Shutting down export controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.vmListerSynced, ctrl.exportListerSynced, ctrl.importListerSynced,
+		ctrl.podListerSynced, ctrl.pvcListerSynced, ctrl.jobListerSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.exportWorker, time.Second, stopCh)
+		go wait.Until(ctrl.importWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (ctrl *ExportController) enqueueWork(queue workqueue.Interface, obj interface{}) {
+	// Beware of "xxx deleted" events
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	objName, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		glog.Errorf("failed to get key from object: %v", err)
+		return
+	}
+	glog.V(5).Infof("enqueued %q for sync", objName)
+	queue.Add(objName)
+}
+
+func (ctrl *ExportController) enqueueOwner(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	ref := metav1.GetControllerOf(meta)
+	if ref == nil {
+		return
+	}
+	switch ref.Kind {
+	case "VirtualMachineExport":
+		ctrl.exportQueue.Add(meta.GetNamespace() + "/" + ref.Name)
+	case "VirtualMachineImport":
+		ctrl.importQueue.Add(meta.GetNamespace() + "/" + ref.Name)
+	}
+}
+
+func (ctrl *ExportController) exportWorker() {
+	workFunc := func() bool {
+		keyObj, quit := ctrl.exportQueue.Get()
+		if quit {
+			return true
+		}
+		defer ctrl.exportQueue.Done(keyObj)
+		key := keyObj.(string)
+		glog.V(5).Infof("exportWorker[%s]", key)
+
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			glog.V(4).Infof("error getting name of export %q to get export from informer: %v", key, err)
+			return false
+		}
+		export, err := ctrl.exportLister.VirtualMachineExports(ns).Get(name)
+		if err == nil {
+			ctrl.updateExport(export)
+			return false
+		}
+		if !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error getting export %q from informer: %v", key, err)
+		}
+		// The pods of deleted exports are garbage collected
+		return false
+	}
+	for {
+		if quit := workFunc(); quit {
+			glog.Infof("export worker queue shutting down")
+			return
+		}
+	}
+}
+
+func (ctrl *ExportController) importWorker() {
+	workFunc := func() bool {
+		keyObj, quit := ctrl.importQueue.Get()
+		if quit {
+			return true
+		}
+		defer ctrl.importQueue.Done(keyObj)
+		key := keyObj.(string)
+		glog.V(5).Infof("importWorker[%s]", key)
+
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			glog.V(4).Infof("error getting name of import %q to get import from informer: %v", key, err)
+			return false
+		}
+		imp, err := ctrl.importLister.VirtualMachineImports(ns).Get(name)
+		if err == nil {
+			ctrl.updateImport(imp)
+			return false
+		}
+		if !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error getting import %q from informer: %v", key, err)
+		}
+		return false
+	}
+	for {
+		if quit := workFunc(); quit {
+			glog.Infof("import worker queue shutting down")
+			return
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/golang/glog"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/bundle"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+const (
+	// AnnotationImport is set on VMs created by an import to the import's
+	// name
+	AnnotationImport = "vm.rancher.com/import"
+
+	// SecretToken is the key of an import's token secret
+	SecretToken = "token"
+
+	targetDir = "/target"
+
+	// Jobs retry transient errors of the bundle's server
+	jobBackoffLimit = 5
+)
+
+func newImportRef(imp *vmapi.VirtualMachineImport) *metav1.OwnerReference {
+	return metav1.NewControllerRef(imp, vmapi.SchemeGroupVersion.WithKind("VirtualMachineImport"))
+}
+
+func (ctrl *ExportController) updateImport(imp *vmapi.VirtualMachineImport) {
+	switch imp.Status.Phase {
+	case vmapi.ImportComplete, vmapi.ImportFailed:
+		return
+	}
+
+	// Never mutate objects from the informer cache
+	original := imp
+	imp = imp.DeepCopy()
+
+	if imp.Status.VirtualMachineSpec == nil {
+		if u, err := url.Parse(imp.Spec.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			ctrl.failImport(imp, fmt.Sprintf("url %q is not an http or https URL", imp.Spec.URL))
+			return
+		}
+		if err := ctrl.checkTokenSecret(imp); err != nil {
+			ctrl.failImport(imp, err.Error())
+			return
+		}
+		manifest, err := ctrl.syncDescriptor(imp)
+		if err != nil {
+			ctrl.failImport(imp, err.Error())
+			return
+		}
+		if manifest == nil {
+			imp.Status.Phase = vmapi.ImportPending
+			if !apiequality.Semantic.DeepEqual(original.Status, imp.Status) {
+				ctrl.updateImportStatus(imp)
+			}
+			return
+		}
+
+		vmName := imp.Spec.VirtualMachineName
+		if vmName == "" {
+			vmName = manifest.VirtualMachineName
+		}
+		if _, err := ctrl.vmLister.VirtualMachines(imp.Namespace).Get(vmName); err == nil {
+			ctrl.failImport(imp, fmt.Sprintf("vm %s already exists", vmName))
+			return
+		}
+		spec := importedSpec(manifest, vmName)
+		imp.Status.Phase = vmapi.ImportInProgress
+		imp.Status.VirtualMachineName = vmName
+		imp.Status.VirtualMachineSpec = &spec
+		imp.Status.Disks = []vmapi.DiskImportStatus{}
+		imp.Status.Message = ""
+		for _, disk := range manifest.Disks {
+			imp.Status.Disks = append(imp.Status.Disks, vmapi.DiskImportStatus{
+				Name:          disk.Name,
+				File:          disk.File,
+				CapacityBytes: disk.CapacityBytes,
+				ClaimName:     vmName + "-" + disk.Name,
+			})
+		}
+		// The resulting update event will requeue the import
+		ctrl.updateImportStatus(imp)
+		return
+	}
+
+	vmName := imp.Status.VirtualMachineName
+	if vm, err := ctrl.vmLister.VirtualMachines(imp.Namespace).Get(vmName); err == nil {
+		if vm.Annotations[AnnotationImport] != imp.Name {
+			ctrl.failImport(imp, fmt.Sprintf("vm %s already exists", vmName))
+			return
+		}
+	}
+
+	ready := true
+	for i := range imp.Status.Disks {
+		disk := &imp.Status.Disks[i]
+		if disk.Ready {
+			continue
+		}
+		if err := ctrl.syncDiskImport(imp, disk); err != nil {
+			ctrl.failImport(imp, fmt.Sprintf("disk %s: %v", disk.Name, err))
+			return
+		}
+		ready = ready && disk.Ready
+	}
+	if !ready {
+		if !apiequality.Semantic.DeepEqual(original.Status, imp.Status) {
+			ctrl.updateImportStatus(imp)
+		}
+		return
+	}
+
+	vm := &vmapi.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vmName,
+			Namespace: imp.Namespace,
+			Annotations: map[string]string{
+				AnnotationImport: imp.Name,
+			},
+		},
+		Spec: *imp.Status.VirtualMachineSpec.DeepCopy(),
+	}
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachines(vm.Namespace).Create(vm)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		glog.V(2).Infof("error creating vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		return
+	}
+
+	now := metav1.Now()
+	imp.Status.Phase = vmapi.ImportComplete
+	imp.Status.CompletionTime = &now
+	ctrl.recorder.Eventf(imp, corev1.EventTypeNormal, "Complete", "Created vm %s", vm.Name)
+	ctrl.updateImportStatus(imp)
+}
+
+// checkTokenSecret checks that the import's token secret, if any, has a
+// token for its jobs
+func (ctrl *ExportController) checkTokenSecret(imp *vmapi.VirtualMachineImport) error {
+	if imp.Spec.TokenSecret == "" {
+		return nil
+	}
+	secret, err := ctrl.kubeClient.CoreV1().Secrets(imp.Namespace).Get(imp.Spec.TokenSecret, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting token secret %s: %v", imp.Spec.TokenSecret, err)
+	}
+	if len(secret.Data[SecretToken]) == 0 {
+		return fmt.Errorf("secret %s has no %s", secret.Name, SecretToken)
+	}
+	return nil
+}
+
+// syncDescriptor starts the job reading the descriptor of the import's
+// bundle, and returns the manifest it read once it completed. The bundle's
+// URL is chosen by users, so its server is only ever contacted from the
+// import's pods.
+func (ctrl *ExportController) syncDescriptor(imp *vmapi.VirtualMachineImport) (*bundle.Manifest, error) {
+	key := imp.Namespace + "/" + imp.Name
+	job, err := ctrl.jobLister.Jobs(imp.Namespace).Get(descriptorJobName(imp))
+	if apierrors.IsNotFound(err) {
+		job = ctrl.newDescriptorJob(imp)
+		if _, err := ctrl.kubeClient.BatchV1().Jobs(imp.Namespace).Create(job); err != nil && !apierrors.IsAlreadyExists(err) {
+			glog.V(2).Infof("error creating descriptor job for import %s/%s: %v", imp.Namespace, imp.Name, err)
+			ctrl.importQueue.AddAfter(key, pollInterval)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		glog.V(2).Infof("error getting descriptor job of import %s/%s: %v", imp.Namespace, imp.Name, err)
+		ctrl.importQueue.AddAfter(key, pollInterval)
+		return nil, nil
+	}
+
+	complete, failed := jobStatus(job)
+	if !complete && !failed {
+		return nil, nil
+	}
+	message, err := ctrl.jobMessage(job, complete)
+	if err != nil {
+		glog.V(2).Infof("error listing pods of job %s/%s: %v", job.Namespace, job.Name, err)
+		ctrl.importQueue.AddAfter(key, pollInterval)
+		return nil, nil
+	}
+	if failed {
+		if message == "" {
+			message = fmt.Sprintf("job %s failed", job.Name)
+		}
+		return nil, fmt.Errorf("error reading descriptor: %s", message)
+	}
+	if message == "" {
+		// The pod's status may lag behind the job's
+		ctrl.importQueue.AddAfter(key, pollInterval)
+		return nil, nil
+	}
+	manifest := &bundle.Manifest{}
+	if err := json.Unmarshal([]byte(message), manifest); err != nil {
+		return nil, fmt.Errorf("invalid descriptor: %v", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// jobMessage returns the termination message of the last pod of the job that
+// succeeded, or failed
+func (ctrl *ExportController) jobMessage(job *batchv1.Job, succeeded bool) (string, error) {
+	pods, err := ctrl.podLister.Pods(job.Namespace).List(labels.Set{"controller-uid": string(job.UID)}.AsSelector())
+	if err != nil {
+		return "", err
+	}
+	var last *corev1.ContainerStateTerminated
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			terminated := status.State.Terminated
+			if terminated == nil || (terminated.ExitCode == 0) != succeeded {
+				continue
+			}
+			if last == nil || last.FinishedAt.Before(&terminated.FinishedAt) {
+				last = terminated
+			}
+		}
+	}
+	if last == nil {
+		return "", nil
+	}
+	return strings.TrimSpace(last.Message), nil
+}
+
+// importedSpec returns the bundle's spec using the imported disks. The VM is
+// created stopped, so that its instance type, preference and networks can be
+// checked against this cluster first. Node ports can't be shared, and VMs
+// imported under another name get their own MAC addresses.
+func importedSpec(manifest *bundle.Manifest, vmName string) vmapi.VirtualMachineSpec {
+	spec := *manifest.VirtualMachineSpec.DeepCopy()
+	spec.Stopped = true
+	for i := range spec.Disks {
+		spec.Disks[i].ClaimName = vmName + "-" + spec.Disks[i].Name
+	}
+	for i := range spec.Ports {
+		spec.Ports[i].NodePort = 0
+	}
+	if vmName != manifest.VirtualMachineName {
+		for i := range spec.Interfaces {
+			spec.Interfaces[i].MACAddress = ""
+		}
+	}
+	return spec
+}
+
+// syncDiskImport creates the claim a disk is imported to and the job
+// downloading it, and reports whether the disk is imported. Claims are
+// labeled rather than owned, so that they outlive the import.
+func (ctrl *ExportController) syncDiskImport(imp *vmapi.VirtualMachineImport, disk *vmapi.DiskImportStatus) error {
+	claim, err := ctrl.pvcLister.PersistentVolumeClaims(imp.Namespace).Get(disk.ClaimName)
+	if apierrors.IsNotFound(err) {
+		claim = newImportClaim(imp, disk)
+		if _, err := ctrl.kubeClient.CoreV1().PersistentVolumeClaims(imp.Namespace).Create(claim); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else if claim.Labels[LabelImport] != imp.Name {
+		return fmt.Errorf("claim %s already exists", claim.Name)
+	}
+
+	job, err := ctrl.jobLister.Jobs(imp.Namespace).Get(diskJobName(imp, disk.Name))
+	if apierrors.IsNotFound(err) {
+		job = ctrl.newImportJob(imp, *disk)
+		if _, err := ctrl.kubeClient.BatchV1().Jobs(imp.Namespace).Create(job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	complete, failed := jobStatus(job)
+	if failed {
+		return fmt.Errorf("import job %s failed", job.Name)
+	}
+	disk.Ready = complete
+	return nil
+}
+
+// newImportClaim returns the claim a disk is imported to. The raw image is
+// as large as the disk's capacity, and the claim's filesystem needs room
+// beyond it.
+func newImportClaim(imp *vmapi.VirtualMachineImport, disk *vmapi.DiskImportStatus) *corev1.PersistentVolumeClaim {
+	sizeMB := (disk.CapacityBytes + 1<<20 - 1) >> 20
+	sizeMB = sizeMB*100/90 + 1
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      disk.ClaimName,
+			Namespace: imp.Namespace,
+			Labels: map[string]string{
+				ranchervm.LabelVMName: imp.Status.VirtualMachineName,
+				LabelImport:           imp.Name,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(fmt.Sprintf("%dMi", sizeMB)),
+				},
+			},
+			StorageClassName: imp.Spec.StorageClassName,
+		},
+	}
+}
+
+func descriptorJobName(imp *vmapi.VirtualMachineImport) string {
+	return imp.Name + "-descriptor"
+}
+
+func diskJobName(imp *vmapi.VirtualMachineImport, disk string) string {
+	return imp.Name + "-disk-" + disk
+}
+
+// tokenEnv passes the token of the import's token secret, if any, to its
+// jobs
+func tokenEnv(imp *vmapi.VirtualMachineImport) []corev1.EnvVar {
+	if imp.Spec.TokenSecret == "" {
+		return nil
+	}
+	return []corev1.EnvVar{
+		corev1.EnvVar{
+			Name: launcher.EnvBundleToken,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: imp.Spec.TokenSecret},
+					Key:                  SecretToken,
+				},
+			},
+		},
+	}
+}
+
+// newDescriptorJob returns a Job reading the descriptor of the bundle, which
+// its pod reports as its termination message
+func (ctrl *ExportController) newDescriptorJob(imp *vmapi.VirtualMachineImport) *batchv1.Job {
+	backoffLimit := int32(jobBackoffLimit)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            descriptorJobName(imp),
+			Namespace:       imp.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*newImportRef(imp)},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						corev1.Container{
+							Name:    "descriptor",
+							Image:   ctrl.launcherImage,
+							Command: launcher.ReadDescriptorCommand(imp.Spec.URL),
+							Env:     tokenEnv(imp),
+							// Errors are logged by the launcher as it exits
+							TerminationMessagePath:   launcher.TerminationLog,
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newImportJob returns a Job downloading a disk of the bundle and converting
+// it to a raw image on its claim
+func (ctrl *ExportController) newImportJob(imp *vmapi.VirtualMachineImport, disk vmapi.DiskImportStatus) *batchv1.Job {
+	backoffLimit := int32(jobBackoffLimit)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            diskJobName(imp, disk.Name),
+			Namespace:       imp.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*newImportRef(imp)},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						corev1.Container{
+							Name:    "import",
+							Image:   ctrl.launcherImage,
+							Command: launcher.ImportDiskCommand(imp.Spec.URL, disk.File, targetDir+"/disk.img"),
+							Env:     tokenEnv(imp),
+							VolumeMounts: []corev1.VolumeMount{
+								corev1.VolumeMount{
+									Name:      "disk",
+									MountPath: targetDir,
+								},
+								corev1.VolumeMount{
+									Name:      "scratch",
+									MountPath: launcher.ScratchDir,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						corev1.Volume{
+							Name: "disk",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: disk.ClaimName,
+								},
+							},
+						},
+						corev1.Volume{
+							Name: "scratch",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// jobStatus returns whether job completed and whether it failed for good
+func jobStatus(job *batchv1.Job) (complete bool, failed bool) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			complete = true
+		case batchv1.JobFailed:
+			failed = true
+		}
+	}
+	return
+}
+
+func (ctrl *ExportController) failImport(imp *vmapi.VirtualMachineImport, message string) {
+	imp.Status.Phase = vmapi.ImportFailed
+	imp.Status.Message = message
+	ctrl.recorder.Event(imp, corev1.EventTypeWarning, "Failed", message)
+	ctrl.updateImportStatus(imp)
+}
+
+func (ctrl *ExportController) updateImportStatus(imp *vmapi.VirtualMachineImport) {
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineImports(imp.Namespace).Update(imp)
+	if err != nil {
+		glog.V(2).Infof("error updating status of import %s/%s: %v", imp.Namespace, imp.Name, err)
+	}
+}
@@ -0,0 +1,93 @@
+package guestagent
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/console"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+const (
+	// balloonHeadroom is the share of its used memory a guest is left on top
+	// of it, so that it can grow until the next poll
+	balloonHeadroom = 4
+	// balloonStep is the share of guest memory the target must move by for
+	// the balloon to be adjusted, so that it isn't for every page
+	balloonStep = 20
+)
+
+// balloon reads the memory of a VM's guest and, if the VM is overcommitted,
+// balloons it down to what it uses, no lower than its pod's request. Returns
+// nil if the memory can't be read.
+func (ctrl *GuestAgentController) balloon(vm *vmapi.VirtualMachine, pod *corev1.Pod) *vmapi.MemoryStatus {
+	out, err := console.ExecOutput(ctrl.config, ctrl.kubeClient, pod, launcher.MemoryStatsCommand)
+	if err != nil {
+		glog.V(4).Infof("error querying memory of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		return nil
+	}
+	memory := &vmapi.MemoryStatus{}
+	if err := json.Unmarshal(out, memory); err != nil {
+		glog.V(2).Infof("error parsing memory of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		return nil
+	}
+	if vm.Status.Memory != nil {
+		memory.TargetMB = vm.Status.Memory.TargetMB
+	}
+
+	// The pod's annotations hold the sizes it was started with
+	guestMB, err := strconv.ParseInt(pod.Annotations[ranchervm.GroupName+"/memory_mb"], 10, 64)
+	if err != nil {
+		return memory
+	}
+	if hotplugged, err := launcher.RecordedHotplugState(pod.Annotations, console.LauncherRestarts(pod)); err == nil && hotplugged != nil {
+		guestMB += int64(hotplugged.MemoryMB())
+	}
+	requestMB, err := strconv.ParseInt(pod.Annotations[ranchervm.GroupName+"/memory_request_mb"], 10, 64)
+	if err != nil {
+		return memory
+	}
+	target, ok := balloonTarget(memory, requestMB, guestMB)
+	if !ok {
+		return memory
+	}
+
+	if _, err := console.ExecOutput(ctrl.config, ctrl.kubeClient, pod, launcher.BalloonCommand(target)); err != nil {
+		glog.V(2).Infof("error ballooning vm %s/%s to %dMB: %v", vm.Namespace, vm.Name, target, err)
+		return memory
+	}
+	glog.V(4).Infof("ballooned vm %s/%s from %dMB to %dMB", vm.Namespace, vm.Name, memory.ActualMB, target)
+	memory.TargetMB = target
+	return memory
+}
+
+// balloonTarget returns the size to balloon a guest of guestMB to, given its
+// memory and its pod's request. Returns false if the balloon should be left
+// alone: the guest isn't overcommitted, its usage is unknown, or the target
+// is too close to its current size.
+func balloonTarget(memory *vmapi.MemoryStatus, requestMB, guestMB int64) (int64, bool) {
+	if requestMB >= guestMB || memory.UsedMB == 0 {
+		return 0, false
+	}
+
+	target := memory.UsedMB + memory.UsedMB/balloonHeadroom
+	if target < requestMB {
+		target = requestMB
+	}
+	if target > guestMB {
+		target = guestMB
+	}
+	diff := target - memory.ActualMB
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff < guestMB/balloonStep {
+		return 0, false
+	}
+	return target, true
+}
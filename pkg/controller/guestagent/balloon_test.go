@@ -0,0 +1,83 @@
+package guestagent
+
+import (
+	"testing"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func TestBalloonTarget(t *testing.T) {
+	tests := []struct {
+		name      string
+		actualMB  int64
+		usedMB    int64
+		requestMB int64
+		guestMB   int64
+		want      int64
+		wantOK    bool
+	}{
+		{
+			name:     "used plus a quarter",
+			actualMB: 4096, usedMB: 1600, requestMB: 1024, guestMB: 4096,
+			want: 2000, wantOK: true,
+		},
+		{
+			name:     "clamped to the request",
+			actualMB: 4096, usedMB: 400, requestMB: 1024, guestMB: 4096,
+			want: 1024, wantOK: true,
+		},
+		{
+			name:     "clamped to the guest",
+			actualMB: 2048, usedMB: 3600, requestMB: 1024, guestMB: 4096,
+			want: 4096, wantOK: true,
+		},
+		{
+			name:     "exactly the request",
+			actualMB: 4096, usedMB: 800, requestMB: 1000, guestMB: 4096,
+			want: 1000, wantOK: true,
+		},
+		{
+			name:     "exactly the guest",
+			actualMB: 1024, usedMB: 3277, requestMB: 1024, guestMB: 4096,
+			want: 4096, wantOK: true,
+		},
+		{
+			name:     "grows back",
+			actualMB: 1024, usedMB: 1600, requestMB: 1024, guestMB: 4096,
+			want: 2000, wantOK: true,
+		},
+		{
+			name:     "less than a step away",
+			actualMB: 2100, usedMB: 1600, requestMB: 1024, guestMB: 4096,
+		},
+		{
+			name:     "a step away",
+			actualMB: 2204, usedMB: 1600, requestMB: 1024, guestMB: 4096,
+			want: 2000, wantOK: true,
+		},
+		{
+			name:     "clamped target already reached",
+			actualMB: 1024, usedMB: 400, requestMB: 1024, guestMB: 4096,
+		},
+		{
+			name:     "not overcommitted",
+			actualMB: 4096, usedMB: 400, requestMB: 4096, guestMB: 4096,
+		},
+		{
+			name:     "request above the guest",
+			actualMB: 4096, usedMB: 400, requestMB: 8192, guestMB: 4096,
+		},
+		{
+			name:     "no usage stats",
+			actualMB: 4096, requestMB: 1024, guestMB: 4096,
+		},
+	}
+
+	for _, test := range tests {
+		memory := &vmapi.MemoryStatus{ActualMB: test.actualMB, UsedMB: test.usedMB}
+		got, ok := balloonTarget(memory, test.requestMB, test.guestMB)
+		if ok != test.wantOK || got != test.want {
+			t.Errorf("%s: got %dMB, %v, want %dMB, %v", test.name, got, ok, test.want, test.wantOK)
+		}
+	}
+}
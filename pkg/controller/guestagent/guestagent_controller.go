@@ -0,0 +1,202 @@
+package guestagent
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	vmclientset "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned"
+	vminformers "github.com/llparse/kube-crd-skel/pkg/client/informers/externalversions/virtualmachine/v1alpha1"
+	vmlisters "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/console"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+// GuestAgentController polls the guest agents of running VMs, copying what
+// they report into the VMs' status
+type GuestAgentController struct {
+	config     *rest.Config
+	vmClient   vmclientset.Interface
+	kubeClient kubernetes.Interface
+
+	vmLister        vmlisters.VirtualMachineLister
+	vmListerSynced  cache.InformerSynced
+	podLister       corelisters.PodLister
+	podListerSynced cache.InformerSynced
+
+	vmQueue workqueue.RateLimitingInterface
+
+	// polling holds the keys of VMs being polled, so that every VM is
+	// polled by a single chain of delayed requeues
+	pollingLock sync.Mutex
+	polling     map[string]bool
+
+	pollInterval time.Duration
+}
+
+func NewGuestAgentController(
+	config *rest.Config,
+	vmClient vmclientset.Interface,
+	kubeClient kubernetes.Interface,
+	vmInformer vminformers.VirtualMachineInformer,
+	podInformer coreinformers.PodInformer,
+	pollInterval time.Duration,
+) *GuestAgentController {
+
+	ctrl := &GuestAgentController{
+		config:       config,
+		vmClient:     vmClient,
+		kubeClient:   kubeClient,
+		vmQueue:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "guestagent"),
+		polling:      map[string]bool{},
+		pollInterval: pollInterval,
+	}
+
+	// Status updates made here would retrigger polls, so VMs are only
+	// queued as they appear and requeue themselves from then on
+	vmInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: ctrl.startPolling,
+		},
+	)
+
+	ctrl.vmLister = vmInformer.Lister()
+	ctrl.vmListerSynced = vmInformer.Informer().HasSynced
+
+	ctrl.podLister = podInformer.Lister()
+	ctrl.podListerSynced = podInformer.Informer().HasSynced
+
+	return ctrl
+}
+
+func (ctrl *GuestAgentController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.vmQueue.ShutDown()
+
+	glog.Infof("Starting guest agent controller")
+	defer glog.Infof("Shutting down guest agent controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.vmListerSynced, ctrl.podListerSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.vmWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (ctrl *GuestAgentController) startPolling(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		glog.Errorf("failed to get key from object: %v", err)
+		return
+	}
+
+	ctrl.pollingLock.Lock()
+	defer ctrl.pollingLock.Unlock()
+	if ctrl.polling[key] {
+		return
+	}
+	ctrl.polling[key] = true
+	ctrl.vmQueue.Add(key)
+}
+
+func (ctrl *GuestAgentController) stopPolling(key string) {
+	ctrl.pollingLock.Lock()
+	defer ctrl.pollingLock.Unlock()
+	delete(ctrl.polling, key)
+}
+
+func (ctrl *GuestAgentController) vmWorker() {
+	workFunc := func() bool {
+		keyObj, quit := ctrl.vmQueue.Get()
+		if quit {
+			return true
+		}
+		defer ctrl.vmQueue.Done(keyObj)
+		key := keyObj.(string)
+		glog.V(5).Infof("vmWorker[%s]", key)
+
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			glog.V(4).Infof("error getting name of vm %q to get vm from informer: %v", key, err)
+			return false
+		}
+		vm, err := ctrl.vmLister.VirtualMachines(ns).Get(name)
+		if apierrors.IsNotFound(err) {
+			ctrl.stopPolling(key)
+			return false
+		}
+		if err != nil {
+			glog.V(2).Infof("error getting vm %q from informer: %v", key, err)
+		} else {
+			ctrl.pollVM(vm)
+		}
+		ctrl.vmQueue.AddAfter(key, ctrl.pollInterval)
+		return false
+	}
+	for {
+		if quit := workFunc(); quit {
+			glog.Infof("guest agent worker queue shutting down")
+			return
+		}
+	}
+}
+
+// pollVM queries the guest agent and balloon of a running VM. Guests
+// without an agent, and VMs that aren't running, have no guest info.
+func (ctrl *GuestAgentController) pollVM(vm *vmapi.VirtualMachine) {
+	var info *vmapi.GuestInfo
+	var memory *vmapi.MemoryStatus
+	if pod := ctrl.runningPod(vm); pod != nil {
+		out, err := console.ExecOutput(ctrl.config, ctrl.kubeClient, pod, launcher.GuestInfoCommand)
+		if err != nil {
+			glog.V(4).Infof("error querying guest agent of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		} else {
+			info = &vmapi.GuestInfo{}
+			if err := json.Unmarshal(out, info); err != nil {
+				glog.V(2).Infof("error parsing guest info of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+				return
+			}
+		}
+		memory = ctrl.balloon(vm, pod)
+	}
+
+	if apiequality.Semantic.DeepEqual(vm.Status.GuestInfo, info) &&
+		apiequality.Semantic.DeepEqual(vm.Status.Memory, memory) {
+		return
+	}
+	// Never mutate objects from the informer cache
+	vm = vm.DeepCopy()
+	vm.Status.GuestInfo = info
+	vm.Status.Memory = memory
+	if _, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachines(vm.Namespace).Update(vm); err != nil {
+		glog.V(2).Infof("error updating guest info of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+	}
+}
+
+// runningPod returns the VM's pod if it is running, or nil
+func (ctrl *GuestAgentController) runningPod(vm *vmapi.VirtualMachine) *corev1.Pod {
+	if !vm.Status.Running || vm.Status.PodName == "" {
+		return nil
+	}
+	pod, err := ctrl.podLister.Pods(vm.Namespace).Get(vm.Status.PodName)
+	if err != nil || pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return nil
+	}
+	return pod
+}
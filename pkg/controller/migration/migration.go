@@ -0,0 +1,402 @@
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/console"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+// The incoming QEMU may not be listening as soon as its pod runs, so
+// starting the migration is retried for a while
+const migrationStartTimeout = 30 * time.Second
+
+func (ctrl *MigrationController) updateMigration(migration *vmapi.VirtualMachineMigration) {
+	switch migration.Status.Phase {
+	case vmapi.MigrationSucceeded, vmapi.MigrationFailed:
+		return
+	}
+
+	// Never mutate objects from the informer cache
+	original := migration
+	migration = migration.DeepCopy()
+	key := migration.Namespace + "/" + migration.Name
+
+	vm, err := ctrl.vmLister.VirtualMachines(migration.Namespace).Get(migration.Spec.VirtualMachineName)
+	if apierrors.IsNotFound(err) {
+		ctrl.failMigration(migration, nil, fmt.Sprintf("vm %s was deleted", migration.Spec.VirtualMachineName))
+		return
+	}
+	if err != nil {
+		glog.V(2).Infof("error getting vm of migration %s/%s: %v", migration.Namespace, migration.Name, err)
+		return
+	}
+
+	switch migration.Status.Phase {
+	case "", vmapi.MigrationPending:
+		if err := ctrl.validateMigration(migration, vm); err != nil {
+			ctrl.failMigration(migration, nil, err.Error())
+			return
+		}
+		ctrl.startMigration(migration, vm)
+		return
+	}
+
+	if vm.Spec.Stopped {
+		ctrl.failMigration(migration, vm, "vm was stopped")
+		return
+	}
+	// Once the VM has been switched over only the handoff remains
+	if vm.Status.PodName == migration.Status.TargetPod {
+		ctrl.completeMigration(migration, vm)
+		return
+	}
+
+	source, err := ctrl.podLister.Pods(migration.Namespace).Get(migration.Status.SourcePod)
+	if apierrors.IsNotFound(err) || (err == nil && (source.DeletionTimestamp != nil || source.Status.Phase != corev1.PodRunning)) {
+		ctrl.failMigration(migration, vm, fmt.Sprintf("source pod %s is not running", migration.Status.SourcePod))
+		return
+	}
+	if err != nil {
+		glog.V(2).Infof("error getting source pod of migration %s/%s: %v", migration.Namespace, migration.Name, err)
+		return
+	}
+	target, err := ctrl.podLister.Pods(migration.Namespace).Get(migration.Status.TargetPod)
+	if apierrors.IsNotFound(err) || (err == nil && target.DeletionTimestamp != nil) {
+		ctrl.failMigration(migration, vm, fmt.Sprintf("target pod %s was deleted", migration.Status.TargetPod))
+		return
+	}
+	if err != nil {
+		glog.V(2).Infof("error getting target pod of migration %s/%s: %v", migration.Namespace, migration.Name, err)
+		return
+	}
+	if target.Status.Phase == corev1.PodFailed || target.Status.Phase == corev1.PodSucceeded {
+		ctrl.failMigration(migration, vm, fmt.Sprintf("target pod %s exited: %s", target.Name, target.Status.Message))
+		return
+	}
+
+	switch migration.Status.Phase {
+	case vmapi.MigrationScheduling:
+		ctrl.syncScheduling(migration, source, target)
+	case vmapi.MigrationMigrating:
+		ctrl.syncMigrating(migration, vm, source)
+	}
+	if migration.Status.Phase == vmapi.MigrationFailed {
+		return
+	}
+
+	if !apiequality.Semantic.DeepEqual(original.Status, migration.Status) {
+		ctrl.updateMigrationStatus(migration)
+	}
+	if migration.Status.Phase == vmapi.MigrationMigrating {
+		ctrl.migrationQueue.AddAfter(key, pollInterval)
+	}
+}
+
+// validateMigration checks that the VM can be live migrated
+func (ctrl *MigrationController) validateMigration(migration *vmapi.VirtualMachineMigration, vm *vmapi.VirtualMachine) error {
+	if vm.Spec.Stopped || vm.Status.Phase != vmapi.VirtualMachineRunning {
+		return fmt.Errorf("vm %s is not running", vm.Name)
+	}
+	if migration.Spec.NodeName != "" && migration.Spec.NodeName == vm.Status.NodeName {
+		return fmt.Errorf("vm %s is already running on node %s", vm.Name, vm.Status.NodeName)
+	}
+
+	// The guest owns the pod IP of bridged interfaces, and the target pod
+	// gets a different one
+	for _, iface := range vm.Spec.Interfaces {
+		if iface.Binding == vmapi.InterfaceBindingBridge && iface.Network == "" {
+			return fmt.Errorf("interface %s bridges the pod network", iface.Name)
+		}
+	}
+
+	// The target node's CPUs may not match the guest's NUMA topology
+	if vm.Spec.NUMAPassthrough {
+		return fmt.Errorf("vm %s has numa_passthrough", vm.Name)
+	}
+
+	// Hotplugged disks would have to be plugged into the same slots of the
+	// target's guest
+	if len(vm.Status.HotpluggedDisks) > 0 {
+		return fmt.Errorf("vm %s has hotplugged disks", vm.Name)
+	}
+
+	// Both pods have the disks attached during the handoff
+	for _, disk := range vm.Spec.Disks {
+		claim, err := ctrl.pvcLister.PersistentVolumeClaims(vm.Namespace).Get(disk.ClaimName)
+		if err != nil {
+			return fmt.Errorf("error getting claim %s of disk %s: %v", disk.ClaimName, disk.Name, err)
+		}
+		if !hasAccessMode(claim, corev1.ReadWriteMany) {
+			return fmt.Errorf("claim %s of disk %s is not ReadWriteMany", disk.ClaimName, disk.Name)
+		}
+	}
+
+	migrations, err := ctrl.migrationLister.VirtualMachineMigrations(migration.Namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, other := range migrations {
+		if other.Name == migration.Name || other.Spec.VirtualMachineName != vm.Name {
+			continue
+		}
+		switch other.Status.Phase {
+		case vmapi.MigrationSucceeded, vmapi.MigrationFailed:
+			continue
+		case "", vmapi.MigrationPending:
+			// Of two pending migrations the older one goes first
+			if !olderThan(other, migration) {
+				continue
+			}
+		}
+		return fmt.Errorf("vm %s is already being migrated by %s", vm.Name, other.Name)
+	}
+	return nil
+}
+
+func hasAccessMode(claim *corev1.PersistentVolumeClaim, mode corev1.PersistentVolumeAccessMode) bool {
+	for _, m := range claim.Spec.AccessModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+func olderThan(a, b *vmapi.VirtualMachineMigration) bool {
+	if a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.Name < b.Name
+	}
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
+}
+
+// startMigration creates the target pod
+func (ctrl *MigrationController) startMigration(migration *vmapi.VirtualMachineMigration, vm *vmapi.VirtualMachine) {
+	source, err := ctrl.podLister.Pods(vm.Namespace).Get(vm.Status.PodName)
+	if err != nil {
+		ctrl.failMigration(migration, nil, fmt.Sprintf("error getting pod of vm %s: %v", vm.Name, err))
+		return
+	}
+
+	if source.Status.PodIP == "" {
+		ctrl.failMigration(migration, nil, fmt.Sprintf("pod of vm %s has no address", vm.Name))
+		return
+	}
+
+	target := newTargetPod(migration, vm, source)
+	if _, err := ctrl.kubeClient.CoreV1().Pods(target.Namespace).Create(target); err != nil && !apierrors.IsAlreadyExists(err) {
+		ctrl.failMigration(migration, nil, fmt.Sprintf("error creating target pod: %v", err))
+		return
+	}
+	ctrl.recorder.Eventf(migration, corev1.EventTypeNormal, "Scheduling", "Created target pod %s", target.Name)
+
+	migration.Status.Phase = vmapi.MigrationScheduling
+	migration.Status.SourcePod = source.Name
+	migration.Status.SourceNode = source.Spec.NodeName
+	migration.Status.TargetPod = target.Name
+	ctrl.updateMigrationStatus(migration)
+}
+
+// syncScheduling waits for the target pod to run, then starts streaming the
+// guest to it
+func (ctrl *MigrationController) syncScheduling(migration *vmapi.VirtualMachineMigration, source, target *corev1.Pod) {
+	migration.Status.TargetNode = target.Spec.NodeName
+	if reason := unschedulableReason(target); reason != "" {
+		if migration.Status.Message != reason {
+			ctrl.recorder.Eventf(migration, corev1.EventTypeWarning, "Unschedulable", "Target pod is unschedulable: %s", reason)
+		}
+		migration.Status.Message = reason
+		return
+	}
+	if target.Status.Phase != corev1.PodRunning || target.Status.PodIP == "" {
+		return
+	}
+
+	// An earlier attempt may have started the migration even though its
+	// exec failed, and QEMU refuses a second one
+	info, err := ctrl.migrationStatus(source)
+	if err != nil {
+		glog.V(2).Infof("error getting status of migration %s/%s: %v", migration.Namespace, migration.Name, err)
+		ctrl.migrationQueue.AddAfter(migration.Namespace+"/"+migration.Name, pollInterval)
+		return
+	}
+	if !migrationStarted(info) {
+		err = ctrl.execMigrate(source, target)
+	}
+	if err != nil {
+		if target.Status.StartTime != nil && time.Since(target.Status.StartTime.Time) > migrationStartTimeout {
+			ctrl.failMigration(migration, nil, fmt.Sprintf("error starting migration: %v", err))
+			return
+		}
+		glog.V(2).Infof("error starting migration %s/%s, retrying: %v", migration.Namespace, migration.Name, err)
+		ctrl.migrationQueue.AddAfter(migration.Namespace+"/"+migration.Name, pollInterval)
+		return
+	}
+
+	now := metav1.Now()
+	migration.Status.Phase = vmapi.MigrationMigrating
+	migration.Status.StartTime = &now
+	migration.Status.Message = ""
+	ctrl.recorder.Eventf(migration, corev1.EventTypeNormal, "Migrating", "Migrating from node %s to %s",
+		migration.Status.SourceNode, migration.Status.TargetNode)
+}
+
+func (ctrl *MigrationController) execMigrate(source, target *corev1.Pod) error {
+	_, err := console.ExecOutput(ctrl.config, ctrl.kubeClient, source, launcher.MigrateCommand(target.Status.PodIP))
+	return err
+}
+
+// migrationStarted returns true if QEMU has an outgoing migration under way
+// or done. Failed and cancelled ones may be retried.
+func migrationStarted(info *launcher.MigrationInfo) bool {
+	switch info.Status {
+	case "", "none", "failed", "cancelled":
+		return false
+	}
+	return true
+}
+
+// unschedulableReason returns why the scheduler can't place pod, if it can't
+func unschedulableReason(pod *corev1.Pod) string {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse && cond.Reason == corev1.PodReasonUnschedulable {
+			return cond.Message
+		}
+	}
+	return ""
+}
+
+// syncMigrating reports the progress of the migration and switches the VM to
+// the target pod once QEMU is done
+func (ctrl *MigrationController) syncMigrating(migration *vmapi.VirtualMachineMigration, vm *vmapi.VirtualMachine, source *corev1.Pod) {
+	info, err := ctrl.migrationStatus(source)
+	if err != nil {
+		glog.V(2).Infof("error getting status of migration %s/%s: %v", migration.Namespace, migration.Name, err)
+		return
+	}
+
+	if ram := info.RAM; ram != nil {
+		migration.Status.TransferredBytes = ram.Transferred
+		migration.Status.RemainingBytes = ram.Remaining
+		migration.Status.TotalBytes = ram.Total
+		migration.Status.BandwidthMbps = int64(ram.Mbps)
+		if ram.Total > 0 {
+			migration.Status.Progress = int32((ram.Total - ram.Remaining) * 100 / ram.Total)
+		}
+	}
+
+	switch info.Status {
+	case "completed":
+		migration.Status.Progress = 100
+		migration.Status.RemainingBytes = 0
+		ctrl.switchVM(migration, vm)
+	case "failed", "cancelled":
+		message := "migration " + info.Status
+		if info.Error != "" {
+			message += ": " + info.Error
+		}
+		ctrl.failMigration(migration, vm, message)
+	}
+}
+
+// migrationStatus queries the outgoing migration of the source pod
+func (ctrl *MigrationController) migrationStatus(source *corev1.Pod) (*launcher.MigrationInfo, error) {
+	out, err := console.ExecOutput(ctrl.config, ctrl.kubeClient, source, launcher.MigrateStatusCommand)
+	if err != nil {
+		return nil, err
+	}
+	info := &launcher.MigrationInfo{}
+	if err := json.Unmarshal(out, info); err != nil {
+		return nil, fmt.Errorf("error parsing migration status: %v", err)
+	}
+	return info, nil
+}
+
+// switchVM points the VM at the target pod. The VM controller picks the
+// target pod up from the VM's status, the rest of the handoff is done by
+// completeMigration.
+func (ctrl *MigrationController) switchVM(migration *vmapi.VirtualMachineMigration, vm *vmapi.VirtualMachine) {
+	vm = vm.DeepCopy()
+	vm.Status.PodName = migration.Status.TargetPod
+	vm.Status.NodeName = migration.Status.TargetNode
+	if _, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachines(vm.Namespace).Update(vm); err != nil {
+		glog.V(2).Infof("error switching vm %s/%s to pod %s: %v", vm.Namespace, vm.Name, migration.Status.TargetPod, err)
+		return
+	}
+	ctrl.recorder.Eventf(vm, corev1.EventTypeNormal, "Migrated", "Migrated to node %s", migration.Status.TargetNode)
+}
+
+// completeMigration hands the VM over to the target pod and removes the
+// source pod
+func (ctrl *MigrationController) completeMigration(migration *vmapi.VirtualMachineMigration, vm *vmapi.VirtualMachine) {
+	target, err := ctrl.podLister.Pods(migration.Namespace).Get(migration.Status.TargetPod)
+	if err != nil {
+		glog.V(2).Infof("error getting target pod of migration %s/%s: %v", migration.Namespace, migration.Name, err)
+		return
+	}
+	if !isActivePod(target, vm) {
+		target = target.DeepCopy()
+		activatePod(target, vm)
+		if _, err := ctrl.kubeClient.CoreV1().Pods(target.Namespace).Update(target); err != nil {
+			glog.V(2).Infof("error relabeling pod %s/%s: %v", target.Namespace, target.Name, err)
+			return
+		}
+	}
+
+	err = ctrl.kubeClient.CoreV1().Pods(migration.Namespace).Delete(migration.Status.SourcePod, &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		glog.V(2).Infof("error deleting pod %s/%s: %v", migration.Namespace, migration.Status.SourcePod, err)
+		return
+	}
+
+	now := metav1.Now()
+	migration.Status.Phase = vmapi.MigrationSucceeded
+	migration.Status.Progress = 100
+	migration.Status.CompletionTime = &now
+	migration.Status.Message = ""
+	ctrl.recorder.Event(migration, corev1.EventTypeNormal, "Succeeded", "Migration succeeded")
+	ctrl.updateMigrationStatus(migration)
+}
+
+// failMigration aborts the migration, leaving the VM running in the source
+// pod. vm is nil if no target pod may have been created yet.
+func (ctrl *MigrationController) failMigration(migration *vmapi.VirtualMachineMigration, vm *vmapi.VirtualMachine, message string) {
+	if migration.Status.Phase == vmapi.MigrationMigrating {
+		if source, err := ctrl.podLister.Pods(migration.Namespace).Get(migration.Status.SourcePod); err == nil {
+			if _, err := console.ExecOutput(ctrl.config, ctrl.kubeClient, source, launcher.MigrateCancelCommand); err != nil {
+				glog.V(2).Infof("error cancelling migration %s/%s: %v", migration.Namespace, migration.Name, err)
+			}
+		}
+	}
+	// Never delete a target pod that took over the VM
+	if migration.Status.TargetPod != "" && (vm == nil || vm.Status.PodName != migration.Status.TargetPod) {
+		err := ctrl.kubeClient.CoreV1().Pods(migration.Namespace).Delete(migration.Status.TargetPod, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error deleting pod %s/%s: %v", migration.Namespace, migration.Status.TargetPod, err)
+		}
+	}
+
+	now := metav1.Now()
+	migration.Status.Phase = vmapi.MigrationFailed
+	migration.Status.CompletionTime = &now
+	migration.Status.Message = message
+	ctrl.recorder.Event(migration, corev1.EventTypeWarning, "Failed", message)
+	ctrl.updateMigrationStatus(migration)
+}
+
+func (ctrl *MigrationController) updateMigrationStatus(migration *vmapi.VirtualMachineMigration) {
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineMigrations(migration.Namespace).Update(migration)
+	if err != nil {
+		glog.V(2).Infof("error updating status of migration %s/%s: %v", migration.Namespace, migration.Name, err)
+	}
+}
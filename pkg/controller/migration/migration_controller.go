@@ -0,0 +1,180 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	vmclientset "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned"
+	vmscheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	vminformers "github.com/llparse/kube-crd-skel/pkg/client/informers/externalversions/virtualmachine/v1alpha1"
+	vmlisters "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
+)
+
+const (
+	// QEMU's migration state is polled as it emits no events we could watch
+	pollInterval = 2 * time.Second
+)
+
+// MigrationController carries out VirtualMachineMigrations
+type MigrationController struct {
+	config     *rest.Config
+	vmClient   vmclientset.Interface
+	kubeClient kubernetes.Interface
+
+	vmLister              vmlisters.VirtualMachineLister
+	vmListerSynced        cache.InformerSynced
+	migrationLister       vmlisters.VirtualMachineMigrationLister
+	migrationListerSynced cache.InformerSynced
+	podLister             corelisters.PodLister
+	podListerSynced       cache.InformerSynced
+	pvcLister             corelisters.PersistentVolumeClaimLister
+	pvcListerSynced       cache.InformerSynced
+
+	migrationQueue workqueue.RateLimitingInterface
+
+	recorder record.EventRecorder
+}
+
+func NewMigrationController(
+	config *rest.Config,
+	vmClient vmclientset.Interface,
+	kubeClient kubernetes.Interface,
+	vmInformer vminformers.VirtualMachineInformer,
+	migrationInformer vminformers.VirtualMachineMigrationInformer,
+	podInformer coreinformers.PodInformer,
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+) *MigrationController {
+
+	ctrl := &MigrationController{
+		config:         config,
+		vmClient:       vmClient,
+		kubeClient:     kubeClient,
+		migrationQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachinemigration"),
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(glog.Infof)
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	ctrl.recorder = broadcaster.NewRecorder(vmscheme.Scheme, corev1.EventSource{Component: "vm-migration-controller"})
+
+	migrationInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueWork(ctrl.migrationQueue, obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueWork(ctrl.migrationQueue, newObj) },
+		},
+	)
+
+	// Target pods are owned by their migration until they take over the VM
+	podInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueOwner(newObj) },
+			DeleteFunc: ctrl.enqueueOwner,
+		},
+	)
+
+	ctrl.vmLister = vmInformer.Lister()
+	ctrl.vmListerSynced = vmInformer.Informer().HasSynced
+
+	ctrl.migrationLister = migrationInformer.Lister()
+	ctrl.migrationListerSynced = migrationInformer.Informer().HasSynced
+
+	ctrl.podLister = podInformer.Lister()
+	ctrl.podListerSynced = podInformer.Informer().HasSynced
+
+	ctrl.pvcLister = pvcInformer.Lister()
+	ctrl.pvcListerSynced = pvcInformer.Informer().HasSynced
+
+	return ctrl
+}
+
+func (ctrl *MigrationController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.migrationQueue.ShutDown()
+
+	glog.Infof("Starting migration controller")
+	defer glog.Infof("Shutting down migration controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.vmListerSynced, ctrl.migrationListerSynced, ctrl.podListerSynced, ctrl.pvcListerSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.migrationWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (ctrl *MigrationController) enqueueWork(queue workqueue.Interface, obj interface{}) {
+	// Beware of "xxx deleted" events
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	objName, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		glog.Errorf("failed to get key from object: %v", err)
+		return
+	}
+	glog.V(5).Infof("enqueued %q for sync", objName)
+	queue.Add(objName)
+}
+
+func (ctrl *MigrationController) enqueueOwner(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	if ref := metav1.GetControllerOf(meta); ref != nil && ref.Kind == "VirtualMachineMigration" {
+		ctrl.migrationQueue.Add(meta.GetNamespace() + "/" + ref.Name)
+	}
+}
+
+func (ctrl *MigrationController) migrationWorker() {
+	workFunc := func() bool {
+		keyObj, quit := ctrl.migrationQueue.Get()
+		if quit {
+			return true
+		}
+		defer ctrl.migrationQueue.Done(keyObj)
+		key := keyObj.(string)
+		glog.V(5).Infof("migrationWorker[%s]", key)
+
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			glog.V(4).Infof("error getting name of migration %q to get migration from informer: %v", key, err)
+			return false
+		}
+		migration, err := ctrl.migrationLister.VirtualMachineMigrations(ns).Get(name)
+		if err == nil {
+			ctrl.updateMigration(migration)
+			return false
+		}
+		if !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error getting migration %q from informer: %v", key, err)
+		}
+		// Target pods of deleted migrations are garbage collected, which
+		// fails the migration on the source side
+		return false
+	}
+	for {
+		if quit := workFunc(); quit {
+			glog.Infof("migration worker queue shutting down")
+			return
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package migration
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/console"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+const (
+	// AnnotationIncoming makes the launcher wait for the guest to be
+	// migrated in
+	AnnotationIncoming = ranchervm.GroupName + "/incoming_migration"
+	// AnnotationMigrationSource is the address of the source pod, the only
+	// one the incoming launcher accepts the guest from
+	AnnotationMigrationSource = ranchervm.GroupName + "/migration_source"
+
+	hostnameLabel = "kubernetes.io/hostname"
+)
+
+func newMigrationRef(migration *vmapi.VirtualMachineMigration) *metav1.OwnerReference {
+	return metav1.NewControllerRef(migration, vmapi.SchemeGroupVersion.WithKind("VirtualMachineMigration"))
+}
+
+// newTargetPod returns the pod the VM is migrated to. It is a copy of the
+// source pod kept off the source node. Until it takes over the VM it is
+// owned by the migration and lacks the VM label, so that services and
+// consoles don't reach it.
+func newTargetPod(migration *vmapi.VirtualMachineMigration, vm *vmapi.VirtualMachine, source *corev1.Pod) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vm.Name + "-" + migration.Name,
+			Namespace: vm.Namespace,
+			Labels: map[string]string{
+				"type":                         "ranchervm",
+				ranchervm.LabelMigrationTarget: migration.Name,
+			},
+			Annotations:     map[string]string{},
+			OwnerReferences: []metav1.OwnerReference{*newMigrationRef(migration)},
+		},
+		Spec: *source.Spec.DeepCopy(),
+	}
+	for k, v := range source.Annotations {
+		pod.Annotations[k] = v
+	}
+	// The target starts with the devices hotplugged into the source, unless
+	// the source's launcher restarted since and the guest lost them
+	if state, err := launcher.RecordedHotplugState(source.Annotations, console.LauncherRestarts(source)); err != nil || state == nil {
+		delete(pod.Annotations, launcher.AnnotationHotplugged)
+	}
+	delete(pod.Annotations, launcher.AnnotationHotpluggedRestarts)
+	pod.Annotations[AnnotationIncoming] = "true"
+	pod.Annotations[AnnotationMigrationSource] = source.Status.PodIP
+	pod.Spec.NodeName = ""
+
+	if migration.Spec.NodeName != "" {
+		if pod.Spec.NodeSelector == nil {
+			pod.Spec.NodeSelector = map[string]string{}
+		}
+		pod.Spec.NodeSelector[hostnameLabel] = migration.Spec.NodeName
+	} else {
+		avoidNode(&pod.Spec, source.Spec.NodeName)
+	}
+	return pod
+}
+
+// avoidNode keeps pods of spec off the named node, on top of whatever node
+// affinity spec already has
+func avoidNode(spec *corev1.PodSpec, nodeName string) {
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      hostnameLabel,
+		Operator: corev1.NodeSelectorOpNotIn,
+		Values:   []string{nodeName},
+	}
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				corev1.NodeSelectorTerm{
+					MatchExpressions: []corev1.NodeSelectorRequirement{requirement},
+				},
+			},
+		}
+		return
+	}
+	// Terms are ORed, so every one of them must exclude the node
+	for i := range required.NodeSelectorTerms {
+		term := &required.NodeSelectorTerms[i]
+		term.MatchExpressions = append(term.MatchExpressions, requirement)
+	}
+}
+
+// isActivePod returns true if pod has taken over vm
+func isActivePod(pod *corev1.Pod, vm *vmapi.VirtualMachine) bool {
+	return pod.Labels[ranchervm.LabelVMName] == vm.Name && metav1.GetControllerOf(pod) == nil
+}
+
+// activatePod makes a target pod the VM's pod. It no longer belongs to the
+// migration, and restarts boot the guest rather than wait for it.
+func activatePod(pod *corev1.Pod, vm *vmapi.VirtualMachine) {
+	pod.Labels[ranchervm.LabelVMName] = vm.Name
+	delete(pod.Labels, ranchervm.LabelMigrationTarget)
+	delete(pod.Annotations, AnnotationIncoming)
+	delete(pod.Annotations, AnnotationMigrationSource)
+	pod.OwnerReferences = nil
+}
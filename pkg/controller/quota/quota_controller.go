@@ -0,0 +1,195 @@
+package quota
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	vmclientset "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned"
+	vminformers "github.com/llparse/kube-crd-skel/pkg/client/informers/externalversions/virtualmachine/v1alpha1"
+	vmlisters "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
+)
+
+// QuotaController reports the usage of VirtualMachineQuotas. Quotas are
+// enforced by the VM controller as VMs start.
+type QuotaController struct {
+	vmClient vmclientset.Interface
+
+	vmLister                 vmlisters.VirtualMachineLister
+	vmListerSynced           cache.InformerSynced
+	quotaLister              vmlisters.VirtualMachineQuotaLister
+	quotaListerSynced        cache.InformerSynced
+	instanceTypeListerSynced cache.InformerSynced
+	pvcListerSynced          cache.InformerSynced
+
+	evaluator *Evaluator
+
+	quotaQueue workqueue.RateLimitingInterface
+}
+
+func NewQuotaController(
+	vmClient vmclientset.Interface,
+	vmInformer vminformers.VirtualMachineInformer,
+	quotaInformer vminformers.VirtualMachineQuotaInformer,
+	instanceTypeInformer vminformers.VirtualMachineInstanceTypeInformer,
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+) *QuotaController {
+
+	ctrl := &QuotaController{
+		vmClient:   vmClient,
+		quotaQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachinequota"),
+	}
+
+	quotaInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueWork(ctrl.quotaQueue, obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueWork(ctrl.quotaQueue, newObj) },
+		},
+	)
+
+	// Usage changes with the VMs and claims of the namespace
+	for _, informer := range []cache.SharedIndexInformer{vmInformer.Informer(), pvcInformer.Informer()} {
+		informer.AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    ctrl.enqueueNamespaceQuotas,
+				UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueNamespaceQuotas(newObj) },
+				DeleteFunc: ctrl.enqueueNamespaceQuotas,
+			},
+		)
+	}
+
+	ctrl.vmLister = vmInformer.Lister()
+	ctrl.vmListerSynced = vmInformer.Informer().HasSynced
+
+	ctrl.quotaLister = quotaInformer.Lister()
+	ctrl.quotaListerSynced = quotaInformer.Informer().HasSynced
+
+	ctrl.instanceTypeListerSynced = instanceTypeInformer.Informer().HasSynced
+	ctrl.pvcListerSynced = pvcInformer.Informer().HasSynced
+
+	ctrl.evaluator = NewEvaluator(pvcInformer.Lister(), instanceTypeInformer.Lister())
+
+	return ctrl
+}
+
+func (ctrl *QuotaController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.quotaQueue.ShutDown()
+
+	glog.Infof("Starting quota controller")
+	defer glog.Infof("Shutting down quota controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.vmListerSynced, ctrl.quotaListerSynced, ctrl.instanceTypeListerSynced, ctrl.pvcListerSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.quotaWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (ctrl *QuotaController) enqueueWork(queue workqueue.Interface, obj interface{}) {
+	// Beware of "xxx deleted" events
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	objName, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		glog.Errorf("failed to get key from object: %v", err)
+		return
+	}
+	glog.V(5).Infof("enqueued %q for sync", objName)
+	queue.Add(objName)
+}
+
+func (ctrl *QuotaController) enqueueNamespaceQuotas(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	quotas, err := ctrl.quotaLister.VirtualMachineQuotas(meta.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		glog.V(2).Infof("error listing quotas of namespace %s: %v", meta.GetNamespace(), err)
+		return
+	}
+	for _, quota := range quotas {
+		ctrl.enqueueWork(ctrl.quotaQueue, quota)
+	}
+}
+
+func (ctrl *QuotaController) quotaWorker() {
+	workFunc := func() bool {
+		keyObj, quit := ctrl.quotaQueue.Get()
+		if quit {
+			return true
+		}
+		defer ctrl.quotaQueue.Done(keyObj)
+		key := keyObj.(string)
+		glog.V(5).Infof("quotaWorker[%s]", key)
+
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			glog.V(4).Infof("error getting name of quota %q to get quota from informer: %v", key, err)
+			return false
+		}
+		quota, err := ctrl.quotaLister.VirtualMachineQuotas(ns).Get(name)
+		if err == nil {
+			ctrl.updateQuota(quota)
+		} else if !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error getting quota %q from informer: %v", key, err)
+		}
+		return false
+	}
+	for {
+		if quit := workFunc(); quit {
+			glog.Infof("quota worker queue shutting down")
+			return
+		}
+	}
+}
+
+func (ctrl *QuotaController) updateQuota(quota *vmapi.VirtualMachineQuota) {
+	vms, err := ctrl.vmLister.VirtualMachines(quota.Namespace).List(labels.Everything())
+	if err != nil {
+		glog.V(2).Infof("error listing vms of namespace %s: %v", quota.Namespace, err)
+		return
+	}
+
+	status := vmapi.VirtualMachineQuotaStatus{
+		Used: ctrl.evaluator.Usage(vms),
+	}
+	quotas := []*vmapi.VirtualMachineQuota{quota}
+	for _, vm := range vms {
+		if vm.Spec.Stopped || vm.Status.PodName != "" {
+			continue
+		}
+		if ctrl.evaluator.Fits(vm, vms, quotas) != nil {
+			status.OverQuota = append(status.OverQuota, vm.Name)
+		}
+	}
+	sort.Strings(status.OverQuota)
+
+	if apiequality.Semantic.DeepEqual(quota.Status, status) {
+		return
+	}
+	// Never mutate objects from the informer cache
+	quota = quota.DeepCopy()
+	quota.Status = status
+	if _, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineQuotas(quota.Namespace).Update(quota); err != nil {
+		glog.V(2).Infof("error updating status of quota %s/%s: %v", quota.Namespace, quota.Name, err)
+	}
+}
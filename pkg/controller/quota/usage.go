@@ -0,0 +1,149 @@
+package quota
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	vmlisters "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
+)
+
+// Evaluator computes what VMs count against quotas
+type Evaluator struct {
+	pvcLister          corelisters.PersistentVolumeClaimLister
+	instanceTypeLister vmlisters.VirtualMachineInstanceTypeLister
+}
+
+func NewEvaluator(
+	pvcLister corelisters.PersistentVolumeClaimLister,
+	instanceTypeLister vmlisters.VirtualMachineInstanceTypeLister,
+) *Evaluator {
+	return &Evaluator{
+		pvcLister:          pvcLister,
+		instanceTypeLister: instanceTypeLister,
+	}
+}
+
+// hasPod returns true if the VM is given CPU and memory
+func hasPod(vm *vmapi.VirtualMachine) bool {
+	return !vm.Spec.Stopped && vm.Status.PodName != ""
+}
+
+// olderThan orders VMs by creation, so that VMs created first are first to
+// fit within quotas
+func olderThan(a, b *vmapi.VirtualMachine) bool {
+	if a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.Name < b.Name
+	}
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
+}
+
+// size returns the CPU and memory of the VM, from its instance type if it
+// has one
+func (e *Evaluator) size(vm *vmapi.VirtualMachine) (int64, int64) {
+	if vm.Spec.InstanceType == "" {
+		return int64(vm.Spec.CpuMillis), int64(vm.Spec.MemoryMB)
+	}
+	// The instance type a VM was started with may have changed since
+	if it := vm.Status.InstanceType; it != nil && it.Name == vm.Spec.InstanceType {
+		return int64(it.Spec.CpuMillis), int64(it.Spec.MemoryMB)
+	}
+	it, err := e.instanceTypeLister.Get(vm.Spec.InstanceType)
+	if err != nil {
+		return 0, 0
+	}
+	return int64(it.Spec.CpuMillis), int64(it.Spec.MemoryMB)
+}
+
+// diskMB returns the storage requested by the claims of the VM's disks
+func (e *Evaluator) diskMB(vm *vmapi.VirtualMachine) int64 {
+	var total int64
+	for _, disk := range vm.Spec.Disks {
+		pvc, err := e.pvcLister.PersistentVolumeClaims(vm.Namespace).Get(disk.ClaimName)
+		if err != nil {
+			continue
+		}
+		storage := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		total += (storage.Value() + 1<<20 - 1) >> 20
+	}
+	return total
+}
+
+// Usage sums what the VMs count against quotas. Every VM counts with its
+// disks, and those with a pod count with their CPU and memory.
+func (e *Evaluator) Usage(vms []*vmapi.VirtualMachine) vmapi.VirtualMachineQuotaUsage {
+	var usage vmapi.VirtualMachineQuotaUsage
+	for _, vm := range vms {
+		usage.VirtualMachines++
+		usage.DiskMB += e.diskMB(vm)
+		if hasPod(vm) {
+			cpuMillis, memoryMB := e.size(vm)
+			usage.CpuMillis += cpuMillis
+			usage.MemoryMB += memoryMB
+		}
+	}
+	return usage
+}
+
+// FitsDiskGrowth checks that the disks of the VMs can grow by growthMB
+// within every quota
+func (e *Evaluator) FitsDiskGrowth(vms []*vmapi.VirtualMachine, quotas []*vmapi.VirtualMachineQuota, growthMB int64) error {
+	usage := e.Usage(vms)
+	for _, quota := range quotas {
+		if quota.Spec.DiskMB != nil && usage.DiskMB+growthMB > *quota.Spec.DiskMB {
+			return fmt.Errorf("quota %s allows %d disk_mb, %d requested", quota.Name, *quota.Spec.DiskMB, usage.DiskMB+growthMB)
+		}
+	}
+	return nil
+}
+
+// Fits checks that the VM can start within every quota, given the other
+// VMs of its namespace. It is counted after the VMs created before it, so
+// a namespace over its VM or disk limit keeps starting its oldest VMs, and
+// with the VMs already given CPU and memory.
+func (e *Evaluator) Fits(vm *vmapi.VirtualMachine, vms []*vmapi.VirtualMachine, quotas []*vmapi.VirtualMachineQuota) error {
+	if len(quotas) == 0 {
+		return nil
+	}
+
+	var older, running []*vmapi.VirtualMachine
+	for _, other := range vms {
+		if other.UID == vm.UID {
+			continue
+		}
+		if olderThan(other, vm) {
+			older = append(older, other)
+		}
+		if hasPod(other) {
+			running = append(running, other)
+		}
+	}
+
+	counted := e.Usage(older)
+	counted.VirtualMachines++
+	counted.DiskMB += e.diskMB(vm)
+
+	compute := e.Usage(running)
+	cpuMillis, memoryMB := e.size(vm)
+	compute.CpuMillis += cpuMillis
+	compute.MemoryMB += memoryMB
+
+	for _, quota := range quotas {
+		spec := &quota.Spec
+		if spec.VirtualMachines != nil && counted.VirtualMachines > *spec.VirtualMachines {
+			return fmt.Errorf("quota %s allows %d VMs", quota.Name, *spec.VirtualMachines)
+		}
+		if spec.DiskMB != nil && counted.DiskMB > *spec.DiskMB {
+			return fmt.Errorf("quota %s allows %d disk_mb, %d requested", quota.Name, *spec.DiskMB, counted.DiskMB)
+		}
+		if spec.CpuMillis != nil && compute.CpuMillis > *spec.CpuMillis {
+			return fmt.Errorf("quota %s allows %d cpu_milli, %d requested", quota.Name, *spec.CpuMillis, compute.CpuMillis)
+		}
+		if spec.MemoryMB != nil && compute.MemoryMB > *spec.MemoryMB {
+			return fmt.Errorf("quota %s allows %d memory_mb, %d requested", quota.Name, *spec.MemoryMB, compute.MemoryMB)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	vmlisters "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
+)
+
+var created = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestEvaluator(claims map[string]string) *Evaluator {
+	pvcs := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for name, size := range claims {
+		pvcs.Add(&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		})
+	}
+	instanceTypes := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	instanceTypes.Add(&vmapi.VirtualMachineInstanceType{
+		ObjectMeta: metav1.ObjectMeta{Name: "large"},
+		Spec:       vmapi.VirtualMachineInstanceTypeSpec{CpuMillis: 4000, MemoryMB: 8192},
+	})
+	return NewEvaluator(
+		corelisters.NewPersistentVolumeClaimLister(pvcs),
+		vmlisters.NewVirtualMachineInstanceTypeLister(instanceTypes),
+	)
+}
+
+// newVM returns a VM created age minutes after the others, with a pod if
+// running
+func newVM(name string, age int, running bool, cpuMillis, memoryMB int32, claims ...string) *vmapi.VirtualMachine {
+	vm := &vmapi.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              name,
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.NewTime(created.Add(time.Duration(age) * time.Minute)),
+		},
+		Spec: vmapi.VirtualMachineSpec{CpuMillis: cpuMillis, MemoryMB: memoryMB},
+	}
+	for _, claim := range claims {
+		vm.Spec.Disks = append(vm.Spec.Disks, vmapi.Disk{Name: claim, ClaimName: claim})
+	}
+	if running {
+		vm.Status.PodName = name
+	}
+	return vm
+}
+
+func newQuota(spec vmapi.VirtualMachineQuotaSpec) []*vmapi.VirtualMachineQuota {
+	return []*vmapi.VirtualMachineQuota{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "quota"},
+		Spec:       spec,
+	}}
+}
+
+func int32Ptr(i int32) *int32 { return &i }
+func int64Ptr(i int64) *int64 { return &i }
+
+func TestFits(t *testing.T) {
+	e := newTestEvaluator(map[string]string{"a": "1Gi", "b": "2Gi", "c": "1500Mi"})
+
+	large := newVM("large", 3, false, 0, 0)
+	large.Spec.InstanceType = "large"
+	stopped := newVM("stopped", 4, true, 2000, 2048)
+	stopped.Spec.Stopped = true
+
+	tests := []struct {
+		name    string
+		vm      *vmapi.VirtualMachine
+		vms     []*vmapi.VirtualMachine
+		quota   vmapi.VirtualMachineQuotaSpec
+		wantErr bool
+	}{
+		{
+			name: "no limits",
+			vm:   newVM("new", 1, false, 1000, 1024),
+			vms:  []*vmapi.VirtualMachine{newVM("old", 0, true, 1000, 1024)},
+		},
+		{
+			name:  "within cpu and memory",
+			vm:    newVM("new", 1, false, 1000, 1024),
+			vms:   []*vmapi.VirtualMachine{newVM("old", 0, true, 1000, 1024)},
+			quota: vmapi.VirtualMachineQuotaSpec{CpuMillis: int64Ptr(2000), MemoryMB: int64Ptr(2048)},
+		},
+		{
+			name:    "over cpu",
+			vm:      newVM("new", 1, false, 1500, 1024),
+			vms:     []*vmapi.VirtualMachine{newVM("old", 0, true, 1000, 1024)},
+			quota:   vmapi.VirtualMachineQuotaSpec{CpuMillis: int64Ptr(2000)},
+			wantErr: true,
+		},
+		{
+			name:    "over memory",
+			vm:      newVM("new", 1, false, 1000, 2048),
+			vms:     []*vmapi.VirtualMachine{newVM("old", 0, true, 1000, 1024)},
+			quota:   vmapi.VirtualMachineQuotaSpec{MemoryMB: int64Ptr(2048)},
+			wantErr: true,
+		},
+		{
+			name:  "pending and stopped vms use no cpu",
+			vm:    newVM("new", 1, false, 2000, 1024),
+			vms:   []*vmapi.VirtualMachine{newVM("old", 0, false, 1000, 1024), stopped},
+			quota: vmapi.VirtualMachineQuotaSpec{CpuMillis: int64Ptr(2000)},
+		},
+		{
+			name:    "size of instance type",
+			vm:      large,
+			quota:   vmapi.VirtualMachineQuotaSpec{MemoryMB: int64Ptr(4096)},
+			wantErr: true,
+		},
+		{
+			name:  "oldest vms keep starting",
+			vm:    newVM("old", 0, false, 1000, 1024),
+			vms:   []*vmapi.VirtualMachine{newVM("new", 1, false, 1000, 1024)},
+			quota: vmapi.VirtualMachineQuotaSpec{VirtualMachines: int32Ptr(1)},
+		},
+		{
+			name:    "newer vms over the vm limit",
+			vm:      newVM("new", 1, false, 1000, 1024),
+			vms:     []*vmapi.VirtualMachine{newVM("old", 0, false, 1000, 1024)},
+			quota:   vmapi.VirtualMachineQuotaSpec{VirtualMachines: int32Ptr(1)},
+			wantErr: true,
+		},
+		{
+			name:  "within disk",
+			vm:    newVM("new", 1, false, 1000, 1024, "b"),
+			vms:   []*vmapi.VirtualMachine{newVM("old", 0, true, 1000, 1024, "a")},
+			quota: vmapi.VirtualMachineQuotaSpec{DiskMB: int64Ptr(3072)},
+		},
+		{
+			name:    "over disk",
+			vm:      newVM("new", 1, false, 1000, 1024, "b", "c"),
+			vms:     []*vmapi.VirtualMachine{newVM("old", 0, true, 1000, 1024, "a")},
+			quota:   vmapi.VirtualMachineQuotaSpec{DiskMB: int64Ptr(4096)},
+			wantErr: true,
+		},
+		{
+			name:  "missing claims use no disk",
+			vm:    newVM("new", 1, false, 1000, 1024, "missing"),
+			quota: vmapi.VirtualMachineQuotaSpec{DiskMB: int64Ptr(0)},
+		},
+	}
+	for _, test := range tests {
+		vms := append(test.vms, test.vm)
+		err := e.Fits(test.vm, vms, newQuota(test.quota))
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error %v", test.name, err, test.wantErr)
+		}
+	}
+
+	if err := e.Fits(newVM("new", 0, false, 1000, 1024), nil, nil); err != nil {
+		t.Errorf("no quotas: got error %v", err)
+	}
+}
+
+func TestFitsDiskGrowth(t *testing.T) {
+	e := newTestEvaluator(map[string]string{"a": "1Gi", "b": "2Gi"})
+	vms := []*vmapi.VirtualMachine{
+		newVM("vm1", 0, true, 1000, 1024, "a"),
+		newVM("vm2", 1, false, 1000, 1024, "b"),
+	}
+
+	tests := []struct {
+		name     string
+		quota    vmapi.VirtualMachineQuotaSpec
+		growthMB int64
+		wantErr  bool
+	}{
+		{"no disk limit", vmapi.VirtualMachineQuotaSpec{CpuMillis: int64Ptr(0)}, 1 << 20, false},
+		{"within", vmapi.VirtualMachineQuotaSpec{DiskMB: int64Ptr(4096)}, 512, false},
+		{"up to the limit", vmapi.VirtualMachineQuotaSpec{DiskMB: int64Ptr(4096)}, 1024, false},
+		{"over", vmapi.VirtualMachineQuotaSpec{DiskMB: int64Ptr(4096)}, 1025, true},
+		{"already over", vmapi.VirtualMachineQuotaSpec{DiskMB: int64Ptr(2048)}, 1, true},
+	}
+	for _, test := range tests {
+		err := e.FitsDiskGrowth(vms, newQuota(test.quota), test.growthMB)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error %v", test.name, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package snapshot
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// AnnotationClone is set on VMs created by a clone to the clone's name
+const AnnotationClone = "vm.rancher.com/clone"
+
+func newCloneRef(clone *vmapi.VirtualMachineClone) *metav1.OwnerReference {
+	return metav1.NewControllerRef(clone, vmapi.SchemeGroupVersion.WithKind("VirtualMachineClone"))
+}
+
+func (ctrl *SnapshotController) updateClone(clone *vmapi.VirtualMachineClone) {
+	switch clone.Status.Phase {
+	case vmapi.CloneComplete, vmapi.CloneFailed:
+		return
+	}
+
+	// Never mutate objects from the informer cache
+	original := clone
+	clone = clone.DeepCopy()
+	key := clone.Namespace + "/" + clone.Name
+
+	source := clone.Spec.Source
+	if (source.VirtualMachineName == "") == (source.SnapshotName == "") {
+		ctrl.failClone(clone, "exactly one of source vm_name and snapshot_name is required")
+		return
+	}
+	if clone.Spec.Target == "" {
+		ctrl.failClone(clone, "target is required")
+		return
+	}
+	if vm, err := ctrl.vmLister.VirtualMachines(clone.Namespace).Get(clone.Spec.Target); err == nil {
+		if vm.Annotations[AnnotationClone] != clone.Name {
+			ctrl.failClone(clone, fmt.Sprintf("vm %s already exists", clone.Spec.Target))
+			return
+		}
+	}
+
+	// VMs are cloned through a snapshot owned by the clone
+	if clone.Status.SnapshotName == "" {
+		clone.Status.Phase = vmapi.CloneInProgress
+		clone.Status.SnapshotName = source.SnapshotName
+		if source.VirtualMachineName != "" {
+			snap := &vmapi.VirtualMachineSnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "clone-" + clone.Name,
+					Namespace:       clone.Namespace,
+					OwnerReferences: []metav1.OwnerReference{*newCloneRef(clone)},
+				},
+				Spec: vmapi.VirtualMachineSnapshotSpec{
+					VirtualMachineName: source.VirtualMachineName,
+				},
+			}
+			_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineSnapshots(clone.Namespace).Create(snap)
+			if err != nil && !apierrors.IsAlreadyExists(err) {
+				glog.V(2).Infof("error creating snapshot for clone %s/%s: %v", clone.Namespace, clone.Name, err)
+				return
+			}
+			clone.Status.SnapshotName = snap.Name
+		}
+		// The resulting update event will requeue the clone
+		ctrl.updateCloneStatus(clone)
+		return
+	}
+
+	snap, err := ctrl.snapshotLister.VirtualMachineSnapshots(clone.Namespace).Get(clone.Status.SnapshotName)
+	if err != nil {
+		if apierrors.IsNotFound(err) && source.VirtualMachineName != "" {
+			// The snapshot was just created
+			ctrl.cloneQueue.AddAfter(key, pollInterval)
+			return
+		}
+		ctrl.failClone(clone, fmt.Sprintf("error getting snapshot %s: %v", clone.Status.SnapshotName, err))
+		return
+	}
+	switch snap.Status.Phase {
+	case vmapi.SnapshotReady:
+	case vmapi.SnapshotFailed:
+		ctrl.failClone(clone, fmt.Sprintf("snapshot %s failed: %s", snap.Name, snap.Status.Message))
+		return
+	default:
+		ctrl.cloneQueue.AddAfter(key, pollInterval)
+		return
+	}
+
+	if clone.Status.Disks == nil {
+		clone.Status.Disks = []vmapi.DiskRestoreStatus{}
+		for _, disk := range snap.Status.Disks {
+			clone.Status.Disks = append(clone.Status.Disks, vmapi.DiskRestoreStatus{
+				Name:      disk.Name,
+				ClaimName: clone.Spec.Target + "-" + disk.Name,
+			})
+		}
+		ctrl.updateCloneStatus(clone)
+		return
+	}
+
+	ready := true
+	for i := range clone.Status.Disks {
+		disk := &clone.Status.Disks[i]
+		diskSource := snapshotDisk(snap, disk.Name)
+		if diskSource == nil {
+			ctrl.failClone(clone, fmt.Sprintf("disk %s is not in snapshot %s", disk.Name, snap.Name))
+			return
+		}
+		labels := map[string]string{
+			ranchervm.LabelVMName: clone.Spec.Target,
+			LabelClone:            clone.Name,
+		}
+		if err := ctrl.syncRestoredDisk(clone.Namespace, labels, newCloneRef(clone), snap, diskSource, disk); err != nil {
+			ctrl.failClone(clone, fmt.Sprintf("disk %s: %v", disk.Name, err))
+			return
+		}
+		ready = ready && disk.Ready
+	}
+	if !ready {
+		if !apiequality.Semantic.DeepEqual(original.Status, clone.Status) {
+			ctrl.updateCloneStatus(clone)
+		}
+		return
+	}
+
+	vm := &vmapi.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clone.Spec.Target,
+			Namespace: clone.Namespace,
+			Annotations: map[string]string{
+				AnnotationClone: clone.Name,
+			},
+		},
+		Spec: cloneSpec(snap.Status.VirtualMachineSpec, clone.Status.Disks),
+	}
+	_, err = ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachines(vm.Namespace).Create(vm)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		glog.V(2).Infof("error creating vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		return
+	}
+
+	now := metav1.Now()
+	clone.Status.Phase = vmapi.CloneComplete
+	clone.Status.Complete = true
+	clone.Status.CompletionTime = &now
+	ctrl.recorder.Eventf(clone, corev1.EventTypeNormal, "Complete", "Created vm %s", vm.Name)
+	ctrl.updateCloneStatus(clone)
+}
+
+// cloneSpec returns a copy of spec using the cloned disks. Everything that
+// identifies the guest is cleared, so that the clone gets its own MAC
+// addresses, its name as hostname and its UID as cloud-init instance ID.
+func cloneSpec(spec *vmapi.VirtualMachineSpec, disks []vmapi.DiskRestoreStatus) vmapi.VirtualMachineSpec {
+	clone := *spec.DeepCopy()
+	for i := range clone.Interfaces {
+		clone.Interfaces[i].MACAddress = ""
+	}
+	for i := range clone.Disks {
+		for _, disk := range disks {
+			if disk.Name == clone.Disks[i].Name {
+				clone.Disks[i].ClaimName = disk.ClaimName
+			}
+		}
+	}
+	clone.Hostname = ""
+	if clone.CloudInit != nil {
+		clone.CloudInit.InstanceID = ""
+	}
+	// Node ports can't be shared
+	for i := range clone.Ports {
+		clone.Ports[i].NodePort = 0
+	}
+	return clone
+}
+
+func (ctrl *SnapshotController) failClone(clone *vmapi.VirtualMachineClone, message string) {
+	clone.Status.Phase = vmapi.CloneFailed
+	clone.Status.Message = message
+	ctrl.recorder.Event(clone, corev1.EventTypeWarning, "Failed", message)
+	ctrl.updateCloneStatus(clone)
+}
+
+func (ctrl *SnapshotController) updateCloneStatus(clone *vmapi.VirtualMachineClone) {
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineClones(clone.Namespace).Update(clone)
+	if err != nil {
+		glog.V(2).Infof("error updating status of clone %s/%s: %v", clone.Namespace, clone.Name, err)
+	}
+}
@@ -0,0 +1,109 @@
+package snapshot
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	copySourceDir = "/source"
+	copyTargetDir = "/target"
+
+	// Copy jobs may be scheduled before their target claim is bound
+	copyBackoffLimit = 5
+)
+
+// newClaim returns a claim like the one described by spec, without binding
+// it to the original's volume
+func newClaim(ns, name string, labels map[string]string, spec corev1.PersistentVolumeClaimSpec) *corev1.PersistentVolumeClaim {
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels:    labels,
+		},
+		Spec: *spec.DeepCopy(),
+	}
+	claim.Spec.VolumeName = ""
+	claim.Spec.Selector = nil
+	return claim
+}
+
+// newCopyJob returns a Job copying the disk image in source to target. The
+// launcher image is used for its qemu-img, which preserves sparseness.
+// Source must not be in use by a VM.
+func (ctrl *SnapshotController) newCopyJob(ns, name string, owner *metav1.OwnerReference, source, target string) *batchv1.Job {
+	command := []string{"qemu-img", "convert", "-f", "raw", "-O", "raw", copySourceDir + "/disk.img", copyTargetDir + "/disk.img"}
+
+	backoffLimit := int32(copyBackoffLimit)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       ns,
+			OwnerReferences: []metav1.OwnerReference{*owner},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						corev1.Container{
+							Name:    "copy",
+							Image:   ctrl.launcherImage,
+							Command: command,
+							VolumeMounts: []corev1.VolumeMount{
+								corev1.VolumeMount{
+									Name:      "source",
+									MountPath: copySourceDir,
+									ReadOnly:  true,
+								},
+								corev1.VolumeMount{
+									Name:      "target",
+									MountPath: copyTargetDir,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						corev1.Volume{
+							Name: "source",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: source,
+									ReadOnly:  true,
+								},
+							},
+						},
+						corev1.Volume{
+							Name: "target",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: target,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return job
+}
+
+// jobStatus returns whether job completed and whether it failed for good
+func jobStatus(job *batchv1.Job) (complete bool, failed bool) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			complete = true
+		case batchv1.JobFailed:
+			failed = true
+		}
+	}
+	return
+}
@@ -0,0 +1,29 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewCopyJob(t *testing.T) {
+	ctrl := &SnapshotController{launcherImage: "launcher"}
+	owner := &metav1.OwnerReference{Name: "snap"}
+
+	job := ctrl.newCopyJob("default", "snap-root", owner, "vm1-root", "snap-root")
+	spec := job.Spec.Template.Spec
+	wantCommand := []string{"qemu-img", "convert", "-f", "raw", "-O", "raw", "/source/disk.img", "/target/disk.img"}
+	if got := spec.Containers[0].Command; !reflect.DeepEqual(got, wantCommand) {
+		t.Errorf("got command %v, want %v", got, wantCommand)
+	}
+	if claim := spec.Volumes[0].PersistentVolumeClaim; claim.ClaimName != "vm1-root" || !claim.ReadOnly {
+		t.Errorf("source mounted as %+v", claim)
+	}
+	if claim := spec.Volumes[1].PersistentVolumeClaim; claim.ClaimName != "snap-root" || claim.ReadOnly {
+		t.Errorf("target mounted as %+v", claim)
+	}
+	if got := job.OwnerReferences; len(got) != 1 || got[0].Name != "snap" {
+		t.Errorf("got owners %+v", got)
+	}
+}
@@ -0,0 +1,214 @@
+package snapshot
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func newRestoreRef(restore *vmapi.VirtualMachineRestore) *metav1.OwnerReference {
+	return metav1.NewControllerRef(restore, vmapi.SchemeGroupVersion.WithKind("VirtualMachineRestore"))
+}
+
+func (ctrl *SnapshotController) updateRestore(restore *vmapi.VirtualMachineRestore) {
+	switch restore.Status.Phase {
+	case vmapi.RestoreComplete, vmapi.RestoreFailed:
+		return
+	}
+
+	// Never mutate objects from the informer cache
+	original := restore
+	restore = restore.DeepCopy()
+	key := restore.Namespace + "/" + restore.Name
+
+	snap, err := ctrl.snapshotLister.VirtualMachineSnapshots(restore.Namespace).Get(restore.Spec.SnapshotName)
+	if err != nil {
+		ctrl.failRestore(restore, fmt.Sprintf("error getting snapshot %s: %v", restore.Spec.SnapshotName, err))
+		return
+	}
+	if snap.Spec.VirtualMachineName != restore.Spec.VirtualMachineName {
+		ctrl.failRestore(restore, fmt.Sprintf("snapshot %s is of vm %s", snap.Name, snap.Spec.VirtualMachineName))
+		return
+	}
+	switch snap.Status.Phase {
+	case vmapi.SnapshotReady:
+	case vmapi.SnapshotFailed:
+		ctrl.failRestore(restore, fmt.Sprintf("snapshot %s failed", snap.Name))
+		return
+	default:
+		ctrl.restoreQueue.AddAfter(key, pollInterval)
+		return
+	}
+
+	vm, err := ctrl.vmLister.VirtualMachines(restore.Namespace).Get(restore.Spec.VirtualMachineName)
+	if err != nil {
+		ctrl.failRestore(restore, fmt.Sprintf("error getting vm %s: %v", restore.Spec.VirtualMachineName, err))
+		return
+	}
+
+	// Disks can only be swapped once the VM's pod is gone
+	if !vm.Spec.Stopped {
+		vm = vm.DeepCopy()
+		vm.Spec.Stopped = true
+		if _, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachines(vm.Namespace).Update(vm); err != nil {
+			glog.V(2).Infof("error stopping vm %s/%s: %v", vm.Namespace, vm.Name, err)
+			return
+		}
+		ctrl.recorder.Eventf(restore, corev1.EventTypeNormal, "Stopping", "Stopped vm %s", vm.Name)
+		ctrl.restoreQueue.AddAfter(key, pollInterval)
+		return
+	}
+	pods, err := ctrl.podLister.Pods(vm.Namespace).List(labels.SelectorFromSet(labels.Set{
+		ranchervm.LabelVMName: vm.Name,
+	}))
+	if err != nil || len(pods) > 0 {
+		ctrl.restoreQueue.AddAfter(key, pollInterval)
+		return
+	}
+
+	if restore.Status.Phase != vmapi.RestoreInProgress {
+		restore.Status.Phase = vmapi.RestoreInProgress
+		restore.Status.Disks = []vmapi.DiskRestoreStatus{}
+		for _, disk := range snap.Status.Disks {
+			restore.Status.Disks = append(restore.Status.Disks, vmapi.DiskRestoreStatus{
+				Name:      disk.Name,
+				ClaimName: restore.Name + "-" + disk.Name,
+			})
+		}
+		// The resulting update event will requeue the restore
+		ctrl.updateRestoreStatus(restore)
+		return
+	}
+
+	ready := true
+	for i := range restore.Status.Disks {
+		disk := &restore.Status.Disks[i]
+		source := snapshotDisk(snap, disk.Name)
+		if source == nil {
+			ctrl.failRestore(restore, fmt.Sprintf("disk %s is not in snapshot %s", disk.Name, snap.Name))
+			return
+		}
+		labels := map[string]string{
+			ranchervm.LabelVMName: restore.Spec.VirtualMachineName,
+			LabelRestore:          restore.Name,
+		}
+		if err := ctrl.syncRestoredDisk(restore.Namespace, labels, newRestoreRef(restore), snap, source, disk); err != nil {
+			ctrl.failRestore(restore, fmt.Sprintf("disk %s: %v", disk.Name, err))
+			return
+		}
+		ready = ready && disk.Ready
+	}
+	if !ready {
+		if !apiequality.Semantic.DeepEqual(original.Status, restore.Status) {
+			ctrl.updateRestoreStatus(restore)
+		}
+		return
+	}
+
+	// Roll the VM back, keeping it stopped
+	vm = vm.DeepCopy()
+	vm.Spec = *snap.Status.VirtualMachineSpec.DeepCopy()
+	vm.Spec.Stopped = true
+	for i := range vm.Spec.Disks {
+		for _, disk := range restore.Status.Disks {
+			if disk.Name == vm.Spec.Disks[i].Name {
+				vm.Spec.Disks[i].ClaimName = disk.ClaimName
+			}
+		}
+	}
+	if _, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachines(vm.Namespace).Update(vm); err != nil {
+		glog.V(2).Infof("error restoring vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		return
+	}
+	ctrl.recorder.Eventf(vm, corev1.EventTypeNormal, "Restored", "Restored snapshot %s", snap.Name)
+
+	now := metav1.Now()
+	restore.Status.Phase = vmapi.RestoreComplete
+	restore.Status.Complete = true
+	restore.Status.CompletionTime = &now
+	ctrl.recorder.Event(restore, corev1.EventTypeNormal, "Complete", "Restore is complete")
+	ctrl.updateRestoreStatus(restore)
+}
+
+func snapshotDisk(snap *vmapi.VirtualMachineSnapshot, name string) *vmapi.DiskSnapshotStatus {
+	for i := range snap.Status.Disks {
+		if snap.Status.Disks[i].Name == name {
+			return &snap.Status.Disks[i]
+		}
+	}
+	return nil
+}
+
+// syncRestoredDisk creates the claim a disk is restored to and reports
+// whether it holds the snapshotted data. Claims are found by labels rather
+// than owned, so that they outlive the restore or clone; jobOwner owns the
+// copy job, if any.
+func (ctrl *SnapshotController) syncRestoredDisk(ns string, labels map[string]string, jobOwner *metav1.OwnerReference,
+	snap *vmapi.VirtualMachineSnapshot, source *vmapi.DiskSnapshotStatus, disk *vmapi.DiskRestoreStatus) error {
+
+	claim, err := ctrl.pvcLister.PersistentVolumeClaims(ns).Get(disk.ClaimName)
+	if apierrors.IsNotFound(err) {
+		claim = newClaim(ns, disk.ClaimName, labels, source.ClaimSpec)
+		if snap.Status.Method == vmapi.SnapshotMethodVolumeSnapshot {
+			claim.Annotations = map[string]string{
+				snapshotAnnotation: source.VolumeSnapshotName,
+			}
+			claim.Spec.StorageClassName = &ctrl.promoterClass
+		}
+		if _, err := ctrl.kubeClient.CoreV1().PersistentVolumeClaims(ns).Create(claim); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch snap.Status.Method {
+	case vmapi.SnapshotMethodVolumeSnapshot:
+		// The promoter binds the claim to a volume restored from the
+		// snapshot
+		disk.Ready = claim.Status.Phase == corev1.ClaimBound
+
+	case vmapi.SnapshotMethodCopy:
+		job, err := ctrl.jobLister.Jobs(ns).Get(disk.ClaimName)
+		if apierrors.IsNotFound(err) {
+			job = ctrl.newCopyJob(ns, disk.ClaimName, jobOwner, source.ClaimName, disk.ClaimName)
+			if _, err := ctrl.kubeClient.BatchV1().Jobs(ns).Create(job); err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		complete, failed := jobStatus(job)
+		if failed {
+			return fmt.Errorf("copy job %s failed", job.Name)
+		}
+		disk.Ready = complete
+	}
+	return nil
+}
+
+func (ctrl *SnapshotController) failRestore(restore *vmapi.VirtualMachineRestore, message string) {
+	restore.Status.Phase = vmapi.RestoreFailed
+	restore.Status.Message = message
+	ctrl.recorder.Event(restore, corev1.EventTypeWarning, "Failed", message)
+	ctrl.updateRestoreStatus(restore)
+}
+
+func (ctrl *SnapshotController) updateRestoreStatus(restore *vmapi.VirtualMachineRestore) {
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineRestores(restore.Namespace).Update(restore)
+	if err != nil {
+		glog.V(2).Infof("error updating status of restore %s/%s: %v", restore.Namespace, restore.Name, err)
+	}
+}
@@ -0,0 +1,369 @@
+package snapshot
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/console"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+func newSnapshotRef(snap *vmapi.VirtualMachineSnapshot) *metav1.OwnerReference {
+	return metav1.NewControllerRef(snap, vmapi.SchemeGroupVersion.WithKind("VirtualMachineSnapshot"))
+}
+
+// diskSnapshotName names the VolumeSnapshot or claim holding a disk's copy
+func diskSnapshotName(snap *vmapi.VirtualMachineSnapshot, disk string) string {
+	return snap.Name + "-" + disk
+}
+
+func (ctrl *SnapshotController) updateSnapshot(snap *vmapi.VirtualMachineSnapshot) {
+	switch snap.Status.Phase {
+	case vmapi.SnapshotReady, vmapi.SnapshotFailed:
+		return
+	}
+
+	// Never mutate objects from the informer cache
+	original := snap
+	snap = snap.DeepCopy()
+
+	if snap.Status.VirtualMachineSpec == nil {
+		// The resulting update event will requeue the snapshot
+		if err := ctrl.startSnapshot(snap); err != nil {
+			ctrl.failSnapshot(snap, err.Error())
+			return
+		}
+		ctrl.updateSnapshotStatus(snap)
+		return
+	}
+
+	// QEMU may write to an image it has open at any time, so disks are only
+	// copied once the VM is stopped
+	if snap.Status.Method == vmapi.SnapshotMethodCopy && !ctrl.stopVM(snap) {
+		return
+	}
+
+	pod, err := ctrl.runningPod(snap.Namespace, snap.Spec.VirtualMachineName)
+	if err != nil {
+		glog.V(2).Infof("error getting pod of snapshot %s/%s: %v", snap.Namespace, snap.Name, err)
+		return
+	}
+	// Quiesce the guest before any disk is captured, and keep it frozen
+	// until all of them are
+	if snap.Status.Method == vmapi.SnapshotMethodVolumeSnapshot && pod != nil && !snap.Status.Frozen && !snapshotStarted(snap) {
+		if err := ctrl.exec(pod, launcher.FreezeCommand); err != nil {
+			ctrl.recorder.Eventf(snap, corev1.EventTypeWarning, "FreezeFailed", "Error freezing guest, snapshot may be inconsistent: %v", err)
+		} else {
+			snap.Status.Frozen = true
+			if err := ctrl.updateSnapshotStatus(snap); err != nil {
+				// Nothing would remember to thaw the guest
+				ctrl.thaw(snap)
+				return
+			}
+			ctrl.recorder.Event(snap, corev1.EventTypeNormal, "Frozen", "Froze guest")
+			return
+		}
+	}
+
+	ready := true
+	for i := range snap.Status.Disks {
+		disk := &snap.Status.Disks[i]
+		var err error
+		switch snap.Status.Method {
+		case vmapi.SnapshotMethodVolumeSnapshot:
+			err = ctrl.syncVolumeSnapshot(snap, disk)
+		case vmapi.SnapshotMethodCopy:
+			err = ctrl.syncCopy(snap, disk)
+		}
+		if err != nil {
+			ctrl.failSnapshot(snap, fmt.Sprintf("disk %s: %v", disk.Name, err))
+			return
+		}
+		ready = ready && disk.Ready
+	}
+
+	// Storage providers cut VolumeSnapshots as they are created, so the
+	// guest needn't wait for them to be uploaded
+	if snap.Status.Frozen && snapshotCut(snap) {
+		ctrl.thaw(snap)
+		snap.Status.Frozen = false
+	}
+
+	if !ready {
+		if !apiequality.Semantic.DeepEqual(original.Status, snap.Status) {
+			ctrl.updateSnapshotStatus(snap)
+		}
+		if snap.Status.Method == vmapi.SnapshotMethodVolumeSnapshot {
+			ctrl.snapshotQueue.AddAfter(snap.Namespace+"/"+snap.Name, pollInterval)
+		}
+		return
+	}
+
+	if snap.Status.StoppedVM {
+		ctrl.startVM(snap)
+		snap.Status.StoppedVM = false
+	}
+	now := metav1.Now()
+	snap.Status.Phase = vmapi.SnapshotReady
+	snap.Status.Ready = true
+	snap.Status.CreationTime = &now
+	ctrl.recorder.Event(snap, corev1.EventTypeNormal, "Ready", "Snapshot is ready")
+	ctrl.updateSnapshotStatus(snap)
+}
+
+// startSnapshot records the VM's spec and the disks to capture
+func (ctrl *SnapshotController) startSnapshot(snap *vmapi.VirtualMachineSnapshot) error {
+	vm, err := ctrl.vmLister.VirtualMachines(snap.Namespace).Get(snap.Spec.VirtualMachineName)
+	if err != nil {
+		return fmt.Errorf("error getting vm %s: %v", snap.Spec.VirtualMachineName, err)
+	}
+
+	method := snap.Spec.Method
+	switch method {
+	case "":
+		method = vmapi.SnapshotMethodCopy
+		if ctrl.volumeSnapshotClient != nil {
+			method = vmapi.SnapshotMethodVolumeSnapshot
+		}
+	case vmapi.SnapshotMethodVolumeSnapshot:
+		if ctrl.volumeSnapshotClient == nil {
+			return fmt.Errorf("VolumeSnapshots are not available in this cluster")
+		}
+	case vmapi.SnapshotMethodCopy:
+	default:
+		return fmt.Errorf("unknown method %q", method)
+	}
+
+	disks := []vmapi.DiskSnapshotStatus{}
+	for _, disk := range vm.Spec.Disks {
+		claim, err := ctrl.pvcLister.PersistentVolumeClaims(snap.Namespace).Get(disk.ClaimName)
+		if err != nil {
+			return fmt.Errorf("error getting claim %s of disk %s: %v", disk.ClaimName, disk.Name, err)
+		}
+		disks = append(disks, vmapi.DiskSnapshotStatus{
+			Name:            disk.Name,
+			SourceClaimName: disk.ClaimName,
+			ClaimSpec:       *claim.Spec.DeepCopy(),
+		})
+	}
+
+	snap.Status.Phase = vmapi.SnapshotInProgress
+	snap.Status.Method = method
+	snap.Status.VirtualMachineSpec = vm.Spec.DeepCopy()
+	snap.Status.Disks = disks
+	return nil
+}
+
+// stopVM stops the snapshot's VM for a copy snapshot, and returns true once
+// its pods are gone. The VM is started again once the disks are copied.
+func (ctrl *SnapshotController) stopVM(snap *vmapi.VirtualMachineSnapshot) bool {
+	key := snap.Namespace + "/" + snap.Name
+	vm, err := ctrl.vmLister.VirtualMachines(snap.Namespace).Get(snap.Spec.VirtualMachineName)
+	if apierrors.IsNotFound(err) {
+		return true
+	}
+	if err != nil {
+		glog.V(2).Infof("error getting vm of snapshot %s/%s: %v", snap.Namespace, snap.Name, err)
+		return false
+	}
+
+	if !vm.Spec.Stopped {
+		if snapshotStarted(snap) {
+			// The VM is started again once every disk is copied
+			if snapshotCopied(snap) {
+				return true
+			}
+			ctrl.failSnapshot(snap, "vm started while its disks were being copied")
+			return false
+		}
+		// Remember to start the VM again before stopping it
+		if !snap.Status.StoppedVM {
+			snap.Status.StoppedVM = true
+			// The resulting update event will requeue the snapshot
+			ctrl.updateSnapshotStatus(snap)
+			return false
+		}
+		vm = vm.DeepCopy()
+		vm.Spec.Stopped = true
+		if _, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachines(vm.Namespace).Update(vm); err != nil {
+			glog.V(2).Infof("error stopping vm %s/%s: %v", vm.Namespace, vm.Name, err)
+			return false
+		}
+		ctrl.recorder.Eventf(snap, corev1.EventTypeNormal, "Stopping", "Stopped vm %s to copy its disks", vm.Name)
+		ctrl.snapshotQueue.AddAfter(key, pollInterval)
+		return false
+	}
+
+	pods, err := ctrl.podLister.Pods(vm.Namespace).List(labels.SelectorFromSet(labels.Set{
+		ranchervm.LabelVMName: vm.Name,
+	}))
+	if err != nil || len(pods) > 0 {
+		ctrl.snapshotQueue.AddAfter(key, pollInterval)
+		return false
+	}
+	return true
+}
+
+// startVM starts a VM stopped by stopVM
+func (ctrl *SnapshotController) startVM(snap *vmapi.VirtualMachineSnapshot) {
+	vm, err := ctrl.vmLister.VirtualMachines(snap.Namespace).Get(snap.Spec.VirtualMachineName)
+	if err != nil || !vm.Spec.Stopped {
+		return
+	}
+	vm = vm.DeepCopy()
+	vm.Spec.Stopped = false
+	if _, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachines(vm.Namespace).Update(vm); err != nil {
+		glog.V(2).Infof("error starting vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		ctrl.recorder.Eventf(snap, corev1.EventTypeWarning, "StartFailed", "Error starting vm %s: %v", vm.Name, err)
+		return
+	}
+	ctrl.recorder.Eventf(snap, corev1.EventTypeNormal, "Starting", "Started vm %s", vm.Name)
+}
+
+// snapshotStarted returns true once any disk is being captured
+func snapshotStarted(snap *vmapi.VirtualMachineSnapshot) bool {
+	for _, disk := range snap.Status.Disks {
+		if disk.VolumeSnapshotName != "" || disk.ClaimName != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotCopied returns true once every disk is captured
+func snapshotCopied(snap *vmapi.VirtualMachineSnapshot) bool {
+	for _, disk := range snap.Status.Disks {
+		if !disk.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// snapshotCut returns true once a VolumeSnapshot exists for every disk
+func snapshotCut(snap *vmapi.VirtualMachineSnapshot) bool {
+	for _, disk := range snap.Status.Disks {
+		if disk.VolumeSnapshotName == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (ctrl *SnapshotController) syncVolumeSnapshot(snap *vmapi.VirtualMachineSnapshot, disk *vmapi.DiskSnapshotStatus) error {
+	if disk.VolumeSnapshotName == "" {
+		vs := &volumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      diskSnapshotName(snap, disk.Name),
+				Namespace: snap.Namespace,
+				Labels: map[string]string{
+					ranchervm.LabelVMName: snap.Spec.VirtualMachineName,
+					LabelSnapshot:         snap.Name,
+				},
+				OwnerReferences: []metav1.OwnerReference{*newSnapshotRef(snap)},
+			},
+			Spec: volumeSnapshotSpec{
+				PersistentVolumeClaimName: disk.SourceClaimName,
+			},
+		}
+		if err := ctrl.createVolumeSnapshot(vs); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		disk.VolumeSnapshotName = vs.Name
+		return nil
+	}
+
+	vs, err := ctrl.getVolumeSnapshot(snap.Namespace, disk.VolumeSnapshotName)
+	if err != nil {
+		return err
+	}
+	if failed, message := vs.condition(volumeSnapshotError); failed {
+		return fmt.Errorf("VolumeSnapshot %s failed: %s", vs.Name, message)
+	}
+	disk.Ready, _ = vs.condition(volumeSnapshotReady)
+	return nil
+}
+
+func (ctrl *SnapshotController) syncCopy(snap *vmapi.VirtualMachineSnapshot, disk *vmapi.DiskSnapshotStatus) error {
+	name := diskSnapshotName(snap, disk.Name)
+	if disk.ClaimName == "" {
+		labels := map[string]string{
+			ranchervm.LabelVMName: snap.Spec.VirtualMachineName,
+			LabelSnapshot:         snap.Name,
+		}
+		claim := newClaim(snap.Namespace, name, labels, disk.ClaimSpec)
+		claim.OwnerReferences = []metav1.OwnerReference{*newSnapshotRef(snap)}
+		if _, err := ctrl.kubeClient.CoreV1().PersistentVolumeClaims(snap.Namespace).Create(claim); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		job := ctrl.newCopyJob(snap.Namespace, name, newSnapshotRef(snap), disk.SourceClaimName, name)
+		if _, err := ctrl.kubeClient.BatchV1().Jobs(snap.Namespace).Create(job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		disk.ClaimName = name
+		return nil
+	}
+
+	job, err := ctrl.jobLister.Jobs(snap.Namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	complete, failed := jobStatus(job)
+	if failed {
+		return fmt.Errorf("copy job %s failed", job.Name)
+	}
+	disk.Ready = complete
+	return nil
+}
+
+func (ctrl *SnapshotController) failSnapshot(snap *vmapi.VirtualMachineSnapshot, message string) {
+	if snap.Status.Frozen {
+		ctrl.thaw(snap)
+		snap.Status.Frozen = false
+	}
+	if snap.Status.StoppedVM {
+		ctrl.startVM(snap)
+		snap.Status.StoppedVM = false
+	}
+	snap.Status.Phase = vmapi.SnapshotFailed
+	snap.Status.Message = message
+	ctrl.recorder.Event(snap, corev1.EventTypeWarning, "Failed", message)
+	ctrl.updateSnapshotStatus(snap)
+}
+
+func (ctrl *SnapshotController) thaw(snap *vmapi.VirtualMachineSnapshot) {
+	pod, err := ctrl.runningPod(snap.Namespace, snap.Spec.VirtualMachineName)
+	if err != nil || pod == nil {
+		// A stopped VM needs no thawing
+		return
+	}
+	if err := ctrl.exec(pod, launcher.ThawCommand); err != nil {
+		glog.V(2).Infof("error thawing vm %s/%s: %v", snap.Namespace, snap.Spec.VirtualMachineName, err)
+		ctrl.recorder.Eventf(snap, corev1.EventTypeWarning, "ThawFailed", "Error thawing guest: %v", err)
+	}
+}
+
+// exec runs a launcher command in pod
+func (ctrl *SnapshotController) exec(pod *corev1.Pod, command []string) error {
+	_, err := console.ExecOutput(ctrl.config, ctrl.kubeClient, pod, command)
+	return err
+}
+
+func (ctrl *SnapshotController) updateSnapshotStatus(snap *vmapi.VirtualMachineSnapshot) error {
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineSnapshots(snap.Namespace).Update(snap)
+	if err != nil {
+		glog.V(2).Infof("error updating status of snapshot %s/%s: %v", snap.Namespace, snap.Name, err)
+	}
+	return err
+}
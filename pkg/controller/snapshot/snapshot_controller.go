@@ -0,0 +1,376 @@
+package snapshot
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	batchinformers "k8s.io/client-go/informers/batch/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	vmclientset "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned"
+	vmscheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	vminformers "github.com/llparse/kube-crd-skel/pkg/client/informers/externalversions/virtualmachine/v1alpha1"
+	vmlisters "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
+)
+
+const (
+	// LabelSnapshot, LabelRestore and LabelClone are set on claims created
+	// for a snapshot, restore or clone to its name
+	LabelSnapshot = "vm.rancher.com/snapshot"
+	LabelRestore  = "vm.rancher.com/restore"
+	LabelClone    = "vm.rancher.com/clone"
+
+	// VolumeSnapshots are polled as there is no informer for them
+	pollInterval = 5 * time.Second
+)
+
+// SnapshotController takes VirtualMachineSnapshots and carries out
+// VirtualMachineRestores and VirtualMachineClones
+type SnapshotController struct {
+	config               *rest.Config
+	vmClient             vmclientset.Interface
+	kubeClient           kubernetes.Interface
+	volumeSnapshotClient *dynamic.Client
+
+	vmLister             vmlisters.VirtualMachineLister
+	vmListerSynced       cache.InformerSynced
+	snapshotLister       vmlisters.VirtualMachineSnapshotLister
+	snapshotListerSynced cache.InformerSynced
+	restoreLister        vmlisters.VirtualMachineRestoreLister
+	restoreListerSynced  cache.InformerSynced
+	cloneLister          vmlisters.VirtualMachineCloneLister
+	cloneListerSynced    cache.InformerSynced
+	podLister            corelisters.PodLister
+	podListerSynced      cache.InformerSynced
+	pvcLister            corelisters.PersistentVolumeClaimLister
+	pvcListerSynced      cache.InformerSynced
+	jobLister            batchlisters.JobLister
+	jobListerSynced      cache.InformerSynced
+
+	snapshotQueue workqueue.RateLimitingInterface
+	restoreQueue  workqueue.RateLimitingInterface
+	cloneQueue    workqueue.RateLimitingInterface
+
+	recorder record.EventRecorder
+
+	launcherImage string
+	promoterClass string
+}
+
+func NewSnapshotController(
+	config *rest.Config,
+	vmClient vmclientset.Interface,
+	kubeClient kubernetes.Interface,
+	vmInformer vminformers.VirtualMachineInformer,
+	snapshotInformer vminformers.VirtualMachineSnapshotInformer,
+	restoreInformer vminformers.VirtualMachineRestoreInformer,
+	cloneInformer vminformers.VirtualMachineCloneInformer,
+	podInformer coreinformers.PodInformer,
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+	jobInformer batchinformers.JobInformer,
+	launcherImage string,
+	promoterClass string,
+) *SnapshotController {
+
+	ctrl := &SnapshotController{
+		config:               config,
+		vmClient:             vmClient,
+		kubeClient:           kubeClient,
+		volumeSnapshotClient: newVolumeSnapshotClient(config, kubeClient),
+		snapshotQueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachinesnapshot"),
+		restoreQueue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachinerestore"),
+		cloneQueue:           workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachineclone"),
+		launcherImage:        launcherImage,
+		promoterClass:        promoterClass,
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(glog.Infof)
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	ctrl.recorder = broadcaster.NewRecorder(vmscheme.Scheme, corev1.EventSource{Component: "vm-snapshot-controller"})
+
+	snapshotInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { ctrl.enqueueWork(ctrl.snapshotQueue, obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				ctrl.enqueueWork(ctrl.snapshotQueue, newObj)
+				ctrl.enqueueOwner(newObj)
+			},
+			DeleteFunc: ctrl.snapshotDeleted,
+		},
+	)
+
+	restoreInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueWork(ctrl.restoreQueue, obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueWork(ctrl.restoreQueue, newObj) },
+		},
+	)
+
+	cloneInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueWork(ctrl.cloneQueue, obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueWork(ctrl.cloneQueue, newObj) },
+		},
+	)
+
+	// Copy jobs are owned by the snapshot, restore or clone they work for
+	jobInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueOwner(newObj) },
+		},
+	)
+
+	// Restored and cloned claims must outlive the restore or clone, so they
+	// are found by label
+	pvcInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				if claim, ok := newObj.(*corev1.PersistentVolumeClaim); ok {
+					if name, ok := claim.Labels[LabelRestore]; ok {
+						ctrl.restoreQueue.Add(claim.Namespace + "/" + name)
+					}
+					if name, ok := claim.Labels[LabelClone]; ok {
+						ctrl.cloneQueue.Add(claim.Namespace + "/" + name)
+					}
+				}
+			},
+		},
+	)
+
+	ctrl.vmLister = vmInformer.Lister()
+	ctrl.vmListerSynced = vmInformer.Informer().HasSynced
+
+	ctrl.snapshotLister = snapshotInformer.Lister()
+	ctrl.snapshotListerSynced = snapshotInformer.Informer().HasSynced
+
+	ctrl.restoreLister = restoreInformer.Lister()
+	ctrl.restoreListerSynced = restoreInformer.Informer().HasSynced
+
+	ctrl.cloneLister = cloneInformer.Lister()
+	ctrl.cloneListerSynced = cloneInformer.Informer().HasSynced
+
+	ctrl.podLister = podInformer.Lister()
+	ctrl.podListerSynced = podInformer.Informer().HasSynced
+
+	ctrl.pvcLister = pvcInformer.Lister()
+	ctrl.pvcListerSynced = pvcInformer.Informer().HasSynced
+
+	ctrl.jobLister = jobInformer.Lister()
+	ctrl.jobListerSynced = jobInformer.Informer().HasSynced
+
+	return ctrl
+}
+
+func (ctrl *SnapshotController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.snapshotQueue.ShutDown()
+	defer ctrl.restoreQueue.ShutDown()
+	defer ctrl.cloneQueue.ShutDown()
+
+	glog.Infof("Starting snapshot controller")
+	defer glog.Infof("Shutting down snapshot controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.vmListerSynced, ctrl.snapshotListerSynced, ctrl.restoreListerSynced,
+		ctrl.cloneListerSynced, ctrl.podListerSynced, ctrl.pvcListerSynced, ctrl.jobListerSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.snapshotWorker, time.Second, stopCh)
+		go wait.Until(ctrl.restoreWorker, time.Second, stopCh)
+		go wait.Until(ctrl.cloneWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (ctrl *SnapshotController) enqueueWork(queue workqueue.Interface, obj interface{}) {
+	// Beware of "xxx deleted" events
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	objName, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		glog.Errorf("failed to get key from object: %v", err)
+		return
+	}
+	glog.V(5).Infof("enqueued %q for sync", objName)
+	queue.Add(objName)
+}
+
+func (ctrl *SnapshotController) enqueueOwner(obj interface{}) {
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	ref := metav1.GetControllerOf(meta)
+	if ref == nil {
+		return
+	}
+	switch ref.Kind {
+	case "VirtualMachineSnapshot":
+		ctrl.snapshotQueue.Add(meta.GetNamespace() + "/" + ref.Name)
+	case "VirtualMachineRestore":
+		ctrl.restoreQueue.Add(meta.GetNamespace() + "/" + ref.Name)
+	case "VirtualMachineClone":
+		ctrl.cloneQueue.Add(meta.GetNamespace() + "/" + ref.Name)
+	}
+}
+
+// snapshotDeleted thaws the guest or starts the VM again if a snapshot is
+// deleted while in progress
+func (ctrl *SnapshotController) snapshotDeleted(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	snap, ok := obj.(*vmapi.VirtualMachineSnapshot)
+	if !ok {
+		return
+	}
+	if snap.Status.Frozen {
+		go ctrl.thaw(snap)
+	}
+	if snap.Status.StoppedVM {
+		go ctrl.startVM(snap)
+	}
+}
+
+func (ctrl *SnapshotController) snapshotWorker() {
+	workFunc := func() bool {
+		keyObj, quit := ctrl.snapshotQueue.Get()
+		if quit {
+			return true
+		}
+		defer ctrl.snapshotQueue.Done(keyObj)
+		key := keyObj.(string)
+		glog.V(5).Infof("snapshotWorker[%s]", key)
+
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			glog.V(4).Infof("error getting name of snapshot %q to get snapshot from informer: %v", key, err)
+			return false
+		}
+		snap, err := ctrl.snapshotLister.VirtualMachineSnapshots(ns).Get(name)
+		if err == nil {
+			ctrl.updateSnapshot(snap)
+			return false
+		}
+		if !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error getting snapshot %q from informer: %v", key, err)
+		}
+		// Dependents of deleted snapshots are garbage collected
+		return false
+	}
+	for {
+		if quit := workFunc(); quit {
+			glog.Infof("snapshot worker queue shutting down")
+			return
+		}
+	}
+}
+
+func (ctrl *SnapshotController) restoreWorker() {
+	workFunc := func() bool {
+		keyObj, quit := ctrl.restoreQueue.Get()
+		if quit {
+			return true
+		}
+		defer ctrl.restoreQueue.Done(keyObj)
+		key := keyObj.(string)
+		glog.V(5).Infof("restoreWorker[%s]", key)
+
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			glog.V(4).Infof("error getting name of restore %q to get restore from informer: %v", key, err)
+			return false
+		}
+		restore, err := ctrl.restoreLister.VirtualMachineRestores(ns).Get(name)
+		if err == nil {
+			ctrl.updateRestore(restore)
+			return false
+		}
+		if !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error getting restore %q from informer: %v", key, err)
+		}
+		return false
+	}
+	for {
+		if quit := workFunc(); quit {
+			glog.Infof("restore worker queue shutting down")
+			return
+		}
+	}
+}
+
+func (ctrl *SnapshotController) cloneWorker() {
+	workFunc := func() bool {
+		keyObj, quit := ctrl.cloneQueue.Get()
+		if quit {
+			return true
+		}
+		defer ctrl.cloneQueue.Done(keyObj)
+		key := keyObj.(string)
+		glog.V(5).Infof("cloneWorker[%s]", key)
+
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			glog.V(4).Infof("error getting name of clone %q to get clone from informer: %v", key, err)
+			return false
+		}
+		clone, err := ctrl.cloneLister.VirtualMachineClones(ns).Get(name)
+		if err == nil {
+			ctrl.updateClone(clone)
+			return false
+		}
+		if !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error getting clone %q from informer: %v", key, err)
+		}
+		return false
+	}
+	for {
+		if quit := workFunc(); quit {
+			glog.Infof("clone worker queue shutting down")
+			return
+		}
+	}
+}
+
+// runningPod returns the VM's pod if it is running, or nil
+func (ctrl *SnapshotController) runningPod(ns, vmName string) (*corev1.Pod, error) {
+	vm, err := ctrl.vmLister.VirtualMachines(ns).Get(vmName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	podName := vm.Status.PodName
+	if podName == "" {
+		podName = vm.Name
+	}
+	pod, err := ctrl.podLister.Pods(ns).Get(podName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	return pod, nil
+}
@@ -0,0 +1,113 @@
+package snapshot
+
+import (
+	"encoding/json"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// VolumeSnapshots are served by the external snapshot controller of
+// kubernetes-incubator/external-storage
+var volumeSnapshotGroupVersion = schema.GroupVersion{Group: "volumesnapshot.external-storage.k8s.io", Version: "v1"}
+
+var volumeSnapshotResource = &metav1.APIResource{
+	Name:       "volumesnapshots",
+	Namespaced: true,
+	Kind:       "VolumeSnapshot",
+}
+
+const (
+	// The snapshot promoter provisions claims carrying this annotation from
+	// the named VolumeSnapshot
+	snapshotAnnotation = "snapshot.alpha.kubernetes.io/snapshot"
+
+	volumeSnapshotReady = "Ready"
+	volumeSnapshotError = "Error"
+)
+
+type volumeSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   volumeSnapshotSpec   `json:"spec"`
+	Status volumeSnapshotStatus `json:"status"`
+}
+
+type volumeSnapshotSpec struct {
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName"`
+}
+
+type volumeSnapshotStatus struct {
+	Conditions []volumeSnapshotCondition `json:"conditions,omitempty"`
+}
+
+type volumeSnapshotCondition struct {
+	Type    string                 `json:"type"`
+	Status  corev1.ConditionStatus `json:"status"`
+	Message string                 `json:"message,omitempty"`
+}
+
+// newVolumeSnapshotClient returns a client for VolumeSnapshots, or nil if
+// the cluster doesn't serve them
+func newVolumeSnapshotClient(config *rest.Config, kubeClient kubernetes.Interface) *dynamic.Client {
+	if _, err := kubeClient.Discovery().ServerResourcesForGroupVersion(volumeSnapshotGroupVersion.String()); err != nil {
+		glog.Infof("VolumeSnapshots are unavailable, disks will be snapshotted by copy: %v", err)
+		return nil
+	}
+
+	conf := *config
+	conf.GroupVersion = &volumeSnapshotGroupVersion
+	conf.APIPath = "/apis"
+	client, err := dynamic.NewClient(&conf)
+	if err != nil {
+		glog.Errorf("error creating VolumeSnapshot client: %v", err)
+		return nil
+	}
+	return client
+}
+
+func (ctrl *SnapshotController) createVolumeSnapshot(vs *volumeSnapshot) error {
+	vs.APIVersion = volumeSnapshotGroupVersion.String()
+	vs.Kind = volumeSnapshotResource.Kind
+
+	data, err := json.Marshal(vs)
+	if err != nil {
+		return err
+	}
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &obj.Object); err != nil {
+		return err
+	}
+	_, err = ctrl.volumeSnapshotClient.Resource(volumeSnapshotResource, vs.Namespace).Create(obj)
+	return err
+}
+
+func (ctrl *SnapshotController) getVolumeSnapshot(ns, name string) (*volumeSnapshot, error) {
+	obj, err := ctrl.volumeSnapshotClient.Resource(volumeSnapshotResource, ns).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	vs := &volumeSnapshot{}
+	return vs, json.Unmarshal(data, vs)
+}
+
+// condition returns the status of the named condition and its message
+func (vs *volumeSnapshot) condition(conditionType string) (bool, string) {
+	for _, cond := range vs.Status.Conditions {
+		if cond.Type == conditionType {
+			return cond.Status == corev1.ConditionTrue, cond.Message
+		}
+	}
+	return false, ""
+}
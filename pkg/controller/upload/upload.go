@@ -0,0 +1,359 @@
+package upload
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/console"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+const targetDir = "/target"
+
+func newUploadRef(upload *vmapi.VirtualMachineImageUpload) *metav1.OwnerReference {
+	return metav1.NewControllerRef(upload, vmapi.SchemeGroupVersion.WithKind("VirtualMachineImageUpload"))
+}
+
+func uploadPodName(upload *vmapi.VirtualMachineImageUpload) string {
+	return upload.Name + "-upload"
+}
+
+// uploadScratchName names the claim an upload stages its image on, which is
+// owned by the upload
+func uploadScratchName(upload *vmapi.VirtualMachineImageUpload) string {
+	return upload.Name + "-scratch"
+}
+
+func uploadClaimName(upload *vmapi.VirtualMachineImageUpload) string {
+	if upload.Spec.ClaimName != "" {
+		return upload.Spec.ClaimName
+	}
+	return upload.Name
+}
+
+func (ctrl *UploadController) updateUpload(upload *vmapi.VirtualMachineImageUpload) {
+	switch upload.Status.Phase {
+	case vmapi.UploadComplete, vmapi.UploadFailed:
+		return
+	}
+
+	// Never mutate objects from the informer cache
+	original := upload
+	upload = upload.DeepCopy()
+
+	if upload.Spec.SizeMB <= 0 {
+		ctrl.failUpload(upload, "size_mb must be positive")
+		return
+	}
+
+	// Claims are labeled rather than owned, so that they outlive the upload
+	claimName := uploadClaimName(upload)
+	claim, err := ctrl.pvcLister.PersistentVolumeClaims(upload.Namespace).Get(claimName)
+	if apierrors.IsNotFound(err) {
+		claim = newUploadClaim(upload)
+		if _, err := ctrl.kubeClient.CoreV1().PersistentVolumeClaims(upload.Namespace).Create(claim); err != nil && !apierrors.IsAlreadyExists(err) {
+			glog.V(2).Infof("error creating claim for upload %s/%s: %v", upload.Namespace, upload.Name, err)
+			return
+		}
+	} else if err != nil {
+		glog.V(2).Infof("error getting claim %s/%s: %v", upload.Namespace, claimName, err)
+		return
+	} else if claim.Labels[LabelUpload] != upload.Name {
+		ctrl.failUpload(upload, fmt.Sprintf("claim %s already exists", claimName))
+		return
+	}
+
+	if !ctrl.syncScratchClaim(upload) || !ctrl.createServerSecret(upload) {
+		return
+	}
+
+	pod, err := ctrl.podLister.Pods(upload.Namespace).Get(uploadPodName(upload))
+	if apierrors.IsNotFound(err) {
+		// What was received so far is kept on the scratch claim, so new pods
+		// resume the upload
+		pod = ctrl.newUploadPod(upload)
+		if _, err := ctrl.kubeClient.CoreV1().Pods(upload.Namespace).Create(pod); err != nil && !apierrors.IsAlreadyExists(err) {
+			glog.V(2).Infof("error creating pod for upload %s/%s: %v", upload.Namespace, upload.Name, err)
+			return
+		}
+		upload.Status.Phase = vmapi.UploadPending
+		upload.Status.Path = ""
+		if !apiequality.Semantic.DeepEqual(original.Status, upload.Status) {
+			ctrl.updateUploadStatus(upload)
+		}
+		return
+	}
+	if err != nil {
+		glog.V(2).Infof("error getting pod of upload %s/%s: %v", upload.Namespace, upload.Name, err)
+		return
+	}
+
+	upload.Status.PodName = pod.Name
+	switch {
+	case pod.Status.Phase == corev1.PodSucceeded:
+		info := &launcher.ImageInfo{}
+		if terminated := uploadTermination(pod); terminated != nil {
+			if err := json.Unmarshal([]byte(terminated.Message), info); err != nil {
+				glog.V(2).Infof("error reading image info of upload %s/%s: %v", upload.Namespace, upload.Name, err)
+			}
+		}
+		now := metav1.Now()
+		upload.Status.Phase = vmapi.UploadComplete
+		upload.Status.Path = ""
+		upload.Status.Format = info.Format
+		upload.Status.VirtualSizeBytes = info.VirtualSize
+		upload.Status.CompletionTime = &now
+		ctrl.recorder.Eventf(upload, corev1.EventTypeNormal, "Complete", "Wrote %s image to claim %s", info.Format, claimName)
+		ctrl.updateUploadStatus(upload)
+		ctrl.deleteScratchClaim(upload)
+		return
+	case pod.Status.Phase == corev1.PodFailed:
+		terminated := uploadTermination(pod)
+		if terminated == nil || pod.Status.Reason == "Evicted" {
+			// Pods evicted or lost with their node are replaced
+			glog.V(2).Infof("deleting pod %s/%s of upload %s: %s", pod.Namespace, pod.Name, upload.Name, pod.Status.Reason)
+			if err := ctrl.kubeClient.CoreV1().Pods(pod.Namespace).Delete(pod.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				glog.V(2).Infof("error deleting pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			}
+			return
+		}
+		message := fmt.Sprintf("pod %s failed", pod.Name)
+		if terminated.Message != "" {
+			message = strings.TrimSpace(terminated.Message)
+		}
+		ctrl.failUpload(upload, message)
+		ctrl.deleteScratchClaim(upload)
+		return
+	case podReady(pod):
+		upload.Status.Phase = vmapi.UploadReady
+		upload.Status.Path = console.UploadPath(upload)
+	}
+	if !apiequality.Semantic.DeepEqual(original.Status, upload.Status) {
+		ctrl.updateUploadStatus(upload)
+	}
+}
+
+// newUploadClaim returns the claim an upload writes its image to
+func newUploadClaim(upload *vmapi.VirtualMachineImageUpload) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      uploadClaimName(upload),
+			Namespace: upload.Namespace,
+			Labels: map[string]string{
+				LabelUpload: upload.Name,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(fmt.Sprintf("%dMi", upload.Spec.SizeMB)),
+				},
+			},
+			StorageClassName: upload.Spec.StorageClassName,
+		},
+	}
+}
+
+// syncScratchClaim creates the claim an upload's image is staged on, as large
+// as the upload's claim. Returns false if the pod can't be created yet.
+func (ctrl *UploadController) syncScratchClaim(upload *vmapi.VirtualMachineImageUpload) bool {
+	name := uploadScratchName(upload)
+	claim, err := ctrl.pvcLister.PersistentVolumeClaims(upload.Namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		claim = newUploadClaim(upload)
+		claim.Name = name
+		claim.Labels = nil
+		claim.OwnerReferences = []metav1.OwnerReference{*newUploadRef(upload)}
+		if _, err := ctrl.kubeClient.CoreV1().PersistentVolumeClaims(upload.Namespace).Create(claim); err != nil && !apierrors.IsAlreadyExists(err) {
+			glog.V(2).Infof("error creating scratch claim for upload %s/%s: %v", upload.Namespace, upload.Name, err)
+			return false
+		}
+		return true
+	}
+	if err != nil {
+		glog.V(2).Infof("error getting claim %s/%s: %v", upload.Namespace, name, err)
+		return false
+	}
+	if ref := metav1.GetControllerOf(claim); ref == nil || ref.UID != upload.UID {
+		ctrl.failUpload(upload, fmt.Sprintf("claim %s already exists", name))
+		return false
+	}
+	return true
+}
+
+// deleteScratchClaim frees the space an upload's image was staged on once it
+// is no longer needed
+func (ctrl *UploadController) deleteScratchClaim(upload *vmapi.VirtualMachineImageUpload) {
+	err := ctrl.kubeClient.CoreV1().PersistentVolumeClaims(upload.Namespace).Delete(uploadScratchName(upload), &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		glog.V(2).Infof("error deleting scratch claim of upload %s/%s: %v", upload.Namespace, upload.Name, err)
+	}
+}
+
+// createServerSecret creates the secret holding the token the upload's pod
+// requires of the console server. It is named after the pod and owned by the
+// upload. Returns false if the pod can't be created yet.
+func (ctrl *UploadController) createServerSecret(upload *vmapi.VirtualMachineImageUpload) bool {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		glog.V(2).Infof("error generating token for upload %s/%s: %v", upload.Namespace, upload.Name, err)
+		return false
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            uploadPodName(upload),
+			Namespace:       upload.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*newUploadRef(upload)},
+		},
+		Data: map[string][]byte{
+			console.SecretServerToken: []byte(hex.EncodeToString(token)),
+		},
+	}
+	_, err := ctrl.kubeClient.CoreV1().Secrets(upload.Namespace).Create(secret)
+	if err == nil {
+		return true
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		glog.V(2).Infof("error creating secret for upload %s/%s: %v", upload.Namespace, upload.Name, err)
+		return false
+	}
+	// A secret made by anyone else would give its token away
+	existing, err := ctrl.kubeClient.CoreV1().Secrets(upload.Namespace).Get(secret.Name, metav1.GetOptions{})
+	if err != nil {
+		glog.V(2).Infof("error getting secret %s/%s: %v", upload.Namespace, secret.Name, err)
+		return false
+	}
+	if ref := metav1.GetControllerOf(existing); ref == nil || ref.UID != upload.UID {
+		ctrl.failUpload(upload, fmt.Sprintf("secret %s already exists", secret.Name))
+		return false
+	}
+	return true
+}
+
+// newUploadPod returns the pod receiving an image and converting it onto the
+// upload's claim. Chunks are staged on the scratch claim until the image is
+// complete, so that pods replacing it resume the upload.
+func (ctrl *UploadController) newUploadPod(upload *vmapi.VirtualMachineImageUpload) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            uploadPodName(upload),
+			Namespace:       upload.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*newUploadRef(upload)},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				corev1.Container{
+					Name:    "upload",
+					Image:   ctrl.launcherImage,
+					Command: launcher.UploadCommand(targetDir + "/disk.img"),
+					Env: []corev1.EnvVar{
+						corev1.EnvVar{
+							Name: launcher.EnvServerToken,
+							ValueFrom: &corev1.EnvVarSource{
+								SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: uploadPodName(upload)},
+									Key:                  console.SecretServerToken,
+								},
+							},
+						},
+					},
+					Ports: []corev1.ContainerPort{
+						corev1.ContainerPort{
+							Name:          "http",
+							ContainerPort: launcher.UploadPort,
+							Protocol:      corev1.ProtocolTCP,
+						},
+					},
+					ReadinessProbe: &corev1.Probe{
+						Handler: corev1.Handler{
+							HTTPGet: &corev1.HTTPGetAction{
+								Path: "/healthz",
+								Port: intstr.FromString("http"),
+							},
+						},
+						PeriodSeconds: 2,
+					},
+					// Errors are logged by the launcher as it exits
+					TerminationMessagePath:   launcher.TerminationLog,
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					VolumeMounts: []corev1.VolumeMount{
+						corev1.VolumeMount{
+							Name:      "disk",
+							MountPath: targetDir,
+						},
+						corev1.VolumeMount{
+							Name:      "scratch",
+							MountPath: launcher.ScratchDir,
+						},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				corev1.Volume{
+					Name: "disk",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: uploadClaimName(upload),
+						},
+					},
+				},
+				corev1.Volume{
+					Name: "scratch",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: uploadScratchName(upload),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// uploadTermination returns how the container of an upload pod terminated
+func uploadTermination(pod *corev1.Pod) *corev1.ContainerStateTerminated {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == "upload" {
+			return status.State.Terminated
+		}
+	}
+	return nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func (ctrl *UploadController) failUpload(upload *vmapi.VirtualMachineImageUpload, message string) {
+	upload.Status.Phase = vmapi.UploadFailed
+	upload.Status.Path = ""
+	upload.Status.Message = message
+	ctrl.recorder.Event(upload, corev1.EventTypeWarning, "Failed", message)
+	ctrl.updateUploadStatus(upload)
+}
+
+func (ctrl *UploadController) updateUploadStatus(upload *vmapi.VirtualMachineImageUpload) {
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineImageUploads(upload.Namespace).Update(upload)
+	if err != nil {
+		glog.V(2).Infof("error updating status of upload %s/%s: %v", upload.Namespace, upload.Name, err)
+	}
+}
@@ -0,0 +1,174 @@
+package upload
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	vmclientset "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned"
+	vmscheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
+	vminformers "github.com/llparse/kube-crd-skel/pkg/client/informers/externalversions/virtualmachine/v1alpha1"
+	vmlisters "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
+)
+
+// LabelUpload is set on claims created for an image upload to its name
+const LabelUpload = "vm.rancher.com/image-upload"
+
+// UploadController runs the pods receiving VirtualMachineImageUploads
+type UploadController struct {
+	vmClient   vmclientset.Interface
+	kubeClient kubernetes.Interface
+
+	uploadLister       vmlisters.VirtualMachineImageUploadLister
+	uploadListerSynced cache.InformerSynced
+	podLister          corelisters.PodLister
+	podListerSynced    cache.InformerSynced
+	pvcLister          corelisters.PersistentVolumeClaimLister
+	pvcListerSynced    cache.InformerSynced
+
+	queue workqueue.RateLimitingInterface
+
+	recorder record.EventRecorder
+
+	launcherImage string
+}
+
+func NewUploadController(
+	vmClient vmclientset.Interface,
+	kubeClient kubernetes.Interface,
+	uploadInformer vminformers.VirtualMachineImageUploadInformer,
+	podInformer coreinformers.PodInformer,
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+	launcherImage string,
+) *UploadController {
+
+	ctrl := &UploadController{
+		vmClient:      vmClient,
+		kubeClient:    kubeClient,
+		queue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachineimageupload"),
+		launcherImage: launcherImage,
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(glog.Infof)
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	ctrl.recorder = broadcaster.NewRecorder(vmscheme.Scheme, corev1.EventSource{Component: "vm-upload-controller"})
+
+	uploadInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    ctrl.enqueueWork,
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueWork(newObj) },
+		},
+	)
+
+	// Uploads own their pod
+	podInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueOwner(newObj) },
+			DeleteFunc: ctrl.enqueueOwner,
+		},
+	)
+
+	ctrl.uploadLister = uploadInformer.Lister()
+	ctrl.uploadListerSynced = uploadInformer.Informer().HasSynced
+
+	ctrl.podLister = podInformer.Lister()
+	ctrl.podListerSynced = podInformer.Informer().HasSynced
+
+	ctrl.pvcLister = pvcInformer.Lister()
+	ctrl.pvcListerSynced = pvcInformer.Informer().HasSynced
+
+	return ctrl
+}
+
+func (ctrl *UploadController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.queue.ShutDown()
+
+	glog.Infof("Starting upload controller")
+	defer glog.Infof("Shutting down upload controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.uploadListerSynced, ctrl.podListerSynced, ctrl.pvcListerSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (ctrl *UploadController) enqueueWork(obj interface{}) {
+	// Beware of "xxx deleted" events
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	objName, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		glog.Errorf("failed to get key from object: %v", err)
+		return
+	}
+	glog.V(5).Infof("enqueued %q for sync", objName)
+	ctrl.queue.Add(objName)
+}
+
+func (ctrl *UploadController) enqueueOwner(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	ref := metav1.GetControllerOf(meta)
+	if ref == nil || ref.Kind != "VirtualMachineImageUpload" {
+		return
+	}
+	ctrl.queue.Add(meta.GetNamespace() + "/" + ref.Name)
+}
+
+func (ctrl *UploadController) worker() {
+	workFunc := func() bool {
+		keyObj, quit := ctrl.queue.Get()
+		if quit {
+			return true
+		}
+		defer ctrl.queue.Done(keyObj)
+		key := keyObj.(string)
+		glog.V(5).Infof("worker[%s]", key)
+
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			glog.V(4).Infof("error getting name of upload %q to get upload from informer: %v", key, err)
+			return false
+		}
+		upload, err := ctrl.uploadLister.VirtualMachineImageUploads(ns).Get(name)
+		if err == nil {
+			ctrl.updateUpload(upload)
+			return false
+		}
+		if !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error getting upload %q from informer: %v", key, err)
+		}
+		// The pods of deleted uploads are garbage collected, while their
+		// claims are kept
+		return false
+	}
+	for {
+		if quit := workFunc(); quit {
+			glog.Infof("upload worker queue shutting down")
+			return
+		}
+	}
+}
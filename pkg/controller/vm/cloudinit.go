@@ -0,0 +1,38 @@
+package vm
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func vmHostname(vm *vmapi.VirtualMachine) string {
+	if vm.Spec.Hostname != "" {
+		return vm.Spec.Hostname
+	}
+	return vm.Name
+}
+
+// setCloudInitAnnotations passes the guest's identity to the launcher. The
+// instance ID defaults to the VM's UID, so that a VM recreated from the same
+// spec is a new instance to cloud-init.
+func setCloudInitAnnotations(vm *vmapi.VirtualMachine, pod *corev1.Pod) error {
+	pod.Annotations[ranchervm.GroupName+"/hostname"] = vmHostname(vm)
+	if vm.Spec.CloudInit == nil {
+		return nil
+	}
+
+	cloudInit := *vm.Spec.CloudInit
+	if cloudInit.InstanceID == "" {
+		cloudInit.InstanceID = string(vm.UID)
+	}
+	data, err := json.Marshal(cloudInit)
+	if err != nil {
+		return err
+	}
+	pod.Annotations[ranchervm.GroupName+"/cloud_init"] = string(data)
+	return nil
+}
@@ -0,0 +1,70 @@
+package vm
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+func validateDisks(disks []vmapi.Disk) error {
+	names := map[string]bool{}
+	for _, disk := range disks {
+		if errs := validation.IsDNS1123Label(diskVolumeName(disk)); len(errs) > 0 {
+			return fmt.Errorf("disk %q: invalid name: %s", disk.Name, strings.Join(errs, ", "))
+		}
+		if names[disk.Name] {
+			return fmt.Errorf("duplicate disk name %q", disk.Name)
+		}
+		names[disk.Name] = true
+
+		if disk.ClaimName == "" {
+			return fmt.Errorf("disk %q: claim_name is required", disk.Name)
+		}
+		if disk.SizeMB < 0 {
+			return fmt.Errorf("disk %q: size_mb must not be negative", disk.Name)
+		}
+	}
+	return nil
+}
+
+func diskVolumeName(disk vmapi.Disk) string {
+	return "disk-" + disk.Name
+}
+
+// setDiskVolumes mounts the claim of every disk in the launcher container and
+// passes the disks to the launcher
+func setDiskVolumes(vm *vmapi.VirtualMachine, pod *corev1.Pod) error {
+	if len(vm.Spec.Disks) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(vm.Spec.Disks)
+	if err != nil {
+		return err
+	}
+	pod.Annotations[ranchervm.GroupName+"/disks"] = string(data)
+
+	container := &pod.Spec.Containers[0]
+	for _, disk := range vm.Spec.Disks {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: diskVolumeName(disk),
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: disk.ClaimName,
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      diskVolumeName(disk),
+			MountPath: filepath.Join(launcher.DiskDir, disk.Name),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// LabelEvictedPod is set on migrations started to evict a pod from its node
+// to the pod's UID, so that failed evictions aren't retried
+const LabelEvictedPod = ranchervm.GroupName + "/evicted-pod"
+
+func evictionStrategy(vm *vmapi.VirtualMachine) vmapi.EvictionStrategy {
+	if vm.Spec.EvictionStrategy == "" {
+		return vmapi.EvictionStrategyShutdown
+	}
+	return vm.Spec.EvictionStrategy
+}
+
+func validateEvictionStrategy(strategy vmapi.EvictionStrategy) error {
+	switch strategy {
+	case "", vmapi.EvictionStrategyLiveMigrate, vmapi.EvictionStrategyShutdown, vmapi.EvictionStrategyBlock:
+		return nil
+	}
+	return fmt.Errorf("unknown eviction strategy %q", strategy)
+}
+
+// syncDisruptionBudget creates or deletes the PodDisruptionBudget keeping
+// drains from evicting the VM's pod
+func (ctrl *VirtualMachineController) syncDisruptionBudget(vm *vmapi.VirtualMachine) {
+	strategy := evictionStrategy(vm)
+	want := !vm.Spec.Stopped && (strategy == vmapi.EvictionStrategyLiveMigrate || strategy == vmapi.EvictionStrategyBlock)
+
+	pdb, err := ctrl.pdbLister.PodDisruptionBudgets(vm.Namespace).Get(vm.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		glog.V(2).Infof("error getting pod disruption budget %s/%s from informer: %v", vm.Namespace, vm.Name, err)
+		return
+	}
+	if err == nil && !isControlledBy(pdb, vm) {
+		glog.V(2).Infof("pod disruption budget %s/%s exists and is not controlled by vm", vm.Namespace, vm.Name)
+		return
+	}
+
+	switch {
+	case !want && err == nil:
+		err := ctrl.kubeClient.PolicyV1beta1().PodDisruptionBudgets(vm.Namespace).Delete(vm.Name, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error deleting pod disruption budget %s/%s: %v", vm.Namespace, vm.Name, err)
+		}
+	case want && apierrors.IsNotFound(err):
+		minAvailable := intstr.FromInt(1)
+		pdb = &policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      vm.Name,
+				Namespace: vm.Namespace,
+				Labels: map[string]string{
+					"type":                "ranchervm",
+					ranchervm.LabelVMName: vm.Name,
+				},
+				OwnerReferences: []metav1.OwnerReference{*newControllerRef(vm)},
+			},
+			Spec: policyv1beta1.PodDisruptionBudgetSpec{
+				MinAvailable: &minAvailable,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"type":                "ranchervm",
+						ranchervm.LabelVMName: vm.Name,
+					},
+				},
+			},
+		}
+		if _, err := ctrl.kubeClient.PolicyV1beta1().PodDisruptionBudgets(vm.Namespace).Create(pdb); err != nil {
+			glog.V(2).Infof("error creating pod disruption budget %s/%s: %v", vm.Namespace, vm.Name, err)
+		}
+	}
+}
+
+// syncEviction moves the VM off its node once the node is cordoned,
+// according to the VM's eviction strategy. Returns true if status was
+// modified.
+func (ctrl *VirtualMachineController) syncEviction(vm *vmapi.VirtualMachine, pod *corev1.Pod) bool {
+	reason, message := ctrl.evict(vm, pod)
+	if reason == "" {
+		return removeCondition(vm, vmapi.VirtualMachineEvictionBlocked)
+	}
+	// Drains keep retrying, warn only as the VM starts blocking one
+	if !setCondition(vm, vmapi.VirtualMachineCondition{
+		Type:    vmapi.VirtualMachineEvictionBlocked,
+		Status:  corev1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	}) {
+		return false
+	}
+	ctrl.recorder.Event(vm, corev1.EventTypeWarning, reason, message)
+	return true
+}
+
+// evict starts moving the VM off a cordoned node. Pods of VMs shut down on
+// eviction have no PodDisruptionBudget and are left for the drain to evict,
+// as cordoning alone doesn't mean the node is being drained. Returns why the
+// VM can't leave the node, if it can't.
+func (ctrl *VirtualMachineController) evict(vm *vmapi.VirtualMachine, pod *corev1.Pod) (string, string) {
+	if pod == nil || pod.DeletionTimestamp != nil || pod.Spec.NodeName == "" {
+		return "", ""
+	}
+	node, err := ctrl.nodeLister.Get(pod.Spec.NodeName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			glog.V(2).Infof("error getting node %s from informer: %v", pod.Spec.NodeName, err)
+		}
+		return "", ""
+	}
+	if !node.Spec.Unschedulable {
+		return "", ""
+	}
+
+	switch evictionStrategy(vm) {
+	case vmapi.EvictionStrategyLiveMigrate:
+		return ctrl.migrateOffNode(vm, pod)
+	case vmapi.EvictionStrategyBlock:
+		return "EvictionBlocked", fmt.Sprintf("Node %s is cordoned, stop or migrate the vm to drain it", node.Name)
+	}
+	return "", ""
+}
+
+// migrateOffNode starts a migration evicting pod, unless one was started
+// already. Returns why the VM can't leave the node if the migration failed.
+func (ctrl *VirtualMachineController) migrateOffNode(vm *vmapi.VirtualMachine, pod *corev1.Pod) (string, string) {
+	migrations, err := ctrl.migrationLister.VirtualMachineMigrations(vm.Namespace).List(labels.Everything())
+	if err != nil {
+		glog.V(2).Infof("error listing migrations of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		return "", ""
+	}
+	for _, migration := range migrations {
+		if migration.Spec.VirtualMachineName != vm.Name {
+			continue
+		}
+		switch migration.Status.Phase {
+		case vmapi.MigrationSucceeded:
+			continue
+		case vmapi.MigrationFailed:
+			if migration.Labels[LabelEvictedPod] == string(pod.UID) {
+				return "EvictionFailed", fmt.Sprintf("Migration %s off cordoned node %s failed, stop or migrate the vm to drain it",
+					migration.Name, pod.Spec.NodeName)
+			}
+			continue
+		}
+		// Wait for any migration in progress
+		return "", ""
+	}
+
+	migration := &vmapi.VirtualMachineMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: vm.Name + "-evict-",
+			Namespace:    vm.Namespace,
+			Labels: map[string]string{
+				ranchervm.LabelVMName: vm.Name,
+				LabelEvictedPod:       string(pod.UID),
+			},
+			OwnerReferences: []metav1.OwnerReference{*newControllerRef(vm)},
+		},
+		Spec: vmapi.VirtualMachineMigrationSpec{
+			VirtualMachineName: vm.Name,
+		},
+	}
+	migration, err = ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineMigrations(vm.Namespace).Create(migration)
+	if err != nil {
+		glog.V(2).Infof("error creating migration of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		return "", ""
+	}
+	ctrl.recorder.Eventf(vm, corev1.EventTypeNormal, "Evicting", "Migrating off cordoned node %s with %s", pod.Spec.NodeName, migration.Name)
+	return "", ""
+}
+
+// enqueueNodeVMs queues the VMs running on a node that was just cordoned or
+// uncordoned
+func (ctrl *VirtualMachineController) enqueueNodeVMs(oldObj, newObj interface{}) {
+	oldNode, ok := oldObj.(*corev1.Node)
+	if !ok {
+		return
+	}
+	node, ok := newObj.(*corev1.Node)
+	if !ok || oldNode.Spec.Unschedulable == node.Spec.Unschedulable {
+		return
+	}
+	vms, err := ctrl.vmLister.List(labels.Everything())
+	if err != nil {
+		glog.V(2).Infof("error listing vms: %v", err)
+		return
+	}
+	for _, vm := range vms {
+		if vm.Status.NodeName == node.Name {
+			ctrl.enqueueWork(ctrl.vmQueue, vm)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// liveExport returns the export holding the VM's disks, if any. Exports hold
+// them from creating their pod until they fail or are deleted.
+func (ctrl *VirtualMachineController) liveExport(vm *vmapi.VirtualMachine) (*vmapi.VirtualMachineExport, error) {
+	exports, err := ctrl.exportLister.VirtualMachineExports(vm.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, export := range exports {
+		if export.Spec.VirtualMachineName == vm.Name && export.Status.PodName != "" && export.Status.Phase != vmapi.ExportFailed {
+			return export, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkExports sets the VM's Exporting condition while an export holds its
+// disks. Returns whether the VM may start, and true if status was modified.
+func (ctrl *VirtualMachineController) checkExports(vm *vmapi.VirtualMachine) (bool, bool) {
+	export, err := ctrl.liveExport(vm)
+	if err != nil {
+		glog.V(2).Infof("error listing exports of namespace %s: %v", vm.Namespace, err)
+		return false, false
+	}
+	if export == nil {
+		return true, removeCondition(vm, vmapi.VirtualMachineExporting)
+	}
+	message := fmt.Sprintf("Export %s holds the disks, delete it to start the vm", export.Name)
+	if !setCondition(vm, vmapi.VirtualMachineCondition{
+		Type:    vmapi.VirtualMachineExporting,
+		Status:  corev1.ConditionTrue,
+		Reason:  "ExportInProgress",
+		Message: message,
+	}) {
+		return false, false
+	}
+	ctrl.recorder.Event(vm, corev1.EventTypeWarning, "ExportInProgress", message)
+	return false, true
+}
+
+// enqueueExportVM queues the VM of an export, which may start once the export
+// is gone
+func (ctrl *VirtualMachineController) enqueueExportVM(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	export, ok := obj.(*vmapi.VirtualMachineExport)
+	if !ok {
+		return
+	}
+	ctrl.vmQueue.Add(export.Namespace + "/" + export.Spec.VirtualMachineName)
+}
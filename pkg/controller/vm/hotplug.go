@@ -0,0 +1,212 @@
+package vm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/console"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+// Linux onlines hotplugged memory in blocks of this size
+const memoryBlockMB = 128
+
+func validateHotplug(spec *vmapi.VirtualMachineSpec) error {
+	if spec.MaxCpuMillis == 0 && spec.MaxMemoryMB == 0 {
+		return nil
+	}
+	if spec.MaxCpuMillis != 0 && spec.MaxCpuMillis < spec.CpuMillis {
+		return fmt.Errorf("max_cpu_milli is below cpu_milli")
+	}
+	if spec.MaxMemoryMB != 0 {
+		if spec.MaxMemoryMB < spec.MemoryMB {
+			return fmt.Errorf("max_memory_mb is below memory_mb")
+		}
+		if spec.MemoryMB%memoryBlockMB != 0 || spec.MaxMemoryMB%memoryBlockMB != 0 {
+			return fmt.Errorf("memory_mb and max_memory_mb must be multiples of %d for memory hotplug", memoryBlockMB)
+		}
+	}
+	if spec.DedicatedCPUPlacement || spec.Hugepages != "" {
+		return fmt.Errorf("max_cpu_milli and max_memory_mb can't be combined with dedicated_cpu_placement or hugepages")
+	}
+	return nil
+}
+
+// setHotplug lets the VM's guest grow up to its maximum size
+func setHotplug(vm *vmapi.VirtualMachine, pod *corev1.Pod) {
+	if vm.Spec.MaxCpuMillis != 0 {
+		pod.Annotations[ranchervm.GroupName+"/max_cpu_milli"] = strconv.Itoa(int(vm.Spec.MaxCpuMillis))
+	}
+	if vm.Spec.MaxMemoryMB != 0 {
+		pod.Annotations[ranchervm.GroupName+"/max_memory_mb"] = strconv.Itoa(int(vm.Spec.MaxMemoryMB))
+	}
+}
+
+// podSize is the size of the guest running in a pod
+type podSize struct {
+	vcpus, memoryMB       int
+	maxVCPUs, maxMemoryMB int
+	hotplugged            launcher.HotplugState
+}
+
+func newPodSize(pod *corev1.Pod) (*podSize, error) {
+	config, err := launcher.ConfigFromAnnotations(pod.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	size := &podSize{
+		vcpus:       config.VCPUs(),
+		memoryMB:    config.MemoryMB,
+		maxVCPUs:    config.MaxVCPUs(),
+		maxMemoryMB: config.MemoryMB,
+	}
+	if config.MaxMemoryMB > config.MemoryMB {
+		size.maxMemoryMB = config.MaxMemoryMB
+	}
+	// A launcher restarting in place boots the guest without the devices
+	// hotplugged before, which are then hotplugged again
+	hotplugged, err := launcher.RecordedHotplugState(pod.Annotations, console.LauncherRestarts(pod))
+	if err != nil {
+		return nil, err
+	}
+	if hotplugged != nil {
+		size.hotplugged = *hotplugged
+	}
+	return size, nil
+}
+
+func (s *podSize) currentVCPUs() int {
+	return s.vcpus + len(s.hotplugged.CPUs)
+}
+
+func (s *podSize) currentMemoryMB() int {
+	return s.memoryMB + s.hotplugged.MemoryMB()
+}
+
+// syncHotplug raises the CPU and memory of a running guest to those of its
+// spec, and reports changes that need a restart. Pods can't be resized in
+// this Kubernetes version, so the resources of the VM's pod are only updated
+// once it restarts. Returns true if status was modified.
+func (ctrl *VirtualMachineController) syncHotplug(vm *vmapi.VirtualMachine, pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	size, err := newPodSize(pod)
+	if err != nil {
+		glog.V(2).Infof("error reading size of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return false
+	}
+
+	// Instance types are resolved in status
+	spec := &effectiveVM(vm).Spec
+	vcpus := (int(spec.CpuMillis) + 999) / 1000
+	memoryMB := int(spec.MemoryMB)
+
+	var changed, grown bool
+	var pending []string
+	if int(spec.MaxCpuMillis) != atoi(pod.Annotations[ranchervm.GroupName+"/max_cpu_milli"]) ||
+		int(spec.MaxMemoryMB) != atoi(pod.Annotations[ranchervm.GroupName+"/max_memory_mb"]) {
+		pending = append(pending, "max_cpu_milli and max_memory_mb apply on restart")
+	}
+	switch {
+	case vcpus < size.currentVCPUs() || memoryMB < size.currentMemoryMB():
+		pending = append(pending, "CPU and memory can only be lowered on restart")
+	case vcpus > size.maxVCPUs || memoryMB > size.maxMemoryMB:
+		pending = append(pending, fmt.Sprintf("the VM was started with at most %d vCPUs and %dMB", size.maxVCPUs, size.maxMemoryMB))
+	case vcpus > size.currentVCPUs() || memoryMB > size.currentMemoryMB():
+		if ctrl.migrating(vm) {
+			return false
+		}
+		// Growth counts against quotas as a VM starting with the new size
+		// would
+		unlock := ctrl.lockQuota(vm.Namespace)
+		fits, quotaChanged := ctrl.checkQuota(vm)
+		hotplugged := fits && ctrl.hotplug(vm, pod, size, vcpus, memoryMB)
+		unlock()
+		if !hotplugged {
+			return quotaChanged
+		}
+		changed, grown = quotaChanged, true
+	}
+	// Quotas only hold back growth
+	if !grown && removeCondition(vm, vmapi.VirtualMachineExceededQuota) {
+		changed = true
+	}
+
+	condition := vmapi.VirtualMachineCondition{
+		Type:   vmapi.VirtualMachineRestartRequired,
+		Status: corev1.ConditionTrue,
+	}
+	switch {
+	case len(pending) > 0:
+		condition.Reason = "SpecNotApplied"
+		condition.Message = strings.Join(pending, "; ")
+	case len(size.hotplugged.CPUs) > 0 || len(size.hotplugged.DIMMs) > 0:
+		condition.Reason = "PodNotResized"
+		condition.Message = "CPU and memory were hotplugged, the pod's resources are updated on restart"
+	default:
+		return removeCondition(vm, vmapi.VirtualMachineRestartRequired) || changed
+	}
+	return setCondition(vm, condition) || changed
+}
+
+// hotplug grows the guest and records what was hotplugged on its pod, so
+// that the targets of later migrations start with it. Returns true on
+// success.
+func (ctrl *VirtualMachineController) hotplug(vm *vmapi.VirtualMachine, pod *corev1.Pod, size *podSize, vcpus, memoryMB int) bool {
+	if memoryMB != size.currentMemoryMB() && (memoryMB-size.currentMemoryMB())%memoryBlockMB != 0 {
+		ctrl.recorder.Eventf(vm, corev1.EventTypeWarning, "FailedHotplug", "Memory must grow by multiples of %dMB", memoryBlockMB)
+		return false
+	}
+	out, err := console.ExecOutput(ctrl.config, ctrl.kubeClient, pod, launcher.HotplugCommand(vcpus, memoryMB))
+	if err != nil {
+		glog.V(2).Infof("error hotplugging into vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		ctrl.recorder.Eventf(vm, corev1.EventTypeWarning, "FailedHotplug", "Error hotplugging CPU and memory: %v", err)
+		return false
+	}
+	if err := json.Unmarshal(out, &size.hotplugged); err != nil {
+		glog.V(2).Infof("error parsing hotplugged devices of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		return false
+	}
+
+	pod = pod.DeepCopy()
+	pod.Annotations[launcher.AnnotationHotplugged] = strings.TrimSpace(string(out))
+	pod.Annotations[launcher.AnnotationHotpluggedRestarts] = strconv.Itoa(console.LauncherRestarts(pod))
+	if _, err := ctrl.kubeClient.CoreV1().Pods(pod.Namespace).Update(pod); err != nil {
+		glog.V(2).Infof("error recording hotplugged devices on pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+	ctrl.recorder.Eventf(vm, corev1.EventTypeNormal, "Hotplugged", "Guest has %d vCPUs and %dMB", vcpus, memoryMB)
+	return true
+}
+
+// migrating returns true if the VM is being migrated, during which devices
+// can't be added
+func (ctrl *VirtualMachineController) migrating(vm *vmapi.VirtualMachine) bool {
+	migrations, err := ctrl.migrationLister.VirtualMachineMigrations(vm.Namespace).List(labels.Everything())
+	if err != nil {
+		glog.V(2).Infof("error listing migrations: %v", err)
+		return true
+	}
+	for _, migration := range migrations {
+		if migration.Spec.VirtualMachineName != vm.Name {
+			continue
+		}
+		if migration.Status.Phase != vmapi.MigrationSucceeded && migration.Status.Phase != vmapi.MigrationFailed {
+			return true
+		}
+	}
+	return false
+}
+
+func atoi(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
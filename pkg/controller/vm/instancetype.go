@@ -0,0 +1,137 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// validateSizing checks that the VM is sized either by an instance type or
+// by its own spec, and that the resulting size is within limits
+func (ctrl *VirtualMachineController) validateSizing(vm, effective *vmapi.VirtualMachine) error {
+	spec := &vm.Spec
+	if spec.InstanceType != "" {
+		if spec.CpuMillis != 0 || spec.MemoryMB != 0 {
+			return fmt.Errorf("cpu_milli and memory_mb can't be set along with instance_type")
+		}
+	} else if spec.CpuMillis == 0 || spec.MemoryMB == 0 {
+		return fmt.Errorf("cpu_milli and memory_mb are required without instance_type")
+	}
+	if err := ctrl.sizeLimits.Validate(effective.Spec.CpuMillis, effective.Spec.MemoryMB); err != nil {
+		return err
+	}
+	if err := validateMemoryRequest(&effective.Spec); err != nil {
+		return err
+	}
+	if err := validateHotplug(&effective.Spec); err != nil {
+		return err
+	}
+	return validatePlacement(&effective.Spec)
+}
+
+// resolveSpec copies the VM's instance type and preference into status. It
+// is called as the VM starts, so that they only take effect on restart.
+// Returns true if status was modified.
+func (ctrl *VirtualMachineController) resolveSpec(vm *vmapi.VirtualMachine) (bool, error) {
+	var instanceType *vmapi.ResolvedInstanceType
+	if vm.Spec.InstanceType != "" {
+		it, err := ctrl.instanceTypeLister.Get(vm.Spec.InstanceType)
+		if err != nil {
+			return false, fmt.Errorf("error getting instance type %s: %v", vm.Spec.InstanceType, err)
+		}
+		instanceType = &vmapi.ResolvedInstanceType{
+			Name: it.Name,
+			Spec: *it.Spec.DeepCopy(),
+		}
+	}
+
+	var preference *vmapi.ResolvedPreference
+	if vm.Spec.Preference != "" {
+		pref, err := ctrl.preferenceLister.VirtualMachinePreferences(vm.Namespace).Get(vm.Spec.Preference)
+		if err != nil {
+			return false, fmt.Errorf("error getting preference %s: %v", vm.Spec.Preference, err)
+		}
+		preference = &vmapi.ResolvedPreference{
+			Name: pref.Name,
+			Spec: *pref.Spec.DeepCopy(),
+		}
+	}
+
+	if apiequality.Semantic.DeepEqual(vm.Status.InstanceType, instanceType) &&
+		apiequality.Semantic.DeepEqual(vm.Status.Preference, preference) {
+		return false, nil
+	}
+	vm.Status.InstanceType = instanceType
+	vm.Status.Preference = preference
+	return true, nil
+}
+
+// effectiveVM returns a copy of vm with the instance type and preference
+// resolved in its status applied to its spec
+func effectiveVM(vm *vmapi.VirtualMachine) *vmapi.VirtualMachine {
+	vm = vm.DeepCopy()
+	spec := &vm.Spec
+
+	if it := vm.Status.InstanceType; it != nil {
+		spec.CpuMillis = it.Spec.CpuMillis
+		spec.MemoryMB = it.Spec.MemoryMB
+		if it.Spec.Hugepages != "" {
+			spec.Hugepages = it.Spec.Hugepages
+		}
+		spec.DedicatedCPUPlacement = spec.DedicatedCPUPlacement || it.Spec.DedicatedCPUPlacement
+	}
+
+	if pref := vm.Status.Preference; pref != nil {
+		if spec.TerminationGracePeriodSeconds == nil && pref.Spec.TerminationGracePeriodSeconds != nil {
+			gracePeriod := *pref.Spec.TerminationGracePeriodSeconds
+			spec.TerminationGracePeriodSeconds = &gracePeriod
+		}
+		if len(spec.NodeSelector) == 0 {
+			spec.NodeSelector = pref.Spec.NodeSelector
+		}
+		if spec.Affinity == nil {
+			spec.Affinity = pref.Spec.Affinity
+		}
+		if len(spec.Tolerations) == 0 {
+			spec.Tolerations = pref.Spec.Tolerations
+		}
+		if spec.PriorityClassName == "" {
+			spec.PriorityClassName = pref.Spec.PriorityClassName
+		}
+		spec.AllowEmulation = spec.AllowEmulation || pref.Spec.AllowEmulation
+	}
+	return vm
+}
+
+// enqueueReferencingVMs queues the VMs that refer to an instance type or
+// preference, as they may be waiting for it to start
+func (ctrl *VirtualMachineController) enqueueReferencingVMs(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	vms, err := ctrl.vmLister.List(labels.Everything())
+	if err != nil {
+		glog.V(2).Infof("error listing vms: %v", err)
+		return
+	}
+	for _, vm := range vms {
+		switch o := obj.(type) {
+		case *vmapi.VirtualMachineInstanceType:
+			if vm.Spec.InstanceType != o.Name {
+				continue
+			}
+		case *vmapi.VirtualMachinePreference:
+			if vm.Namespace != o.Namespace || vm.Spec.Preference != o.Name {
+				continue
+			}
+		default:
+			return
+		}
+		ctrl.enqueueWork(ctrl.vmQueue, vm)
+	}
+}
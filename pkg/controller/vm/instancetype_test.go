@@ -0,0 +1,108 @@
+package vm
+
+import (
+	"testing"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func TestEffectiveVM(t *testing.T) {
+	gracePeriod := int64(30)
+	ownGracePeriod := int64(300)
+
+	tests := []struct {
+		name   string
+		spec   vmapi.VirtualMachineSpec
+		status vmapi.VirtualMachineStatus
+		check  func(*vmapi.VirtualMachineSpec) bool
+	}{
+		{
+			name: "instance type sizes the vm",
+			spec: vmapi.VirtualMachineSpec{InstanceType: "large"},
+			status: vmapi.VirtualMachineStatus{
+				InstanceType: &vmapi.ResolvedInstanceType{
+					Name: "large",
+					Spec: vmapi.VirtualMachineInstanceTypeSpec{
+						CpuMillis:             4000,
+						MemoryMB:              8192,
+						Hugepages:             "1Gi",
+						DedicatedCPUPlacement: true,
+					},
+				},
+			},
+			check: func(spec *vmapi.VirtualMachineSpec) bool {
+				return spec.CpuMillis == 4000 && spec.MemoryMB == 8192 &&
+					spec.Hugepages == "1Gi" && spec.DedicatedCPUPlacement
+			},
+		},
+		{
+			name: "vm keeps its own hugepages and dedicated cpus",
+			spec: vmapi.VirtualMachineSpec{
+				InstanceType:          "large",
+				Hugepages:             "2Mi",
+				DedicatedCPUPlacement: true,
+			},
+			status: vmapi.VirtualMachineStatus{
+				InstanceType: &vmapi.ResolvedInstanceType{
+					Name: "large",
+					Spec: vmapi.VirtualMachineInstanceTypeSpec{CpuMillis: 4000, MemoryMB: 8192},
+				},
+			},
+			check: func(spec *vmapi.VirtualMachineSpec) bool {
+				return spec.Hugepages == "2Mi" && spec.DedicatedCPUPlacement
+			},
+		},
+		{
+			name: "preference fills in defaults",
+			spec: vmapi.VirtualMachineSpec{CpuMillis: 1000, MemoryMB: 1024, Preference: "batch"},
+			status: vmapi.VirtualMachineStatus{
+				Preference: &vmapi.ResolvedPreference{
+					Name: "batch",
+					Spec: vmapi.VirtualMachinePreferenceSpec{
+						TerminationGracePeriodSeconds: &gracePeriod,
+						NodeSelector:                  map[string]string{"pool": "batch"},
+						PriorityClassName:             "low",
+						AllowEmulation:                true,
+					},
+				},
+			},
+			check: func(spec *vmapi.VirtualMachineSpec) bool {
+				return *spec.TerminationGracePeriodSeconds == 30 && spec.NodeSelector["pool"] == "batch" &&
+					spec.PriorityClassName == "low" && spec.AllowEmulation
+			},
+		},
+		{
+			name: "vm overrides preference",
+			spec: vmapi.VirtualMachineSpec{
+				CpuMillis:                     1000,
+				MemoryMB:                      1024,
+				Preference:                    "batch",
+				TerminationGracePeriodSeconds: &ownGracePeriod,
+				NodeSelector:                  map[string]string{"pool": "default"},
+			},
+			status: vmapi.VirtualMachineStatus{
+				Preference: &vmapi.ResolvedPreference{
+					Name: "batch",
+					Spec: vmapi.VirtualMachinePreferenceSpec{
+						TerminationGracePeriodSeconds: &gracePeriod,
+						NodeSelector:                  map[string]string{"pool": "batch"},
+					},
+				},
+			},
+			check: func(spec *vmapi.VirtualMachineSpec) bool {
+				return *spec.TerminationGracePeriodSeconds == 300 && spec.NodeSelector["pool"] == "default"
+			},
+		},
+	}
+
+	for _, test := range tests {
+		vm := &vmapi.VirtualMachine{Spec: test.spec, Status: test.status}
+		effective := effectiveVM(vm)
+		if !test.check(&effective.Spec) {
+			t.Errorf("%s: unexpected spec %+v", test.name, effective.Spec)
+		}
+		if effective == vm || vm.Spec.CpuMillis != test.spec.CpuMillis || vm.Spec.Hugepages != test.spec.Hugepages {
+			t.Errorf("%s: vm was modified", test.name)
+		}
+	}
+}
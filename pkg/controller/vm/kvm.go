@@ -0,0 +1,173 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+const kvmDevice = "/dev/kvm"
+
+// usesKVMNodeLabel returns true if VMs may fall back to privileged pods on
+// nodes carrying the KVM node label
+func (ctrl *VirtualMachineController) usesKVMNodeLabel() bool {
+	return ctrl.allowPrivileged && ctrl.kvmNodeLabel != ""
+}
+
+// kvmNodes returns how many nodes advertise the KVM device resource and how
+// many carry the KVM node label, if the label is used
+func (ctrl *VirtualMachineController) kvmNodes() (resourceNodes, labelNodes int, err error) {
+	nodes, err := ctrl.nodeLister.List(labels.Everything())
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, node := range nodes {
+		if ctrl.kvmResource != "" {
+			if quantity, ok := node.Status.Allocatable[ctrl.kvmResource]; ok && !quantity.IsZero() {
+				resourceNodes++
+			}
+		}
+		if ctrl.usesKVMNodeLabel() && node.Labels[ctrl.kvmNodeLabel] == "true" {
+			labelNodes++
+		}
+	}
+	return resourceNodes, labelNodes, nil
+}
+
+// hasKVM returns true if any node can run the VM. The
+// HardwareVirtualization condition tells why not.
+func (ctrl *VirtualMachineController) hasKVM(vm *vmapi.VirtualMachine) (bool, error) {
+	if vm.Spec.AllowEmulation {
+		return true, nil
+	}
+	resourceNodes, labelNodes, err := ctrl.kvmNodes()
+	return resourceNodes > 0 || labelNodes > 0, err
+}
+
+// setKVM keeps the pod of a VM that doesn't allow emulation on nodes with
+// KVM. The KVM device resource is requested if any node advertises it.
+// Otherwise, if privileged pods are allowed, the KVM node label is required.
+func (ctrl *VirtualMachineController) setKVM(vm *vmapi.VirtualMachine, pod *corev1.Pod) error {
+	if vm.Spec.AllowEmulation {
+		pod.Annotations[ranchervm.GroupName+"/allow_emulation"] = "true"
+		return nil
+	}
+
+	resourceNodes, _, err := ctrl.kvmNodes()
+	if err != nil {
+		return err
+	}
+	container := &pod.Spec.Containers[0]
+
+	if resourceNodes > 0 {
+		// The device plugin hands the device to the container
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = corev1.ResourceList{}
+		}
+		container.Resources.Limits[ctrl.kvmResource] = resource.MustParse("1")
+		return nil
+	}
+
+	if !ctrl.usesKVMNodeLabel() {
+		return fmt.Errorf("no node advertises %s", ctrl.kvmResource)
+	}
+	if pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = map[string]string{}
+	}
+	pod.Spec.NodeSelector[ctrl.kvmNodeLabel] = "true"
+
+	// Without a device plugin the device is mounted from the host, which
+	// only privileged containers may open
+	charDevice := corev1.HostPathCharDev
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: "kvm",
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{
+				Path: kvmDevice,
+				Type: &charDevice,
+			},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "kvm",
+		MountPath: kvmDevice,
+	})
+	privileged := true
+	container.SecurityContext.Privileged = &privileged
+	return nil
+}
+
+// syncKVMCondition reports whether any node can run the VM with KVM.
+// Returns true if status was modified.
+func (ctrl *VirtualMachineController) syncKVMCondition(vm *vmapi.VirtualMachine) bool {
+	if vm.Spec.AllowEmulation {
+		return removeCondition(vm, vmapi.VirtualMachineHardwareVirtualization)
+	}
+
+	resourceNodes, labelNodes, err := ctrl.kvmNodes()
+	if err != nil {
+		glog.V(2).Infof("error listing nodes: %v", err)
+		return false
+	}
+	condition := vmapi.VirtualMachineCondition{
+		Type:   vmapi.VirtualMachineHardwareVirtualization,
+		Status: corev1.ConditionTrue,
+		Reason: "KVMAvailable",
+	}
+	if resourceNodes == 0 && labelNodes == 0 {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "NoKVMNodes"
+		condition.Message = fmt.Sprintf("No node provides %s: deploy a device plugin advertising %s or set allow_emulation",
+			kvmDevice, ctrl.kvmResource)
+		if ctrl.usesKVMNodeLabel() {
+			condition.Message = fmt.Sprintf("No node provides %s: deploy a device plugin advertising %s, label nodes with %s=true, or set allow_emulation",
+				kvmDevice, ctrl.kvmResource, ctrl.kvmNodeLabel)
+		}
+	}
+	return setCondition(vm, condition)
+}
+
+// enqueueVMsWithoutKVM queues the VMs lacking KVM nodes when a node
+// provides KVM
+func (ctrl *VirtualMachineController) enqueueVMsWithoutKVM(obj interface{}) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return
+	}
+	quantity, ok := node.Status.Allocatable[ctrl.kvmResource]
+	hasResource := ctrl.kvmResource != "" && ok && !quantity.IsZero()
+	hasLabel := ctrl.usesKVMNodeLabel() && node.Labels[ctrl.kvmNodeLabel] == "true"
+	if !hasResource && !hasLabel {
+		return
+	}
+	vms, err := ctrl.vmLister.List(labels.Everything())
+	if err != nil {
+		glog.V(2).Infof("error listing vms: %v", err)
+		return
+	}
+	for _, vm := range vms {
+		for _, cond := range vm.Status.Conditions {
+			if cond.Type == vmapi.VirtualMachineHardwareVirtualization && cond.Status == corev1.ConditionFalse {
+				ctrl.enqueueWork(ctrl.vmQueue, vm)
+			}
+		}
+	}
+}
+
+// removeCondition removes the condition of the given type. Returns true if
+// status was modified.
+func removeCondition(vm *vmapi.VirtualMachine, conditionType vmapi.VirtualMachineConditionType) bool {
+	for i, cond := range vm.Status.Conditions {
+		if cond.Type == conditionType {
+			vm.Status.Conditions = append(vm.Status.Conditions[:i], vm.Status.Conditions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
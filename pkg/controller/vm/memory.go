@@ -0,0 +1,46 @@
+package vm
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func validateMemoryRequest(spec *vmapi.VirtualMachineSpec) error {
+	if spec.MemoryRequestMB < 0 || spec.MemoryRequestMB > spec.MemoryMB {
+		return fmt.Errorf("memory_request_mb must be between 0 and memory_mb")
+	}
+	return nil
+}
+
+// memoryRequestMB returns the guest memory requested for the VM's pod.
+// Memory backed by hugepages or pinned to dedicated CPUs isn't overcommitted.
+func (ctrl *VirtualMachineController) memoryRequestMB(spec *vmapi.VirtualMachineSpec) int32 {
+	switch {
+	case spec.DedicatedCPUPlacement || spec.Hugepages != "":
+		return spec.MemoryMB
+	case spec.MemoryRequestMB != 0:
+		return spec.MemoryRequestMB
+	default:
+		return int32(int64(spec.MemoryMB) * 100 / int64(ctrl.memoryOvercommit))
+	}
+}
+
+// setResources requests CPU and memory for the VM's pod. There is no limit,
+// as QEMU's own usage varies and the guest may use up to memory_mb.
+func (ctrl *VirtualMachineController) setResources(vm *vmapi.VirtualMachine, pod *corev1.Pod) {
+	requestMB := ctrl.memoryRequestMB(&vm.Spec)
+	container := &pod.Spec.Containers[0]
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = corev1.ResourceList{}
+	}
+	container.Resources.Requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(vm.Spec.CpuMillis), resource.DecimalSI)
+	container.Resources.Requests[corev1.ResourceMemory] = resource.MustParse(fmt.Sprintf("%dMi", requestMB+launcherOverheadMB))
+	// Guests are ballooned no lower than their request
+	pod.Annotations[ranchervm.GroupName+"/memory_request_mb"] = strconv.Itoa(int(requestMB))
+}
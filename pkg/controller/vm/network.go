@@ -0,0 +1,259 @@
+package vm
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+const (
+	// Multus reads secondary networks from this pod annotation...
+	multusNetworksAnnotation = "k8s.v1.cni.cncf.io/networks"
+	// ...and reports the attached interfaces back through this one
+	multusNetworksStatusAnnotation = "k8s.v1.cni.cncf.io/networks-status"
+
+	defaultInterfaceName = "default"
+)
+
+// multusNetworkStatus is an entry of the Multus networks-status annotation
+type multusNetworkStatus struct {
+	Name      string   `json:"name"`
+	Interface string   `json:"interface"`
+	IPs       []string `json:"ips"`
+	Mac       string   `json:"mac"`
+}
+
+// vmInterfaces returns the VM's network interfaces, defaulting to a single
+// masquerade interface on the pod network.
+func vmInterfaces(vm *vmapi.VirtualMachine) []vmapi.NetworkInterface {
+	if len(vm.Spec.Interfaces) == 0 {
+		return []vmapi.NetworkInterface{
+			vmapi.NetworkInterface{
+				Name:    defaultInterfaceName,
+				Binding: vmapi.InterfaceBindingMasquerade,
+			},
+		}
+	}
+	return vm.Spec.Interfaces
+}
+
+func validateInterfaces(ifaces []vmapi.NetworkInterface) error {
+	names := map[string]bool{}
+	macs := map[string]string{}
+	podNetwork := false
+	for _, iface := range ifaces {
+		if iface.Name == "" {
+			return fmt.Errorf("interface name is required")
+		}
+		if names[iface.Name] {
+			return fmt.Errorf("duplicate interface name %q", iface.Name)
+		}
+		names[iface.Name] = true
+
+		switch iface.Binding {
+		case vmapi.InterfaceBindingBridge:
+		case vmapi.InterfaceBindingMasquerade:
+			if iface.Network != "" {
+				return fmt.Errorf("interface %q: masquerade binding is only supported on the pod network", iface.Name)
+			}
+		default:
+			return fmt.Errorf("interface %q: unknown binding %q", iface.Name, iface.Binding)
+		}
+
+		if iface.Network == "" {
+			if podNetwork {
+				return fmt.Errorf("interface %q: only one interface may use the pod network", iface.Name)
+			}
+			podNetwork = true
+		}
+
+		if iface.MACAddress != "" {
+			mac, err := net.ParseMAC(iface.MACAddress)
+			if err != nil {
+				return fmt.Errorf("interface %q: %v", iface.Name, err)
+			}
+			if other, ok := macs[mac.String()]; ok {
+				return fmt.Errorf("interface %q: MAC address %s is already used by interface %q", iface.Name, iface.MACAddress, other)
+			}
+			macs[mac.String()] = iface.Name
+		}
+	}
+	return nil
+}
+
+// normalizeMAC returns the canonical form of a MAC address so that
+// differently written addresses compare equal
+func normalizeMAC(mac string) string {
+	if hw, err := net.ParseMAC(mac); err == nil {
+		return hw.String()
+	}
+	return strings.ToLower(mac)
+}
+
+// generateMAC returns a random unicast, locally administered MAC address in
+// the range QEMU uses for guest NICs.
+func generateMAC() (string, error) {
+	buf := make([]byte, 3)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("52:54:00:%02x:%02x:%02x", buf[0], buf[1], buf[2]), nil
+}
+
+// assignMACs fills in a MAC address for every interface in status, keeping
+// addresses previously assigned by the controller stable unless they clash
+// with another interface. Returns true if status was modified.
+func assignMACs(vm *vmapi.VirtualMachine) (bool, error) {
+	assigned := map[string]string{}
+	addrs := map[string]string{}
+	for _, status := range vm.Status.Interfaces {
+		assigned[status.Name] = status.MACAddress
+		addrs[status.Name] = status.IP
+	}
+
+	ifaces := vmInterfaces(vm)
+	// Addresses set in the spec take precedence over assigned ones
+	used := map[string]bool{}
+	for _, iface := range ifaces {
+		if iface.MACAddress != "" {
+			used[normalizeMAC(iface.MACAddress)] = true
+		}
+	}
+
+	changed := len(ifaces) != len(vm.Status.Interfaces)
+	statuses := []vmapi.NetworkInterfaceStatus{}
+	for i, iface := range ifaces {
+		mac := iface.MACAddress
+		if mac == "" {
+			mac = assigned[iface.Name]
+			if mac != "" && used[normalizeMAC(mac)] {
+				mac = ""
+			}
+			for mac == "" || used[normalizeMAC(mac)] {
+				var err error
+				if mac, err = generateMAC(); err != nil {
+					return false, err
+				}
+			}
+			used[normalizeMAC(mac)] = true
+		}
+		// Status is kept in spec order
+		if !changed && (vm.Status.Interfaces[i].Name != iface.Name || vm.Status.Interfaces[i].MACAddress != mac) {
+			changed = true
+		}
+		statuses = append(statuses, vmapi.NetworkInterfaceStatus{
+			Name:       iface.Name,
+			MACAddress: mac,
+			IP:         addrs[iface.Name],
+		})
+	}
+
+	if changed {
+		vm.Status.Interfaces = statuses
+	}
+	return changed, nil
+}
+
+// resolvedInterfaces returns the VM's interfaces with the MAC addresses
+// recorded in status filled in.
+func resolvedInterfaces(vm *vmapi.VirtualMachine) []vmapi.NetworkInterface {
+	macs := map[string]string{}
+	for _, status := range vm.Status.Interfaces {
+		macs[status.Name] = status.MACAddress
+	}
+	ifaces := []vmapi.NetworkInterface{}
+	for _, iface := range vmInterfaces(vm) {
+		if iface.MACAddress == "" {
+			iface.MACAddress = macs[iface.Name]
+		}
+		ifaces = append(ifaces, iface)
+	}
+	return ifaces
+}
+
+// setNetworkAnnotations describes the VM's interfaces and ports to the
+// launcher and requests secondary networks from Multus.
+func setNetworkAnnotations(vm *vmapi.VirtualMachine, pod *corev1.Pod) error {
+	ifaces := resolvedInterfaces(vm)
+	data, err := json.Marshal(ifaces)
+	if err != nil {
+		return err
+	}
+	pod.Annotations[ranchervm.GroupName+"/interfaces"] = string(data)
+
+	networks := []string{}
+	for _, iface := range ifaces {
+		if iface.Network != "" {
+			networks = append(networks, iface.Network)
+		}
+	}
+	if len(networks) > 0 {
+		pod.Annotations[multusNetworksAnnotation] = strings.Join(networks, ",")
+	}
+
+	// Masqueraded guests need exposed ports forwarded by the launcher
+	if len(vm.Spec.Ports) > 0 {
+		data, err := json.Marshal(vm.Spec.Ports)
+		if err != nil {
+			return err
+		}
+		pod.Annotations[ranchervm.GroupName+"/ports"] = string(data)
+	}
+	return nil
+}
+
+// syncInterfaceStatus copies guest addresses observed on the pod, which may
+// be nil, into status. Returns true if status was modified.
+func syncInterfaceStatus(vm *vmapi.VirtualMachine, pod *corev1.Pod) bool {
+	if pod == nil {
+		pod = &corev1.Pod{}
+	}
+
+	// Several interfaces may attach to the same network, so addresses are
+	// matched by the pod interface Multus created for each
+	secondary := map[string]string{}
+	if data, ok := pod.Annotations[multusNetworksStatusAnnotation]; ok {
+		var networks []multusNetworkStatus
+		if err := json.Unmarshal([]byte(data), &networks); err != nil {
+			glog.V(2).Infof("error parsing network status of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+		for _, network := range networks {
+			if len(network.IPs) > 0 {
+				secondary[network.Interface] = network.IPs[0]
+			}
+		}
+	}
+
+	ifaces := resolvedInterfaces(vm)
+	ips := map[string]string{}
+	for i, iface := range ifaces {
+		ip := pod.Status.PodIP
+		if iface.Network != "" {
+			ip = secondary[launcher.PodInterfaceName(ifaces, i)]
+		}
+		ips[iface.Name] = ip
+	}
+
+	changed := false
+	for i := range vm.Status.Interfaces {
+		status := &vm.Status.Interfaces[i]
+		ip, ok := ips[status.Name]
+		if !ok {
+			continue
+		}
+		if status.IP != ip {
+			status.IP = ip
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,242 @@
+package vm
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func TestValidateInterfaces(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifaces  []vmapi.NetworkInterface
+		wantErr string
+	}{
+		{
+			name: "default",
+			ifaces: []vmapi.NetworkInterface{
+				{Name: "default", Binding: vmapi.InterfaceBindingMasquerade},
+			},
+		},
+		{
+			name: "bridged secondary networks",
+			ifaces: []vmapi.NetworkInterface{
+				{Name: "default", Binding: vmapi.InterfaceBindingBridge, MACAddress: "52:54:00:00:00:01"},
+				{Name: "lan", Binding: vmapi.InterfaceBindingBridge, Network: "lan"},
+				{Name: "lan2", Binding: vmapi.InterfaceBindingBridge, Network: "lan"},
+			},
+		},
+		{
+			name:    "missing name",
+			ifaces:  []vmapi.NetworkInterface{{Binding: vmapi.InterfaceBindingBridge}},
+			wantErr: "name is required",
+		},
+		{
+			name: "duplicate name",
+			ifaces: []vmapi.NetworkInterface{
+				{Name: "a", Binding: vmapi.InterfaceBindingBridge},
+				{Name: "a", Binding: vmapi.InterfaceBindingBridge, Network: "lan"},
+			},
+			wantErr: "duplicate interface name",
+		},
+		{
+			name:    "unknown binding",
+			ifaces:  []vmapi.NetworkInterface{{Name: "a", Binding: "sriov"}},
+			wantErr: "unknown binding",
+		},
+		{
+			name:    "masquerade on secondary network",
+			ifaces:  []vmapi.NetworkInterface{{Name: "a", Binding: vmapi.InterfaceBindingMasquerade, Network: "lan"}},
+			wantErr: "only supported on the pod network",
+		},
+		{
+			name: "two pod network interfaces",
+			ifaces: []vmapi.NetworkInterface{
+				{Name: "a", Binding: vmapi.InterfaceBindingMasquerade},
+				{Name: "b", Binding: vmapi.InterfaceBindingBridge},
+			},
+			wantErr: "only one interface may use the pod network",
+		},
+		{
+			name:    "invalid MAC",
+			ifaces:  []vmapi.NetworkInterface{{Name: "a", Binding: vmapi.InterfaceBindingBridge, MACAddress: "52:54:00"}},
+			wantErr: "invalid MAC",
+		},
+		{
+			name: "duplicate MAC",
+			ifaces: []vmapi.NetworkInterface{
+				{Name: "a", Binding: vmapi.InterfaceBindingBridge, MACAddress: "52:54:00:00:00:0a"},
+				{Name: "b", Binding: vmapi.InterfaceBindingBridge, Network: "lan", MACAddress: "52-54-00-00-00-0A"},
+			},
+			wantErr: "already used by interface \"a\"",
+		},
+	}
+
+	for _, test := range tests {
+		err := validateInterfaces(test.ifaces)
+		switch {
+		case test.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		case test.wantErr != "" && err == nil:
+			t.Errorf("%s: expected error containing %q", test.name, test.wantErr)
+		case test.wantErr != "" && !strings.Contains(err.Error(), test.wantErr):
+			t.Errorf("%s: got error %q, want %q", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestAssignMACs(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifaces      []vmapi.NetworkInterface
+		status      []vmapi.NetworkInterfaceStatus
+		wantChanged bool
+		// wantMACs are the expected addresses, empty for generated ones
+		wantMACs []string
+	}{
+		{
+			name:        "default interface",
+			wantChanged: true,
+			wantMACs:    []string{""},
+		},
+		{
+			name: "spec address",
+			ifaces: []vmapi.NetworkInterface{
+				{Name: "a", Binding: vmapi.InterfaceBindingBridge, MACAddress: "52:54:00:00:00:01"},
+			},
+			wantChanged: true,
+			wantMACs:    []string{"52:54:00:00:00:01"},
+		},
+		{
+			name: "assigned addresses are stable",
+			ifaces: []vmapi.NetworkInterface{
+				{Name: "a", Binding: vmapi.InterfaceBindingBridge},
+				{Name: "b", Binding: vmapi.InterfaceBindingBridge, Network: "lan"},
+			},
+			status: []vmapi.NetworkInterfaceStatus{
+				{Name: "a", MACAddress: "52:54:00:00:00:01", IP: "10.42.0.5"},
+				{Name: "b", MACAddress: "52:54:00:00:00:02"},
+			},
+			wantMACs: []string{"52:54:00:00:00:01", "52:54:00:00:00:02"},
+		},
+		{
+			name: "interface added",
+			ifaces: []vmapi.NetworkInterface{
+				{Name: "a", Binding: vmapi.InterfaceBindingBridge},
+				{Name: "b", Binding: vmapi.InterfaceBindingBridge, Network: "lan"},
+			},
+			status: []vmapi.NetworkInterfaceStatus{
+				{Name: "a", MACAddress: "52:54:00:00:00:01"},
+			},
+			wantChanged: true,
+			wantMACs:    []string{"52:54:00:00:00:01", ""},
+		},
+		{
+			name: "spec address clashing with an assigned one",
+			ifaces: []vmapi.NetworkInterface{
+				{Name: "a", Binding: vmapi.InterfaceBindingBridge},
+				{Name: "b", Binding: vmapi.InterfaceBindingBridge, Network: "lan", MACAddress: "52:54:00:00:00:01"},
+			},
+			status: []vmapi.NetworkInterfaceStatus{
+				{Name: "a", MACAddress: "52:54:00:00:00:01"},
+				{Name: "b", MACAddress: "52:54:00:00:00:02"},
+			},
+			wantChanged: true,
+			wantMACs:    []string{"", "52:54:00:00:00:01"},
+		},
+	}
+
+	for _, test := range tests {
+		vm := &vmapi.VirtualMachine{
+			Spec:   vmapi.VirtualMachineSpec{Interfaces: test.ifaces},
+			Status: vmapi.VirtualMachineStatus{Interfaces: test.status},
+		}
+		changed, err := assignMACs(vm)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if changed != test.wantChanged {
+			t.Errorf("%s: got changed %v, want %v", test.name, changed, test.wantChanged)
+		}
+		if len(vm.Status.Interfaces) != len(test.wantMACs) {
+			t.Errorf("%s: got %d interfaces, want %d", test.name, len(vm.Status.Interfaces), len(test.wantMACs))
+			continue
+		}
+		seen := map[string]bool{}
+		for i, status := range vm.Status.Interfaces {
+			if want := test.wantMACs[i]; want != "" && status.MACAddress != want {
+				t.Errorf("%s: interface %s got MAC %s, want %s", test.name, status.Name, status.MACAddress, want)
+			}
+			if seen[status.MACAddress] {
+				t.Errorf("%s: MAC %s assigned twice", test.name, status.MACAddress)
+			}
+			seen[status.MACAddress] = true
+		}
+		if err := validateInterfaces(resolvedInterfaces(vm)); err != nil {
+			t.Errorf("%s: resolved interfaces are invalid: %v", test.name, err)
+		}
+	}
+}
+
+func TestSyncInterfaceStatus(t *testing.T) {
+	vm := &vmapi.VirtualMachine{
+		Spec: vmapi.VirtualMachineSpec{
+			Interfaces: []vmapi.NetworkInterface{
+				{Name: "default", Binding: vmapi.InterfaceBindingMasquerade},
+				{Name: "lan-a", Binding: vmapi.InterfaceBindingBridge, Network: "infra/lan"},
+				{Name: "lan-b", Binding: vmapi.InterfaceBindingBridge, Network: "infra/lan"},
+			},
+		},
+		Status: vmapi.VirtualMachineStatus{
+			Interfaces: []vmapi.NetworkInterfaceStatus{
+				{Name: "default", MACAddress: "52:54:00:00:00:01"},
+				{Name: "lan-a", MACAddress: "52:54:00:00:00:02"},
+				{Name: "lan-b", MACAddress: "52:54:00:00:00:03"},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				multusNetworksStatusAnnotation: `[
+					{"name":"cbr0","interface":"eth0","ips":["10.42.0.5"]},
+					{"name":"lan","interface":"net1","ips":["192.168.0.10"]},
+					{"name":"lan","interface":"net2","ips":["192.168.0.11"]}
+				]`,
+			},
+		},
+		Status: corev1.PodStatus{PodIP: "10.42.0.5"},
+	}
+
+	if !syncInterfaceStatus(vm, pod) {
+		t.Errorf("expected status to change")
+	}
+	want := map[string]string{
+		"default": "10.42.0.5",
+		"lan-a":   "192.168.0.10",
+		"lan-b":   "192.168.0.11",
+	}
+	for _, status := range vm.Status.Interfaces {
+		if status.IP != want[status.Name] {
+			t.Errorf("interface %s: got IP %q, want %q", status.Name, status.IP, want[status.Name])
+		}
+	}
+	if syncInterfaceStatus(vm, pod) {
+		t.Errorf("expected no change on resync")
+	}
+
+	// Addresses are cleared once the pod is gone
+	if !syncInterfaceStatus(vm, nil) {
+		t.Errorf("expected status to change without a pod")
+	}
+	for _, status := range vm.Status.Interfaces {
+		if status.IP != "" {
+			t.Errorf("interface %s: got IP %q without a pod", status.Name, status.IP)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package vm
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+// launcherOverheadMB is the memory QEMU and the launcher need on top of
+// guest memory
+const launcherOverheadMB = 256
+
+func validatePlacement(spec *vmapi.VirtualMachineSpec) error {
+	if spec.DedicatedCPUPlacement && spec.CpuMillis%1000 != 0 {
+		return fmt.Errorf("cpu_milli must be a multiple of 1000 with dedicated_cpu_placement")
+	}
+	if spec.NUMAPassthrough && !spec.DedicatedCPUPlacement {
+		return fmt.Errorf("numa_passthrough requires dedicated_cpu_placement")
+	}
+	if spec.Hugepages != "" {
+		pageMB, ok := launcher.HugepageSizesMB[spec.Hugepages]
+		if !ok {
+			return fmt.Errorf("unsupported hugepage size %q, use 2Mi or 1Gi", spec.Hugepages)
+		}
+		if int(spec.MemoryMB)%pageMB != 0 {
+			return fmt.Errorf("memory_mb must be a multiple of %d with %s hugepages", pageMB, spec.Hugepages)
+		}
+	}
+	return nil
+}
+
+// setPlacement sizes the pod of a VM with dedicated CPUs or hugepages,
+// overriding the requests of setResources. Requests equal limits, so that
+// pods with dedicated CPUs get the Guaranteed QoS class the CPU manager
+// requires to hand out exclusive CPUs.
+func setPlacement(vm *vmapi.VirtualMachine, pod *corev1.Pod) {
+	spec := &vm.Spec
+	if !spec.DedicatedCPUPlacement && spec.Hugepages == "" {
+		return
+	}
+	container := &pod.Spec.Containers[0]
+	resources := corev1.ResourceList{}
+
+	memoryMB := spec.MemoryMB + launcherOverheadMB
+	if spec.Hugepages != "" {
+		// Guest memory comes out of hugepages rather than the pod's memory
+		hugepages := corev1.ResourceName(corev1.ResourceHugePagesPrefix + spec.Hugepages)
+		resources[hugepages] = resource.MustParse(fmt.Sprintf("%dMi", spec.MemoryMB))
+		memoryMB = launcherOverheadMB
+
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: "hugepages",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium: corev1.StorageMediumHugePages,
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "hugepages",
+			MountPath: launcher.HugepagesDir,
+		})
+		pod.Annotations[ranchervm.GroupName+"/hugepages"] = spec.Hugepages
+	}
+	resources[corev1.ResourceMemory] = resource.MustParse(fmt.Sprintf("%dMi", memoryMB))
+
+	if spec.DedicatedCPUPlacement {
+		resources[corev1.ResourceCPU] = *resource.NewQuantity(int64(spec.CpuMillis/1000), resource.DecimalSI)
+		pod.Annotations[ranchervm.GroupName+"/dedicated_cpu_placement"] = "true"
+		if spec.NUMAPassthrough {
+			pod.Annotations[ranchervm.GroupName+"/numa_passthrough"] = "true"
+		}
+	}
+
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = corev1.ResourceList{}
+	}
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = corev1.ResourceList{}
+	}
+	for name, quantity := range resources {
+		container.Resources.Requests[name] = quantity
+		container.Resources.Limits[name] = quantity.DeepCopy()
+	}
+}
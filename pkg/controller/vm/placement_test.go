@@ -0,0 +1,90 @@
+package vm
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func TestPodResources(t *testing.T) {
+	ctrl := &VirtualMachineController{memoryOvercommit: 200}
+
+	tests := []struct {
+		name         string
+		spec         vmapi.VirtualMachineSpec
+		wantRequests map[corev1.ResourceName]string
+		wantLimits   map[corev1.ResourceName]string
+	}{
+		{
+			name: "shared cpus",
+			spec: vmapi.VirtualMachineSpec{CpuMillis: 1500, MemoryMB: 2048},
+			wantRequests: map[corev1.ResourceName]string{
+				corev1.ResourceCPU:    "1500m",
+				corev1.ResourceMemory: "1280Mi",
+			},
+			wantLimits: map[corev1.ResourceName]string{
+				"devices.example.com/kvm": "1",
+			},
+		},
+		{
+			name: "dedicated cpus",
+			spec: vmapi.VirtualMachineSpec{CpuMillis: 2000, MemoryMB: 2048, DedicatedCPUPlacement: true},
+			wantRequests: map[corev1.ResourceName]string{
+				corev1.ResourceCPU:    "2",
+				corev1.ResourceMemory: "2304Mi",
+			},
+			wantLimits: map[corev1.ResourceName]string{
+				corev1.ResourceCPU:        "2",
+				corev1.ResourceMemory:     "2304Mi",
+				"devices.example.com/kvm": "1",
+			},
+		},
+		{
+			name: "hugepages",
+			spec: vmapi.VirtualMachineSpec{CpuMillis: 1000, MemoryMB: 2048, Hugepages: "2Mi"},
+			wantRequests: map[corev1.ResourceName]string{
+				corev1.ResourceCPU:    "1",
+				corev1.ResourceMemory: "256Mi",
+				"hugepages-2Mi":       "2Gi",
+			},
+			wantLimits: map[corev1.ResourceName]string{
+				corev1.ResourceMemory:     "256Mi",
+				"hugepages-2Mi":           "2Gi",
+				"devices.example.com/kvm": "1",
+			},
+		},
+	}
+
+	check := func(name, kind string, got corev1.ResourceList, want map[corev1.ResourceName]string) {
+		if len(got) != len(want) {
+			t.Errorf("%s: got %s %v, want %v", name, kind, got, want)
+			return
+		}
+		for resourceName, quantity := range want {
+			if q, ok := got[resourceName]; !ok || q.Cmp(resource.MustParse(quantity)) != 0 {
+				t.Errorf("%s: got %s %s of %v, want %s", name, kind, resourceName, got[resourceName], quantity)
+			}
+		}
+	}
+	for _, test := range tests {
+		vm := &vmapi.VirtualMachine{Spec: test.spec}
+		pod := &corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Resources: corev1.ResourceRequirements{
+						// Resources set before placement must survive it
+						Limits: corev1.ResourceList{"devices.example.com/kvm": resource.MustParse("1")},
+					},
+				}},
+			},
+		}
+		pod.Annotations = map[string]string{}
+		ctrl.setResources(vm, pod)
+		setPlacement(vm, pod)
+		check(test.name, "requests", pod.Spec.Containers[0].Resources.Requests, test.wantRequests)
+		check(test.name, "limits", pod.Spec.Containers[0].Resources.Limits, test.wantLimits)
+	}
+}
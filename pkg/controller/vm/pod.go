@@ -0,0 +1,221 @@
+package vm
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+const launcherContainer = "vm-in-a-pod"
+
+// activePodName returns the name of the pod running the VM. It is the VM's
+// name unless the VM was migrated.
+func activePodName(vm *vmapi.VirtualMachine) string {
+	if vm.Status.PodName != "" {
+		return vm.Status.PodName
+	}
+	return vm.Name
+}
+
+const (
+	// defaultTerminationGracePeriod is how long guests are given to power off
+	defaultTerminationGracePeriod = 120
+
+	// podDeletionInterval is how often the controller checks whether the
+	// previous pod of a starting VM is gone
+	podDeletionInterval = 5 * time.Second
+)
+
+// newLauncherPod returns the pod that runs the VM
+func (ctrl *VirtualMachineController) newLauncherPod(vm *vmapi.VirtualMachine) (*corev1.Pod, error) {
+	// The grace period is carried by the pod, so that it also applies when
+	// the pod is deleted along with its VM
+	gracePeriod := int64(defaultTerminationGracePeriod)
+	if vm.Spec.TerminationGracePeriodSeconds != nil {
+		gracePeriod = *vm.Spec.TerminationGracePeriodSeconds
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vm.Name,
+			Namespace: vm.Namespace,
+			Labels: map[string]string{
+				"type":                "ranchervm",
+				ranchervm.LabelVMName: vm.Name,
+			},
+			Annotations: map[string]string{
+				ranchervm.GroupName + "/vm_namespace": vm.Namespace,
+				ranchervm.GroupName + "/vm_name":      vm.Name,
+				ranchervm.GroupName + "/cpu_milli":    strconv.Itoa(int(vm.Spec.CpuMillis)),
+				ranchervm.GroupName + "/memory_mb":    strconv.Itoa(int(vm.Spec.MemoryMB)),
+				// The launcher exports guest statistics
+				"prometheus.io/scrape": "true",
+				"prometheus.io/port":   strconv.Itoa(launcher.MetricsPort),
+				"prometheus.io/path":   "/metrics",
+			},
+		},
+		Spec: corev1.PodSpec{
+			TerminationGracePeriodSeconds: &gracePeriod,
+			Containers: []corev1.Container{
+				corev1.Container{
+					Name:  launcherContainer,
+					Image: ctrl.launcherImage,
+					SecurityContext: &corev1.SecurityContext{
+						// Required to wire guest NICs to pod interfaces and
+						// create /dev/net/tun for them
+						Capabilities: &corev1.Capabilities{
+							Add: []corev1.Capability{"NET_ADMIN", "MKNOD"},
+						},
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						corev1.VolumeMount{
+							Name:      "podinfo",
+							MountPath: launcher.PodInfoDir,
+						},
+					},
+					// Power the guest off rather than have QEMU killed
+					Lifecycle: &corev1.Lifecycle{
+						PreStop: &corev1.Handler{
+							Exec: &corev1.ExecAction{
+								Command: launcher.ShutdownCommand,
+							},
+						},
+					},
+					TerminationMessagePath: launcher.TerminationLog,
+					Ports: []corev1.ContainerPort{
+						corev1.ContainerPort{
+							Name:          "metrics",
+							ContainerPort: launcher.MetricsPort,
+						},
+					},
+				},
+			},
+			// The launcher reads its config from the pod annotations
+			Volumes: []corev1.Volume{
+				corev1.Volume{
+					Name: "podinfo",
+					VolumeSource: corev1.VolumeSource{
+						DownwardAPI: &corev1.DownwardAPIVolumeSource{
+							Items: []corev1.DownwardAPIVolumeFile{
+								corev1.DownwardAPIVolumeFile{
+									Path: "annotations",
+									FieldRef: &corev1.ObjectFieldSelector{
+										FieldPath: "metadata.annotations",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := setNetworkAnnotations(vm, pod); err != nil {
+		return nil, err
+	}
+	if err := setDiskVolumes(vm, pod); err != nil {
+		return nil, err
+	}
+	if err := setCloudInitAnnotations(vm, pod); err != nil {
+		return nil, err
+	}
+	setScheduling(vm, pod)
+	ctrl.setResources(vm, pod)
+	setPlacement(vm, pod)
+	setHotplug(vm, pod)
+	if err := ctrl.setKVM(vm, pod); err != nil {
+		return nil, err
+	}
+	return pod, nil
+}
+
+func (ctrl *VirtualMachineController) createPod(vm *vmapi.VirtualMachine) {
+	pod, err := ctrl.newLauncherPod(vm)
+	if err != nil {
+		glog.V(2).Infof("error generating pod for vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		ctrl.recorder.Eventf(vm, corev1.EventTypeWarning, "FailedCreate", "Error generating pod: %v", err)
+		return
+	}
+
+	pod, err = ctrl.kubeClient.CoreV1().Pods(vm.Namespace).Create(pod)
+	if apierrors.IsAlreadyExists(err) {
+		// The informer hasn't caught up with a pod that is still
+		// terminating, such as one shut down to leave a cordoned node. The
+		// VM must not start until it's gone.
+		glog.V(2).Infof("pod %s/%s still exists, waiting for it to be deleted", vm.Namespace, vm.Name)
+		ctrl.vmQueue.AddAfter(vm.Namespace+"/"+vm.Name, podDeletionInterval)
+		return
+	}
+	if err != nil {
+		glog.V(2).Infof("Error creating pod %s/%s: %v", vm.Namespace, vm.Name, err)
+		ctrl.recorder.Eventf(vm, corev1.EventTypeWarning, "FailedCreate", "Error creating pod: %v", err)
+		return
+	}
+	ctrl.recorder.Eventf(vm, corev1.EventTypeNormal, "Starting", "Created pod %s", pod.Name)
+}
+
+// deletePod deletes the named pod, returning false on failure
+func (ctrl *VirtualMachineController) deletePod(ns, name string) bool {
+	err := ctrl.kubeClient.CoreV1().Pods(ns).Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		glog.V(2).Infof("error deleting pod %s/%s: %v", ns, name, err)
+		return false
+	}
+	return true
+}
+
+// podPhase maps the state of a VM's pod onto a VM phase
+func podPhase(vm *vmapi.VirtualMachine, pod *corev1.Pod) vmapi.VirtualMachinePhase {
+	if pod == nil || pod.DeletionTimestamp != nil {
+		if vm.Spec.Stopped {
+			return vmapi.VirtualMachineStopped
+		}
+		return vmapi.VirtualMachinePending
+	}
+	switch pod.Status.Phase {
+	case corev1.PodRunning:
+		return vmapi.VirtualMachineRunning
+	case corev1.PodSucceeded:
+		return vmapi.VirtualMachineStopped
+	case corev1.PodFailed:
+		return vmapi.VirtualMachineFailed
+	default:
+		return vmapi.VirtualMachinePending
+	}
+}
+
+// syncStatus copies the observed state of the VM's pod, which may be nil,
+// into status. Returns true if status was modified.
+func syncStatus(vm *vmapi.VirtualMachine, pod *corev1.Pod) bool {
+	changed := syncInterfaceStatus(vm, pod)
+	if syncShutdownCondition(vm, pod) {
+		changed = true
+	}
+	if syncScheduledCondition(vm, pod) {
+		changed = true
+	}
+
+	phase := podPhase(vm, pod)
+	running := phase == vmapi.VirtualMachineRunning
+	nodeName, podName := "", ""
+	if pod != nil {
+		nodeName = pod.Spec.NodeName
+		podName = pod.Name
+	}
+	if vm.Status.Phase != phase || vm.Status.Running != running || vm.Status.NodeName != nodeName || vm.Status.PodName != podName {
+		vm.Status.Phase = phase
+		vm.Status.Running = running
+		vm.Status.NodeName = nodeName
+		vm.Status.PodName = podName
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,170 @@
+package vm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/controller/quota"
+)
+
+// quotaRetryInterval is how soon a VM is synced again after its quotas
+// couldn't be checked
+const quotaRetryInterval = 5 * time.Second
+
+// lockQuota serializes the quota checks of a namespace and returns the
+// function releasing them. Workers must hold it from checking a quota until
+// what they checked is created, or concurrent syncs could both fit.
+func (ctrl *VirtualMachineController) lockQuota(ns string) func() {
+	ctrl.quotaLocksLock.Lock()
+	lock, ok := ctrl.quotaLocks[ns]
+	if !ok {
+		lock = &sync.Mutex{}
+		ctrl.quotaLocks[ns] = lock
+	}
+	ctrl.quotaLocksLock.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// quotaState is what the quotas of a namespace are checked against
+type quotaState struct {
+	quotas    []*vmapi.VirtualMachineQuota
+	vms       []*vmapi.VirtualMachine
+	evaluator *quota.Evaluator
+}
+
+// readQuotaState reads the quotas, VMs, pods and claims of a namespace from
+// the API server, since the informers lag behind VMs other workers just
+// started. It returns nil if the namespace has no quotas.
+func (ctrl *VirtualMachineController) readQuotaState(ns string) (*quotaState, error) {
+	// Namespaces without quotas are common, and spared the reads
+	cached, err := ctrl.quotaLister.VirtualMachineQuotas(ns).List(labels.Everything())
+	if err != nil || len(cached) == 0 {
+		return nil, err
+	}
+
+	quotaList, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachineQuotas(ns).List(metav1.ListOptions{})
+	if err != nil || len(quotaList.Items) == 0 {
+		return nil, err
+	}
+	vmList, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachines(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	podList, err := ctrl.kubeClient.CoreV1().Pods(ns).List(metav1.ListOptions{LabelSelector: "type=ranchervm"})
+	if err != nil {
+		return nil, err
+	}
+	pvcList, err := ctrl.kubeClient.CoreV1().PersistentVolumeClaims(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	state := &quotaState{}
+	for i := range quotaList.Items {
+		state.quotas = append(state.quotas, &quotaList.Items[i])
+	}
+	// VMs whose pod was just created haven't recorded it yet, and count as
+	// started all the same
+	podNames := map[string]string{}
+	for _, pod := range podList.Items {
+		if name, ok := pod.Labels[ranchervm.LabelVMName]; ok {
+			podNames[name] = pod.Name
+		}
+	}
+	for i := range vmList.Items {
+		vm := &vmList.Items[i]
+		if podName, ok := podNames[vm.Name]; ok && vm.Status.PodName == "" {
+			vm.Status.PodName = podName
+		}
+		state.vms = append(state.vms, vm)
+	}
+	claims := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for i := range pvcList.Items {
+		claims.Add(&pvcList.Items[i])
+	}
+	state.evaluator = quota.NewEvaluator(corelisters.NewPersistentVolumeClaimLister(claims), ctrl.instanceTypeLister)
+	return state, nil
+}
+
+// checkDiskQuota checks that the disks of the VM can grow by growthMB within
+// the quotas of its namespace. Callers must hold lockQuota until the claim
+// is expanded.
+func (ctrl *VirtualMachineController) checkDiskQuota(vm *vmapi.VirtualMachine, growthMB int64) error {
+	state, err := ctrl.readQuotaState(vm.Namespace)
+	if err != nil || state == nil {
+		return err
+	}
+	return state.evaluator.FitsDiskGrowth(state.vms, state.quotas, growthMB)
+}
+
+// checkQuota checks that the VM fits within the quotas of its namespace
+// with its spec's size, and sets its ExceededQuota condition. Callers must
+// hold lockQuota until what they checked is created. Returns whether the VM
+// fits, and true if status was modified.
+func (ctrl *VirtualMachineController) checkQuota(vm *vmapi.VirtualMachine) (bool, bool) {
+	state, err := ctrl.readQuotaState(vm.Namespace)
+	if err != nil {
+		glog.V(2).Infof("error reading quotas of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		ctrl.vmQueue.AddAfter(vm.Namespace+"/"+vm.Name, quotaRetryInterval)
+		return false, false
+	}
+	if state != nil {
+		if err := state.evaluator.Fits(vm, state.vms, state.quotas); err != nil {
+			glog.V(2).Infof("vm %s/%s exceeds quota: %v", vm.Namespace, vm.Name, err)
+			if !setCondition(vm, vmapi.VirtualMachineCondition{
+				Type:    vmapi.VirtualMachineExceededQuota,
+				Status:  corev1.ConditionTrue,
+				Reason:  "ExceededQuota",
+				Message: err.Error(),
+			}) {
+				return false, false
+			}
+			ctrl.recorder.Eventf(vm, corev1.EventTypeWarning, "ExceededQuota", "%v", err)
+			return false, true
+		}
+	}
+	return true, removeCondition(vm, vmapi.VirtualMachineExceededQuota)
+}
+
+// startWithinQuota creates the VM's pod if it fits within the quotas of its
+// namespace. Returns true if status was modified.
+func (ctrl *VirtualMachineController) startWithinQuota(vm, effective *vmapi.VirtualMachine) bool {
+	unlock := ctrl.lockQuota(vm.Namespace)
+	defer unlock()
+
+	fits, changed := ctrl.checkQuota(vm)
+	if fits {
+		ctrl.createPod(effective)
+	}
+	return changed
+}
+
+// enqueueNamespaceVMs queues the VMs waiting to start in the namespace of a
+// quota
+func (ctrl *VirtualMachineController) enqueueNamespaceVMs(obj interface{}) {
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	vms, err := ctrl.vmLister.VirtualMachines(meta.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		glog.V(2).Infof("error listing vms of namespace %s: %v", meta.GetNamespace(), err)
+		return
+	}
+	for _, vm := range vms {
+		if !vm.Spec.Stopped && vm.Status.PodName == "" {
+			ctrl.enqueueWork(ctrl.vmQueue, vm)
+		}
+	}
+}
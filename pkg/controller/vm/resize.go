@@ -0,0 +1,211 @@
+package vm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/console"
+	"github.com/llparse/kube-crd-skel/pkg/launcher"
+)
+
+const (
+	// Filesystems lose some of their claim's capacity to metadata. The
+	// filesystem of a claim counts as expanded once it has this percentage
+	// of the claim's capacity.
+	filesystemExpandedPercent = 90
+
+	// Claims and their filesystems are polled while disks grow, as
+	// filesystems are expanded without any event we could watch
+	diskResizeInterval = 10 * time.Second
+)
+
+// syncDiskResize grows disks to their size_mb. Their claim is expanded
+// first, then the image on it and the disk of the running guest. Guests
+// must grow their partitions and filesystems themselves. Returns true if
+// status was modified.
+func (ctrl *VirtualMachineController) syncDiskResize(vm *vmapi.VirtualMachine, pod *corev1.Pod) bool {
+	running := pod != nil && pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil
+	changed := false
+	// Images fill their claim as pods start
+	if !running && vm.Status.Disks != nil {
+		vm.Status.Disks = nil
+		changed = true
+	}
+
+	var resizing, failed []string
+	for _, disk := range vm.Spec.Disks {
+		if disk.SizeMB == 0 {
+			continue
+		}
+		claim, err := ctrl.pvcLister.PersistentVolumeClaims(vm.Namespace).Get(disk.ClaimName)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("disk %s: %v", disk.Name, err))
+			continue
+		}
+		capacity := claim.Status.Capacity[corev1.ResourceStorage]
+		capacityMB := capacity.Value() >> 20
+		if running && diskStatus(vm, disk.Name) == nil {
+			vm.Status.Disks = append(vm.Status.Disks, vmapi.DiskStatus{Name: disk.Name, CapacityMB: capacityMB})
+			changed = true
+		}
+
+		requested := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+		if requestedMB := (requested.Value() + 1<<20 - 1) >> 20; requestedMB < disk.SizeMB {
+			if err := ctrl.expandClaim(vm, claim, disk.SizeMB, disk.SizeMB-requestedMB); err != nil {
+				failed = append(failed, fmt.Sprintf("disk %s: %v", disk.Name, err))
+				continue
+			}
+			resizing = append(resizing, fmt.Sprintf("disk %s: expanding claim %s", disk.Name, claim.Name))
+			continue
+		}
+		if capacityMB < disk.SizeMB || claimResizing(claim) {
+			resizing = append(resizing, fmt.Sprintf("disk %s: waiting for claim %s to expand", disk.Name, claim.Name))
+			continue
+		}
+
+		status := diskStatus(vm, disk.Name)
+		if !running || status.CapacityMB >= capacityMB {
+			continue
+		}
+		if ctrl.migrating(vm) {
+			resizing = append(resizing, fmt.Sprintf("disk %s: waiting for migration to finish", disk.Name))
+			continue
+		}
+		size, err := ctrl.resizeDisk(vm, pod, disk)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("disk %s: %v", disk.Name, err))
+			continue
+		}
+		if size.FilesystemMB*100 < capacityMB*filesystemExpandedPercent {
+			resizing = append(resizing, fmt.Sprintf("disk %s: waiting for the filesystem of claim %s to expand", disk.Name, claim.Name))
+			continue
+		}
+		status.CapacityMB = capacityMB
+		changed = true
+		ctrl.recorder.Eventf(vm, corev1.EventTypeNormal, "ResizedDisk", "Grew disk %s to %dMB", disk.Name, size.SizeMB)
+	}
+
+	if len(resizing) > 0 || len(failed) > 0 {
+		ctrl.vmQueue.AddAfter(vm.Namespace+"/"+vm.Name, diskResizeInterval)
+	}
+	condition := vmapi.VirtualMachineCondition{
+		Type: vmapi.VirtualMachineDiskResizing,
+	}
+	switch {
+	case len(failed) > 0:
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "ResizeFailed"
+		condition.Message = strings.Join(failed, "; ")
+	case len(resizing) > 0:
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "Resizing"
+		condition.Message = strings.Join(resizing, "; ")
+	default:
+		return removeCondition(vm, vmapi.VirtualMachineDiskResizing) || changed
+	}
+	return setCondition(vm, condition) || changed
+}
+
+// expandClaim raises the storage request of a disk's claim to sizeMB, if its
+// storage class and the quotas of its namespace allow it
+func (ctrl *VirtualMachineController) expandClaim(vm *vmapi.VirtualMachine, claim *corev1.PersistentVolumeClaim, sizeMB, growthMB int64) error {
+	className := claimStorageClass(claim)
+	if className == "" {
+		return fmt.Errorf("claim %s has no storage class", claim.Name)
+	}
+	class, err := ctrl.storageClassLister.Get(className)
+	if err != nil {
+		return err
+	}
+	if class.AllowVolumeExpansion == nil || !*class.AllowVolumeExpansion {
+		return fmt.Errorf("storage class %s doesn't allow volume expansion", className)
+	}
+	unlock := ctrl.lockQuota(vm.Namespace)
+	defer unlock()
+	if err := ctrl.checkDiskQuota(vm, growthMB); err != nil {
+		return err
+	}
+
+	// Never mutate objects from the informer cache
+	claim = claim.DeepCopy()
+	if claim.Spec.Resources.Requests == nil {
+		claim.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	claim.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(fmt.Sprintf("%dMi", sizeMB))
+	if _, err := ctrl.kubeClient.CoreV1().PersistentVolumeClaims(claim.Namespace).Update(claim); err != nil {
+		return err
+	}
+	ctrl.recorder.Eventf(vm, corev1.EventTypeNormal, "ExpandingClaim", "Expanding claim %s to %dMi", claim.Name, sizeMB)
+	return nil
+}
+
+// resizeDisk grows the disk of the guest running in the pod to fill its
+// claim
+func (ctrl *VirtualMachineController) resizeDisk(vm *vmapi.VirtualMachine, pod *corev1.Pod, disk vmapi.Disk) (*launcher.DiskSize, error) {
+	out, err := console.ExecOutput(ctrl.config, ctrl.kubeClient, pod, launcher.ResizeDiskCommand(disk.Name))
+	if err != nil {
+		glog.V(2).Infof("error resizing disk %s of vm %s/%s: %v", disk.Name, vm.Namespace, vm.Name, err)
+		ctrl.recorder.Eventf(vm, corev1.EventTypeWarning, "FailedResize", "Error resizing disk %s: %v", disk.Name, err)
+		return nil, err
+	}
+	size := &launcher.DiskSize{}
+	if err := json.Unmarshal(out, size); err != nil {
+		return nil, err
+	}
+	return size, nil
+}
+
+// enqueueClaimVMs queues the VMs with a disk on a claim, so that they follow
+// its expansion
+func (ctrl *VirtualMachineController) enqueueClaimVMs(obj interface{}) {
+	claim, ok := obj.(*corev1.PersistentVolumeClaim)
+	if !ok {
+		return
+	}
+	vms, err := ctrl.vmLister.VirtualMachines(claim.Namespace).List(labels.Everything())
+	if err != nil {
+		glog.V(2).Infof("error listing vms of namespace %s: %v", claim.Namespace, err)
+		return
+	}
+	for _, vm := range vms {
+		for _, disk := range vm.Spec.Disks {
+			if disk.ClaimName == claim.Name && disk.SizeMB != 0 {
+				ctrl.enqueueWork(ctrl.vmQueue, vm)
+				break
+			}
+		}
+	}
+}
+
+func claimStorageClass(claim *corev1.PersistentVolumeClaim) string {
+	if claim.Spec.StorageClassName != nil {
+		return *claim.Spec.StorageClassName
+	}
+	return claim.Annotations[corev1.BetaStorageClassAnnotation]
+}
+
+func claimResizing(claim *corev1.PersistentVolumeClaim) bool {
+	for _, condition := range claim.Status.Conditions {
+		if condition.Type == corev1.PersistentVolumeClaimResizing && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func diskStatus(vm *vmapi.VirtualMachine, name string) *vmapi.DiskStatus {
+	for i := range vm.Status.Disks {
+		if vm.Status.Disks[i].Name == name {
+			return &vm.Status.Disks[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package vm
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// setScheduling carries the VM's scheduling constraints into its pod. The
+// VM's labels are copied onto the pod, except where they would override the
+// controller's own, so that pod affinity terms can select VMs.
+func setScheduling(vm *vmapi.VirtualMachine, pod *corev1.Pod) {
+	for k, v := range vm.Labels {
+		if _, ok := pod.Labels[k]; !ok {
+			pod.Labels[k] = v
+		}
+	}
+
+	if len(vm.Spec.NodeSelector) > 0 {
+		pod.Spec.NodeSelector = map[string]string{}
+		for k, v := range vm.Spec.NodeSelector {
+			pod.Spec.NodeSelector[k] = v
+		}
+	}
+	if vm.Spec.Affinity != nil {
+		pod.Spec.Affinity = vm.Spec.Affinity.DeepCopy()
+	}
+	for _, toleration := range vm.Spec.Tolerations {
+		pod.Spec.Tolerations = append(pod.Spec.Tolerations, *toleration.DeepCopy())
+	}
+	pod.Spec.PriorityClassName = vm.Spec.PriorityClassName
+}
+
+// syncScheduledCondition copies the scheduling state of the VM's pod into
+// status. Returns true if status was modified.
+func syncScheduledCondition(vm *vmapi.VirtualMachine, pod *corev1.Pod) bool {
+	if pod == nil {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type != corev1.PodScheduled {
+			continue
+		}
+		return setCondition(vm, vmapi.VirtualMachineCondition{
+			Type:               vmapi.VirtualMachineScheduled,
+			Status:             cond.Status,
+			LastTransitionTime: cond.LastTransitionTime,
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+		})
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package vm
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func TestSetScheduling(t *testing.T) {
+	affinity := &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+				TopologyKey:   "kubernetes.io/hostname",
+			}},
+		},
+	}
+	toleration := corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "vms", Effect: corev1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		spec       vmapi.VirtualMachineSpec
+		wantLabels map[string]string
+		wantSpec   corev1.PodSpec
+	}{
+		{
+			name: "no constraints",
+			wantLabels: map[string]string{
+				"type":                "ranchervm",
+				ranchervm.LabelVMName: "vm1",
+			},
+		},
+		{
+			name: "labels don't override the controller's",
+			labels: map[string]string{
+				"app":                 "db",
+				"type":                "other",
+				ranchervm.LabelVMName: "other",
+			},
+			wantLabels: map[string]string{
+				"app":                 "db",
+				"type":                "ranchervm",
+				ranchervm.LabelVMName: "vm1",
+			},
+		},
+		{
+			name: "all constraints",
+			spec: vmapi.VirtualMachineSpec{
+				NodeSelector:      map[string]string{"disk": "ssd"},
+				Affinity:          affinity,
+				Tolerations:       []corev1.Toleration{toleration},
+				PriorityClassName: "high",
+			},
+			wantLabels: map[string]string{
+				"type":                "ranchervm",
+				ranchervm.LabelVMName: "vm1",
+			},
+			wantSpec: corev1.PodSpec{
+				NodeSelector:      map[string]string{"disk": "ssd"},
+				Affinity:          affinity,
+				Tolerations:       []corev1.Toleration{toleration},
+				PriorityClassName: "high",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		vm := &vmapi.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "vm1", Namespace: "default", Labels: test.labels},
+			Spec:       test.spec,
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					"type":                "ranchervm",
+					ranchervm.LabelVMName: "vm1",
+				},
+			},
+		}
+		setScheduling(vm, pod)
+		if !apiequality.Semantic.DeepEqual(pod.Labels, test.wantLabels) {
+			t.Errorf("%s: got labels %v, want %v", test.name, pod.Labels, test.wantLabels)
+		}
+		if !apiequality.Semantic.DeepEqual(pod.Spec, test.wantSpec) {
+			t.Errorf("%s: got spec %+v, want %+v", test.name, pod.Spec, test.wantSpec)
+		}
+
+		// The pod must not share the VM's objects
+		if pod.Spec.Affinity != nil && pod.Spec.Affinity == vm.Spec.Affinity {
+			t.Errorf("%s: pod shares the vm's affinity", test.name)
+		}
+		if pod.Spec.NodeSelector != nil {
+			pod.Spec.NodeSelector["disk"] = "hdd"
+			if vm.Spec.NodeSelector["disk"] != "ssd" {
+				t.Errorf("%s: pod shares the vm's node selector", test.name)
+			}
+		}
+	}
+}
+
+func TestSyncScheduledCondition(t *testing.T) {
+	transition := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	unschedulable := corev1.PodCondition{
+		Type:               corev1.PodScheduled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: transition,
+		Reason:             corev1.PodReasonUnschedulable,
+		Message:            "0/3 nodes are available",
+	}
+	scheduled := corev1.PodCondition{
+		Type:               corev1.PodScheduled,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: transition,
+	}
+	mirrored := func(cond corev1.PodCondition) *vmapi.VirtualMachineCondition {
+		return &vmapi.VirtualMachineCondition{
+			Type:               vmapi.VirtualMachineScheduled,
+			Status:             cond.Status,
+			LastTransitionTime: cond.LastTransitionTime,
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		current     []vmapi.VirtualMachineCondition
+		pod         *corev1.Pod
+		wantChanged bool
+		want        *vmapi.VirtualMachineCondition
+	}{
+		{
+			name: "no pod",
+		},
+		{
+			name: "pod not yet seen by the scheduler",
+			pod:  &corev1.Pod{},
+		},
+		{
+			name: "other conditions are ignored",
+			pod: &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+			}}},
+		},
+		{
+			name: "unschedulable",
+			pod: &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+				{Type: corev1.PodInitialized, Status: corev1.ConditionTrue},
+				unschedulable,
+			}}},
+			wantChanged: true,
+			want:        mirrored(unschedulable),
+		},
+		{
+			name:    "unchanged",
+			current: []vmapi.VirtualMachineCondition{*mirrored(unschedulable)},
+			pod: &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+				unschedulable,
+			}}},
+			want: mirrored(unschedulable),
+		},
+		{
+			name:    "scheduled",
+			current: []vmapi.VirtualMachineCondition{*mirrored(unschedulable)},
+			pod: &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+				scheduled,
+			}}},
+			wantChanged: true,
+			want:        mirrored(scheduled),
+		},
+	}
+
+	for _, test := range tests {
+		vm := &vmapi.VirtualMachine{
+			Status: vmapi.VirtualMachineStatus{Conditions: test.current},
+		}
+		if changed := syncScheduledCondition(vm, test.pod); changed != test.wantChanged {
+			t.Errorf("%s: got changed %v, want %v", test.name, changed, test.wantChanged)
+		}
+		var got *vmapi.VirtualMachineCondition
+		for i := range vm.Status.Conditions {
+			if vm.Status.Conditions[i].Type == vmapi.VirtualMachineScheduled {
+				got = &vm.Status.Conditions[i]
+			}
+		}
+		if !apiequality.Semantic.DeepEqual(got, test.want) {
+			t.Errorf("%s: got condition %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
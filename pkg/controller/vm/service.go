@@ -0,0 +1,152 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// newControllerRef returns an owner reference marking vm as the controller
+// of a dependent object, so that dependents are garbage collected with it.
+func newControllerRef(vm *vmapi.VirtualMachine) *metav1.OwnerReference {
+	return metav1.NewControllerRef(vm, vmapi.SchemeGroupVersion.WithKind("VirtualMachine"))
+}
+
+// isControlledBy returns true if vm is the controller of obj.
+func isControlledBy(obj metav1.Object, vm *vmapi.VirtualMachine) bool {
+	ref := metav1.GetControllerOf(obj)
+	return ref != nil && ref.UID == vm.UID
+}
+
+func validateServiceType(serviceType corev1.ServiceType) error {
+	switch serviceType {
+	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+		return nil
+	}
+	return fmt.Errorf("unsupported service type %q", serviceType)
+}
+
+// serviceSpec returns the spec of the Service exposing the VM's ports.
+// current is the existing Service, if any.
+func serviceSpec(vm *vmapi.VirtualMachine, current *corev1.Service) corev1.ServiceSpec {
+	serviceType := vm.Spec.ServiceType
+	if serviceType == "" {
+		serviceType = corev1.ServiceTypeClusterIP
+	}
+
+	spec := corev1.ServiceSpec{
+		Type: serviceType,
+		Selector: map[string]string{
+			ranchervm.LabelVMName: vm.Name,
+		},
+	}
+	if current != nil {
+		// ClusterIP is immutable, and fields the apiserver defaulted must be
+		// kept to compare equal
+		spec = *current.Spec.DeepCopy()
+		spec.Type = serviceType
+		spec.Selector = map[string]string{
+			ranchervm.LabelVMName: vm.Name,
+		}
+		// The apiserver rejects fields the new type doesn't support
+		if serviceType != corev1.ServiceTypeLoadBalancer {
+			spec.LoadBalancerIP = ""
+			spec.LoadBalancerSourceRanges = nil
+			spec.HealthCheckNodePort = 0
+		}
+		if serviceType == corev1.ServiceTypeClusterIP {
+			spec.ExternalTrafficPolicy = ""
+		}
+	}
+
+	spec.Ports = []corev1.ServicePort{}
+	for _, port := range vm.Spec.Ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		servicePort := corev1.ServicePort{
+			Name:       port.Name,
+			Protocol:   protocol,
+			Port:       port.Port,
+			TargetPort: intstr.FromInt(int(port.Port)),
+		}
+		if serviceType != corev1.ServiceTypeClusterIP {
+			servicePort.NodePort = port.NodePort
+			// Keep node ports the apiserver allocated to us
+			if current != nil && servicePort.NodePort == 0 {
+				for _, currentPort := range current.Spec.Ports {
+					if currentPort.Name == port.Name {
+						servicePort.NodePort = currentPort.NodePort
+					}
+				}
+			}
+		}
+		spec.Ports = append(spec.Ports, servicePort)
+	}
+	return spec
+}
+
+// syncService creates, updates or deletes the Service exposing the VM's ports
+func (ctrl *VirtualMachineController) syncService(vm *vmapi.VirtualMachine) {
+	svc, err := ctrl.serviceLister.Services(vm.Namespace).Get(vm.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		glog.V(2).Infof("error getting service %s/%s from informer: %v", vm.Namespace, vm.Name, err)
+		return
+	}
+	if err == nil && !isControlledBy(svc, vm) {
+		glog.V(2).Infof("service %s/%s exists and is not controlled by vm", vm.Namespace, vm.Name)
+		return
+	}
+
+	if len(vm.Spec.Ports) == 0 {
+		if svc != nil {
+			ctrl.deleteService(vm.Namespace, vm.Name)
+		}
+		return
+	}
+
+	if apierrors.IsNotFound(err) {
+		svc = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      vm.Name,
+				Namespace: vm.Namespace,
+				Labels: map[string]string{
+					"type":                "ranchervm",
+					ranchervm.LabelVMName: vm.Name,
+				},
+				OwnerReferences: []metav1.OwnerReference{*newControllerRef(vm)},
+			},
+			Spec: serviceSpec(vm, nil),
+		}
+		if _, err := ctrl.kubeClient.CoreV1().Services(vm.Namespace).Create(svc); err != nil {
+			glog.V(2).Infof("error creating service %s/%s: %v", vm.Namespace, vm.Name, err)
+		}
+		return
+	}
+
+	spec := serviceSpec(vm, svc)
+	if apiequality.Semantic.DeepEqual(spec, svc.Spec) {
+		return
+	}
+	svc = svc.DeepCopy()
+	svc.Spec = spec
+	if _, err := ctrl.kubeClient.CoreV1().Services(vm.Namespace).Update(svc); err != nil {
+		glog.V(2).Infof("error updating service %s/%s: %v", vm.Namespace, vm.Name, err)
+	}
+}
+
+func (ctrl *VirtualMachineController) deleteService(ns, name string) {
+	err := ctrl.kubeClient.CoreV1().Services(ns).Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		glog.V(2).Infof("error deleting service %s/%s: %v", ns, name, err)
+	}
+}
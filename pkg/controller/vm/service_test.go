@@ -0,0 +1,149 @@
+package vm
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func TestServiceSpec(t *testing.T) {
+	selector := map[string]string{ranchervm.LabelVMName: "vm1"}
+	newVM := func(serviceType corev1.ServiceType, ports ...vmapi.VirtualMachinePort) *vmapi.VirtualMachine {
+		return &vmapi.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "vm1", Namespace: "default"},
+			Spec: vmapi.VirtualMachineSpec{
+				ServiceType: serviceType,
+				Ports:       ports,
+			},
+		}
+	}
+	ssh := vmapi.VirtualMachinePort{Name: "ssh", Port: 22}
+	dns := vmapi.VirtualMachinePort{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP}
+	pinned := vmapi.VirtualMachinePort{Name: "ssh", Port: 22, NodePort: 30022}
+
+	tests := []struct {
+		name    string
+		vm      *vmapi.VirtualMachine
+		current *corev1.Service
+		want    corev1.ServiceSpec
+	}{
+		{
+			name: "cluster ip ignores node ports",
+			vm:   newVM("", pinned, dns),
+			want: corev1.ServiceSpec{
+				Type:     corev1.ServiceTypeClusterIP,
+				Selector: selector,
+				Ports: []corev1.ServicePort{
+					{Name: "ssh", Protocol: corev1.ProtocolTCP, Port: 22, TargetPort: intstr.FromInt(22)},
+					{Name: "dns", Protocol: corev1.ProtocolUDP, Port: 53, TargetPort: intstr.FromInt(53)},
+				},
+			},
+		},
+		{
+			name: "node port keeps allocated ports",
+			vm:   newVM(corev1.ServiceTypeNodePort, ssh),
+			current: &corev1.Service{Spec: corev1.ServiceSpec{
+				Type:                  corev1.ServiceTypeNodePort,
+				ClusterIP:             "10.43.0.20",
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeCluster,
+				Ports:                 []corev1.ServicePort{{Name: "ssh", Port: 22, NodePort: 31000}},
+			}},
+			want: corev1.ServiceSpec{
+				Type:                  corev1.ServiceTypeNodePort,
+				ClusterIP:             "10.43.0.20",
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeCluster,
+				Selector:              selector,
+				Ports: []corev1.ServicePort{
+					{Name: "ssh", Protocol: corev1.ProtocolTCP, Port: 22, TargetPort: intstr.FromInt(22), NodePort: 31000},
+				},
+			},
+		},
+		{
+			name: "pinned node port",
+			vm:   newVM(corev1.ServiceTypeNodePort, pinned),
+			current: &corev1.Service{Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeNodePort,
+				Ports: []corev1.ServicePort{{Name: "ssh", Port: 22, NodePort: 31000}},
+			}},
+			want: corev1.ServiceSpec{
+				Type:     corev1.ServiceTypeNodePort,
+				Selector: selector,
+				Ports: []corev1.ServicePort{
+					{Name: "ssh", Protocol: corev1.ProtocolTCP, Port: 22, TargetPort: intstr.FromInt(22), NodePort: 30022},
+				},
+			},
+		},
+		{
+			name: "load balancer to cluster ip drops type specific fields",
+			vm:   newVM(corev1.ServiceTypeClusterIP, ssh),
+			current: &corev1.Service{Spec: corev1.ServiceSpec{
+				Type:                     corev1.ServiceTypeLoadBalancer,
+				ClusterIP:                "10.43.0.20",
+				LoadBalancerIP:           "192.0.2.10",
+				LoadBalancerSourceRanges: []string{"192.0.2.0/24"},
+				ExternalTrafficPolicy:    corev1.ServiceExternalTrafficPolicyTypeLocal,
+				HealthCheckNodePort:      32000,
+				Ports:                    []corev1.ServicePort{{Name: "ssh", Port: 22, NodePort: 31000}},
+			}},
+			want: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeClusterIP,
+				ClusterIP: "10.43.0.20",
+				Selector:  selector,
+				Ports: []corev1.ServicePort{
+					{Name: "ssh", Protocol: corev1.ProtocolTCP, Port: 22, TargetPort: intstr.FromInt(22)},
+				},
+			},
+		},
+		{
+			name: "load balancer to node port keeps traffic policy",
+			vm:   newVM(corev1.ServiceTypeNodePort, ssh),
+			current: &corev1.Service{Spec: corev1.ServiceSpec{
+				Type:                  corev1.ServiceTypeLoadBalancer,
+				LoadBalancerIP:        "192.0.2.10",
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+				HealthCheckNodePort:   32000,
+				Ports:                 []corev1.ServicePort{{Name: "ssh", Port: 22, NodePort: 31000}},
+			}},
+			want: corev1.ServiceSpec{
+				Type:                  corev1.ServiceTypeNodePort,
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+				Selector:              selector,
+				Ports: []corev1.ServicePort{
+					{Name: "ssh", Protocol: corev1.ProtocolTCP, Port: 22, TargetPort: intstr.FromInt(22), NodePort: 31000},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got := serviceSpec(test.vm, test.current)
+		if !apiequality.Semantic.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestValidateServiceType(t *testing.T) {
+	tests := []struct {
+		serviceType corev1.ServiceType
+		wantErr     bool
+	}{
+		{"", false},
+		{corev1.ServiceTypeClusterIP, false},
+		{corev1.ServiceTypeNodePort, false},
+		{corev1.ServiceTypeLoadBalancer, false},
+		{corev1.ServiceTypeExternalName, true},
+		{"Headless", true},
+	}
+	for _, test := range tests {
+		if err := validateServiceType(test.serviceType); (err != nil) != test.wantErr {
+			t.Errorf("%q: got error %v, want error %v", test.serviceType, err, test.wantErr)
+		}
+	}
+}
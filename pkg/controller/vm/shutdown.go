@@ -0,0 +1,120 @@
+package vm
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// launcherTermination returns the last termination of the launcher in pod,
+// if it terminated
+func launcherTermination(pod *corev1.Pod) *corev1.ContainerStateTerminated {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != launcherContainer {
+			continue
+		}
+		if status.State.Terminated != nil {
+			return status.State.Terminated
+		}
+		return status.LastTerminationState.Terminated
+	}
+	return nil
+}
+
+// syncShutdownCondition records whether the guest powered off by itself the
+// last time the launcher terminated. Returns true if status was modified.
+func syncShutdownCondition(vm *vmapi.VirtualMachine, pod *corev1.Pod) bool {
+	if pod == nil {
+		return false
+	}
+	terminated := launcherTermination(pod)
+	if terminated == nil {
+		return false
+	}
+
+	condition := vmapi.VirtualMachineCondition{
+		Type:               vmapi.VirtualMachineCleanShutdown,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: terminated.FinishedAt,
+		Reason:             "PoweredOff",
+		Message:            terminated.Message,
+	}
+	if terminated.ExitCode != 0 {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = terminated.Reason
+		if terminated.Signal != 0 || terminated.ExitCode == 137 {
+			condition.Reason = "Killed"
+			condition.Message = "Guest did not power off within the termination grace period"
+		}
+	}
+	return setCondition(vm, condition)
+}
+
+// podDeleted keeps the final state of a deleted launcher pod. Pods are
+// removed as soon as the launcher exits after its preStop shutdown, often
+// before the controller sees how it terminated.
+func (ctrl *VirtualMachineController) podDeleted(obj interface{}) {
+	ctrl.enqueuePod(obj)
+
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || launcherTermination(pod) == nil {
+		return
+	}
+	if name, ok := pod.Labels[ranchervm.LabelVMName]; ok {
+		ctrl.deletedPodsLock.Lock()
+		defer ctrl.deletedPodsLock.Unlock()
+		ctrl.deletedPods[pod.Namespace+"/"+name] = pod
+	}
+}
+
+// syncDeletedPod records how the guest shut down if the VM's pod, which may
+// be nil, was deleted. Returns true if status was modified.
+func (ctrl *VirtualMachineController) syncDeletedPod(vm *vmapi.VirtualMachine, pod *corev1.Pod) bool {
+	key := vm.Namespace + "/" + vm.Name
+	ctrl.deletedPodsLock.Lock()
+	deleted := ctrl.deletedPods[key]
+	ctrl.deletedPodsLock.Unlock()
+	if deleted == nil {
+		return false
+	}
+	// Source pods of migrations are deleted while the VM runs on
+	if pod == nil && deleted.Name == activePodName(vm) && syncShutdownCondition(vm, deleted) {
+		// Kept until the condition is persisted
+		return true
+	}
+	ctrl.forgetDeletedPod(key)
+	return false
+}
+
+func (ctrl *VirtualMachineController) forgetDeletedPod(key string) {
+	ctrl.deletedPodsLock.Lock()
+	defer ctrl.deletedPodsLock.Unlock()
+	delete(ctrl.deletedPods, key)
+}
+
+// setCondition adds or replaces the condition of the same type. Returns true
+// if status was modified.
+func setCondition(vm *vmapi.VirtualMachine, condition vmapi.VirtualMachineCondition) bool {
+	if condition.LastTransitionTime.IsZero() {
+		condition.LastTransitionTime = metav1.Now()
+	}
+	for i := range vm.Status.Conditions {
+		current := &vm.Status.Conditions[i]
+		if current.Type != condition.Type {
+			continue
+		}
+		if current.Status == condition.Status && current.Reason == condition.Reason && current.Message == condition.Message {
+			return false
+		}
+		*current = condition
+		return true
+	}
+	vm.Status.Conditions = append(vm.Status.Conditions, condition)
+	return true
+}
@@ -1,54 +1,137 @@
 package vm
 
 import (
-	"strconv"
+	"sync"
 	"time"
 
 	"github.com/golang/glog"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
 	coreinformers "k8s.io/client-go/informers/core/v1"
+	policyinformers "k8s.io/client-go/informers/policy/v1beta1"
+	storageinformers "k8s.io/client-go/informers/storage/v1"
 	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	policylisters "k8s.io/client-go/listers/policy/v1beta1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
 	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
 	vmclientset "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned"
+	vmscheme "github.com/llparse/kube-crd-skel/pkg/client/clientset/versioned/scheme"
 	vminformers "github.com/llparse/kube-crd-skel/pkg/client/informers/externalversions/virtualmachine/v1alpha1"
 	vmlisters "github.com/llparse/kube-crd-skel/pkg/client/listers/virtualmachine/v1alpha1"
 )
 
 type VirtualMachineController struct {
+	config     *rest.Config
 	vmClient   vmclientset.Interface
 	kubeClient kubernetes.Interface
 
-	vmLister        vmlisters.VirtualMachineLister
-	vmListerSynced  cache.InformerSynced
-	podLister       corelisters.PodLister
-	podListerSynced cache.InformerSynced
+	vmLister                 vmlisters.VirtualMachineLister
+	vmListerSynced           cache.InformerSynced
+	migrationLister          vmlisters.VirtualMachineMigrationLister
+	migrationListerSynced    cache.InformerSynced
+	instanceTypeLister       vmlisters.VirtualMachineInstanceTypeLister
+	instanceTypeListerSynced cache.InformerSynced
+	preferenceLister         vmlisters.VirtualMachinePreferenceLister
+	preferenceListerSynced   cache.InformerSynced
+	quotaLister              vmlisters.VirtualMachineQuotaLister
+	quotaListerSynced        cache.InformerSynced
+	exportLister             vmlisters.VirtualMachineExportLister
+	exportListerSynced       cache.InformerSynced
+	podLister                corelisters.PodLister
+	podListerSynced          cache.InformerSynced
+	serviceLister            corelisters.ServiceLister
+	serviceListerSynced      cache.InformerSynced
+	nodeLister               corelisters.NodeLister
+	nodeListerSynced         cache.InformerSynced
+	pdbLister                policylisters.PodDisruptionBudgetLister
+	pdbListerSynced          cache.InformerSynced
+	pvcLister                corelisters.PersistentVolumeClaimLister
+	pvcListerSynced          cache.InformerSynced
+	storageClassLister       storagelisters.StorageClassLister
+	storageClassListerSynced cache.InformerSynced
+
+	// quotaLocks serializes the quota checks of each namespace
+	quotaLocksLock sync.Mutex
+	quotaLocks     map[string]*sync.Mutex
+
+	// deletedPods holds the final state of deleted launcher pods by VM key,
+	// until how their guest shut down is recorded on the VM
+	deletedPodsLock sync.Mutex
+	deletedPods     map[string]*corev1.Pod
 
 	vmQueue  workqueue.RateLimitingInterface
 	podQueue workqueue.RateLimitingInterface
+
+	recorder record.EventRecorder
+
+	launcherImage string
+	kvmResource   corev1.ResourceName
+	kvmNodeLabel  string
+	// allowPrivileged lets VMs run in privileged pods on nodes with the KVM
+	// node label if no node advertises kvmResource
+	allowPrivileged bool
+	sizeLimits      ranchervm.SizeLimits
+	// memoryOvercommit is the percentage of pod memory requests given to
+	// guests of VMs without a memory request
+	memoryOvercommit int
 }
 
 func NewVirtualMachineController(
+	config *rest.Config,
 	vmClient vmclientset.Interface,
 	kubeClient kubernetes.Interface,
 	vmInformer vminformers.VirtualMachineInformer,
+	migrationInformer vminformers.VirtualMachineMigrationInformer,
+	instanceTypeInformer vminformers.VirtualMachineInstanceTypeInformer,
+	preferenceInformer vminformers.VirtualMachinePreferenceInformer,
+	quotaInformer vminformers.VirtualMachineQuotaInformer,
+	exportInformer vminformers.VirtualMachineExportInformer,
 	podInformer coreinformers.PodInformer,
+	serviceInformer coreinformers.ServiceInformer,
+	nodeInformer coreinformers.NodeInformer,
+	pdbInformer policyinformers.PodDisruptionBudgetInformer,
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+	storageClassInformer storageinformers.StorageClassInformer,
+	launcherImage string,
+	kvmResource string,
+	kvmNodeLabel string,
+	allowPrivileged bool,
+	sizeLimits ranchervm.SizeLimits,
+	memoryOvercommit int,
 ) *VirtualMachineController {
 
 	ctrl := &VirtualMachineController{
-		vmClient:   vmClient,
-		kubeClient: kubeClient,
-		vmQueue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachine"),
-		podQueue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "pod"),
+		config:           config,
+		vmClient:         vmClient,
+		kubeClient:       kubeClient,
+		vmQueue:          workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "virtualmachine"),
+		podQueue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "pod"),
+		launcherImage:    launcherImage,
+		kvmResource:      corev1.ResourceName(kvmResource),
+		kvmNodeLabel:     kvmNodeLabel,
+		allowPrivileged:  allowPrivileged,
+		sizeLimits:       sizeLimits,
+		memoryOvercommit: memoryOvercommit,
+		deletedPods:      map[string]*corev1.Pod{},
+		quotaLocks:       map[string]*sync.Mutex{},
 	}
 
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(glog.Infof)
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	ctrl.recorder = broadcaster.NewRecorder(vmscheme.Scheme, corev1.EventSource{Component: "vm-controller"})
+
 	vmInformer.Informer().AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc:    func(obj interface{}) { ctrl.enqueueWork(ctrl.vmQueue, obj) },
@@ -61,19 +144,113 @@ func NewVirtualMachineController(
 		cache.FilteringResourceEventHandler{
 			FilterFunc: ctrl.podFilterFunc,
 			Handler: cache.ResourceEventHandlerFuncs{
-				AddFunc:    func(obj interface{}) { ctrl.enqueueWork(ctrl.podQueue, obj) },
-				UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueWork(ctrl.podQueue, newObj) },
-				DeleteFunc: func(obj interface{}) { ctrl.enqueueWork(ctrl.podQueue, obj) },
+				AddFunc:    ctrl.enqueuePod,
+				UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueuePod(newObj) },
+				DeleteFunc: ctrl.podDeleted,
+			},
+		},
+	)
+
+	// Services are named after their VM, so their events map directly onto
+	// the VM queue
+	serviceInformer.Informer().AddEventHandler(
+		cache.FilteringResourceEventHandler{
+			FilterFunc: ctrl.serviceFilterFunc,
+			Handler: cache.ResourceEventHandlerFuncs{
+				UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueWork(ctrl.vmQueue, newObj) },
+				DeleteFunc: func(obj interface{}) { ctrl.enqueueWork(ctrl.vmQueue, obj) },
+			},
+		},
+	)
+
+	// Instance types and preferences are resolved as VMs start, so VMs
+	// waiting for one to appear or to be corrected need a resync
+	instanceTypeInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    ctrl.enqueueReferencingVMs,
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueReferencingVMs(newObj) },
+			DeleteFunc: ctrl.enqueueReferencingVMs,
+		},
+	)
+	preferenceInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    ctrl.enqueueReferencingVMs,
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueReferencingVMs(newObj) },
+			DeleteFunc: ctrl.enqueueReferencingVMs,
+		},
+	)
+
+	// VMs held back by a quota may fit once it changes. Quota status
+	// changes as VMs stop, so this also catches VMs freeing up quota.
+	quotaInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    ctrl.enqueueNamespaceVMs,
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueNamespaceVMs(newObj) },
+		},
+	)
+
+	// Exports keep their VM from starting until they fail or are deleted
+	exportInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueExportVM(newObj) },
+			DeleteFunc: ctrl.enqueueExportVM,
+		},
+	)
+
+	// VMs are moved off nodes as they are cordoned, and may run once nodes
+	// provide KVM
+	nodeInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: ctrl.enqueueVMsWithoutKVM,
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				ctrl.enqueueNodeVMs(oldObj, newObj)
+				ctrl.enqueueVMsWithoutKVM(newObj)
 			},
 		},
 	)
 
+	// Disks grow as their claims expand
+	pvcInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueClaimVMs(newObj) },
+		},
+	)
+
 	ctrl.vmLister = vmInformer.Lister()
 	ctrl.vmListerSynced = vmInformer.Informer().HasSynced
 
+	ctrl.migrationLister = migrationInformer.Lister()
+	ctrl.migrationListerSynced = migrationInformer.Informer().HasSynced
+
+	ctrl.instanceTypeLister = instanceTypeInformer.Lister()
+	ctrl.instanceTypeListerSynced = instanceTypeInformer.Informer().HasSynced
+
+	ctrl.preferenceLister = preferenceInformer.Lister()
+	ctrl.preferenceListerSynced = preferenceInformer.Informer().HasSynced
+
+	ctrl.quotaLister = quotaInformer.Lister()
+	ctrl.quotaListerSynced = quotaInformer.Informer().HasSynced
+
+	ctrl.exportLister = exportInformer.Lister()
+	ctrl.exportListerSynced = exportInformer.Informer().HasSynced
+
+	ctrl.pvcLister = pvcInformer.Lister()
+	ctrl.pvcListerSynced = pvcInformer.Informer().HasSynced
+	ctrl.storageClassLister = storageClassInformer.Lister()
+	ctrl.storageClassListerSynced = storageClassInformer.Informer().HasSynced
+
 	ctrl.podLister = podInformer.Lister()
 	ctrl.podListerSynced = podInformer.Informer().HasSynced
 
+	ctrl.serviceLister = serviceInformer.Lister()
+	ctrl.serviceListerSynced = serviceInformer.Informer().HasSynced
+
+	ctrl.nodeLister = nodeInformer.Lister()
+	ctrl.nodeListerSynced = nodeInformer.Informer().HasSynced
+
+	ctrl.pdbLister = pdbInformer.Lister()
+	ctrl.pdbListerSynced = pdbInformer.Informer().HasSynced
+
 	return ctrl
 }
 
@@ -83,7 +260,9 @@ func (ctrl *VirtualMachineController) Run(workers int, stopCh <-chan struct{}) {
 	glog.Infof("Starting vm controller")
 	defer glog.Infof("Shutting down vm Controller")
 
-	if !cache.WaitForCacheSync(stopCh, ctrl.vmListerSynced, ctrl.podListerSynced) {
+	if !cache.WaitForCacheSync(stopCh, ctrl.vmListerSynced, ctrl.migrationListerSynced, ctrl.instanceTypeListerSynced,
+		ctrl.preferenceListerSynced, ctrl.quotaListerSynced, ctrl.exportListerSynced, ctrl.podListerSynced, ctrl.serviceListerSynced,
+		ctrl.nodeListerSynced, ctrl.pdbListerSynced, ctrl.pvcListerSynced, ctrl.storageClassListerSynced) {
 		return
 	}
 
@@ -109,57 +288,211 @@ func (ctrl *VirtualMachineController) enqueueWork(queue workqueue.Interface, obj
 	queue.Add(objName)
 }
 
-func (ctrl *VirtualMachineController) updateVM(vm *vmapi.VirtualMachine) {
-	// Find pod associated with the VM
-	pod, err := ctrl.podLister.Pods(vm.Namespace).Get(vm.Name)
-	if err == nil {
-		// Update pod (what vm spec updates can we support?)
-		glog.V(2).Infof("Pod %s/%s already exists", pod.Namespace, pod.Name)
+// enqueuePod queues the VM of a pod. Pods are not always named after their
+// VM, as migrated VMs run in the migration's target pod.
+func (ctrl *VirtualMachineController) enqueuePod(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
 		return
 	}
-	if !apierrors.IsNotFound(err) {
-		glog.V(2).Infof("error getting pod %s/%s from informer: %v", vm.Namespace, vm.Name, err)
+	if name, ok := pod.Labels[ranchervm.LabelVMName]; ok {
+		glog.V(5).Infof("enqueued pod %s/%s for sync of vm %s", pod.Namespace, pod.Name, name)
+		ctrl.podQueue.Add(pod.Namespace + "/" + name)
+	}
+}
+
+// validateSpec returns the reason and error of the first problem in the
+// spec the controller can't act on
+func validateSpec(vm *vmapi.VirtualMachine) (string, error) {
+	if err := validateInterfaces(vmInterfaces(vm)); err != nil {
+		return "InvalidInterfaces", err
+	}
+	if err := validateDisks(vm.Spec.Disks); err != nil {
+		return "InvalidDisks", err
+	}
+	if err := validateEvictionStrategy(vm.Spec.EvictionStrategy); err != nil {
+		return "InvalidEvictionStrategy", err
+	}
+	if err := validateServiceType(vm.Spec.ServiceType); err != nil {
+		return "InvalidServiceType", err
+	}
+	return "", nil
+}
+
+func (ctrl *VirtualMachineController) updateVM(vm *vmapi.VirtualMachine) {
+	// Never mutate objects from the informer cache
+	vm = vm.DeepCopy()
+
+	if reason, err := validateSpec(vm); err != nil {
+		glog.V(2).Infof("invalid spec of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		if setCondition(vm, vmapi.VirtualMachineCondition{
+			Type:    vmapi.VirtualMachineInvalidSpec,
+			Status:  corev1.ConditionTrue,
+			Reason:  reason,
+			Message: err.Error(),
+		}) {
+			ctrl.recorder.Event(vm, corev1.EventTypeWarning, reason, err.Error())
+			ctrl.updateVMStatus(vm)
+		}
 		return
 	}
+	changed := removeCondition(vm, vmapi.VirtualMachineInvalidSpec)
 
-	// Create pod with vm spec
-	// FIXME
-	pod, err = ctrl.kubeClient.CoreV1().Pods(vm.Namespace).Create(&corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      vm.Name,
-			Namespace: vm.Namespace,
-			Labels: map[string]string{
-				"type": "ranchervm",
-			},
-			Annotations: map[string]string{
-				ranchervm.GroupName + "/cpu_milli": strconv.Itoa(int(vm.Spec.CpuMillis)),
-				ranchervm.GroupName + "/memory_mb": strconv.Itoa(int(vm.Spec.MemoryMB)),
-			},
-		},
-		Spec: corev1.PodSpec{
-			Containers: []corev1.Container{
-				corev1.Container{
-					Name:  "vm-in-a-pod",
-					Image: "alpine:3.7",
-					Args:  []string{"sleep", "999999"},
-				},
-			},
-		},
-	})
+	// MAC addresses must be persisted before the pod is created so that they
+	// survive pod restarts
+	assigned, err := assignMACs(vm)
 	if err != nil {
-		glog.V(2).Infof("Error creating pod %s/%s: %v", vm.Namespace, vm.Name, err)
+		glog.V(2).Infof("error assigning MAC addresses to vm %s/%s: %v", vm.Namespace, vm.Name, err)
+		return
+	}
+	if assigned {
+		// The resulting update event will requeue the VM
+		ctrl.updateVMStatus(vm)
+		return
+	}
+
+	ctrl.syncService(vm)
+	ctrl.syncDisruptionBudget(vm)
+
+	// Find pod associated with the VM
+	pod, err := ctrl.podLister.Pods(vm.Namespace).Get(activePodName(vm))
+	if err != nil && !apierrors.IsNotFound(err) {
+		glog.V(2).Infof("error getting pod %s/%s from informer: %v", vm.Namespace, activePodName(vm), err)
 		return
 	}
+	if apierrors.IsNotFound(err) {
+		pod = nil
+	}
+
+	// Update pod (what vm spec updates can we support?)
+	switch {
+	case vm.Spec.Stopped && pod != nil && pod.DeletionTimestamp == nil:
+		if ctrl.deletePod(vm.Namespace, pod.Name) {
+			ctrl.recorder.Event(vm, corev1.EventTypeNormal, "Stopping", "Deleted pod")
+		}
+	case !vm.Spec.Stopped && pod == nil:
+		// The VM may have been handed over to another pod in the meantime,
+		// and must never run twice
+		pods, err := ctrl.podLister.Pods(vm.Namespace).List(labels.SelectorFromSet(labels.Set{
+			ranchervm.LabelVMName: vm.Name,
+		}))
+		if err != nil {
+			glog.V(2).Infof("error listing pods of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+			return
+		}
+		if len(pods) > 0 {
+			break
+		}
+		resolved, err := ctrl.resolveSpec(vm)
+		if err != nil {
+			glog.V(2).Infof("error resolving spec of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+			ctrl.recorder.Eventf(vm, corev1.EventTypeWarning, "FailedCreate", "Error resolving spec: %v", err)
+			return
+		}
+		if resolved {
+			// The resulting update event will requeue the VM
+			ctrl.updateVMStatus(vm)
+			return
+		}
+		// Limits may have changed since the VM or its instance type were
+		// admitted, running VMs are left alone
+		effective := effectiveVM(vm)
+		if err := ctrl.validateSizing(vm, effective); err != nil {
+			glog.V(2).Infof("invalid size of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+			ctrl.recorder.Eventf(vm, corev1.EventTypeWarning, "InvalidSize", "%v", err)
+			return
+		}
+		if ok, err := ctrl.hasKVM(vm); err != nil || !ok {
+			// The HardwareVirtualization condition tells why
+			if err != nil {
+				glog.V(2).Infof("error listing nodes: %v", err)
+			}
+			break
+		}
+		start, exportChanged := ctrl.checkExports(vm)
+		if exportChanged {
+			changed = true
+		}
+		if !start {
+			break
+		}
+		if ctrl.startWithinQuota(vm, effective) {
+			changed = true
+		}
+	default:
+		if ctrl.syncEviction(vm, pod) {
+			changed = true
+		}
+		if ctrl.syncHotplug(vm, pod) {
+			changed = true
+		}
+	}
+
+	if ctrl.syncDeletedPod(vm, pod) {
+		changed = true
+	}
+	if syncStatus(vm, pod) {
+		changed = true
+	}
+	if ctrl.syncDiskResize(vm, pod) {
+		changed = true
+	}
+	// Restarts apply the whole spec
+	if pod == nil && removeCondition(vm, vmapi.VirtualMachineRestartRequired) {
+		changed = true
+	}
+	if pod == nil && removeCondition(vm, vmapi.VirtualMachineEvictionBlocked) {
+		changed = true
+	}
+	if pod == nil && vm.Spec.Stopped && removeCondition(vm, vmapi.VirtualMachineExceededQuota) {
+		changed = true
+	}
+	if pod == nil && vm.Spec.Stopped && removeCondition(vm, vmapi.VirtualMachineExporting) {
+		changed = true
+	}
+	if ctrl.syncKVMCondition(vm) {
+		changed = true
+	}
+	if changed {
+		ctrl.updateVMStatus(vm)
+	}
+}
+
+func (ctrl *VirtualMachineController) updateVMStatus(vm *vmapi.VirtualMachine) {
+	_, err := ctrl.vmClient.VirtualmachineV1alpha1().VirtualMachines(vm.Namespace).Update(vm)
+	if err != nil {
+		glog.V(2).Infof("error updating status of vm %s/%s: %v", vm.Namespace, vm.Name, err)
+	}
 }
 
 func (ctrl *VirtualMachineController) deleteVM(ns, name string) {
-	err := ctrl.kubeClient.CoreV1().Pods(ns).Delete(name, &metav1.DeleteOptions{})
-	if err != nil && !apierrors.IsNotFound(err) {
-		glog.V(2).Infof("error deleting pod %s/%s: %v", ns, name, err)
+	ctrl.forgetDeletedPod(ns + "/" + name)
+
+	pods, err := ctrl.podLister.Pods(ns).List(labels.SelectorFromSet(labels.Set{
+		ranchervm.LabelVMName: name,
+	}))
+	if err != nil {
+		glog.V(2).Infof("error listing pods of vm %s/%s: %v", ns, name, err)
 		return
 	}
+	for _, pod := range pods {
+		if !ctrl.deletePod(ns, pod.Name) {
+			return
+		}
+	}
 	// TODO suppress podInformer from receiving delete event and subsequently
 	// requeueing the VM
+
+	// The garbage collector would get to it eventually, but don't leave ports
+	// exposed in the meantime
+	if svc, err := ctrl.serviceLister.Services(ns).Get(name); err == nil {
+		if ref := metav1.GetControllerOf(svc); ref != nil && ref.Kind == "VirtualMachine" {
+			ctrl.deleteService(ns, name)
+		}
+	}
 }
 
 func (ctrl *VirtualMachineController) vmWorker() {
@@ -224,6 +557,18 @@ func (ctrl *VirtualMachineController) podWorker() {
 	}
 }
 
+func (ctrl *VirtualMachineController) serviceFilterFunc(obj interface{}) bool {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	if svc, ok := obj.(*corev1.Service); ok {
+		if svcType, ok := svc.Labels["type"]; ok && svcType == "ranchervm" {
+			return true
+		}
+	}
+	return false
+}
+
 func (ctrl *VirtualMachineController) podFilterFunc(obj interface{}) bool {
 	if pod, ok := obj.(*corev1.Pod); ok {
 		if podType, ok := pod.Labels["type"]; ok && podType == "ranchervm" {
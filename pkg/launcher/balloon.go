@@ -0,0 +1,66 @@
+package launcher
+
+import (
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+const (
+	balloonPath = "/machine/peripheral/balloon0"
+	// balloonStatsInterval is how often, in seconds, the balloon driver
+	// reports guest memory statistics
+	balloonStatsInterval = 10
+)
+
+type balloonStats struct {
+	Stats struct {
+		TotalMemory     int64 `json:"stat-total-memory"`
+		AvailableMemory int64 `json:"stat-available-memory"`
+	} `json:"stats"`
+}
+
+// MemoryStats reports the memory left to the guest by the balloon and how
+// much of it the guest uses. Guests without a balloon driver only report
+// the former.
+func MemoryStats() (*vmapi.MemoryStatus, error) {
+	q, err := DialQMP()
+	if err != nil {
+		return nil, err
+	}
+	defer q.Close()
+
+	var balloon struct {
+		Actual int64 `json:"actual"`
+	}
+	if err := q.Execute("query-balloon", nil, &balloon); err != nil {
+		return nil, err
+	}
+	status := &vmapi.MemoryStatus{
+		ActualMB: balloon.Actual >> 20,
+	}
+
+	// Statistics are only collected once polling is enabled
+	if err := q.Execute("qom-set", map[string]interface{}{
+		"path":     balloonPath,
+		"property": "guest-stats-polling-interval",
+		"value":    balloonStatsInterval,
+	}, nil); err != nil {
+		return nil, err
+	}
+	var stats balloonStats
+	if err := q.Execute("qom-get", map[string]string{
+		"path":     balloonPath,
+		"property": "guest-stats",
+	}, &stats); err != nil {
+		return nil, err
+	}
+	// Statistics the guest doesn't report are -1
+	if stats.Stats.TotalMemory > 0 && stats.Stats.AvailableMemory >= 0 {
+		status.UsedMB = (stats.Stats.TotalMemory - stats.Stats.AvailableMemory) >> 20
+	}
+	return status, nil
+}
+
+// SetBalloon inflates or deflates the balloon to leave the guest targetMB
+func SetBalloon(targetMB int) error {
+	return ExecuteQMP("balloon", map[string]int64{"value": int64(targetMB) << 20}, nil)
+}
@@ -0,0 +1,50 @@
+package launcher
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// CloudInitImage is the NoCloud seed disk given to guests with cloud-init
+// config
+const CloudInitImage = RunDir + "/cloud-init.iso"
+
+// CreateCloudInitImage writes the NoCloud seed disk for config, if it has
+// cloud-init config
+func CreateCloudInitImage(config *Config) error {
+	if config.CloudInit == nil {
+		return nil
+	}
+	dir, err := ioutil.TempDir("", "cloud-init")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"meta-data": fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", config.CloudInit.InstanceID, config.Hostname),
+		"user-data": config.CloudInit.UserData,
+	}
+	if config.CloudInit.NetworkData != "" {
+		files["network-config"] = config.CloudInit.NetworkData
+	}
+	args := []string{"-output", CloudInitImage, "-volid", "cidata", "-joliet", "-rock"}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+			return err
+		}
+		args = append(args, path)
+	}
+
+	if err := os.MkdirAll(RunDir, 0700); err != nil {
+		return err
+	}
+	if out, err := exec.Command("genisoimage", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("genisoimage: %v: %s", err, out)
+	}
+	return nil
+}
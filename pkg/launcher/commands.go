@@ -0,0 +1,123 @@
+package launcher
+
+import (
+	"strconv"
+)
+
+// Binary is the path of the launcher within its image. The commands below
+// run its subcommands in launcher, disk server and job pods.
+const Binary = "/vm-launcher"
+
+// FreezeCommand and ThawCommand pause and resume the guest of a launcher pod
+var (
+	FreezeCommand = []string{Binary, "freeze"}
+	ThawCommand   = []string{Binary, "thaw"}
+)
+
+// MigrateStatusCommand prints the state of a launcher pod's outgoing
+// migration as JSON, and MigrateCancelCommand aborts it
+var (
+	MigrateStatusCommand = []string{Binary, "migrate-status"}
+	MigrateCancelCommand = []string{Binary, "migrate-cancel"}
+)
+
+// ShutdownCommand powers the guest of a launcher pod off and waits for it
+// to exit. It is the launcher's preStop hook.
+var ShutdownCommand = []string{Binary, "shutdown"}
+
+// GuestInfoCommand prints what the guest agent of a launcher pod reports
+// about the guest as JSON
+var GuestInfoCommand = []string{Binary, "guest-info"}
+
+// MemoryStatsCommand prints the guest memory of a launcher pod as JSON
+var MemoryStatsCommand = []string{Binary, "memory-stats"}
+
+// BalloonCommand balloons the guest of a launcher pod to targetMB
+func BalloonCommand(targetMB int64) []string {
+	return []string{Binary, "balloon", strconv.FormatInt(targetMB, 10)}
+}
+
+// HotplugCommand grows the guest of a launcher pod to vcpus and memoryMB,
+// printing what was hotplugged into it as JSON
+func HotplugCommand(vcpus, memoryMB int) []string {
+	return []string{Binary, "hotplug", strconv.Itoa(vcpus), strconv.Itoa(memoryMB)}
+}
+
+// ResizeDiskCommand grows the named disk of a launcher pod to fill its
+// claim, printing its size as JSON
+func ResizeDiskCommand(disk string) []string {
+	return []string{Binary, "resize-disk", disk}
+}
+
+// ServeDiskCommand exports the named disk of a disk server pod to the VMs
+// it is attached to
+func ServeDiskCommand(disk string) []string {
+	return []string{Binary, "serve-disk", disk}
+}
+
+// AttachDiskCommand plugs the named disk served by the pod at serverIP into
+// the guest of a launcher pod, and DetachDiskCommand unplugs it
+func AttachDiskCommand(disk, serverIP string) []string {
+	return []string{Binary, "attach-disk", disk, serverIP}
+}
+
+func DetachDiskCommand(disk string) []string {
+	return []string{Binary, "detach-disk", disk}
+}
+
+// BackupDiskCommand uploads the disk image at path as the named disk of the
+// backup at location, reusing the chunks of the parent backup if set. The
+// target is configured by the environment of the backup job.
+func BackupDiskCommand(image, location, disk, parent string) []string {
+	command := []string{Binary, "backup-disk", image, location, disk}
+	if parent != "" {
+		command = append(command, parent)
+	}
+	return command
+}
+
+// RestoreDiskCommand downloads the named disk of the backup at location to
+// the disk image at path
+func RestoreDiskCommand(location, disk, image string) []string {
+	return []string{Binary, "restore-disk", location, disk, image}
+}
+
+// ExportCommand converts the disks of an export pod to format and serves
+// the bundle
+func ExportCommand(format string) []string {
+	return []string{Binary, "export", format}
+}
+
+// ReadDescriptorCommand reads the descriptor of the bundle at url, writing
+// its manifest to the termination log
+func ReadDescriptorCommand(url string) []string {
+	return []string{Binary, "read-descriptor", url}
+}
+
+// ImportDiskCommand downloads the bundle at url and converts its file to the
+// raw disk image at path
+func ImportDiskCommand(url, file, image string) []string {
+	return []string{Binary, "import-disk", url, file, image}
+}
+
+// UploadCommand receives an uploaded image and converts it to the raw disk
+// image at path
+func UploadCommand(image string) []string {
+	return []string{Binary, "upload", image}
+}
+
+// MigrateCommand migrates the guest of a launcher pod to the incoming
+// launcher pod at targetIP
+func MigrateCommand(targetIP string) []string {
+	return []string{Binary, "migrate", targetIP}
+}
+
+// SerialCommand returns the command that attaches to the serial console from
+// within a launcher pod. If log is set, buffered output is printed instead.
+func SerialCommand(log bool) []string {
+	command := []string{Binary, "console"}
+	if log {
+		command = append(command, "--log")
+	}
+	return command
+}
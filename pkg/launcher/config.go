@@ -0,0 +1,163 @@
+package launcher
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+const (
+	// RunDir holds the sockets shared between QEMU and the launcher
+	RunDir = "/var/run/vm"
+
+	SerialSocket  = RunDir + "/serial.sock"
+	QMPSocket     = RunDir + "/qmp.sock"
+	ConsoleSocket = RunDir + "/console.sock"
+	// MigrationSocket is where an incoming QEMU reads the migration stream,
+	// relayed from MigrationPort by ServeIncomingMigration
+	MigrationSocket = RunDir + "/migration.sock"
+
+	// PodInfoDir is where the controller mounts the pod's annotations
+	PodInfoDir = "/etc/podinfo"
+)
+
+// Config describes the VM to launch. It is passed from the controller through
+// pod annotations.
+type Config struct {
+	// Namespace and Name identify the VirtualMachine
+	Namespace string
+	Name      string
+	CpuMillis int
+	MemoryMB  int
+	// MaxCpuMillis and MaxMemoryMB bound CPU and memory hotplug
+	MaxCpuMillis int
+	MaxMemoryMB  int
+	// Hotplugged are the devices hotplugged into the guest before it was
+	// migrated here
+	Hotplugged *HotplugState
+	Interfaces []vmapi.NetworkInterface
+	Ports      []vmapi.VirtualMachinePort
+	Disks      []vmapi.Disk
+	Hostname   string
+	// CloudInit has its instance ID resolved by the controller
+	CloudInit *vmapi.CloudInit
+	// AllowEmulation falls back to software emulation without KVM
+	AllowEmulation bool
+	// Incoming makes QEMU wait for the guest to be migrated in rather than
+	// booting it
+	Incoming bool
+	// MigrationSource is the address of the pod the guest is migrated from,
+	// the only one allowed to send the migration stream
+	MigrationSource string
+
+	DedicatedCPUPlacement bool
+	Hugepages             string
+	NUMAPassthrough       bool
+	// HostCPUs are the host CPUs the vCPUs are pinned to, picked by the
+	// launcher with DedicatedCPUs
+	HostCPUs []HostCPU
+}
+
+// VCPUs returns the number of vCPUs, rounding CpuMillis up
+func (c *Config) VCPUs() int {
+	return (c.CpuMillis + 999) / 1000
+}
+
+// MaxVCPUs returns how many vCPUs the guest can have
+func (c *Config) MaxVCPUs() int {
+	if max := (c.MaxCpuMillis + 999) / 1000; max > c.VCPUs() {
+		return max
+	}
+	return c.VCPUs()
+}
+
+// ReadAnnotations parses a downward API annotations file
+func ReadAnnotations(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	annotations := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		kv := strings.SplitN(scanner.Text(), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		value, err := strconv.Unquote(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing annotation %s: %v", kv[0], err)
+		}
+		annotations[kv[0]] = value
+	}
+	return annotations, scanner.Err()
+}
+
+// ConfigFromAnnotations builds the launcher config from pod annotations
+func ConfigFromAnnotations(annotations map[string]string) (*Config, error) {
+	var err error
+	config := &Config{
+		Namespace: annotations[ranchervm.GroupName+"/vm_namespace"],
+		Name:      annotations[ranchervm.GroupName+"/vm_name"],
+	}
+
+	if config.CpuMillis, err = strconv.Atoi(annotations[ranchervm.GroupName+"/cpu_milli"]); err != nil {
+		return nil, fmt.Errorf("invalid cpu_milli: %v", err)
+	}
+	if config.MemoryMB, err = strconv.Atoi(annotations[ranchervm.GroupName+"/memory_mb"]); err != nil {
+		return nil, fmt.Errorf("invalid memory_mb: %v", err)
+	}
+	if data, ok := annotations[ranchervm.GroupName+"/max_cpu_milli"]; ok {
+		if config.MaxCpuMillis, err = strconv.Atoi(data); err != nil {
+			return nil, fmt.Errorf("invalid max_cpu_milli: %v", err)
+		}
+	}
+	if data, ok := annotations[ranchervm.GroupName+"/max_memory_mb"]; ok {
+		if config.MaxMemoryMB, err = strconv.Atoi(data); err != nil {
+			return nil, fmt.Errorf("invalid max_memory_mb: %v", err)
+		}
+	}
+	if data, ok := annotations[AnnotationHotplugged]; ok {
+		config.Hotplugged = &HotplugState{}
+		if err := json.Unmarshal([]byte(data), config.Hotplugged); err != nil {
+			return nil, fmt.Errorf("invalid hotplugged: %v", err)
+		}
+	}
+	if data, ok := annotations[ranchervm.GroupName+"/interfaces"]; ok {
+		if err := json.Unmarshal([]byte(data), &config.Interfaces); err != nil {
+			return nil, fmt.Errorf("invalid interfaces: %v", err)
+		}
+	}
+	if data, ok := annotations[ranchervm.GroupName+"/ports"]; ok {
+		if err := json.Unmarshal([]byte(data), &config.Ports); err != nil {
+			return nil, fmt.Errorf("invalid ports: %v", err)
+		}
+	}
+	config.Hostname = annotations[ranchervm.GroupName+"/hostname"]
+	if data, ok := annotations[ranchervm.GroupName+"/cloud_init"]; ok {
+		config.CloudInit = &vmapi.CloudInit{}
+		if err := json.Unmarshal([]byte(data), config.CloudInit); err != nil {
+			return nil, fmt.Errorf("invalid cloud_init: %v", err)
+		}
+	}
+	if data, ok := annotations[ranchervm.GroupName+"/disks"]; ok {
+		if err := json.Unmarshal([]byte(data), &config.Disks); err != nil {
+			return nil, fmt.Errorf("invalid disks: %v", err)
+		}
+	}
+	config.AllowEmulation = annotations[ranchervm.GroupName+"/allow_emulation"] == "true"
+	config.Incoming = annotations[ranchervm.GroupName+"/incoming_migration"] == "true"
+	config.MigrationSource = annotations[ranchervm.GroupName+"/migration_source"]
+	config.DedicatedCPUPlacement = annotations[ranchervm.GroupName+"/dedicated_cpu_placement"] == "true"
+	config.Hugepages = annotations[ranchervm.GroupName+"/hugepages"]
+	config.NUMAPassthrough = annotations[ranchervm.GroupName+"/numa_passthrough"] == "true"
+	return config, nil
+}
@@ -0,0 +1,131 @@
+package launcher
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+func annotations(kv ...string) map[string]string {
+	m := map[string]string{
+		ranchervm.GroupName + "/vm_namespace": "default",
+		ranchervm.GroupName + "/vm_name":      "vm1",
+		ranchervm.GroupName + "/cpu_milli":    "1000",
+		ranchervm.GroupName + "/memory_mb":    "512",
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i+1] == "" {
+			delete(m, ranchervm.GroupName+"/"+kv[i])
+			continue
+		}
+		m[ranchervm.GroupName+"/"+kv[i]] = kv[i+1]
+	}
+	return m
+}
+
+func TestConfigFromAnnotations(t *testing.T) {
+	base := Config{
+		Namespace: "default",
+		Name:      "vm1",
+		CpuMillis: 1000,
+		MemoryMB:  512,
+	}
+	with := func(f func(*Config)) *Config {
+		config := base
+		f(&config)
+		return &config
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *Config
+		wantErr     bool
+	}{
+		{
+			name:        "minimal",
+			annotations: annotations(),
+			want:        &base,
+		},
+		{
+			name:        "missing cpu",
+			annotations: annotations("cpu_milli", ""),
+			wantErr:     true,
+		},
+		{
+			name:        "invalid memory",
+			annotations: annotations("memory_mb", "lots"),
+			wantErr:     true,
+		},
+		{
+			name:        "hotplug limits",
+			annotations: annotations("max_cpu_milli", "4000", "max_memory_mb", "2048"),
+			want: with(func(c *Config) {
+				c.MaxCpuMillis = 4000
+				c.MaxMemoryMB = 2048
+			}),
+		},
+		{
+			name: "network",
+			annotations: annotations(
+				"interfaces", `[{"name":"default","binding":"bridge","mac_address":"52:54:00:00:00:01"}]`,
+				"ports", `[{"name":"ssh","port":22}]`,
+				"hostname", "guest"),
+			want: with(func(c *Config) {
+				c.Interfaces = []vmapi.NetworkInterface{
+					{Name: "default", Binding: vmapi.InterfaceBindingBridge, MACAddress: "52:54:00:00:00:01"},
+				}
+				c.Ports = []vmapi.VirtualMachinePort{{Name: "ssh", Port: 22}}
+				c.Hostname = "guest"
+			}),
+		},
+		{
+			name:        "invalid interfaces",
+			annotations: annotations("interfaces", `{"name":"default"}`),
+			wantErr:     true,
+		},
+		{
+			name:        "disks",
+			annotations: annotations("disks", `[{"name":"root","claim_name":"vm1-root"}]`),
+			want: with(func(c *Config) {
+				c.Disks = []vmapi.Disk{{Name: "root", ClaimName: "vm1-root"}}
+			}),
+		},
+		{
+			name: "flags",
+			annotations: annotations(
+				"allow_emulation", "true",
+				"incoming_migration", "true",
+				"migration_source", "10.42.0.7",
+				"dedicated_cpu_placement", "true",
+				"hugepages", "1Gi",
+				"numa_passthrough", "false"),
+			want: with(func(c *Config) {
+				c.AllowEmulation = true
+				c.Incoming = true
+				c.MigrationSource = "10.42.0.7"
+				c.DedicatedCPUPlacement = true
+				c.Hugepages = "1Gi"
+			}),
+		},
+	}
+
+	for _, test := range tests {
+		config, err := ConfigFromAnnotations(test.annotations)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(config, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, config, test.want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package launcher
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/golang/glog"
+)
+
+// HugepagesDir is where the controller mounts the hugepages backing guest
+// memory
+const HugepagesDir = "/dev/hugepages"
+
+// HugepageSizesMB are the supported hugepage sizes
+var HugepageSizesMB = map[string]int{
+	"2Mi": 2,
+	"1Gi": 1024,
+}
+
+// HostCPU is a host CPU dedicated to a vCPU
+type HostCPU struct {
+	ID   int
+	Node int
+}
+
+// DedicatedCPUs picks a host CPU for every vCPU among those the container
+// may run on, which the kubelet's CPU manager restricts to the CPUs it
+// dedicated to the pod. They are ordered by NUMA node, so that every guest
+// node gets consecutive vCPUs.
+func DedicatedCPUs(config *Config) ([]HostCPU, error) {
+	allowed, err := allowedCPUs()
+	if err != nil {
+		return nil, err
+	}
+	vcpus := config.VCPUs()
+	if len(allowed) < vcpus {
+		return nil, fmt.Errorf("%d vcpus need dedicated cpus, only %d are allowed", vcpus, len(allowed))
+	}
+	cpus := make([]HostCPU, len(allowed))
+	for i, id := range allowed {
+		cpus[i] = HostCPU{ID: id, Node: cpuNode(id)}
+	}
+	sort.Slice(cpus, func(i, j int) bool {
+		if cpus[i].Node != cpus[j].Node {
+			return cpus[i].Node < cpus[j].Node
+		}
+		return cpus[i].ID < cpus[j].ID
+	})
+	return cpus[:vcpus], nil
+}
+
+// allowedCPUs returns the CPUs the launcher may run on
+func allowedCPUs() ([]int, error) {
+	f, err := os.Open("/proc/self/status")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "Cpus_allowed_list:") {
+			return parseCPUList(strings.TrimSpace(strings.TrimPrefix(line, "Cpus_allowed_list:")))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("no Cpus_allowed_list in /proc/self/status")
+}
+
+// parseCPUList parses a kernel CPU list such as 0-3,8,10-11
+func parseCPUList(list string) ([]int, error) {
+	var cpus []int
+	for _, r := range strings.Split(list, ",") {
+		bounds := strings.SplitN(r, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpu list %q", list)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil || last < first {
+				return nil, fmt.Errorf("invalid cpu list %q", list)
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// cpuNode returns the NUMA node of a host CPU, 0 on hosts without NUMA
+func cpuNode(cpu int) int {
+	entries, err := ioutil.ReadDir(fmt.Sprintf("/sys/devices/system/cpu/cpu%d", cpu))
+	if err != nil {
+		return 0
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "node") {
+			continue
+		}
+		if node, err := strconv.Atoi(strings.TrimPrefix(entry.Name(), "node")); err == nil {
+			return node
+		}
+	}
+	return 0
+}
+
+// guestNode is a guest NUMA node backed by a host node
+type guestNode struct {
+	hostNode  int
+	firstVCPU int
+	lastVCPU  int
+	memoryMB  int
+}
+
+// guestNodes mirrors the NUMA nodes of the dedicated CPUs in the guest.
+// Guest memory is split across nodes in proportion to their vCPUs, in whole
+// pages.
+func guestNodes(config *Config) []guestNode {
+	var nodes []guestNode
+	for i, cpu := range config.HostCPUs {
+		if len(nodes) == 0 || nodes[len(nodes)-1].hostNode != cpu.Node {
+			nodes = append(nodes, guestNode{hostNode: cpu.Node, firstVCPU: i})
+		}
+		nodes[len(nodes)-1].lastVCPU = i
+	}
+
+	pageMB := 1
+	if size, ok := HugepageSizesMB[config.Hugepages]; ok {
+		pageMB = size
+	}
+	pages := config.MemoryMB / pageMB
+	assigned := 0
+	for i := range nodes {
+		vcpus := nodes[i].lastVCPU - nodes[i].firstVCPU + 1
+		nodePages := pages * vcpus / len(config.HostCPUs)
+		nodes[i].memoryMB = nodePages * pageMB
+		assigned += nodePages
+	}
+	// Rounding leftovers go to the first node
+	nodes[0].memoryMB += (pages - assigned) * pageMB
+	return nodes
+}
+
+// memoryArgs returns the QEMU arguments backing guest memory
+func memoryArgs(config *Config) []string {
+	if config.NUMAPassthrough && len(config.HostCPUs) > 0 {
+		var args []string
+		for i, node := range guestNodes(config) {
+			backend := fmt.Sprintf("memory-backend-ram,id=mem%d,size=%dM", i, node.memoryMB)
+			if config.Hugepages != "" {
+				backend = fmt.Sprintf("memory-backend-file,id=mem%d,size=%dM,mem-path=%s,prealloc=on", i, node.memoryMB, HugepagesDir)
+			}
+			backend += fmt.Sprintf(",host-nodes=%d,policy=bind", node.hostNode)
+			args = append(args,
+				"-object", backend,
+				"-numa", fmt.Sprintf("node,nodeid=%d,cpus=%d-%d,memdev=mem%d", i, node.firstVCPU, node.lastVCPU, i))
+		}
+		return args
+	}
+	if config.Hugepages != "" {
+		return []string{"-mem-path", HugepagesDir, "-mem-prealloc"}
+	}
+	return nil
+}
+
+// PinVCPUs pins every vCPU thread of the QEMU just started to its dedicated
+// host CPU
+func PinVCPUs(config *Config) error {
+	var cpus []struct {
+		CPU      int `json:"CPU"`
+		ThreadID int `json:"thread_id"`
+	}
+	// QEMU creates its QMP socket as it starts
+	var err error
+	for i := 0; i < 20; i++ {
+		if err = ExecuteQMP("query-cpus", nil, &cpus); err == nil {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	if err != nil {
+		return fmt.Errorf("error querying vcpus: %v", err)
+	}
+
+	for _, cpu := range cpus {
+		if cpu.CPU >= len(config.HostCPUs) {
+			return fmt.Errorf("vcpu %d has no dedicated cpu", cpu.CPU)
+		}
+		host := config.HostCPUs[cpu.CPU].ID
+		if err := setAffinity(cpu.ThreadID, host); err != nil {
+			return fmt.Errorf("error pinning vcpu %d to cpu %d: %v", cpu.CPU, host, err)
+		}
+		glog.V(2).Infof("Pinned vcpu %d to cpu %d", cpu.CPU, host)
+	}
+	return nil
+}
+
+// setAffinity restricts a thread to a single CPU
+func setAffinity(tid, cpu int) error {
+	var mask [16]uint64
+	if cpu >= len(mask)*64 {
+		return fmt.Errorf("cpu %d out of range", cpu)
+	}
+	mask[cpu/64] |= 1 << uint(cpu%64)
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY,
+		uintptr(tid), uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package launcher
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCPUList(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []int
+		wantErr bool
+	}{
+		{list: "0", want: []int{0}},
+		{list: "0-3", want: []int{0, 1, 2, 3}},
+		{list: "0-3,8,10-11", want: []int{0, 1, 2, 3, 8, 10, 11}},
+		{list: "5-5", want: []int{5}},
+		{list: "", wantErr: true},
+		{list: "0,", wantErr: true},
+		{list: "a-3", wantErr: true},
+		{list: "0-b", wantErr: true},
+		{list: "3-1", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := parseCPUList(test.list)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: got error %v, want error %v", test.list, err, test.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.list, got, test.want)
+		}
+	}
+}
+
+func TestGuestNodes(t *testing.T) {
+	cpus := func(nodes ...int) []HostCPU {
+		var cpus []HostCPU
+		for i, node := range nodes {
+			cpus = append(cpus, HostCPU{ID: i, Node: node})
+		}
+		return cpus
+	}
+
+	tests := []struct {
+		name   string
+		config Config
+		want   []guestNode
+	}{
+		{
+			name:   "single node",
+			config: Config{MemoryMB: 1024, HostCPUs: cpus(0, 0)},
+			want:   []guestNode{{hostNode: 0, firstVCPU: 0, lastVCPU: 1, memoryMB: 1024}},
+		},
+		{
+			name:   "even split",
+			config: Config{MemoryMB: 2048, HostCPUs: cpus(0, 0, 1, 1)},
+			want: []guestNode{
+				{hostNode: 0, firstVCPU: 0, lastVCPU: 1, memoryMB: 1024},
+				{hostNode: 1, firstVCPU: 2, lastVCPU: 3, memoryMB: 1024},
+			},
+		},
+		{
+			name:   "split by vcpus with leftovers on the first node",
+			config: Config{MemoryMB: 1000, HostCPUs: cpus(1, 3, 3)},
+			want: []guestNode{
+				{hostNode: 1, firstVCPU: 0, lastVCPU: 0, memoryMB: 334},
+				{hostNode: 3, firstVCPU: 1, lastVCPU: 2, memoryMB: 666},
+			},
+		},
+		{
+			name:   "whole hugepages",
+			config: Config{MemoryMB: 3072, Hugepages: "1Gi", HostCPUs: cpus(0, 1)},
+			want: []guestNode{
+				{hostNode: 0, firstVCPU: 0, lastVCPU: 0, memoryMB: 2048},
+				{hostNode: 1, firstVCPU: 1, lastVCPU: 1, memoryMB: 1024},
+			},
+		},
+	}
+	for _, test := range tests {
+		got := guestNodes(&test.config)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+		total := 0
+		for _, node := range got {
+			total += node.memoryMB
+		}
+		if total != test.config.MemoryMB {
+			t.Errorf("%s: nodes have %d MB, want %d", test.name, total, test.config.MemoryMB)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/golang/glog"
+)
+
+const (
+	dhcpServerPort = 67
+	dhcpClientPort = 68
+
+	dhcpBootRequest = 1
+	dhcpBootReply   = 2
+
+	dhcpDiscover = 1
+	dhcpOffer    = 2
+	dhcpRequest  = 3
+	dhcpAck      = 5
+	dhcpNak      = 6
+
+	dhcpOptPad          = 0
+	dhcpOptSubnetMask   = 1
+	dhcpOptRouter       = 3
+	dhcpOptDNS          = 6
+	dhcpOptHostname     = 12
+	dhcpOptDomainName   = 15
+	dhcpOptMTU          = 26
+	dhcpOptRequestedIP  = 50
+	dhcpOptLeaseTime    = 51
+	dhcpOptMessageType  = 53
+	dhcpOptServerID     = 54
+	dhcpOptDomainSearch = 119
+	dhcpOptRoutes       = 121
+	dhcpOptEnd          = 255
+
+	// dhcpHeaderLen is the fixed BOOTP header plus the options magic cookie
+	dhcpHeaderLen = 240
+
+	// The pod address is the guest's for as long as the pod lives
+	dhcpInfiniteLease = 0xffffffff
+)
+
+var dhcpMagicCookie = []byte{99, 130, 83, 99}
+
+// DHCPLease is the configuration handed to a bridged guest interface. It
+// is taken from the pod interface before its address is released.
+type DHCPLease struct {
+	MAC      net.HardwareAddr
+	IP       net.IP
+	Mask     net.IPMask
+	Gateway  net.IP
+	DNS      []net.IP
+	Search   []string
+	Hostname string
+	MTU      int
+}
+
+// DHCPServer answers the guest on a bridge with the address its pod
+// interface had, so that bridged guests can configure themselves.
+type DHCPServer struct {
+	bridge string
+	// serverIP is a link-local address on the bridge identifying the server
+	serverIP net.IP
+	lease    *DHCPLease
+}
+
+func NewDHCPServer(bridge string, serverIP net.IP, lease *DHCPLease) *DHCPServer {
+	return &DHCPServer{
+		bridge:   bridge,
+		serverIP: serverIP.To4(),
+		lease:    lease,
+	}
+}
+
+// Run answers DHCP requests on the bridge until stopCh is closed
+func (s *DHCPServer) Run(stopCh <-chan struct{}) error {
+	conn, err := listenDHCP(s.bridge)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-stopCh
+		conn.Close()
+	}()
+
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			select {
+			case <-stopCh:
+				return nil
+			default:
+			}
+			return err
+		}
+		reply := s.reply(buf[:n])
+		if reply == nil {
+			continue
+		}
+		// The guest has no address to answer to yet
+		if _, err := conn.WriteTo(reply, &net.UDPAddr{IP: net.IPv4bcast, Port: dhcpClientPort}); err != nil {
+			glog.V(2).Infof("error sending dhcp reply on %s: %v", s.bridge, err)
+		}
+	}
+}
+
+// listenDHCP opens the DHCP server port on device only, as each bridge
+// runs its own server
+func listenDHCP(device string) (net.PacketConn, error) {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM, syscall.IPPROTO_UDP)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range []int{syscall.SO_REUSEADDR, syscall.SO_BROADCAST} {
+		if err := syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, opt, 1); err != nil {
+			syscall.Close(fd)
+			return nil, err
+		}
+	}
+	if err := syscall.SetsockoptString(fd, syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, device); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrInet4{Port: dhcpServerPort}); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	f := os.NewFile(uintptr(fd), "dhcp-"+device)
+	defer f.Close()
+	return net.FilePacketConn(f)
+}
+
+// reply returns the answer to a DHCP request, or nil if the request is
+// ignored
+func (s *DHCPServer) reply(req []byte) []byte {
+	if len(req) < dhcpHeaderLen || req[0] != dhcpBootRequest || !bytes.Equal(req[236:240], dhcpMagicCookie) {
+		return nil
+	}
+	// Only the guest interface bridged here gets an address
+	hlen := int(req[2])
+	if hlen != len(s.lease.MAC) || !bytes.Equal(req[28:28+hlen], s.lease.MAC) {
+		return nil
+	}
+
+	options := parseDHCPOptions(req[dhcpHeaderLen:])
+	msgType := byte(0)
+	if opt := options[dhcpOptMessageType]; len(opt) == 1 {
+		msgType = opt[0]
+	}
+
+	var replyType byte
+	switch msgType {
+	case dhcpDiscover:
+		replyType = dhcpOffer
+	case dhcpRequest:
+		requested := net.IP(options[dhcpOptRequestedIP])
+		if len(requested) != net.IPv4len {
+			// Renewing clients name their address in ciaddr instead
+			requested = net.IP(req[12:16])
+		}
+		replyType = dhcpAck
+		if !requested.Equal(net.IPv4zero) && !requested.Equal(s.lease.IP) {
+			replyType = dhcpNak
+		}
+	default:
+		return nil
+	}
+
+	resp := make([]byte, dhcpHeaderLen, 576)
+	resp[0] = dhcpBootReply
+	// htype, hlen, xid, secs, flags and ciaddr are echoed back
+	copy(resp[1:16], req[1:16])
+	resp[3] = 0
+	// giaddr and chaddr too
+	copy(resp[24:44], req[24:44])
+	copy(resp[236:240], dhcpMagicCookie)
+
+	resp = appendDHCPOption(resp, dhcpOptMessageType, []byte{replyType})
+	resp = appendDHCPOption(resp, dhcpOptServerID, s.serverIP)
+	if replyType == dhcpNak {
+		return append(resp, dhcpOptEnd)
+	}
+	copy(resp[16:20], s.lease.IP.To4())
+	return s.appendLeaseOptions(resp)
+}
+
+func (s *DHCPServer) appendLeaseOptions(resp []byte) []byte {
+	lease := s.lease
+	leaseTime := make([]byte, 4)
+	binary.BigEndian.PutUint32(leaseTime, dhcpInfiniteLease)
+	resp = appendDHCPOption(resp, dhcpOptLeaseTime, leaseTime)
+	resp = appendDHCPOption(resp, dhcpOptSubnetMask, []byte(lease.Mask))
+
+	if gateway := lease.Gateway.To4(); gateway != nil {
+		resp = appendDHCPOption(resp, dhcpOptRouter, gateway)
+		// Pod networks often hand out /32 addresses with a gateway outside
+		// the subnet, which needs an explicit route to be reachable
+		routes := append([]byte{32}, gateway...)
+		routes = append(routes, 0, 0, 0, 0)
+		routes = append(routes, 0)
+		routes = append(routes, gateway...)
+		resp = appendDHCPOption(resp, dhcpOptRoutes, routes)
+	}
+	if len(lease.DNS) > 0 {
+		dns := []byte{}
+		for _, ip := range lease.DNS {
+			if ip4 := ip.To4(); ip4 != nil {
+				dns = append(dns, ip4...)
+			}
+		}
+		resp = appendDHCPOption(resp, dhcpOptDNS, dns)
+	}
+	if len(lease.Search) > 0 {
+		resp = appendDHCPOption(resp, dhcpOptDomainName, []byte(lease.Search[0]))
+		resp = appendDHCPOption(resp, dhcpOptDomainSearch, encodeDomainSearch(lease.Search))
+	}
+	if lease.Hostname != "" {
+		resp = appendDHCPOption(resp, dhcpOptHostname, []byte(lease.Hostname))
+	}
+	if lease.MTU > 0 {
+		mtu := make([]byte, 2)
+		binary.BigEndian.PutUint16(mtu, uint16(lease.MTU))
+		resp = appendDHCPOption(resp, dhcpOptMTU, mtu)
+	}
+	return append(resp, dhcpOptEnd)
+}
+
+// parseDHCPOptions returns the options of a DHCP message by code
+func parseDHCPOptions(data []byte) map[byte][]byte {
+	options := map[byte][]byte{}
+	for len(data) > 0 {
+		code := data[0]
+		if code == dhcpOptEnd {
+			break
+		}
+		if code == dhcpOptPad {
+			data = data[1:]
+			continue
+		}
+		if len(data) < 2 || len(data) < 2+int(data[1]) {
+			break
+		}
+		options[code] = data[2 : 2+int(data[1])]
+		data = data[2+int(data[1]):]
+	}
+	return options
+}
+
+// appendDHCPOption appends an option, splitting values over 255 bytes as
+// RFC 3396 describes
+func appendDHCPOption(data []byte, code byte, value []byte) []byte {
+	for {
+		n := len(value)
+		if n > 255 {
+			n = 255
+		}
+		data = append(data, code, byte(n))
+		data = append(data, value[:n]...)
+		value = value[n:]
+		if len(value) == 0 {
+			return data
+		}
+	}
+}
+
+// encodeDomainSearch encodes domains as DNS names, which is what the
+// domain search option carries
+func encodeDomainSearch(domains []string) []byte {
+	data := []byte{}
+	for _, domain := range domains {
+		for _, label := range strings.Split(strings.TrimSuffix(domain, "."), ".") {
+			if label == "" || len(label) > 63 {
+				continue
+			}
+			data = append(data, byte(len(label)))
+			data = append(data, label...)
+		}
+		data = append(data, 0)
+	}
+	return data
+}
+
+// PodLease reads the address configuration of a pod interface. Returns nil
+// if the interface has no IPv4 address, as with secondary networks
+// without IPAM.
+func PodLease(podIface string) (*DHCPLease, error) {
+	iface, err := net.InterfaceByName(podIface)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	var lease *DHCPLease
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok || ipnet.IP.To4() == nil {
+			continue
+		}
+		lease = &DHCPLease{
+			IP:   ipnet.IP.To4(),
+			Mask: ipnet.Mask,
+			MTU:  iface.MTU,
+		}
+		break
+	}
+	if lease == nil {
+		return nil, nil
+	}
+
+	routes, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer routes.Close()
+	if lease.Gateway, err = parseDefaultGateway(routes, podIface); err != nil {
+		return nil, err
+	}
+
+	resolvConf, err := os.Open("/etc/resolv.conf")
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		defer resolvConf.Close()
+		if lease.DNS, lease.Search, err = parseResolvConf(resolvConf); err != nil {
+			return nil, err
+		}
+	}
+	return lease, nil
+}
+
+// parseDefaultGateway returns the gateway of the default route through
+// iface in /proc/net/route format, or nil if there is none
+func parseDefaultGateway(r io.Reader, iface string) (net.IP, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[0] != iface || fields[1] != "00000000" {
+			continue
+		}
+		gateway, err := strconv.ParseUint(fields[2], 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gateway %q: %v", fields[2], err)
+		}
+		// Addresses are in host byte order
+		ip := make(net.IP, net.IPv4len)
+		binary.LittleEndian.PutUint32(ip, uint32(gateway))
+		return ip, nil
+	}
+	return nil, scanner.Err()
+}
+
+// parseResolvConf returns the name servers and search domains of a
+// resolv.conf
+func parseResolvConf(r io.Reader) ([]net.IP, []string, error) {
+	var servers []net.IP
+	var search []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			if ip := net.ParseIP(fields[1]); ip != nil && ip.To4() != nil {
+				servers = append(servers, ip)
+			}
+		case "search", "domain":
+			search = fields[1:]
+		}
+	}
+	return servers, search, scanner.Err()
+}
@@ -0,0 +1,153 @@
+package launcher
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+)
+
+func dhcpPacket(mac net.HardwareAddr, msgType byte, ciaddr net.IP, options ...[]byte) []byte {
+	req := make([]byte, dhcpHeaderLen)
+	req[0] = dhcpBootRequest
+	req[1] = 1
+	req[2] = byte(len(mac))
+	copy(req[4:8], []byte{1, 2, 3, 4})
+	if ciaddr != nil {
+		copy(req[12:16], ciaddr.To4())
+	}
+	copy(req[28:], mac)
+	copy(req[236:240], dhcpMagicCookie)
+	req = appendDHCPOption(req, dhcpOptMessageType, []byte{msgType})
+	for _, opt := range options {
+		req = appendDHCPOption(req, opt[0], opt[1:])
+	}
+	return append(req, dhcpOptEnd)
+}
+
+func TestDHCPReply(t *testing.T) {
+	guest, _ := net.ParseMAC("52:54:00:00:00:01")
+	other, _ := net.ParseMAC("52:54:00:00:00:02")
+	server := NewDHCPServer("br0", net.IPv4(169, 254, 75, 10), &DHCPLease{
+		MAC:      guest,
+		IP:       net.IPv4(10, 42, 0, 5).To4(),
+		Mask:     net.CIDRMask(32, 32),
+		Gateway:  net.IPv4(169, 254, 1, 1).To4(),
+		DNS:      []net.IP{net.IPv4(10, 43, 0, 10)},
+		Search:   []string{"default.svc.cluster.local", "cluster.local"},
+		Hostname: "guest",
+		MTU:      1450,
+	})
+	requested := append([]byte{dhcpOptRequestedIP}, 10, 42, 0, 5)
+	wrongIP := append([]byte{dhcpOptRequestedIP}, 10, 42, 0, 6)
+
+	tests := []struct {
+		name     string
+		req      []byte
+		wantType byte
+	}{
+		{"discover", dhcpPacket(guest, dhcpDiscover, nil), dhcpOffer},
+		{"request", dhcpPacket(guest, dhcpRequest, nil, requested), dhcpAck},
+		{"renew", dhcpPacket(guest, dhcpRequest, net.IPv4(10, 42, 0, 5)), dhcpAck},
+		{"request for another address", dhcpPacket(guest, dhcpRequest, nil, wrongIP), dhcpNak},
+		{"other guest", dhcpPacket(other, dhcpDiscover, nil), 0},
+		{"release", dhcpPacket(guest, 7, net.IPv4(10, 42, 0, 5)), 0},
+		{"truncated", dhcpPacket(guest, dhcpDiscover, nil)[:100], 0},
+	}
+
+	for _, test := range tests {
+		reply := server.reply(test.req)
+		if test.wantType == 0 {
+			if reply != nil {
+				t.Errorf("%s: unexpected reply", test.name)
+			}
+			continue
+		}
+		if reply == nil {
+			t.Errorf("%s: no reply", test.name)
+			continue
+		}
+		if reply[0] != dhcpBootReply || !bytes.Equal(reply[4:8], []byte{1, 2, 3, 4}) || !bytes.Equal(reply[28:34], guest) {
+			t.Errorf("%s: malformed reply header", test.name)
+		}
+		options := parseDHCPOptions(reply[dhcpHeaderLen:])
+		if got := options[dhcpOptMessageType]; len(got) != 1 || got[0] != test.wantType {
+			t.Errorf("%s: got message type %v, want %d", test.name, got, test.wantType)
+		}
+		if got := net.IP(options[dhcpOptServerID]); !got.Equal(net.IPv4(169, 254, 75, 10)) {
+			t.Errorf("%s: got server id %v", test.name, got)
+		}
+		if test.wantType == dhcpNak {
+			continue
+		}
+		if got := net.IP(reply[16:20]); !got.Equal(net.IPv4(10, 42, 0, 5)) {
+			t.Errorf("%s: got address %v", test.name, got)
+		}
+		if got := options[dhcpOptSubnetMask]; !bytes.Equal(got, []byte{255, 255, 255, 255}) {
+			t.Errorf("%s: got mask %v", test.name, got)
+		}
+		// The gateway sits outside the /32 and needs a route of its own
+		wantRoutes := []byte{32, 169, 254, 1, 1, 0, 0, 0, 0, 0, 169, 254, 1, 1}
+		if got := options[dhcpOptRoutes]; !bytes.Equal(got, wantRoutes) {
+			t.Errorf("%s: got routes %v, want %v", test.name, got, wantRoutes)
+		}
+		if got := string(options[dhcpOptHostname]); got != "guest" {
+			t.Errorf("%s: got hostname %q", test.name, got)
+		}
+		if got := options[dhcpOptMTU]; !bytes.Equal(got, []byte{0x05, 0xaa}) {
+			t.Errorf("%s: got mtu %v", test.name, got)
+		}
+	}
+}
+
+func TestEncodeDomainSearch(t *testing.T) {
+	got := encodeDomainSearch([]string{"svc.cluster.local", "local."})
+	want := []byte("\x03svc\x07cluster\x05local\x00\x05local\x00")
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseDefaultGateway(t *testing.T) {
+	routes := `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+net1	00000000	0100A8C0	0003	0	0	0	00000000	0	0	0
+eth0	00000000	0101FEA9	0003	0	0	0	00000000	0	0	0
+eth0	0101FEA9	00000000	0005	0	0	0	FFFFFFFF	0	0	0
+`
+	tests := []struct {
+		iface string
+		want  net.IP
+	}{
+		{"eth0", net.IPv4(169, 254, 1, 1)},
+		{"net1", net.IPv4(192, 168, 0, 1)},
+		{"net2", nil},
+	}
+	for _, test := range tests {
+		got, err := parseDefaultGateway(strings.NewReader(routes), test.iface)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.iface, err)
+			continue
+		}
+		if !got.Equal(test.want) {
+			t.Errorf("%s: got %v, want %v", test.iface, got, test.want)
+		}
+	}
+}
+
+func TestParseResolvConf(t *testing.T) {
+	servers, search, err := parseResolvConf(strings.NewReader(`# generated
+nameserver 10.43.0.10
+nameserver fd00::10
+search default.svc.cluster.local svc.cluster.local
+options ndots:5
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(servers) != 1 || !servers[0].Equal(net.IPv4(10, 43, 0, 10)) {
+		t.Errorf("got servers %v", servers)
+	}
+	if len(search) != 2 || search[0] != "default.svc.cluster.local" {
+		t.Errorf("got search %v", search)
+	}
+}
@@ -0,0 +1,113 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/golang/glog"
+)
+
+// DiskDir is where the controller mounts the claims backing guest disks,
+// one directory per disk
+const DiskDir = "/var/lib/vm/disks"
+
+// DiskImage returns the path of the image of the named disk
+func DiskImage(name string) string {
+	return filepath.Join(DiskDir, name, "disk.img")
+}
+
+// PrepareDisks gives every disk without an image a sparse raw image filling
+// its claim
+func PrepareDisks(config *Config) error {
+	for _, disk := range config.Disks {
+		if err := PrepareDisk(disk.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrepareDisk gives the named disk a sparse raw image filling its claim,
+// or grows its image if the claim was expanded
+func PrepareDisk(name string) error {
+	path := DiskImage(name)
+	if _, err := os.Stat(path); err == nil {
+		_, err := growImage(path)
+		return err
+	} else if !os.IsNotExist(err) {
+		// Never create a blank image over one that can't be read
+		return fmt.Errorf("error checking image of disk %s: %v", name, err)
+	}
+
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(filepath.Dir(path), &fs); err != nil {
+		return err
+	}
+	// Leave the filesystem some headroom, aligned to 1MiB
+	size := int64(fs.Bavail) * int64(fs.Bsize) * 95 / 100
+	size &^= 1<<20 - 1
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0660)
+	if err != nil {
+		return err
+	}
+	err = f.Truncate(size)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	glog.Infof("Created %d byte image for disk %s", size, name)
+	return nil
+}
+
+// DiskSize is the size of a disk image and of the filesystem of its claim
+type DiskSize struct {
+	SizeMB       int64 `json:"size_mb"`
+	FilesystemMB int64 `json:"filesystem_mb"`
+}
+
+// growImage grows the sparse image at path to fill the filesystem of its
+// claim, which may have been expanded since the image was created
+func growImage(path string) (*DiskSize, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(filepath.Dir(path), &fs); err != nil {
+		return nil, err
+	}
+	// Images are sparse, so free space doesn't account for them. Leave the
+	// same headroom as new images.
+	total := int64(fs.Blocks) * int64(fs.Bsize)
+	size := total * 95 / 100
+	size &^= 1<<20 - 1
+
+	if size > info.Size() {
+		if err := os.Truncate(path, size); err != nil {
+			return nil, err
+		}
+		glog.Infof("Grew image %s from %d to %d bytes", path, info.Size(), size)
+	} else {
+		size = info.Size()
+	}
+	return &DiskSize{SizeMB: size >> 20, FilesystemMB: total >> 20}, nil
+}
+
+// ResizeDisk grows the image of the named disk to fill its claim, and the
+// disk of the running guest with it
+func ResizeDisk(name string) (*DiskSize, error) {
+	size, err := growImage(DiskImage(name))
+	if err != nil {
+		return nil, err
+	}
+	if err := ExecuteQMP("block_resize", map[string]interface{}{
+		"device": driveID(name),
+		"size":   size.SizeMB << 20,
+	}, nil); err != nil {
+		return nil, err
+	}
+	return size, nil
+}
@@ -0,0 +1,224 @@
+package launcher
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+	"github.com/llparse/kube-crd-skel/pkg/bundle"
+)
+
+// ExportPort is where export pods serve their bundle
+const ExportPort = 9104
+
+const (
+	// EnvBundleManifest holds the manifest of the VM an export pod bundles,
+	// as JSON
+	EnvBundleManifest = "BUNDLE_MANIFEST"
+	// EnvBundleToken holds the bearer token import pods download bundles
+	// with, if any
+	EnvBundleToken = "BUNDLE_TOKEN"
+	// EnvServerToken holds the bearer token export and upload pods require
+	// of requests. Only the console server, which authorizes users, is
+	// given it.
+	EnvServerToken = "SERVER_TOKEN"
+
+	// maxTerminationMessage is the most the kubelet reads of a termination
+	// log
+	maxTerminationMessage = 4096
+
+	// Only the descriptor at the start of a bundle is read by ReadDescriptor
+	descriptorTimeout = 30 * time.Second
+)
+
+// requireToken passes requests bearing the token of EnvServerToken on to
+// handler
+func requireToken(handler http.Handler) (http.Handler, error) {
+	token := os.Getenv(EnvServerToken)
+	if token == "" {
+		return nil, fmt.Errorf("%s is not set", EnvServerToken)
+	}
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}), nil
+}
+
+// Export converts the disks of the VM described by the environment to
+// format, then serves the bundle over HTTP until killed. The server only
+// listens once the bundle is ready, and only serves requests bearing the
+// token of EnvServerToken.
+func Export(format vmapi.ExportFormat) error {
+	// Converting takes a while, and would be in vain
+	if os.Getenv(EnvServerToken) == "" {
+		return fmt.Errorf("%s is not set", EnvServerToken)
+	}
+	manifest := &bundle.Manifest{}
+	if err := json.Unmarshal([]byte(os.Getenv(EnvBundleManifest)), manifest); err != nil {
+		return fmt.Errorf("invalid %s: %v", EnvBundleManifest, err)
+	}
+
+	var args []string
+	var extension string
+	switch format {
+	case vmapi.ExportFormatQcow2:
+		args, extension = []string{"-O", "qcow2"}, ".qcow2"
+	case vmapi.ExportFormatOVA:
+		args, extension = []string{"-O", "vmdk", "-o", "subformat=streamOptimized"}, ".vmdk"
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+
+	for i := range manifest.Disks {
+		disk := &manifest.Disks[i]
+		disk.File = disk.Name + extension
+		info, err := os.Stat(DiskImage(disk.Name))
+		if err != nil {
+			return err
+		}
+		disk.CapacityBytes = info.Size()
+
+		target := filepath.Join(ScratchDir, disk.File)
+		cmd := exec.Command("qemu-img", append(append([]string{"convert", "-f", "raw"}, args...), DiskImage(disk.Name), target)...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		glog.Infof("Converting disk %s to %s", disk.Name, disk.File)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("error converting disk %s: %v", disk.Name, err)
+		}
+		if info, err = os.Stat(target); err != nil {
+			return err
+		}
+		disk.SizeBytes = info.Size()
+	}
+
+	descriptorName, filename := bundle.ManifestFile, manifest.VirtualMachineName+".tar"
+	var descriptor []byte
+	var err error
+	if format == vmapi.ExportFormatOVA {
+		descriptorName, filename = manifest.VirtualMachineName+".ovf", manifest.VirtualMachineName+".ova"
+		descriptor, err = bundle.OVF(manifest)
+	} else {
+		descriptor, err = json.MarshalIndent(manifest, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	})
+	handler, err := requireToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-tar")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		if err := bundle.Write(w, descriptorName, descriptor, ScratchDir, manifest.Disks); err != nil {
+			glog.Errorf("error serving bundle: %v", err)
+		}
+	}))
+	if err != nil {
+		return err
+	}
+	mux.Handle("/", handler)
+	glog.Infof("Serving %s on port %d", filename, ExportPort)
+	return http.ListenAndServe(fmt.Sprintf(":%d", ExportPort), mux)
+}
+
+// getBundle starts downloading the bundle at url, with the bearer token of
+// EnvBundleToken if set
+func getBundle(client *http.Client, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := os.Getenv(EnvBundleToken); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// ReadDescriptor reads the manifest of the bundle at url and writes it to the
+// termination log as JSON, for the import controller. Only the descriptor at
+// the start of the bundle is downloaded.
+func ReadDescriptor(url string) error {
+	body, err := getBundle(&http.Client{Timeout: descriptorTimeout}, url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	manifest, err := bundle.Read(body)
+	if err != nil {
+		return err
+	}
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxTerminationMessage {
+		return fmt.Errorf("descriptor is too large, %d bytes as JSON", len(data))
+	}
+	glog.Infof("Read descriptor of vm %s with %d disks", manifest.VirtualMachineName, len(manifest.Disks))
+	return ioutil.WriteFile(TerminationLog, data, 0644)
+}
+
+// ImportDisk downloads a bundle from url and converts its file to a raw image
+// at path
+func ImportDisk(url, file, image string) error {
+	body, err := getBundle(http.DefaultClient, url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	r, size, err := bundle.Open(body, file)
+	if err != nil {
+		return err
+	}
+	scratch := filepath.Join(ScratchDir, "import")
+	f, err := os.Create(scratch)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(scratch)
+	glog.Infof("Downloading %s, %d bytes", file, size)
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	info, err := ConvertImage(scratch, image)
+	if err != nil {
+		return err
+	}
+	glog.Infof("Imported %s image %s, %d bytes", info.Format, file, info.VirtualSize)
+	return nil
+}
@@ -0,0 +1,77 @@
+package launcher
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/golang/glog"
+)
+
+// Freeze quiesces the guest so its disks can be copied consistently. The
+// guest agent flushes and freezes guest filesystems if it is running;
+// otherwise the vCPUs are paused, which leaves the disks crash-consistent.
+// QEMU flushes its own caches on pause.
+func Freeze() error {
+	a, err := DialGuestAgent()
+	if err != nil {
+		glog.Infof("Guest agent is unavailable, pausing guest: %v", err)
+		return ExecuteQMP("stop", nil, nil)
+	}
+	defer a.Close()
+
+	err = a.Execute("guest-fsfreeze-freeze", nil, nil)
+	if err == nil {
+		return nil
+	}
+	// The freeze may still reach the guest, so it is left to Thaw rather
+	// than pausing on top of it
+	if isTimeout(err) {
+		return fmt.Errorf("guest agent didn't confirm the freeze: %v", err)
+	}
+	glog.Infof("Guest agent can't freeze filesystems, pausing guest: %v", err)
+	return ExecuteQMP("stop", nil, nil)
+}
+
+// Thaw resumes a guest frozen by Freeze. The guest is resumed if it is
+// paused, and its filesystems are thawed if the agent reports them frozen,
+// since a freeze that timed out may have completed after the guest was
+// paused.
+func Thaw() error {
+	var status struct {
+		Status string `json:"status"`
+	}
+	if err := ExecuteQMP("query-status", nil, &status); err != nil {
+		return err
+	}
+	paused := status.Status == "paused"
+	if paused {
+		if err := ExecuteQMP("cont", nil, nil); err != nil {
+			return err
+		}
+	}
+
+	a, err := DialGuestAgent()
+	if err != nil {
+		// Guests without an agent are only ever paused
+		if paused {
+			glog.Infof("Guest agent is unavailable, not thawing filesystems: %v", err)
+			return nil
+		}
+		return err
+	}
+	defer a.Close()
+
+	var fsStatus string
+	if err := a.Execute("guest-fsfreeze-status", nil, &fsStatus); err != nil {
+		return err
+	}
+	if fsStatus == "frozen" {
+		return a.Execute("guest-fsfreeze-thaw", nil, nil)
+	}
+	return nil
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
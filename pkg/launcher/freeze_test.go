@@ -0,0 +1,235 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeQEMU serves QMP and guest agent sockets. Like the real agent, replies
+// the agent owes a client that went away are sent to the next one.
+type fakeQEMU struct {
+	mu         sync.Mutex
+	paused     bool
+	frozen     bool
+	noAgent    bool
+	hangFreeze bool
+	pending    []string
+}
+
+func (f *fakeQEMU) serveQMP(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		conn.Write([]byte(`{"QMP":{}}` + "\n"))
+		decoder := json.NewDecoder(conn)
+		for {
+			var cmd qmpCommand
+			if err := decoder.Decode(&cmd); err != nil {
+				break
+			}
+			f.mu.Lock()
+			ret := "{}"
+			switch cmd.Execute {
+			case "query-status":
+				ret = `{"status":"running"}`
+				if f.paused {
+					ret = `{"status":"paused"}`
+				}
+			case "stop":
+				f.paused = true
+			case "cont":
+				f.paused = false
+			}
+			f.mu.Unlock()
+			conn.Write([]byte(`{"return":` + ret + "}\n"))
+		}
+		conn.Close()
+	}
+}
+
+func (f *fakeQEMU) serveAgent(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		for _, reply := range f.pending {
+			conn.Write([]byte(reply))
+		}
+		f.pending = nil
+		f.mu.Unlock()
+
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				break
+			}
+			var cmd struct {
+				Execute   string           `json:"execute"`
+				Arguments map[string]int64 `json:"arguments"`
+			}
+			if err := json.Unmarshal(bytes.TrimLeft(line, "\xff"), &cmd); err != nil {
+				break
+			}
+			f.mu.Lock()
+			reply := `{"return":{}}` + "\n"
+			switch cmd.Execute {
+			case "guest-sync-delimited":
+				reply = "\xff" + `{"return":` + string(mustMarshal(cmd.Arguments["id"])) + "}\n"
+			case "guest-fsfreeze-freeze":
+				f.frozen = true
+				reply = `{"return":1}` + "\n"
+				if f.hangFreeze {
+					f.pending = append(f.pending, reply)
+					reply = ""
+				}
+			case "guest-fsfreeze-status":
+				reply = `{"return":"thawed"}` + "\n"
+				if f.frozen {
+					reply = `{"return":"frozen"}` + "\n"
+				}
+			case "guest-fsfreeze-thaw":
+				f.frozen = false
+				reply = `{"return":1}` + "\n"
+			}
+			f.mu.Unlock()
+			conn.Write([]byte(reply))
+		}
+		conn.Close()
+	}
+}
+
+func mustMarshal(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// start points the launcher at f's sockets until the returned function is
+// called
+func (f *fakeQEMU) start(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "freeze")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qmp, err := net.Listen("unix", filepath.Join(dir, "qmp.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go f.serveQMP(qmp)
+	var agent net.Listener
+	if !f.noAgent {
+		if agent, err = net.Listen("unix", filepath.Join(dir, "qga.sock")); err != nil {
+			t.Fatal(err)
+		}
+		go f.serveAgent(agent)
+	}
+
+	oldQMP, oldAgent, oldTimeout := qmpSocket, guestAgentSocket, guestAgentTimeout
+	qmpSocket = filepath.Join(dir, "qmp.sock")
+	guestAgentSocket = filepath.Join(dir, "qga.sock")
+	guestAgentTimeout = 200 * time.Millisecond
+	return func() {
+		qmpSocket, guestAgentSocket, guestAgentTimeout = oldQMP, oldAgent, oldTimeout
+		qmp.Close()
+		if agent != nil {
+			agent.Close()
+		}
+		os.RemoveAll(dir)
+	}
+}
+
+func (f *fakeQEMU) state() (paused, frozen bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.paused, f.frozen
+}
+
+func TestFreezeThaw(t *testing.T) {
+	tests := []struct {
+		name          string
+		qemu          *fakeQEMU
+		wantFreezeErr bool
+		// State after Freeze
+		wantPaused bool
+		wantFrozen bool
+	}{
+		{
+			name:       "agent freezes filesystems",
+			qemu:       &fakeQEMU{},
+			wantFrozen: true,
+		},
+		{
+			name:       "no agent pauses the guest",
+			qemu:       &fakeQEMU{noAgent: true},
+			wantPaused: true,
+		},
+		{
+			name:          "timed out freeze doesn't pause the guest",
+			qemu:          &fakeQEMU{hangFreeze: true},
+			wantFreezeErr: true,
+			wantFrozen:    true,
+		},
+	}
+	for _, test := range tests {
+		stop := test.qemu.start(t)
+		err := Freeze()
+		if (err != nil) != test.wantFreezeErr {
+			t.Errorf("%s: got freeze error %v, want error %v", test.name, err, test.wantFreezeErr)
+		}
+		if paused, frozen := test.qemu.state(); paused != test.wantPaused || frozen != test.wantFrozen {
+			t.Errorf("%s: after freeze got paused %v frozen %v, want %v and %v", test.name, paused, frozen, test.wantPaused, test.wantFrozen)
+		}
+		if err := Thaw(); err != nil {
+			t.Errorf("%s: error thawing: %v", test.name, err)
+		}
+		if paused, frozen := test.qemu.state(); paused || frozen {
+			t.Errorf("%s: after thaw got paused %v frozen %v", test.name, paused, frozen)
+		}
+		stop()
+	}
+}
+
+func TestThawPausedAndFrozen(t *testing.T) {
+	qemu := &fakeQEMU{paused: true, frozen: true}
+	defer qemu.start(t)()
+
+	if err := Thaw(); err != nil {
+		t.Fatalf("error thawing: %v", err)
+	}
+	if paused, frozen := qemu.state(); paused || frozen {
+		t.Errorf("got paused %v frozen %v, want a running guest", paused, frozen)
+	}
+}
+
+func TestDialGuestAgentSkipsLateReplies(t *testing.T) {
+	qemu := &fakeQEMU{pending: []string{`{"return":1}` + "\n", `{"return":"frozen"}` + "\n"}}
+	defer qemu.start(t)()
+
+	a, err := DialGuestAgent()
+	if err != nil {
+		t.Fatalf("error dialing agent: %v", err)
+	}
+	defer a.Close()
+	var fsStatus string
+	if err := a.Execute("guest-fsfreeze-status", nil, &fsStatus); err != nil {
+		t.Fatalf("error getting freeze status: %v", err)
+	}
+	if fsStatus != "thawed" {
+		t.Errorf("got status %q, want thawed", fsStatus)
+	}
+}
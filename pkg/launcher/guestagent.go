@@ -0,0 +1,224 @@
+package launcher
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// GuestAgentSocket is the host side of the virtio-serial channel to the QEMU
+// guest agent
+const GuestAgentSocket = RunDir + "/qga.sock"
+
+// guestAgentSocket is the socket DialGuestAgent connects to, replaced in
+// tests
+var guestAgentSocket = GuestAgentSocket
+
+// The agent may not be running in the guest at all, in which case commands
+// would block forever
+var guestAgentTimeout = 5 * time.Second
+
+// agentDelimiter precedes the response to guest-sync-delimited. It is never
+// valid in JSON.
+const agentDelimiter = 0xff
+
+// GuestAgent is a client of the QEMU guest agent. Like QMP, the agent
+// serves one client at a time.
+type GuestAgent struct {
+	conn    net.Conn
+	decoder *json.Decoder
+}
+
+// DialGuestAgent connects to the guest agent and syncs with it, discarding
+// any response left over from a previous client. A client that timed out
+// leaves the agent to reply later on the same channel, so a late reply must
+// not be mistaken for the answer to the next command.
+func DialGuestAgent() (*GuestAgent, error) {
+	conn, err := net.Dial("unix", guestAgentSocket)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(guestAgentTimeout))
+
+	// The delimiter resets the agent's parser if a previous client left a
+	// partial command behind
+	id := rand.Int63()
+	if _, err := conn.Write([]byte{agentDelimiter}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := json.NewEncoder(conn).Encode(qmpCommand{"guest-sync-delimited", map[string]int64{"id": id}}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// Everything up to the delimiter was meant for someone else
+	reader := bufio.NewReader(conn)
+	for {
+		if _, err := reader.ReadBytes(agentDelimiter); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error syncing with guest agent: %v", err)
+		}
+		decoder := json.NewDecoder(reader)
+		var resp qmpResponse
+		if err := decoder.Decode(&resp); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error syncing with guest agent: %v", err)
+		}
+		var ret int64
+		if json.Unmarshal(resp.Return, &ret) == nil && ret == id {
+			return &GuestAgent{
+				conn:    conn,
+				decoder: decoder,
+			}, nil
+		}
+		// A previous client's sync; keep what the decoder read ahead
+		reader = bufio.NewReader(io.MultiReader(decoder.Buffered(), reader))
+	}
+}
+
+// Execute runs command with arguments, decoding its return value into result
+// if it is not nil.
+func (a *GuestAgent) Execute(command string, arguments, result interface{}) error {
+	a.conn.SetDeadline(time.Now().Add(guestAgentTimeout))
+	if err := json.NewEncoder(a.conn).Encode(qmpCommand{command, arguments}); err != nil {
+		return err
+	}
+	var resp qmpResponse
+	if err := a.decoder.Decode(&resp); err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s: %s: %s", command, resp.Error.Class, resp.Error.Desc)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Return, result)
+}
+
+func (a *GuestAgent) Close() error {
+	return a.conn.Close()
+}
+
+// ExecuteGuestAgent runs a single command over a new guest agent connection
+func ExecuteGuestAgent(command string, arguments, result interface{}) error {
+	a, err := DialGuestAgent()
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+	return a.Execute(command, arguments, result)
+}
+
+type guestOSInfo struct {
+	Name          string `json:"name"`
+	PrettyName    string `json:"pretty-name"`
+	Version       string `json:"version"`
+	KernelRelease string `json:"kernel-release"`
+}
+
+type guestHostName struct {
+	HostName string `json:"host-name"`
+}
+
+type guestNetworkInterface struct {
+	Name            string `json:"name"`
+	HardwareAddress string `json:"hardware-address"`
+	IPAddresses     []struct {
+		Address string `json:"ip-address"`
+	} `json:"ip-addresses"`
+}
+
+type guestUser struct {
+	User      string  `json:"user"`
+	Domain    string  `json:"domain"`
+	LoginTime float64 `json:"login-time"`
+}
+
+type guestFilesystem struct {
+	Name       string `json:"name"`
+	MountPoint string `json:"mountpoint"`
+	Type       string `json:"type"`
+	TotalBytes int64  `json:"total-bytes"`
+	UsedBytes  int64  `json:"used-bytes"`
+}
+
+// GetGuestInfo collects what the guest agent knows about the guest. Only
+// the connection to the agent is required to succeed, as older agents lack
+// some of the commands.
+func GetGuestInfo() (*vmapi.GuestInfo, error) {
+	a, err := DialGuestAgent()
+	if err != nil {
+		return nil, err
+	}
+	defer a.Close()
+
+	info := &vmapi.GuestInfo{}
+
+	var osInfo guestOSInfo
+	if a.Execute("guest-get-osinfo", nil, &osInfo) == nil {
+		info.OSName = osInfo.PrettyName
+		if info.OSName == "" {
+			info.OSName = osInfo.Name
+		}
+		info.OSVersion = osInfo.Version
+		info.KernelRelease = osInfo.KernelRelease
+	}
+
+	var hostName guestHostName
+	if a.Execute("guest-get-host-name", nil, &hostName) == nil {
+		info.Hostname = hostName.HostName
+	}
+
+	var ifaces []guestNetworkInterface
+	if a.Execute("guest-network-get-interfaces", nil, &ifaces) == nil {
+		for _, iface := range ifaces {
+			if iface.Name == "lo" {
+				continue
+			}
+			status := vmapi.GuestInterface{
+				Name:       iface.Name,
+				MACAddress: iface.HardwareAddress,
+			}
+			for _, addr := range iface.IPAddresses {
+				status.IPs = append(status.IPs, addr.Address)
+			}
+			info.Interfaces = append(info.Interfaces, status)
+		}
+	}
+
+	var users []guestUser
+	if a.Execute("guest-get-users", nil, &users) == nil {
+		for _, user := range users {
+			sec := int64(user.LoginTime)
+			info.Users = append(info.Users, vmapi.GuestUser{
+				Name:      user.User,
+				Domain:    user.Domain,
+				LoginTime: metav1.Unix(sec, 0),
+			})
+		}
+	}
+
+	var filesystems []guestFilesystem
+	if a.Execute("guest-get-fsinfo", nil, &filesystems) == nil {
+		for _, fs := range filesystems {
+			info.Filesystems = append(info.Filesystems, vmapi.GuestFilesystem{
+				Name:       fs.Name,
+				MountPoint: fs.MountPoint,
+				Type:       fs.Type,
+				TotalBytes: fs.TotalBytes,
+				UsedBytes:  fs.UsedBytes,
+			})
+		}
+	}
+	return info, nil
+}
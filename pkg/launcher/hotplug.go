@@ -0,0 +1,204 @@
+package launcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/llparse/kube-crd-skel/pkg/apis/ranchervm"
+)
+
+const (
+	// AnnotationHotplugged records the devices hotplugged into the guest of
+	// a launcher pod
+	AnnotationHotplugged = ranchervm.GroupName + "/hotplugged"
+	// AnnotationHotpluggedRestarts is the restart count of the pod when its
+	// devices were recorded, 0 if unset. A launcher restarting in place
+	// starts a new guest without them.
+	AnnotationHotpluggedRestarts = ranchervm.GroupName + "/hotplugged_restarts"
+
+	// hotplugSlots is how many DIMMs can be plugged into a guest
+	hotplugSlots = 16
+	// hotplugPath is where QEMU places devices added with an ID
+	hotplugPath = "/machine/peripheral/"
+)
+
+// HotplugState lists the devices hotplugged into a running guest. The
+// target of a migration is started with them, as it must match the source.
+type HotplugState struct {
+	CPUs  []HotpluggedCPU  `json:"cpus,omitempty"`
+	DIMMs []HotpluggedDIMM `json:"dimms,omitempty"`
+}
+
+type HotpluggedCPU struct {
+	ID       string `json:"id"`
+	Driver   string `json:"driver"`
+	SocketID int    `json:"socket_id"`
+	CoreID   int    `json:"core_id"`
+	ThreadID int    `json:"thread_id"`
+}
+
+type HotpluggedDIMM struct {
+	ID     string `json:"id"`
+	SizeMB int    `json:"size_mb"`
+}
+
+// MemoryMB returns the hotplugged memory
+func (s *HotplugState) MemoryMB() int {
+	total := 0
+	for _, dimm := range s.DIMMs {
+		total += dimm.SizeMB
+	}
+	return total
+}
+
+// RecordedHotplugState returns the devices recorded as hotplugged into the
+// guest of a launcher pod, given its annotations and current restart count.
+// It returns nil if none are, or if the launcher restarted since.
+func RecordedHotplugState(annotations map[string]string, restarts int) (*HotplugState, error) {
+	data, ok := annotations[AnnotationHotplugged]
+	if !ok {
+		return nil, nil
+	}
+	recorded := 0
+	if value, ok := annotations[AnnotationHotpluggedRestarts]; ok {
+		var err error
+		if recorded, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("invalid hotplugged restarts: %v", err)
+		}
+	}
+	if recorded != restarts {
+		return nil, nil
+	}
+	state := &HotplugState{}
+	if err := json.Unmarshal([]byte(data), state); err != nil {
+		return nil, fmt.Errorf("invalid hotplugged: %v", err)
+	}
+	return state, nil
+}
+
+type hotpluggableCPU struct {
+	Type  string `json:"type"`
+	Props struct {
+		SocketID int `json:"socket-id"`
+		CoreID   int `json:"core-id"`
+		ThreadID int `json:"thread-id"`
+	} `json:"props"`
+	QOMPath string `json:"qom-path"`
+}
+
+type memoryDevice struct {
+	Type string `json:"type"`
+	Data struct {
+		ID   string `json:"id"`
+		Size int64  `json:"size"`
+	} `json:"data"`
+}
+
+// hotplugArgs returns the QEMU arguments recreating hotplugged devices
+func hotplugArgs(state *HotplugState) []string {
+	var args []string
+	for _, cpu := range state.CPUs {
+		args = append(args, "-device", fmt.Sprintf("%s,id=%s,socket-id=%d,core-id=%d,thread-id=%d",
+			cpu.Driver, cpu.ID, cpu.SocketID, cpu.CoreID, cpu.ThreadID))
+	}
+	for _, dimm := range state.DIMMs {
+		args = append(args,
+			"-object", fmt.Sprintf("memory-backend-ram,id=mem-%s,size=%dM", dimm.ID, dimm.SizeMB),
+			"-device", fmt.Sprintf("pc-dimm,id=%s,memdev=mem-%s", dimm.ID, dimm.ID))
+	}
+	return args
+}
+
+// Hotplug adds CPUs and memory to the guest until it has vcpus and
+// memoryMB, and returns what was hotplugged into it since it started. The
+// guest must bring the new CPUs and memory online.
+func Hotplug(config *Config, vcpus, memoryMB int) (*HotplugState, error) {
+	q, err := DialQMP()
+	if err != nil {
+		return nil, err
+	}
+	defer q.Close()
+
+	var cpus []hotpluggableCPU
+	if err := q.Execute("query-hotpluggable-cpus", nil, &cpus); err != nil {
+		return nil, err
+	}
+	plugged := 0
+	for _, cpu := range cpus {
+		if cpu.QOMPath != "" {
+			plugged++
+		}
+	}
+	for i := range cpus {
+		cpu := &cpus[i]
+		if plugged >= vcpus {
+			break
+		}
+		if cpu.QOMPath != "" {
+			continue
+		}
+		id := fmt.Sprintf("vcpu%d", cpu.Props.SocketID)
+		if err := q.Execute("device_add", map[string]interface{}{
+			"driver":    cpu.Type,
+			"id":        id,
+			"socket-id": cpu.Props.SocketID,
+			"core-id":   cpu.Props.CoreID,
+			"thread-id": cpu.Props.ThreadID,
+		}, nil); err != nil {
+			return nil, fmt.Errorf("error adding %s: %v", id, err)
+		}
+		cpu.QOMPath = hotplugPath + id
+		plugged++
+	}
+	if plugged < vcpus {
+		return nil, fmt.Errorf("only %d of %d vcpus fit", plugged, vcpus)
+	}
+
+	var devices []memoryDevice
+	if err := q.Execute("query-memory-devices", nil, &devices); err != nil {
+		return nil, err
+	}
+	state := &HotplugState{}
+	for _, dev := range devices {
+		if dev.Type == "dimm" {
+			state.DIMMs = append(state.DIMMs, HotpluggedDIMM{ID: dev.Data.ID, SizeMB: int(dev.Data.Size >> 20)})
+		}
+	}
+	if current := config.MemoryMB + state.MemoryMB(); memoryMB > current {
+		dimm := HotpluggedDIMM{
+			ID:     fmt.Sprintf("dimm%d", len(state.DIMMs)),
+			SizeMB: memoryMB - current,
+		}
+		if err := q.Execute("object-add", map[string]interface{}{
+			"qom-type": "memory-backend-ram",
+			"id":       "mem-" + dimm.ID,
+			"props":    map[string]int64{"size": int64(dimm.SizeMB) << 20},
+		}, nil); err != nil {
+			return nil, fmt.Errorf("error adding memory backend of %s: %v", dimm.ID, err)
+		}
+		if err := q.Execute("device_add", map[string]interface{}{
+			"driver": "pc-dimm",
+			"id":     dimm.ID,
+			"memdev": "mem-" + dimm.ID,
+		}, nil); err != nil {
+			return nil, fmt.Errorf("error adding %s: %v", dimm.ID, err)
+		}
+		state.DIMMs = append(state.DIMMs, dimm)
+	}
+
+	for _, cpu := range cpus {
+		if !strings.HasPrefix(cpu.QOMPath, hotplugPath) {
+			continue
+		}
+		state.CPUs = append(state.CPUs, HotpluggedCPU{
+			ID:       strings.TrimPrefix(cpu.QOMPath, hotplugPath),
+			Driver:   cpu.Type,
+			SocketID: cpu.Props.SocketID,
+			CoreID:   cpu.Props.CoreID,
+			ThreadID: cpu.Props.ThreadID,
+		})
+	}
+	return state, nil
+}
@@ -0,0 +1,69 @@
+package launcher
+
+import "testing"
+
+func TestRecordedHotplugState(t *testing.T) {
+	const state = `{"cpus":[{"id":"vcpu1","driver":"qemu64-x86_64-cpu","socket_id":1}],"dimms":[{"id":"dimm0","size_mb":512}]}`
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		restarts    int
+		wantCPUs    int
+		wantMB      int
+		wantNil     bool
+		wantErr     bool
+	}{
+		{
+			name:        "nothing hotplugged",
+			annotations: map[string]string{},
+			wantNil:     true,
+		},
+		{
+			name:        "recorded before any restart",
+			annotations: map[string]string{AnnotationHotplugged: state},
+			wantCPUs:    1,
+			wantMB:      512,
+		},
+		{
+			name:        "launcher restarted since",
+			annotations: map[string]string{AnnotationHotplugged: state},
+			restarts:    1,
+			wantNil:     true,
+		},
+		{
+			name:        "recorded after a restart",
+			annotations: map[string]string{AnnotationHotplugged: state, AnnotationHotpluggedRestarts: "2"},
+			restarts:    2,
+			wantCPUs:    1,
+			wantMB:      512,
+		},
+		{
+			name:        "invalid restarts",
+			annotations: map[string]string{AnnotationHotplugged: state, AnnotationHotpluggedRestarts: "x"},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid state",
+			annotations: map[string]string{AnnotationHotplugged: "{"},
+			wantErr:     true,
+		},
+	}
+	for _, test := range tests {
+		got, err := RecordedHotplugState(test.annotations, test.restarts)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error %v", test.name, err, test.wantErr)
+			continue
+		}
+		if test.wantErr {
+			continue
+		}
+		if (got == nil) != test.wantNil {
+			t.Errorf("%s: got %+v, want nil %v", test.name, got, test.wantNil)
+			continue
+		}
+		if got != nil && (len(got.CPUs) != test.wantCPUs || got.MemoryMB() != test.wantMB) {
+			t.Errorf("%s: got %d cpus and %dMB, want %d and %dMB", test.name, len(got.CPUs), got.MemoryMB(), test.wantCPUs, test.wantMB)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package launcher
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ScratchDir is where export, import and upload pods keep images while
+// converting them
+const ScratchDir = "/var/lib/vm/scratch"
+
+const (
+	// qcow2DataFile is the incompatible feature bit of qcow2 images keeping
+	// their data in another file
+	qcow2DataFile = 1 << 2
+	// vdiSignature is at offset 0x40 of VDI images
+	vdiSignature = 0xbeda107f
+	// maxVMDKDescriptor bounds the descriptor embedded in sparse VMDKs
+	maxVMDKDescriptor = 1 << 20
+)
+
+// vmdkCreateTypes are the VMDK variants keeping all their data in one file
+var vmdkCreateTypes = map[string]bool{
+	"monolithicSparse": true,
+	"streamOptimized":  true,
+}
+
+var vmdkCreateType = regexp.MustCompile(`(?m)^\s*createType\s*=\s*"([^"]*)"`)
+
+// ImageInfo is what qemu-img reports about an image
+type ImageInfo struct {
+	Format          string `json:"format"`
+	VirtualSize     int64  `json:"virtual-size"`
+	BackingFilename string `json:"backing-filename,omitempty"`
+}
+
+// ProbeImage returns the format and size of the image at path. Images are
+// untrusted, so the format is read from the image's header rather than
+// probed by QEMU, and only images keeping all their data in the one file are
+// accepted: backing files, external data files and VMDK descriptors could
+// point QEMU anywhere on the host.
+func ProbeImage(path string) (*ImageInfo, error) {
+	if err := checkFilename(path); err != nil {
+		return nil, err
+	}
+	format, err := probeFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := exec.Command("qemu-img", "info", "-f", format, "--output=json", path).Output()
+	if err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("qemu-img info: %v: %s", err, exit.Stderr)
+		}
+		return nil, err
+	}
+	var report struct {
+		ImageInfo
+		FormatSpecific struct {
+			Data struct {
+				DataFile string `json:"data-file"`
+			} `json:"data"`
+		} `json:"format-specific"`
+	}
+	if err := json.Unmarshal(out, &report); err != nil {
+		return nil, err
+	}
+	// The header was checked already, which QEMU confirms
+	if report.BackingFilename != "" {
+		return nil, fmt.Errorf("images with a backing file are not supported")
+	}
+	if report.FormatSpecific.Data.DataFile != "" {
+		return nil, fmt.Errorf("images with an external data file are not supported")
+	}
+	return &report.ImageInfo, nil
+}
+
+// ConvertImage converts the image at source, in any format ProbeImage
+// accepts, to a raw image at target
+func ConvertImage(source, target string) (*ImageInfo, error) {
+	if err := checkFilename(target); err != nil {
+		return nil, err
+	}
+	info, err := ProbeImage(source)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("qemu-img", "convert", "-f", info.Format, "-O", "raw", source, target)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error converting %s image: %v", info.Format, err)
+	}
+	return info, nil
+}
+
+// checkFilename refuses filenames QEMU would parse as options or protocols
+// rather than open as files
+func checkFilename(path string) error {
+	if strings.HasPrefix(path, "json:") || !filepath.IsAbs(path) {
+		return fmt.Errorf("invalid image filename %q", path)
+	}
+	return nil
+}
+
+// probeFormat returns the format of the image at path from its header. Files
+// in no other format are raw images.
+func probeFormat(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	return checkHeader(f, header[:n])
+}
+
+// checkHeader returns the format of the image with header, read from r
+func checkHeader(r io.ReaderAt, header []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(header, []byte("QFI\xfb")):
+		return "qcow2", checkQcow2(header)
+	case bytes.HasPrefix(header, []byte("KDMV")):
+		return "vmdk", checkVMDK(r, header)
+	case bytes.HasPrefix(header, []byte("# Disk DescriptorFile")):
+		return "", fmt.Errorf("VMDK descriptors refer to other files and are not supported")
+	case bytes.HasPrefix(header, []byte("vhdxfile")):
+		return "vhdx", nil
+	case bytes.HasPrefix(header, []byte("conectix")):
+		return "vpc", nil
+	case len(header) >= 0x44 && binary.LittleEndian.Uint32(header[0x40:]) == vdiSignature:
+		return "vdi", nil
+	}
+	return "raw", nil
+}
+
+// checkQcow2 refuses qcow2 images with a backing file or an external data
+// file
+func checkQcow2(header []byte) error {
+	if len(header) < 72 {
+		return fmt.Errorf("truncated qcow2 header")
+	}
+	if binary.BigEndian.Uint64(header[8:]) != 0 {
+		return fmt.Errorf("images with a backing file are not supported")
+	}
+	if version := binary.BigEndian.Uint32(header[4:]); version >= 3 {
+		if len(header) < 80 {
+			return fmt.Errorf("truncated qcow2 header")
+		}
+		if binary.BigEndian.Uint64(header[72:])&qcow2DataFile != 0 {
+			return fmt.Errorf("images with an external data file are not supported")
+		}
+	}
+	return nil
+}
+
+// checkVMDK refuses sparse VMDKs whose embedded descriptor makes them
+// anything but a single file
+func checkVMDK(r io.ReaderAt, header []byte) error {
+	if len(header) < 44 {
+		return fmt.Errorf("truncated VMDK header")
+	}
+	// Offsets are in sectors
+	offset := binary.LittleEndian.Uint64(header[28:])
+	size := binary.LittleEndian.Uint64(header[36:])
+	if size == 0 {
+		return nil
+	}
+	if size > maxVMDKDescriptor/512 || offset > 1<<40 {
+		return fmt.Errorf("invalid VMDK descriptor of %d sectors at sector %d", size, offset)
+	}
+	descriptor := make([]byte, size*512)
+	if _, err := r.ReadAt(descriptor, int64(offset)*512); err != nil && err != io.EOF {
+		return fmt.Errorf("error reading VMDK descriptor: %v", err)
+	}
+	match := vmdkCreateType.FindSubmatch(descriptor)
+	if match == nil {
+		return fmt.Errorf("VMDK descriptor has no createType")
+	}
+	if !vmdkCreateTypes[string(match[1])] {
+		return fmt.Errorf("VMDK images of type %s are not supported", match[1])
+	}
+	return nil
+}
+
+var (
+	// vhdxMetadataRegion and vhdxVirtualDiskSize are the GUIDs of the VHDX
+	// metadata region and its virtual disk size item, as laid out on disk
+	vhdxMetadataRegion  = []byte{0x06, 0xa2, 0x7c, 0x8b, 0x90, 0x47, 0x9a, 0x4b, 0xb8, 0xfe, 0x57, 0x5f, 0x05, 0x0f, 0x88, 0x6e}
+	vhdxVirtualDiskSize = []byte{0x24, 0x42, 0xa5, 0x2f, 0x1b, 0xcd, 0x76, 0x48, 0xb2, 0x11, 0x5d, 0xbe, 0xd8, 0x3b, 0xf4, 0xb8}
+)
+
+// VirtualSize returns the size of the disk in the image read from r, or 0 for
+// raw images and images whose header it can't read. It is larger than the
+// image for sparse formats.
+func VirtualSize(r io.ReaderAt) (int64, error) {
+	header := make([]byte, 512)
+	n, err := r.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	header = header[:n]
+	switch {
+	case bytes.HasPrefix(header, []byte("QFI\xfb")) && len(header) >= 32:
+		return int64(binary.BigEndian.Uint64(header[24:])), nil
+	case bytes.HasPrefix(header, []byte("KDMV")) && len(header) >= 20:
+		// Capacity is in sectors
+		return int64(binary.LittleEndian.Uint64(header[12:])) * 512, nil
+	case bytes.HasPrefix(header, []byte("conectix")) && len(header) >= 56:
+		// Dynamic VHDs start with a copy of their footer
+		return int64(binary.BigEndian.Uint64(header[48:])), nil
+	case len(header) >= 0x178 && binary.LittleEndian.Uint32(header[0x40:]) == vdiSignature:
+		return int64(binary.LittleEndian.Uint64(header[0x170:])), nil
+	case bytes.HasPrefix(header, []byte("vhdxfile")):
+		return vhdxVirtualSize(r)
+	}
+	return 0, nil
+}
+
+// vhdxVirtualSize reads the virtual disk size item of the VHDX metadata
+// region, found in the region table at 192 KiB
+func vhdxVirtualSize(r io.ReaderAt) (int64, error) {
+	regions := make([]byte, 64<<10)
+	if _, err := r.ReadAt(regions, 192<<10); err != nil && err != io.EOF {
+		return 0, err
+	}
+	if !bytes.HasPrefix(regions, []byte("regi")) {
+		return 0, nil
+	}
+	count := int(binary.LittleEndian.Uint32(regions[8:]))
+	var metadataOffset int64 = -1
+	for i := 0; i < count && 16+32*(i+1) <= len(regions); i++ {
+		entry := regions[16+32*i:]
+		if bytes.Equal(entry[:16], vhdxMetadataRegion) {
+			metadataOffset = int64(binary.LittleEndian.Uint64(entry[16:]))
+			break
+		}
+	}
+	if metadataOffset < 0 {
+		return 0, nil
+	}
+
+	table := make([]byte, 64<<10)
+	if _, err := r.ReadAt(table, metadataOffset); err != nil && err != io.EOF {
+		return 0, err
+	}
+	if !bytes.HasPrefix(table, []byte("metadata")) {
+		return 0, nil
+	}
+	count = int(binary.LittleEndian.Uint16(table[10:]))
+	for i := 0; i < count && 32+32*(i+1) <= len(table); i++ {
+		entry := table[32+32*i:]
+		if !bytes.Equal(entry[:16], vhdxVirtualDiskSize) {
+			continue
+		}
+		size := make([]byte, 8)
+		if _, err := r.ReadAt(size, metadataOffset+int64(binary.LittleEndian.Uint32(entry[16:]))); err != nil {
+			return 0, err
+		}
+		return int64(binary.LittleEndian.Uint64(size)), nil
+	}
+	return 0, nil
+}
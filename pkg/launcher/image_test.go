@@ -0,0 +1,145 @@
+package launcher
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func qcow2Header(version uint32, backingOffset, incompatible uint64) []byte {
+	header := make([]byte, 104)
+	copy(header, "QFI\xfb")
+	binary.BigEndian.PutUint32(header[4:], version)
+	binary.BigEndian.PutUint64(header[8:], backingOffset)
+	binary.BigEndian.PutUint64(header[72:], incompatible)
+	return header
+}
+
+// vmdkImage returns a sparse VMDK with descriptor embedded at sector 1
+func vmdkImage(descriptor string) []byte {
+	image := make([]byte, 2048)
+	copy(image, "KDMV")
+	binary.LittleEndian.PutUint64(image[28:], 1)
+	binary.LittleEndian.PutUint64(image[36:], 2)
+	copy(image[512:], descriptor)
+	return image
+}
+
+func TestCheckHeader(t *testing.T) {
+	vdi := make([]byte, 512)
+	copy(vdi, "<<< Oracle VM VirtualBox Disk Image >>>\n")
+	binary.LittleEndian.PutUint32(vdi[0x40:], vdiSignature)
+
+	tests := []struct {
+		name    string
+		image   []byte
+		want    string
+		wantErr string
+	}{
+		{name: "raw", image: make([]byte, 512), want: "raw"},
+		{name: "short raw", image: []byte("boot"), want: "raw"},
+		{name: "qcow2", image: qcow2Header(2, 0, 0), want: "qcow2"},
+		{name: "qcow2 v3", image: qcow2Header(3, 0, 1), want: "qcow2"},
+		{name: "qcow2 backing file", image: qcow2Header(3, 104, 0), wantErr: "backing file"},
+		{name: "qcow2 data file", image: qcow2Header(3, 0, qcow2DataFile), wantErr: "data file"},
+		{name: "truncated qcow2", image: qcow2Header(2, 0, 0)[:32], wantErr: "truncated"},
+		{name: "vmdk", image: vmdkImage("# Disk DescriptorFile\nversion=1\ncreateType=\"streamOptimized\"\n"), want: "vmdk"},
+		{name: "vmdk split", image: vmdkImage("# Disk DescriptorFile\ncreateType=\"twoGbMaxExtentSparse\"\n"), wantErr: "twoGbMaxExtentSparse"},
+		{name: "vmdk no create type", image: vmdkImage("# Disk DescriptorFile\n"), wantErr: "no createType"},
+		{name: "vmdk descriptor", image: []byte("# Disk DescriptorFile\nRW 8 FLAT \"/etc/shadow\" 0\n"), wantErr: "descriptors"},
+		{name: "vhdx", image: []byte("vhdxfile\x00\x00"), want: "vhdx"},
+		{name: "vpc", image: []byte("conectix\x00\x00"), want: "vpc"},
+		{name: "vdi", image: vdi, want: "vdi"},
+	}
+	for _, test := range tests {
+		header := test.image
+		if len(header) > 512 {
+			header = header[:512]
+		}
+		got, err := checkHeader(bytes.NewReader(test.image), header)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%s: got error %v, want %q", test.name, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("%s: got %q, %v, want %q", test.name, got, err, test.want)
+		}
+	}
+}
+
+func TestCheckFilename(t *testing.T) {
+	for _, path := range []string{"/var/lib/vm/scratch/upload", "/images/json:disk"} {
+		if err := checkFilename(path); err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+	for _, path := range []string{`json:{"file.filename":"/etc/shadow"}`, "nbd://host/disk", "disk.qcow2"} {
+		if err := checkFilename(path); err == nil {
+			t.Errorf("%s: got no error", path)
+		}
+	}
+}
+
+// vhdxImage returns a VHDX whose metadata region, at 1 MiB, holds size
+func vhdxImage(size uint64) []byte {
+	image := make([]byte, 2<<20)
+	copy(image, "vhdxfile")
+	regions := image[192<<10:]
+	copy(regions, "regi")
+	binary.LittleEndian.PutUint32(regions[8:], 2)
+	// The BAT region comes first
+	copy(regions[16:], "\x66\x77\xc2\x2d\x23\xf6\x00\x42\x9d\x64\x11\x5e\x9b\xfd\x4a\x08")
+	binary.LittleEndian.PutUint64(regions[32:], 3<<20)
+	copy(regions[48:], vhdxMetadataRegion)
+	binary.LittleEndian.PutUint64(regions[64:], 1<<20)
+
+	metadata := image[1<<20:]
+	copy(metadata, "metadata")
+	binary.LittleEndian.PutUint16(metadata[10:], 2)
+	copy(metadata[32:], "\x37\x67\xa1\xca\x36\xfa\x43\x4d\xb3\xb6\x33\xf0\xaa\x44\xe7\x6b")
+	binary.LittleEndian.PutUint32(metadata[48:], 64<<10)
+	copy(metadata[64:], vhdxVirtualDiskSize)
+	binary.LittleEndian.PutUint32(metadata[80:], 64<<10+8)
+	binary.LittleEndian.PutUint64(metadata[64<<10+8:], size)
+	return image
+}
+
+func TestVirtualSize(t *testing.T) {
+	qcow2 := qcow2Header(3, 0, 0)
+	binary.BigEndian.PutUint64(qcow2[24:], 10<<30)
+
+	vmdk := vmdkImage("")
+	binary.LittleEndian.PutUint64(vmdk[12:], 4<<20)
+
+	vhd := make([]byte, 512)
+	copy(vhd, "conectix")
+	binary.BigEndian.PutUint64(vhd[48:], 3<<30)
+
+	vdi := make([]byte, 512)
+	binary.LittleEndian.PutUint32(vdi[0x40:], vdiSignature)
+	binary.LittleEndian.PutUint64(vdi[0x170:], 5<<30)
+
+	tests := []struct {
+		name  string
+		image []byte
+		want  int64
+	}{
+		{"raw", make([]byte, 4096), 0},
+		{"empty", nil, 0},
+		{"qcow2", qcow2, 10 << 30},
+		{"vmdk", vmdk, 2 << 30},
+		{"vhd", vhd, 3 << 30},
+		{"vdi", vdi, 5 << 30},
+		{"vhdx", vhdxImage(7 << 30), 7 << 30},
+		{"vhdx without region table", []byte("vhdxfile"), 0},
+	}
+	for _, test := range tests {
+		got, err := VirtualSize(bytes.NewReader(test.image))
+		if err != nil || got != test.want {
+			t.Errorf("%s: got %d, %v, want %d", test.name, got, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package launcher
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// MetricsPort is where the launcher serves guest statistics in the
+// Prometheus text format
+const MetricsPort = 9102
+
+// clockTicks is the kernel's USER_HZ, the unit of thread times in /proc
+const clockTicks = 100
+
+// MetricsServer exports the statistics QEMU keeps about the guest
+type MetricsServer struct {
+	config *Config
+	// labels identify the VM in every sample
+	labels string
+}
+
+func NewMetricsServer(config *Config) *MetricsServer {
+	return &MetricsServer{
+		config: config,
+		labels: fmt.Sprintf("namespace=%q,name=%q", config.Namespace, config.Name),
+	}
+}
+
+func (s *MetricsServer) Run(stopCh <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.serveMetrics)
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", MetricsPort),
+		Handler: mux,
+	}
+	go func() {
+		<-stopCh
+		server.Close()
+	}()
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+type metricsWriter struct {
+	bytes.Buffer
+	labels string
+	// described tracks the metrics whose HELP and TYPE were written
+	described map[string]bool
+}
+
+func (w *metricsWriter) sample(name, metricType, help string, value float64, labels ...string) {
+	if !w.described[name] {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+		w.described[name] = true
+	}
+	fmt.Fprintf(w, "%s{%s", name, w.labels)
+	for i := 0; i+1 < len(labels); i += 2 {
+		fmt.Fprintf(w, ",%s=%q", labels[i], labels[i+1])
+	}
+	fmt.Fprintf(w, "} %s\n", strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// serveMetrics collects what it can; a failing source doesn't hide the
+// others
+func (s *MetricsServer) serveMetrics(rw http.ResponseWriter, req *http.Request) {
+	w := &metricsWriter{labels: s.labels, described: map[string]bool{}}
+
+	q, err := DialQMP()
+	if err != nil {
+		glog.V(2).Infof("error connecting to qmp: %v", err)
+	} else {
+		s.collectVCPUs(w, q)
+		s.collectMemory(w, q)
+		s.collectDisks(w, q)
+		q.Close()
+	}
+	s.collectInterfaces(w)
+
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	rw.Write(w.Bytes())
+}
+
+func (s *MetricsServer) collectVCPUs(w *metricsWriter, q *QMP) {
+	var cpus []struct {
+		CPU      int `json:"CPU"`
+		ThreadID int `json:"thread_id"`
+	}
+	if err := q.Execute("query-cpus", nil, &cpus); err != nil {
+		glog.V(2).Infof("error querying vcpus: %v", err)
+		return
+	}
+	for _, cpu := range cpus {
+		seconds, err := threadCPUSeconds(cpu.ThreadID)
+		if err != nil {
+			glog.V(2).Infof("error reading time of vcpu %d: %v", cpu.CPU, err)
+			continue
+		}
+		w.sample("ranchervm_vcpu_seconds_total", "counter", "Time spent running the vCPU.",
+			seconds, "vcpu", strconv.Itoa(cpu.CPU))
+	}
+}
+
+// threadCPUSeconds returns the user and system time of a QEMU thread
+func threadCPUSeconds(tid int) (float64, error) {
+	data, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", tid))
+	if err != nil {
+		return 0, err
+	}
+	// The command name may contain spaces, fields are counted after it
+	stat := string(data)
+	fields := strings.Fields(stat[strings.LastIndex(stat, ")")+1:])
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("short stat line")
+	}
+	utime, err := strconv.ParseFloat(fields[11], 64)
+	if err != nil {
+		return 0, err
+	}
+	stime, err := strconv.ParseFloat(fields[12], 64)
+	if err != nil {
+		return 0, err
+	}
+	return (utime + stime) / clockTicks, nil
+}
+
+func (s *MetricsServer) collectMemory(w *metricsWriter, q *QMP) {
+	w.sample("ranchervm_memory_configured_bytes", "gauge", "Memory the guest was started with.",
+		float64(int64(s.config.MemoryMB)<<20))
+
+	var balloon struct {
+		Actual int64 `json:"actual"`
+	}
+	if err := q.Execute("query-balloon", nil, &balloon); err != nil {
+		glog.V(2).Infof("error querying balloon: %v", err)
+		return
+	}
+	w.sample("ranchervm_memory_actual_bytes", "gauge", "Memory currently available to the guest through the balloon.",
+		float64(balloon.Actual))
+}
+
+func (s *MetricsServer) collectDisks(w *metricsWriter, q *QMP) {
+	var devices []struct {
+		Device string `json:"device"`
+		Stats  struct {
+			ReadBytes       int64 `json:"rd_bytes"`
+			WriteBytes      int64 `json:"wr_bytes"`
+			ReadOperations  int64 `json:"rd_operations"`
+			WriteOperations int64 `json:"wr_operations"`
+		} `json:"stats"`
+	}
+	if err := q.Execute("query-blockstats", nil, &devices); err != nil {
+		glog.V(2).Infof("error querying block stats: %v", err)
+		return
+	}
+	for _, disk := range s.config.Disks {
+		for _, dev := range devices {
+			if dev.Device != driveID(disk.Name) {
+				continue
+			}
+			w.sample("ranchervm_disk_read_bytes_total", "counter", "Bytes read from the disk.",
+				float64(dev.Stats.ReadBytes), "disk", disk.Name)
+			w.sample("ranchervm_disk_write_bytes_total", "counter", "Bytes written to the disk.",
+				float64(dev.Stats.WriteBytes), "disk", disk.Name)
+			w.sample("ranchervm_disk_read_requests_total", "counter", "Read requests completed by the disk.",
+				float64(dev.Stats.ReadOperations), "disk", disk.Name)
+			w.sample("ranchervm_disk_write_requests_total", "counter", "Write requests completed by the disk.",
+				float64(dev.Stats.WriteOperations), "disk", disk.Name)
+		}
+	}
+}
+
+// collectInterfaces reports the traffic of bridged interfaces from their
+// tap devices. Masqueraded traffic goes through the pod interface, which
+// also carries the launcher's own traffic.
+func (s *MetricsServer) collectInterfaces(w *metricsWriter) {
+	for i, iface := range s.config.Interfaces {
+		var rx, tx int64
+		var err error
+		switch iface.Binding {
+		case vmapi.InterfaceBindingBridge:
+			// What the tap transmits, the guest receives
+			tx, err = interfaceCounter(tapName(i), "rx_bytes")
+			if err == nil {
+				rx, err = interfaceCounter(tapName(i), "tx_bytes")
+			}
+		default:
+			podIface := PodInterfaceName(s.config.Interfaces, i)
+			rx, err = interfaceCounter(podIface, "rx_bytes")
+			if err == nil {
+				tx, err = interfaceCounter(podIface, "tx_bytes")
+			}
+		}
+		if err != nil {
+			glog.V(2).Infof("error reading counters of interface %s: %v", iface.Name, err)
+			continue
+		}
+		w.sample("ranchervm_network_receive_bytes_total", "counter", "Bytes received by the guest interface.",
+			float64(rx), "interface", iface.Name)
+		w.sample("ranchervm_network_transmit_bytes_total", "counter", "Bytes transmitted by the guest interface.",
+			float64(tx), "interface", iface.Name)
+	}
+}
+
+func interfaceCounter(iface, counter string) (int64, error) {
+	data, err := ioutil.ReadFile(filepath.Join("/sys/class/net", iface, "statistics", counter))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
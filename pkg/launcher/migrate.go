@@ -0,0 +1,137 @@
+package launcher
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// MigrationPort is where an incoming launcher listens for the migration
+// stream
+const MigrationPort = 4444
+
+const (
+	// migrationDowntime is the longest the guest may be paused while its last dirty
+	// pages are sent
+	migrationDowntime = 300 * time.Millisecond
+)
+
+// MigrationInfo is the subset of QMP's query-migrate result the controller
+// reports
+type MigrationInfo struct {
+	Status string        `json:"status,omitempty"`
+	RAM    *MigrationRAM `json:"ram,omitempty"`
+	Error  string        `json:"error-desc,omitempty"`
+}
+
+type MigrationRAM struct {
+	Transferred int64   `json:"transferred"`
+	Remaining   int64   `json:"remaining"`
+	Total       int64   `json:"total"`
+	Mbps        float64 `json:"mbps"`
+}
+
+// Migrate starts migrating the guest to the QEMU listening at host. It
+// returns once the migration is under way; its progress is reported by
+// MigrationStatus.
+func Migrate(host string) error {
+	q, err := DialQMP()
+	if err != nil {
+		return err
+	}
+	defer q.Close()
+
+	if err := q.Execute("migrate_set_downtime", map[string]interface{}{
+		"value": migrationDowntime.Seconds(),
+	}, nil); err != nil {
+		return err
+	}
+	return q.Execute("migrate", map[string]interface{}{
+		"uri": fmt.Sprintf("tcp:%s:%d", host, MigrationPort),
+	}, nil)
+}
+
+// MigrationStatus returns the state of the outgoing migration
+func MigrationStatus() (*MigrationInfo, error) {
+	info := &MigrationInfo{}
+	return info, ExecuteQMP("query-migrate", nil, info)
+}
+
+// CancelMigration aborts the outgoing migration, leaving the guest running
+// here
+func CancelMigration() error {
+	return ExecuteQMP("migrate_cancel", nil, nil)
+}
+
+// ServeIncomingMigration relays the migration stream from the source pod to
+// the incoming QEMU until stopCh is closed. QEMU only listens on
+// MigrationSocket, so that no other pod can feed it a guest.
+func ServeIncomingMigration(source string, stopCh <-chan struct{}) error {
+	if net.ParseIP(source) == nil {
+		return fmt.Errorf("invalid migration source %q", source)
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", MigrationPort))
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-stopCh
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-stopCh:
+				return nil
+			default:
+			}
+			glog.V(2).Infof("error accepting migration stream: %v", err)
+			continue
+		}
+		if !isMigrationSource(conn.RemoteAddr(), source) {
+			glog.Warningf("Refusing migration stream from %s, expecting %s", conn.RemoteAddr(), source)
+			conn.Close()
+			continue
+		}
+		go relayMigration(conn)
+	}
+}
+
+// isMigrationSource returns true if addr is the source pod's
+func isMigrationSource(addr net.Addr, source string) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	return ok && tcpAddr.IP.Equal(net.ParseIP(source))
+}
+
+// relayMigration copies the migration stream between conn and QEMU
+func relayMigration(conn net.Conn) {
+	defer conn.Close()
+
+	// QEMU creates the socket shortly after starting
+	var qemu net.Conn
+	err := wait.PollImmediate(100*time.Millisecond, 30*time.Second, func() (bool, error) {
+		var err error
+		qemu, err = net.Dial("unix", MigrationSocket)
+		return err == nil, nil
+	})
+	if err != nil {
+		glog.Errorf("error connecting to incoming qemu: %v", err)
+		return
+	}
+	defer qemu.Close()
+
+	done := make(chan struct{})
+	go func() {
+		io.Copy(conn, qemu)
+		close(done)
+	}()
+	io.Copy(qemu, conn)
+	qemu.(*net.UnixConn).CloseWrite()
+	<-done
+}
@@ -0,0 +1,23 @@
+package launcher
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIsMigrationSource(t *testing.T) {
+	tests := []struct {
+		addr net.Addr
+		want bool
+	}{
+		{&net.TCPAddr{IP: net.ParseIP("10.42.0.7"), Port: 51234}, true},
+		{&net.TCPAddr{IP: net.ParseIP("::ffff:10.42.0.7"), Port: 51234}, true},
+		{&net.TCPAddr{IP: net.ParseIP("10.42.0.8"), Port: 51234}, false},
+		{&net.UnixAddr{Name: MigrationSocket, Net: "unix"}, false},
+	}
+	for _, test := range tests {
+		if got := isMigrationSource(test.addr, "10.42.0.7"); got != test.want {
+			t.Errorf("%v: got %v, want %v", test.addr, got, test.want)
+		}
+	}
+}
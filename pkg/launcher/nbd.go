@@ -0,0 +1,149 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// NBDPort is where disk server pods export the disks hotplugged into VMs
+const NBDPort = 10809
+
+// ServeDisk exports the named disk over NBD until killed. The disk is
+// prepared like the disks of a launcher pod, so that it may later be used as
+// one.
+func ServeDisk(name string) error {
+	if err := PrepareDisk(name); err != nil {
+		return err
+	}
+	cmd := exec.Command("qemu-nbd",
+		"--format=raw",
+		"--bind=0.0.0.0",
+		"--port="+strconv.Itoa(NBDPort),
+		"--export-name="+name,
+		"--persistent",
+		DiskImage(name))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	glog.Infof("Serving disk %s on port %d", name, NBDPort)
+	return cmd.Run()
+}
+
+// hotplugDeviceID is the ID of the device of a hotplugged disk
+func hotplugDeviceID(name string) string {
+	return "hotplug-" + name
+}
+
+// AttachDisk plugs the named disk served by the pod at host into the guest.
+// Attaching is idempotent.
+func AttachDisk(name, host string) error {
+	q, err := DialQMP()
+	if err != nil {
+		return err
+	}
+	defer q.Close()
+
+	if !attached(q, name) {
+		if err := q.Execute("blockdev-add", map[string]interface{}{
+			"driver":    "raw",
+			"node-name": driveID(name),
+			"file": map[string]interface{}{
+				"driver": "nbd",
+				"export": name,
+				"server": map[string]string{
+					"type": "inet",
+					"host": host,
+					"port": strconv.Itoa(NBDPort),
+				},
+			},
+		}, nil); err != nil {
+			return fmt.Errorf("error connecting to disk server: %v", err)
+		}
+	}
+	if pluggedIn(q, name) {
+		return nil
+	}
+	if err := q.Execute("device_add", map[string]interface{}{
+		"driver": "virtio-blk-pci",
+		"id":     hotplugDeviceID(name),
+		"drive":  driveID(name),
+		"serial": name,
+	}, nil); err != nil {
+		q.Execute("blockdev-del", map[string]string{"node-name": driveID(name)}, nil)
+		return fmt.Errorf("error adding disk: %v", err)
+	}
+	return nil
+}
+
+// DetachDisk unplugs the named disk from the guest. The guest must release
+// the device, which is only removed once it did.
+func DetachDisk(name string) error {
+	// Unplugging a device that is already gone fails, which is fine as long
+	// as its node is gone too
+	deviceErr := ExecuteQMP("device_del", map[string]string{"id": hotplugDeviceID(name)}, nil)
+
+	// The node can only be deleted after the guest released the device.
+	// Other QMP clients get their turn while the guest takes its time.
+	var err error
+	for i := 0; i < 30; i++ {
+		var deleted bool
+		if deleted, err = deleteNode(name); deleted {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	if deviceErr != nil {
+		return fmt.Errorf("error removing disk: %v", deviceErr)
+	}
+	return fmt.Errorf("guest did not release disk: %v", err)
+}
+
+// deleteNode deletes the node of the named disk. Returns true once the disk
+// has no node.
+func deleteNode(name string) (bool, error) {
+	q, err := DialQMP()
+	if err != nil {
+		return false, err
+	}
+	defer q.Close()
+
+	err = q.Execute("blockdev-del", map[string]string{"node-name": driveID(name)}, nil)
+	return err == nil || !attached(q, name), err
+}
+
+// attached returns true if the named disk has a node in the guest
+func attached(q *QMP, name string) bool {
+	var nodes []struct {
+		NodeName string `json:"node-name"`
+	}
+	if err := q.Execute("query-named-block-nodes", nil, &nodes); err != nil {
+		return true
+	}
+	for _, node := range nodes {
+		if node.NodeName == driveID(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// pluggedIn returns true if the guest has the device of the named disk
+func pluggedIn(q *QMP, name string) bool {
+	var devices []struct {
+		Name string `json:"name"`
+	}
+	if err := q.Execute("qom-list", map[string]string{"path": strings.TrimSuffix(hotplugPath, "/")}, &devices); err != nil {
+		return false
+	}
+	for _, dev := range devices {
+		if dev.Name == hotplugDeviceID(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package launcher
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/golang/glog"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+const (
+	qemuBinary = "qemu-system-x86_64"
+
+	tunDevice = "/dev/net/tun"
+	// tunDeviceNumber is misc device 10:200
+	tunDeviceNumber = 10<<8 | 200
+)
+
+// QemuArgs returns the QEMU command line for config
+func QemuArgs(config *Config) []string {
+	accel := "kvm"
+	if config.AllowEmulation {
+		accel = "kvm:tcg"
+	}
+	args := []string{
+		"-machine", "q35,accel=" + accel,
+		"-smp", fmt.Sprintf("%d,maxcpus=%d", config.VCPUs(), config.MaxVCPUs()),
+		"-m", memorySize(config),
+		"-vga", "std",
+		"-vnc", ":0",
+		"-serial", "unix:" + SerialSocket + ",server,nowait",
+		"-qmp", "unix:" + QMPSocket + ",server,nowait",
+		// Channel to the guest agent, if the guest runs one
+		"-chardev", "socket,id=qga0,path=" + GuestAgentSocket + ",server,nowait",
+		"-device", "virtio-serial",
+		"-device", "virtserialport,chardev=qga0,name=org.qemu.guest_agent.0",
+		"-device", "virtio-balloon-pci,id=balloon0",
+	}
+	args = append(args, memoryArgs(config)...)
+
+	for _, disk := range config.Disks {
+		// The serial makes the disk show up as /dev/disk/by-id/virtio-<name>
+		args = append(args,
+			"-drive", fmt.Sprintf("id=%s,file=%s,format=raw,if=none", driveID(disk.Name), DiskImage(disk.Name)),
+			"-device", fmt.Sprintf("virtio-blk-pci,drive=%s,serial=%s", driveID(disk.Name), disk.Name))
+	}
+	if config.CloudInit != nil {
+		args = append(args,
+			"-drive", fmt.Sprintf("id=cloud-init,file=%s,format=raw,if=none,readonly=on", CloudInitImage),
+			"-device", "virtio-blk-pci,drive=cloud-init")
+	}
+
+	for i, iface := range config.Interfaces {
+		id := fmt.Sprintf("net%d", i)
+		switch iface.Binding {
+		case vmapi.InterfaceBindingMasquerade:
+			netdev := "user,id=" + id
+			if config.Hostname != "" {
+				netdev += ",hostname=" + config.Hostname
+			}
+			for _, port := range config.Ports {
+				protocol := strings.ToLower(string(port.Protocol))
+				if protocol == "" {
+					protocol = "tcp"
+				}
+				netdev += fmt.Sprintf(",hostfwd=%s::%d-:%d", protocol, port.Port, port.Port)
+			}
+			args = append(args, "-netdev", netdev)
+		case vmapi.InterfaceBindingBridge:
+			args = append(args, "-netdev", fmt.Sprintf("tap,id=%s,ifname=%s,script=no,downscript=no", id, tapName(i)))
+		}
+		args = append(args, "-device", fmt.Sprintf("virtio-net-pci,netdev=%s,mac=%s", id, iface.MACAddress))
+	}
+
+	if config.Incoming {
+		if config.Hotplugged != nil {
+			args = append(args, hotplugArgs(config.Hotplugged)...)
+		}
+		args = append(args, "-incoming", "unix:"+MigrationSocket)
+	}
+	return args
+}
+
+// memorySize returns the -m argument, leaving room for hotplugged memory
+func memorySize(config *Config) string {
+	if config.MaxMemoryMB <= config.MemoryMB {
+		return strconv.Itoa(config.MemoryMB)
+	}
+	return fmt.Sprintf("%d,slots=%d,maxmem=%dM", config.MemoryMB, hotplugSlots, config.MaxMemoryMB)
+}
+
+func driveID(disk string) string {
+	return "drive-" + disk
+}
+
+func tapName(i int) string {
+	return fmt.Sprintf("tap%d", i)
+}
+
+// PodInterfaceName returns the pod interface backing a guest interface. The
+// pod network is always eth0; Multus names secondary networks net1, net2...
+// in the order they were requested.
+func PodInterfaceName(ifaces []vmapi.NetworkInterface, i int) string {
+	if ifaces[i].Network == "" {
+		return "eth0"
+	}
+	n := 0
+	for _, iface := range ifaces[:i+1] {
+		if iface.Network != "" {
+			n++
+		}
+	}
+	return fmt.Sprintf("net%d", n)
+}
+
+// dhcpServerIP is the link-local address identifying the DHCP server of the
+// i-th bridge
+func dhcpServerIP(i int) net.IP {
+	return net.IPv4(169, 254, 75, byte(10+i))
+}
+
+// SetupNetwork bridges a tap device to the pod interface of every bridged
+// guest interface. The pod interface's address is released so the guest can
+// claim it through the returned DHCP servers.
+func SetupNetwork(config *Config) ([]*DHCPServer, error) {
+	servers := []*DHCPServer{}
+	for i, iface := range config.Interfaces {
+		if iface.Binding != vmapi.InterfaceBindingBridge {
+			continue
+		}
+		if err := createTunDevice(); err != nil {
+			return nil, err
+		}
+
+		bridge := fmt.Sprintf("br%d", i)
+		tap := tapName(i)
+		podIface := PodInterfaceName(config.Interfaces, i)
+
+		// Read the address before it's flushed
+		lease, err := PodLease(podIface)
+		if err != nil {
+			return nil, fmt.Errorf("error reading address of %s: %v", podIface, err)
+		}
+
+		cmds := [][]string{
+			{"ip", "link", "add", bridge, "type", "bridge"},
+			{"ip", "tuntap", "add", tap, "mode", "tap"},
+			{"ip", "addr", "flush", "dev", podIface},
+			{"ip", "link", "set", tap, "master", bridge},
+			{"ip", "link", "set", podIface, "master", bridge},
+			{"ip", "link", "set", tap, "up"},
+			{"ip", "link", "set", bridge, "up"},
+		}
+		if lease != nil {
+			cmds = append(cmds, []string{"ip", "addr", "add", dhcpServerIP(i).String() + "/32", "dev", bridge})
+		}
+		for _, cmd := range cmds {
+			if out, err := exec.Command(cmd[0], cmd[1:]...).CombinedOutput(); err != nil {
+				return nil, fmt.Errorf("%s: %v: %s", strings.Join(cmd, " "), err, out)
+			}
+		}
+		glog.V(2).Infof("Bridged %s to %s", podIface, tap)
+
+		if lease == nil {
+			continue
+		}
+		mac, err := net.ParseMAC(iface.MACAddress)
+		if err != nil {
+			return nil, fmt.Errorf("interface %s: %v", iface.Name, err)
+		}
+		lease.MAC = mac
+		lease.Hostname = config.Hostname
+		servers = append(servers, NewDHCPServer(bridge, dhcpServerIP(i), lease))
+	}
+	return servers, nil
+}
+
+// createTunDevice creates /dev/net/tun for QEMU to open tap devices
+// through, as container runtimes don't always provide it
+func createTunDevice() error {
+	if _, err := os.Stat(tunDevice); err == nil || !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(tunDevice), 0755); err != nil {
+		return err
+	}
+	if err := syscall.Mknod(tunDevice, syscall.S_IFCHR|0666, tunDeviceNumber); err != nil {
+		return fmt.Errorf("error creating %s: %v", tunDevice, err)
+	}
+	return nil
+}
+
+// StartQemu starts QEMU for config
+func StartQemu(config *Config) (*exec.Cmd, error) {
+	if err := os.MkdirAll(RunDir, 0700); err != nil {
+		return nil, err
+	}
+
+	args := QemuArgs(config)
+	glog.Infof("Starting %s %s", qemuBinary, strings.Join(args, " "))
+
+	cmd := exec.Command(qemuBinary, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
@@ -0,0 +1,164 @@
+package launcher
+
+import (
+	"testing"
+
+	vmapi "github.com/llparse/kube-crd-skel/pkg/apis/ranchervm/v1alpha1"
+)
+
+// argValues returns the values following every occurrence of flag in args
+func argValues(args []string, flag string) []string {
+	var values []string
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == flag {
+			values = append(values, args[i+1])
+		}
+	}
+	return values
+}
+
+func hasArg(args []string, flag, value string) bool {
+	for _, v := range argValues(args, flag) {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestQemuArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   [][2]string
+		absent []string
+	}{
+		{
+			name: "minimal",
+			config: Config{
+				CpuMillis: 1500,
+				MemoryMB:  512,
+			},
+			want: [][2]string{
+				{"-machine", "q35,accel=kvm"},
+				{"-smp", "2,maxcpus=2"},
+				{"-m", "512"},
+			},
+			absent: []string{"-netdev", "-incoming", "-mem-path"},
+		},
+		{
+			name: "emulation and hotplug headroom",
+			config: Config{
+				CpuMillis:      1000,
+				MemoryMB:       1024,
+				MaxCpuMillis:   4000,
+				MaxMemoryMB:    4096,
+				AllowEmulation: true,
+			},
+			want: [][2]string{
+				{"-machine", "q35,accel=kvm:tcg"},
+				{"-smp", "1,maxcpus=4"},
+				{"-m", "1024,slots=16,maxmem=4096M"},
+			},
+		},
+		{
+			name: "masquerade with ports",
+			config: Config{
+				CpuMillis: 1000,
+				MemoryMB:  512,
+				Hostname:  "guest",
+				Interfaces: []vmapi.NetworkInterface{
+					{Name: "default", Binding: vmapi.InterfaceBindingMasquerade, MACAddress: "52:54:00:00:00:01"},
+				},
+				Ports: []vmapi.VirtualMachinePort{
+					{Name: "ssh", Port: 22},
+					{Name: "dns", Port: 53, Protocol: "UDP"},
+				},
+			},
+			want: [][2]string{
+				{"-netdev", "user,id=net0,hostname=guest,hostfwd=tcp::22-:22,hostfwd=udp::53-:53"},
+				{"-device", "virtio-net-pci,netdev=net0,mac=52:54:00:00:00:01"},
+			},
+		},
+		{
+			name: "bridge",
+			config: Config{
+				CpuMillis: 1000,
+				MemoryMB:  512,
+				Interfaces: []vmapi.NetworkInterface{
+					{Name: "default", Binding: vmapi.InterfaceBindingMasquerade, MACAddress: "52:54:00:00:00:01"},
+					{Name: "lan", Binding: vmapi.InterfaceBindingBridge, Network: "lan", MACAddress: "52:54:00:00:00:02"},
+				},
+			},
+			want: [][2]string{
+				{"-netdev", "tap,id=net1,ifname=tap1,script=no,downscript=no"},
+				{"-device", "virtio-net-pci,netdev=net1,mac=52:54:00:00:00:02"},
+			},
+		},
+		{
+			name: "disks and cloud-init",
+			config: Config{
+				CpuMillis: 1000,
+				MemoryMB:  512,
+				Disks:     []vmapi.Disk{{Name: "root"}},
+				CloudInit: &vmapi.CloudInit{},
+			},
+			want: [][2]string{
+				{"-drive", "id=drive-root,file=" + DiskImage("root") + ",format=raw,if=none"},
+				{"-device", "virtio-blk-pci,drive=drive-root,serial=root"},
+				{"-device", "virtio-blk-pci,drive=cloud-init"},
+			},
+		},
+		{
+			name: "hugepages",
+			config: Config{
+				CpuMillis: 1000,
+				MemoryMB:  2048,
+				Hugepages: "2Mi",
+			},
+			want: [][2]string{
+				{"-mem-path", HugepagesDir},
+			},
+		},
+		{
+			name: "incoming",
+			config: Config{
+				CpuMillis:       1000,
+				MemoryMB:        512,
+				Incoming:        true,
+				MigrationSource: "10.42.0.7",
+			},
+			want: [][2]string{
+				{"-incoming", "unix:" + MigrationSocket},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		args := QemuArgs(&test.config)
+		for _, want := range test.want {
+			if !hasArg(args, want[0], want[1]) {
+				t.Errorf("%s: missing %s %s in %v", test.name, want[0], want[1], args)
+			}
+		}
+		for _, flag := range test.absent {
+			if values := argValues(args, flag); len(values) > 0 {
+				t.Errorf("%s: unexpected %s %v", test.name, flag, values)
+			}
+		}
+	}
+}
+
+func TestPodInterfaceName(t *testing.T) {
+	ifaces := []vmapi.NetworkInterface{
+		{Name: "a", Network: "lan"},
+		{Name: "default"},
+		{Name: "b", Network: "lan"},
+		{Name: "c", Network: "storage"},
+	}
+	for i, want := range []string{"net1", "eth0", "net2", "net3"} {
+		if got := PodInterfaceName(ifaces, i); got != want {
+			t.Errorf("interface %d: got %s, want %s", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package launcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+// qmpTimeout bounds every exchange with QEMU. QEMU serves one QMP client at
+// a time, so a client stuck on its connection would block all others.
+const qmpTimeout = 10 * time.Second
+
+// qmpSocket is the socket DialQMP connects to, replaced in tests
+var qmpSocket = QMPSocket
+
+// QMP is a client of the QEMU Machine Protocol. QEMU serves one QMP client
+// at a time, so connections should be short lived.
+type QMP struct {
+	conn    net.Conn
+	decoder *json.Decoder
+}
+
+type qmpCommand struct {
+	Execute   string      `json:"execute"`
+	Arguments interface{} `json:"arguments,omitempty"`
+}
+
+type qmpResponse struct {
+	Return json.RawMessage `json:"return"`
+	Error  *struct {
+		Class string `json:"class"`
+		Desc  string `json:"desc"`
+	} `json:"error"`
+	Event string `json:"event"`
+}
+
+// DialQMP connects to QEMU's QMP socket and negotiates capabilities
+func DialQMP() (*QMP, error) {
+	conn, err := net.DialTimeout("unix", qmpSocket, qmpTimeout)
+	if err != nil {
+		return nil, err
+	}
+	q := &QMP{
+		conn:    conn,
+		decoder: json.NewDecoder(conn),
+	}
+
+	// QEMU greets clients once it's done with the previous one
+	if err := conn.SetDeadline(time.Now().Add(qmpTimeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	// Discard the greeting
+	var greeting map[string]interface{}
+	if err := q.decoder.Decode(&greeting); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error reading qmp greeting: %v", err)
+	}
+	if err := q.Execute("qmp_capabilities", nil, nil); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return q, nil
+}
+
+// Execute runs command with arguments, decoding its return value into result
+// if it is not nil.
+func (q *QMP) Execute(command string, arguments, result interface{}) error {
+	if err := q.conn.SetDeadline(time.Now().Add(qmpTimeout)); err != nil {
+		return err
+	}
+	if err := json.NewEncoder(q.conn).Encode(qmpCommand{command, arguments}); err != nil {
+		return err
+	}
+	for {
+		var resp qmpResponse
+		if err := q.decoder.Decode(&resp); err != nil {
+			return err
+		}
+		// Events may be interleaved with the response
+		if resp.Event != "" {
+			continue
+		}
+		if resp.Error != nil {
+			return fmt.Errorf("%s: %s: %s", command, resp.Error.Class, resp.Error.Desc)
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Return, result)
+	}
+}
+
+func (q *QMP) Close() error {
+	return q.conn.Close()
+}
+
+// ExecuteQMP runs a single command over a new QMP connection
+func ExecuteQMP(command string, arguments, result interface{}) error {
+	q, err := DialQMP()
+	if err != nil {
+		return err
+	}
+	defer q.Close()
+	return q.Execute(command, arguments, result)
+}
@@ -0,0 +1,53 @@
+package launcher
+
+import (
+	"sync"
+)
+
+// RingBuffer retains the last size bytes written to it
+type RingBuffer struct {
+	mu   sync.Mutex
+	buf  []byte
+	pos  int
+	full bool
+}
+
+func NewRingBuffer(size int) *RingBuffer {
+	return &RingBuffer{
+		buf: make([]byte, size),
+	}
+}
+
+func (b *RingBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := len(p)
+	// Only the tail of an oversized write can be retained
+	if len(p) > len(b.buf) {
+		p = p[len(p)-len(b.buf):]
+	}
+	for len(p) > 0 {
+		c := copy(b.buf[b.pos:], p)
+		p = p[c:]
+		b.pos += c
+		if b.pos == len(b.buf) {
+			b.pos = 0
+			b.full = true
+		}
+	}
+	return n, nil
+}
+
+// Bytes returns a copy of the buffered data, oldest first
+func (b *RingBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return append([]byte{}, b.buf[:b.pos]...)
+	}
+	out := make([]byte, 0, len(b.buf))
+	out = append(out, b.buf[b.pos:]...)
+	return append(out, b.buf[:b.pos]...)
+}
@@ -0,0 +1,45 @@
+package launcher
+
+import (
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		writes []string
+		want   string
+	}{
+		{"empty", 4, nil, ""},
+		{"partial", 8, []string{"abc", "de"}, "abcde"},
+		{"exactly full", 4, []string{"ab", "cd"}, "abcd"},
+		{"wraps", 4, []string{"abc", "def"}, "cdef"},
+		{"wraps twice", 3, []string{"ab", "cd", "ef", "g"}, "efg"},
+		{"oversized write", 4, []string{"ab", "cdefghij"}, "ghij"},
+		{"oversized first write", 4, []string{"abcdefgh", "i"}, "fghi"},
+	}
+
+	for _, test := range tests {
+		b := NewRingBuffer(test.size)
+		for _, w := range test.writes {
+			n, err := b.Write([]byte(w))
+			if n != len(w) || err != nil {
+				t.Errorf("%s: write %q returned %d, %v", test.name, w, n, err)
+			}
+		}
+		if got := string(b.Bytes()); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRingBufferBytesIsCopy(t *testing.T) {
+	b := NewRingBuffer(4)
+	b.Write([]byte("abcd"))
+	out := b.Bytes()
+	out[0] = 'x'
+	if got := string(b.Bytes()); got != "abcd" {
+		t.Errorf("buffer modified through Bytes: %q", got)
+	}
+}
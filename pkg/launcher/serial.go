@@ -0,0 +1,186 @@
+package launcher
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// ConsoleAttach streams the serial console to and from the client
+	ConsoleAttach = "attach"
+	// ConsoleLog writes the buffered serial output to the client and closes
+	ConsoleLog = "log"
+
+	// consoleLogSize is how much serial output is kept for post-mortem reads
+	consoleLogSize = 1 << 20
+	// clientBacklog is how many reads of serial output a client may fall
+	// behind before it's disconnected
+	clientBacklog = 256
+)
+
+// SerialConsole multiplexes QEMU's serial port between any number of
+// attached clients, retaining recent output in a ring buffer.
+type SerialConsole struct {
+	log *RingBuffer
+
+	mu     sync.Mutex
+	serial net.Conn
+	// clients are sent serial output through buffered channels, so that a
+	// slow client can't stall the guest or other clients
+	clients map[net.Conn]chan []byte
+}
+
+func NewSerialConsole() *SerialConsole {
+	return &SerialConsole{
+		log:     NewRingBuffer(consoleLogSize),
+		clients: map[net.Conn]chan []byte{},
+	}
+}
+
+// Run connects to QEMU's serial socket and serves clients on ConsoleSocket
+// until stopCh is closed.
+func (c *SerialConsole) Run(stopCh <-chan struct{}) error {
+	// QEMU creates the socket shortly after starting
+	var serial net.Conn
+	err := wait.PollImmediate(100*time.Millisecond, 30*time.Second, func() (bool, error) {
+		var err error
+		serial, err = net.Dial("unix", SerialSocket)
+		return err == nil, nil
+	})
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.serial = serial
+	c.mu.Unlock()
+
+	os.Remove(ConsoleSocket)
+	listener, err := net.Listen("unix", ConsoleSocket)
+	if err != nil {
+		serial.Close()
+		return err
+	}
+
+	go func() {
+		<-stopCh
+		listener.Close()
+		serial.Close()
+	}()
+
+	go c.readSerial(serial)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-stopCh:
+				return nil
+			default:
+			}
+			glog.V(2).Infof("error accepting console client: %v", err)
+			continue
+		}
+		go c.serve(conn)
+	}
+}
+
+// readSerial copies guest output to the log and every attached client
+func (c *SerialConsole) readSerial(serial net.Conn) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := serial.Read(buf)
+		if n > 0 {
+			c.log.Write(buf[:n])
+			data := append([]byte{}, buf[:n]...)
+			c.mu.Lock()
+			for client, output := range c.clients {
+				select {
+				case output <- data:
+				default:
+					glog.V(2).Infof("Disconnecting console client falling behind")
+					c.removeClient(client)
+				}
+			}
+			c.mu.Unlock()
+		}
+		if err != nil {
+			if err != io.EOF {
+				glog.V(2).Infof("error reading serial console: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// removeClient stops sending output to client and disconnects it. c.mu must
+// be held.
+func (c *SerialConsole) removeClient(client net.Conn) {
+	if output, ok := c.clients[client]; ok {
+		delete(c.clients, client)
+		close(output)
+		client.Close()
+	}
+}
+
+func (c *SerialConsole) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	command, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	switch strings.TrimSpace(command) {
+	case ConsoleLog:
+		conn.Write(c.log.Bytes())
+		conn.Close()
+
+	case ConsoleAttach:
+		output := make(chan []byte, clientBacklog)
+		c.mu.Lock()
+		c.clients[conn] = output
+		serial := c.serial
+		c.mu.Unlock()
+
+		go func() {
+			for data := range output {
+				if _, err := conn.Write(data); err != nil {
+					break
+				}
+			}
+			// Unblocks the copy of client input below
+			conn.Close()
+		}()
+
+		// Copy client input to the guest until the client goes away
+		io.Copy(serial, reader)
+
+		c.mu.Lock()
+		c.removeClient(conn)
+		c.mu.Unlock()
+
+	default:
+		conn.Close()
+	}
+}
+
+// ConnectConsole opens a client connection to the launcher's serial console
+func ConnectConsole(command string) (net.Conn, error) {
+	conn, err := net.Dial("unix", ConsoleSocket)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(conn, command+"\n"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
@@ -0,0 +1,76 @@
+package launcher
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// attach connects a client to the console as ConnectConsole would
+func attach(t *testing.T, c *SerialConsole) net.Conn {
+	c.mu.Lock()
+	attached := len(c.clients)
+	c.mu.Unlock()
+
+	client, server := net.Pipe()
+	go c.serve(server)
+	if _, err := io.WriteString(client, ConsoleAttach+"\n"); err != nil {
+		t.Fatalf("error attaching: %v", err)
+	}
+	// Wait for the client to be registered
+	for i := 0; i < 100; i++ {
+		c.mu.Lock()
+		n := len(c.clients)
+		c.mu.Unlock()
+		if n > attached {
+			return client
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("client was not attached")
+	return nil
+}
+
+func TestSerialConsoleDropsSlowClients(t *testing.T) {
+	guest, serial := net.Pipe()
+	defer guest.Close()
+	c := NewSerialConsole()
+	c.serial = serial
+
+	slow := attach(t, c)
+	defer slow.Close()
+	fast := attach(t, c)
+	defer fast.Close()
+
+	chunk := bytes.Repeat([]byte("x"), 100)
+	go c.readSerial(serial)
+
+	// The slow client never reads, and must not hold up the fast one. Each
+	// chunk is read back before the next is written so that the fast client
+	// never falls behind.
+	got := make([]byte, len(chunk))
+	for i := 0; i < clientBacklog*2; i++ {
+		if _, err := guest.Write(chunk); err != nil {
+			t.Fatalf("error writing serial output: %v", err)
+		}
+		if _, err := io.ReadFull(fast, got); err != nil {
+			t.Fatalf("fast client: error reading chunk %d: %v", i, err)
+		}
+		if !bytes.Equal(got, chunk) {
+			t.Fatalf("fast client: got %q for chunk %d", got, i)
+		}
+	}
+
+	c.mu.Lock()
+	attached := len(c.clients)
+	c.mu.Unlock()
+	if attached != 1 {
+		t.Errorf("%d clients attached, want only the fast one", attached)
+	}
+	want := bytes.Repeat(chunk, clientBacklog*2)
+	if got := c.log.Bytes(); !bytes.Equal(got, want) {
+		t.Errorf("log has %d bytes, want %d", len(got), len(want))
+	}
+}
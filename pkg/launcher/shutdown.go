@@ -0,0 +1,47 @@
+package launcher
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// TerminationLog is where the kubelet reads the launcher's exit message from
+const TerminationLog = "/dev/termination-log"
+
+// Shutdown presses the guest's ACPI power button. QEMU exits once the guest
+// has powered off.
+func Shutdown() error {
+	return ExecuteQMP("system_powerdown", nil, nil)
+}
+
+// ShutdownAndWait shuts the guest down and waits for QEMU to exit. Guests
+// that aren't running, such as the paused source of a completed migration,
+// can't react to ACPI, so QEMU is told to quit instead.
+func ShutdownAndWait() error {
+	var status struct {
+		Status string `json:"status"`
+	}
+	if err := ExecuteQMP("query-status", nil, &status); err != nil {
+		return err
+	}
+	command := "system_powerdown"
+	if status.Status != "running" {
+		command = "quit"
+	}
+	glog.Infof("Guest is %s, sending %s", status.Status, command)
+	if err := ExecuteQMP(command, nil, nil); err != nil {
+		return err
+	}
+
+	// QEMU stops accepting QMP connections as it exits. The kubelet kills
+	// the pod if this outlasts its grace period.
+	for {
+		q, err := DialQMP()
+		if err != nil {
+			return nil
+		}
+		q.Close()
+		time.Sleep(time.Second)
+	}
+}
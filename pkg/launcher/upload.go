@@ -0,0 +1,190 @@
+package launcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/golang/glog"
+)
+
+// UploadPort is where upload pods receive images
+const UploadPort = 9105
+
+// UploadStatus is the progress of an upload. Clients resume interrupted
+// uploads from Offset.
+type UploadStatus struct {
+	// Offset is how many bytes were received
+	Offset int64 `json:"offset"`
+	// Size is the size of the image, once the first chunk declared it
+	Size int64 `json:"size,omitempty"`
+}
+
+// uploadServer appends chunks of an image to a scratch file. Chunks are PUT
+// in order with a Content-Range header, and a GET returns the status.
+type uploadServer struct {
+	mu       sync.Mutex
+	file     *os.File
+	sizePath string
+	status   UploadStatus
+	done     chan struct{}
+}
+
+// newUploadServer receives the image at path. Scratch space outlives the
+// pod, so what an earlier pod received is resumed from, with the size the
+// first chunk declared kept alongside.
+func newUploadServer(path string) (*uploadServer, error) {
+	s := &uploadServer{sizePath: path + ".size", done: make(chan struct{})}
+	data, err := ioutil.ReadFile(s.sizePath)
+	if err == nil {
+		if s.status.Size, err = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", s.sizePath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	flags := os.O_RDWR | os.O_CREATE
+	// Chunks are only written once their size is known
+	if s.status.Size == 0 {
+		flags |= os.O_TRUNC
+	}
+	if s.file, err = os.OpenFile(path, flags, 0644); err != nil {
+		return nil, err
+	}
+	if s.status.Offset, err = s.file.Seek(0, io.SeekEnd); err != nil {
+		s.file.Close()
+		return nil, err
+	}
+	if s.status.Offset > s.status.Size {
+		s.file.Close()
+		return nil, fmt.Errorf("received %d bytes of a %d byte image", s.status.Offset, s.status.Size)
+	}
+	if s.status.Size != 0 && s.status.Offset == s.status.Size {
+		close(s.done)
+	}
+	return s, nil
+}
+
+func (s *uploadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case "GET", "HEAD":
+		s.writeStatus(w, http.StatusOK)
+	case "PUT":
+		var start, end, size int64
+		if _, err := fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &size); err != nil {
+			http.Error(w, "Content-Range of the form bytes <start>-<end>/<size> required", http.StatusBadRequest)
+			return
+		}
+		// Empty images would never be complete
+		if size <= 0 {
+			http.Error(w, "empty images are not supported", http.StatusBadRequest)
+			return
+		}
+		if start < 0 || start > end || end >= size || (s.status.Size != 0 && size != s.status.Size) {
+			http.Error(w, "invalid Content-Range "+r.Header.Get("Content-Range"), http.StatusBadRequest)
+			return
+		}
+		// Chunks overlapping what was received are refused, so that clients
+		// resume from the right offset
+		if start != s.status.Offset {
+			s.writeStatus(w, http.StatusConflict)
+			return
+		}
+		if s.status.Size == 0 {
+			if err := ioutil.WriteFile(s.sizePath, []byte(strconv.FormatInt(size, 10)), 0644); err != nil {
+				glog.Errorf("error writing image size: %v", err)
+				s.writeStatus(w, http.StatusInternalServerError)
+				return
+			}
+			s.status.Size = size
+		}
+		n, err := io.CopyN(s.file, r.Body, end-start+1)
+		s.status.Offset += n
+		if err == nil {
+			err = s.file.Sync()
+		}
+		if err != nil {
+			glog.Errorf("error receiving chunk at %d: %v", start, err)
+			s.writeStatus(w, http.StatusInternalServerError)
+			return
+		}
+		s.writeStatus(w, http.StatusOK)
+		if s.status.Offset == s.status.Size {
+			close(s.done)
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *uploadServer) writeStatus(w http.ResponseWriter, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(s.status)
+}
+
+// Upload receives an image over HTTP, then converts it to a raw image at
+// image. The server only serves requests bearing the token of
+// EnvServerToken, and keeps reporting its status while converting.
+func Upload(image string) (*ImageInfo, error) {
+	path := filepath.Join(ScratchDir, "upload")
+	s, err := newUploadServer(path)
+	if err != nil {
+		return nil, err
+	}
+	handler, err := requireToken(s)
+	if err != nil {
+		s.file.Close()
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	})
+	mux.Handle("/", handler)
+	errCh := make(chan error, 1)
+	go func() {
+		glog.Infof("Receiving image on port %d", UploadPort)
+		errCh <- http.ListenAndServe(fmt.Sprintf(":%d", UploadPort), mux)
+	}()
+	select {
+	case err := <-errCh:
+		s.file.Close()
+		return nil, err
+	case <-s.done:
+	}
+	// Chunks may still be written by requests in flight
+	s.mu.Lock()
+	err = s.file.Close()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := ProbeImage(path)
+	if err != nil {
+		return nil, err
+	}
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(filepath.Dir(image), &fs); err != nil {
+		return nil, err
+	}
+	if available := int64(fs.Bavail) * int64(fs.Bsize); info.VirtualSize > available {
+		return nil, fmt.Errorf("%s image needs %d bytes, the claim has %d", info.Format, info.VirtualSize, available)
+	}
+	glog.Infof("Converting %d byte %s image", s.status.Size, info.Format)
+	return ConvertImage(path, image)
+}
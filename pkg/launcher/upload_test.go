@@ -0,0 +1,181 @@
+package launcher
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func putChunk(t *testing.T, s *uploadServer, contentRange, body string) (int, UploadStatus) {
+	req := httptest.NewRequest("PUT", "/", strings.NewReader(body))
+	if contentRange != "" {
+		req.Header.Set("Content-Range", contentRange)
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	var status UploadStatus
+	if w.Code == http.StatusOK || w.Code == http.StatusConflict {
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatalf("%s: invalid status %q: %v", contentRange, w.Body.String(), err)
+		}
+	}
+	return w.Code, status
+}
+
+func isDone(s *uploadServer) bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestUploadServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "upload")
+	s, err := newUploadServer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		contentRange, body string
+		wantCode           int
+		wantOffset         int64
+	}{
+		{"", "hello", http.StatusBadRequest, 0},
+		{"bytes 0-4", "hello", http.StatusBadRequest, 0},
+		{"bytes 0--1/0", "", http.StatusBadRequest, 0},
+		{"bytes 4-0/11", "hello", http.StatusBadRequest, 0},
+		{"bytes 0-11/11", "hello world", http.StatusBadRequest, 0},
+		{"bytes -1-3/11", "hello", http.StatusBadRequest, 0},
+		{"bytes 5-10/11", " world", http.StatusConflict, 0},
+		{"bytes 0-4/11", "hello", http.StatusOK, 5},
+		// Sizes can't change once declared
+		{"bytes 5-10/12", " world", http.StatusBadRequest, 5},
+		{"bytes 0-4/11", "hello", http.StatusConflict, 5},
+		// Short bodies are kept, and resumed from
+		{"bytes 5-10/11", " wo", http.StatusInternalServerError, 8},
+		{"bytes 8-10/11", "rld", http.StatusOK, 11},
+	}
+	for _, test := range tests {
+		code, status := putChunk(t, s, test.contentRange, test.body)
+		if code != test.wantCode || s.status.Offset != test.wantOffset {
+			t.Errorf("%q: got code %d and offset %d, want %d and %d", test.contentRange, code, s.status.Offset, test.wantCode, test.wantOffset)
+		}
+		if code == http.StatusOK && status != s.status {
+			t.Errorf("%q: got status %+v, want %+v", test.contentRange, status, s.status)
+		}
+		if done := isDone(s); done != (s.status.Offset == 11) {
+			t.Errorf("%q: got done %v at offset %d", test.contentRange, done, s.status.Offset)
+		}
+	}
+	s.file.Close()
+	if data, err := ioutil.ReadFile(path); err != nil || string(data) != "hello world" {
+		t.Errorf("got image %q, %v", data, err)
+	}
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("POST", "/", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: got code %d", w.Code)
+	}
+}
+
+func TestUploadServerResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "upload")
+
+	// Files without a size are from pods that received nothing
+	if err := ioutil.WriteFile(path, []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := newUploadServer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.status != (UploadStatus{}) {
+		t.Errorf("got status %+v of a stale file", s.status)
+	}
+	if code, _ := putChunk(t, s, "bytes 0-4/11", "hello"); code != http.StatusOK {
+		t.Fatalf("got code %d", code)
+	}
+	s.file.Close()
+
+	s, err = newUploadServer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.status != (UploadStatus{Offset: 5, Size: 11}) || isDone(s) {
+		t.Errorf("got status %+v after restarting", s.status)
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != `{"offset":5,"size":11}` {
+		t.Errorf("GET: got %d %q", w.Code, w.Body.String())
+	}
+	if code, _ := putChunk(t, s, "bytes 5-10/11", " world"); code != http.StatusOK || !isDone(s) {
+		t.Fatalf("got code %d", code)
+	}
+	s.file.Close()
+
+	// Pods restarting while converting convert the image again
+	s, err = newUploadServer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.file.Close()
+	if !isDone(s) {
+		t.Error("complete image isn't done")
+	}
+
+	if err := ioutil.WriteFile(path+".size", []byte("3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newUploadServer(path); err == nil {
+		t.Error("got no error for an image larger than its size")
+	}
+}
+
+func TestRequireToken(t *testing.T) {
+	os.Setenv(EnvServerToken, "secret")
+	defer os.Unsetenv(EnvServerToken)
+	handler, err := requireToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for auth, want := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer":        http.StatusUnauthorized,
+		"Bearer secre":  http.StatusUnauthorized,
+		"Basic secret":  http.StatusUnauthorized,
+		"Bearer secret": http.StatusOK,
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", auth)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("%q: got code %d, want %d", auth, w.Code, want)
+		}
+	}
+
+	os.Unsetenv(EnvServerToken)
+	if _, err := requireToken(handler); err == nil {
+		t.Error("got no error without a token")
+	}
+}